@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+)
+
+// informerResync is how often the dashboard's informers replay their cache, in case a
+// watch event was missed
+const informerResync = 10 * time.Minute
+
+// cachedClient is the informer-backed client shared by every request when the
+// dashboard runs with -informer-cache, nil otherwise
+var cachedClient *k8s.Client
+
+// enableInformerCache starts the informers behind cachedClient, so requests read the
+// cluster from a local cache kept warm by watches instead of the API server
+func enableInformerCache() error {
+	client, err := k8s.NewClient()
+	if err != nil {
+		return err
+	}
+	cached, err := k8s.NewCachedClient(context.Background(), client.Interface, informerResync)
+	if err != nil {
+		return err
+	}
+	cachedClient = &k8s.Client{Interface: cached, Config: client.Config}
+	return nil
+}
+
+// dashboardClient returns the shared cached client when the informer cache is
+// enabled, and a new client otherwise
+func dashboardClient() (*k8s.Client, error) {
+	if cachedClient != nil {
+		return cachedClient, nil
+	}
+	return k8s.NewClient()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		origins       []string
+		method        string
+		origin        string
+		requestMethod string
+		wantStatus    int
+		wantAllow     string
+		wantMethods   string
+	}{
+		{name: "no origins configured", method: http.MethodGet, origin: "https://app.example.com", wantStatus: http.StatusOK},
+		{name: "allowed origin", origins: []string{"https://app.example.com"}, method: http.MethodGet,
+			origin: "https://app.example.com", wantStatus: http.StatusOK, wantAllow: "https://app.example.com"},
+		{name: "configured origin is trimmed", origins: []string{" https://app.example.com/ "}, method: http.MethodGet,
+			origin: "https://app.example.com", wantStatus: http.StatusOK, wantAllow: "https://app.example.com"},
+		{name: "other origin", origins: []string{"https://app.example.com"}, method: http.MethodGet,
+			origin: "https://evil.example.com", wantStatus: http.StatusOK},
+		{name: "wildcard echoes the origin", origins: []string{"*"}, method: http.MethodGet,
+			origin: "https://any.example.com", wantStatus: http.StatusOK, wantAllow: "https://any.example.com"},
+		{name: "same-origin request", origins: []string{"*"}, method: http.MethodGet, wantStatus: http.StatusOK},
+		{name: "preflight", origins: []string{"https://app.example.com"}, method: http.MethodOptions,
+			origin: "https://app.example.com", requestMethod: http.MethodGet, wantStatus: http.StatusNoContent,
+			wantAllow: "https://app.example.com", wantMethods: "GET, OPTIONS"},
+		{name: "preflight from other origin", origins: []string{"https://app.example.com"}, method: http.MethodOptions,
+			origin: "https://evil.example.com", requestMethod: http.MethodGet, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(corsMiddleware(tt.origins))
+			router.GET("/api/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(tt.method, "/api/health", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, tt.wantStatus, recorder.Code)
+			assert.Equal(t, tt.wantAllow, recorder.Header().Get("Access-Control-Allow-Origin"))
+			assert.Equal(t, tt.wantMethods, recorder.Header().Get("Access-Control-Allow-Methods"))
+		})
+	}
+}
@@ -1,12 +1,11 @@
 package main
 
 import (
-	"context"
 	"net/http"
 
-	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
 	"github.com/abrarahmad1510/k8s-lens/pkg/multicluster"
 	"github.com/gin-gonic/gin"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -18,7 +17,7 @@ func healthHandler(c *gin.Context) {
 }
 
 func clusterInfoHandler(c *gin.Context) {
-	client, err := k8s.NewClient()
+	client, err := dashboardClient()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -30,18 +29,24 @@ func clusterInfoHandler(c *gin.Context) {
 		return
 	}
 
-	// Get node count
-	nodes, err := client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
-	nodeCount := 0
-	if err == nil {
-		nodeCount = len(nodes.Items)
-	}
+	// Count nodes and pods page by page so large clusters are never loaded in one response
+	nodeCount, nodeErr := countItems(func(opts metav1.ListOptions) (int, metav1.ListMeta, error) {
+		nodes, err := client.CoreV1().Nodes().List(c.Request.Context(), opts)
+		if err != nil {
+			return 0, metav1.ListMeta{}, err
+		}
+		return len(nodes.Items), nodes.ListMeta, nil
+	})
 
-	// Get pod count
-	pods, err := client.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
-	podCount := 0
-	if err == nil {
-		podCount = len(pods.Items)
+	podCount, podErr := countItems(func(opts metav1.ListOptions) (int, metav1.ListMeta, error) {
+		pods, err := client.CoreV1().Pods("").List(c.Request.Context(), opts)
+		if err != nil {
+			return 0, metav1.ListMeta{}, err
+		}
+		return len(pods.Items), pods.ListMeta, nil
+	})
+	if nodeErr == nil && podErr == nil {
+		metrics.setClusterCounts(nodeCount, podCount)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -52,6 +57,59 @@ func clusterInfoHandler(c *gin.Context) {
 	})
 }
 
+// podsHandler lists pods one page at a time. Pass ?limit= to set the page size and the
+// returned continue token as ?continue= to fetch the next page. ?fieldSelector= filters
+// them like kubectl --field-selector, e.g. status.phase=Pending; pass it with every page.
+func podsHandler(c *gin.Context) {
+	opts, err := pageOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts.FieldSelector = c.Query("fieldSelector")
+
+	client, err := dashboardClient()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	pods, err := client.CoreV1().Pods(c.Query("namespace")).List(c.Request.Context(), opts)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if apierrors.IsResourceExpired(err) {
+			// The continue token is too old; the client has to restart from the first page
+			status = http.StatusGone
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]gin.H, 0, len(pods.Items))
+	for _, pod := range pods.Items {
+		var restarts int32
+		for _, status := range pod.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+		items = append(items, gin.H{
+			"name":      pod.Name,
+			"namespace": pod.Namespace,
+			"phase":     pod.Status.Phase,
+			"node":      pod.Spec.NodeName,
+			"restarts":  restarts,
+		})
+	}
+
+	response := gin.H{
+		"pods":     items,
+		"continue": pods.Continue,
+	}
+	if pods.RemainingItemCount != nil {
+		response["remainingItemCount"] = *pods.RemainingItemCount
+	}
+	c.JSON(http.StatusOK, response)
+}
+
 func analysisHandler(c *gin.Context) {
 	resourceType := c.Param("resourceType")
 	resourceName := c.Param("resourceName")
@@ -113,7 +171,7 @@ func multiclusterCompareHandler(c *gin.Context) {
 		return
 	}
 
-	comparison, err := manager.CompareClusters(resourceType)
+	comparison, err := manager.CompareClusters(c.Request.Context(), resourceType)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -134,7 +192,7 @@ func multiclusterFederatedHandler(c *gin.Context) {
 		return
 	}
 
-	report, err := manager.FederatedAnalysis()
+	report, err := manager.FederatedAnalysis(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
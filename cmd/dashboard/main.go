@@ -1,25 +1,48 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"net/http"
 	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	corsOrigins := flag.String("cors-origins", os.Getenv("CORS_ORIGINS"),
+		"Comma-separated origins allowed to call the API cross-origin, or * for any (default none, env CORS_ORIGINS)")
+	informerCache := flag.Bool("informer-cache", false,
+		"Serve cluster reads from an informer cache kept warm by watches instead of querying the API server on every request")
+	flag.Parse()
+
+	if *informerCache {
+		if err := enableInformerCache(); err != nil {
+			log.Fatalf("Failed to start informer cache: %v", err)
+		}
+		log.Printf("Informer cache enabled")
+	}
+
 	router := gin.Default()
+	router.Use(metrics.middleware())
+	if *corsOrigins != "" {
+		router.Use(corsMiddleware(strings.Split(*corsOrigins, ",")))
+		log.Printf("CORS enabled for origins: %s", *corsOrigins)
+	}
 
 	// Serve static files
 	router.Static("/static", "./web/static")
 	router.LoadHTMLGlob("web/templates/*")
 
+	router.GET("/metrics", metrics.handler)
+
 	// API routes
 	api := router.Group("/api")
 	{
 		api.GET("/health", healthHandler)
 		api.GET("/cluster/info", clusterInfoHandler)
+		api.GET("/pods", podsHandler)
 		api.GET("/analysis/:resourceType/:resourceName", analysisHandler)
 		api.GET("/optimization/:namespace", optimizationHandler)
 		api.GET("/multicluster/contexts", multiclusterContextsHandler)
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// knownMethods are the HTTP methods recorded under their own name. Any other method is
+// recorded as "other", so requests with made-up methods cannot create unbounded series.
+var knownMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true,
+	http.MethodPatch: true, http.MethodDelete: true, http.MethodOptions: true,
+}
+
+// dashboardMetrics collects the dashboard server's own metrics and the cluster stats
+// it last observed, in a registry of its own served on /metrics
+type dashboardMetrics struct {
+	registry     *prometheus.Registry
+	requests     *prometheus.CounterVec
+	latencies    *prometheus.HistogramVec
+	clusterNodes prometheus.Gauge
+	clusterPods  prometheus.Gauge
+	// observe registers the cluster gauges on the first observation, so a scrape
+	// before the first overview load does not report an empty cluster
+	observe sync.Once
+}
+
+var metrics = newDashboardMetrics()
+
+func newDashboardMetrics() *dashboardMetrics {
+	m := &dashboardMetrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "k8s_lens_dashboard_http_requests_total",
+			Help: "Total HTTP requests handled by the dashboard.",
+		}, []string{"method", "route", "status"}),
+		latencies: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "k8s_lens_dashboard_http_request_duration_seconds",
+			Help:    "HTTP request latency by route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		clusterNodes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "k8s_lens_dashboard_cluster_nodes",
+			Help: "Nodes in the cluster at the last observation.",
+		}),
+		clusterPods: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "k8s_lens_dashboard_cluster_pods",
+			Help: "Pods in the cluster at the last observation.",
+		}),
+	}
+	m.registry.MustRegister(m.requests, m.latencies)
+	return m
+}
+
+// middleware records the count and latency of each request by route template, so
+// /api/analysis/pod/web and /api/analysis/pod/db are counted under the same route
+func (m *dashboardMetrics) middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		if !knownMethods[method] {
+			method = "other"
+		}
+		m.requests.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		m.latencies.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// setClusterCounts records the node and pod counts from the last cluster info request
+func (m *dashboardMetrics) setClusterCounts(nodes, pods int) {
+	m.observe.Do(func() {
+		m.registry.MustRegister(m.clusterNodes, m.clusterPods)
+	})
+	m.clusterNodes.Set(float64(nodes))
+	m.clusterPods.Set(float64(pods))
+}
+
+func (m *dashboardMetrics) handler(c *gin.Context) {
+	promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}).ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantMethod string
+		wantRoute  string
+		wantStatus string
+	}{
+		{name: "route template", method: http.MethodGet, path: "/api/analysis/pod/web", wantMethod: "GET",
+			wantRoute: "/api/analysis/pod/:name", wantStatus: "200"},
+		{name: "unmatched route", method: http.MethodGet, path: "/missing", wantMethod: "GET",
+			wantRoute: "unmatched", wantStatus: "404"},
+		{name: "unknown method", method: "BREW", path: "/missing", wantMethod: "other",
+			wantRoute: "unmatched", wantStatus: "404"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newDashboardMetrics()
+			router := gin.New()
+			router.Use(m.middleware())
+			router.GET("/api/analysis/pod/:name", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, 1, testutil.CollectAndCount(m.requests))
+			assert.Equal(t, float64(1), testutil.ToFloat64(m.requests.WithLabelValues(tt.wantMethod, tt.wantRoute, tt.wantStatus)))
+		})
+	}
+}
+
+func TestDashboardMetricsClusterGauges(t *testing.T) {
+	m := newDashboardMetrics()
+	router := gin.New()
+	router.GET("/metrics", m.handler)
+
+	scrape := func() string {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		return recorder.Body.String()
+	}
+
+	assert.NotContains(t, scrape(), "k8s_lens_dashboard_cluster_nodes", "no gauges before the cluster is observed")
+
+	m.setClusterCounts(3, 42)
+	m.setClusterCounts(4, 40)
+	body := scrape()
+	assert.True(t, strings.Contains(body, "k8s_lens_dashboard_cluster_nodes 4"), body)
+	assert.True(t, strings.Contains(body, "k8s_lens_dashboard_cluster_pods 40"), body)
+}
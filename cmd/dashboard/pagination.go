@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultPageSize is the number of items listed per API request
+	defaultPageSize = 500
+	// maxPageSize caps ?limit= so a single request cannot load a whole large cluster
+	maxPageSize = 5000
+)
+
+// pageOptions builds list options from the ?limit= and ?continue= query parameters
+func pageOptions(c *gin.Context) (metav1.ListOptions, error) {
+	opts := metav1.ListOptions{
+		Limit:    defaultPageSize,
+		Continue: c.Query("continue"),
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || limit <= 0 {
+			return opts, fmt.Errorf("invalid limit %q: must be a positive number", value)
+		}
+		if limit > maxPageSize {
+			limit = maxPageSize
+		}
+		opts.Limit = limit
+	}
+	return opts, nil
+}
+
+// countItems counts the items of a list call. It uses the remaining item count the API
+// server reports for the first page when available, and otherwise pages through the list.
+func countItems(list func(opts metav1.ListOptions) (int, metav1.ListMeta, error)) (int, error) {
+	opts := metav1.ListOptions{Limit: defaultPageSize}
+	total := 0
+	for {
+		count, meta, err := list(opts)
+		if err != nil {
+			return 0, err
+		}
+		total += count
+		if meta.RemainingItemCount != nil {
+			return total + int(*meta.RemainingItemCount), nil
+		}
+		if meta.Continue == "" {
+			return total, nil
+		}
+		opts.Continue = meta.Continue
+	}
+}
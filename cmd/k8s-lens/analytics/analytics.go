@@ -15,4 +15,5 @@ func init() {
 	AnalyticsCmd.AddCommand(anomalyCmd)
 	AnalyticsCmd.AddCommand(predictCmd)
 	AnalyticsCmd.AddCommand(trendCmd)
+	AnalyticsCmd.AddCommand(diskPressureCmd)
 }
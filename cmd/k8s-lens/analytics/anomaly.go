@@ -3,8 +3,10 @@ package analytics
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations/notify"
 	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
 	"github.com/abrarahmad1510/k8s-lens/pkg/machinelearning"
 	"github.com/spf13/cobra"
@@ -13,11 +15,22 @@ import (
 var anomalyCmd = &cobra.Command{
 	Use:   "anomaly [namespace]",
 	Short: "Detect anomalies in Kubernetes namespace",
-	Long:  "Use machine learning to detect unusual patterns and potential issues in your Kubernetes namespace",
-	Args:  cobra.ExactArgs(1),
+	Long: `Use machine learning to detect unusual patterns and potential issues in your Kubernetes namespace.
+
+By default, or with --once, the namespace is evaluated once and a full report is printed.
+With --watch the detector keeps running as a lightweight monitor: it watches the pods of
+the namespace, re-evaluates them whenever one changes and prints only the anomalies that
+newly appeared, one per line, optionally posting them to a webhook as well:
+
+  k8s-lens analytics anomaly shop --watch --notify-webhook https://hooks.slack.com/...`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		namespace := args[0]
 		verbose, _ := cmd.Flags().GetBool("verbose")
+		if watch, _ := cmd.Flags().GetBool("watch"); watch {
+			watchAnomalies(cmd, namespace)
+			return
+		}
 
 		utils.PrintInfo("Starting anomaly detection for namespace: %s", namespace)
 
@@ -28,7 +41,7 @@ var anomalyCmd = &cobra.Command{
 		}
 
 		detector := machinelearning.NewAnomalyDetector(k8sClient)
-		report, err := detector.DetectNamespaceAnomalies(namespace)
+		report, err := detector.DetectNamespaceAnomalies(cmd.Context(), namespace)
 		if err != nil {
 			utils.PrintError("Error detecting anomalies: %v", err)
 			os.Exit(1)
@@ -64,6 +77,51 @@ var anomalyCmd = &cobra.Command{
 	},
 }
 
+// watchAnomalies streams the anomalies that appear in a namespace until interrupted
+func watchAnomalies(cmd *cobra.Command, namespace string) {
+	k8sClient, err := k8s.NewClient()
+	if err != nil {
+		utils.PrintError("Error creating Kubernetes client: %v", err)
+		os.Exit(1)
+	}
+
+	resync, _ := cmd.Flags().GetDuration("resync")
+	webhookURL, _ := cmd.Flags().GetString("notify-webhook")
+	format, _ := cmd.Flags().GetString("notify-format")
+
+	detector := machinelearning.NewAnomalyDetector(k8sClient)
+
+	utils.PrintInfo("Watching namespace %s for new anomalies (Ctrl+C to stop)", namespace)
+	err = detector.WatchNamespaceAnomalies(cmd.Context(), namespace, resync, func(report *machinelearning.AnomalyReport, anomalies []machinelearning.Anomaly) {
+		for _, anomaly := range anomalies {
+			fmt.Printf("%s [%s] %s %s: %s (confidence %.0f%%)\n", anomaly.Timestamp.Format("2006-01-02 15:04:05"),
+				anomaly.Severity, anomaly.Type, anomaly.Resource, anomaly.Message, anomaly.Confidence*100)
+		}
+		if webhookURL == "" {
+			return
+		}
+
+		summary := notify.NewSummary("namespace", namespace, namespace,
+			fmt.Sprintf("%d new anomalies (score %d/100)", len(anomalies), report.Score))
+		for _, anomaly := range anomalies {
+			summary.AddIssue(anomaly.Severity, fmt.Sprintf("[%s] %s: %s", anomaly.Type, anomaly.Resource, anomaly.Message))
+		}
+		if err := notify.NewWebhookNotifier(webhookURL, format).Notify(summary); err != nil {
+			utils.PrintWarning("Failed to send webhook notification: %v", err)
+		}
+	})
+	if err != nil {
+		utils.PrintError("Error watching for anomalies: %v", err)
+		os.Exit(1)
+	}
+}
+
 func init() {
 	anomalyCmd.Flags().BoolP("verbose", "v", false, "Verbose output")
+	anomalyCmd.Flags().Bool("watch", false, "Keep watching the namespace and print anomalies as they appear")
+	anomalyCmd.Flags().Bool("once", false, "Evaluate the namespace once and print a report (the default)")
+	anomalyCmd.MarkFlagsMutuallyExclusive("watch", "once")
+	anomalyCmd.Flags().Duration("resync", time.Minute, "With --watch, how often pods are re-evaluated when none change")
+	anomalyCmd.Flags().String("notify-webhook", "", "With --watch, also post new anomalies to a Slack-compatible webhook URL")
+	anomalyCmd.Flags().String("notify-format", "slack", "Webhook payload format (slack, json)")
 }
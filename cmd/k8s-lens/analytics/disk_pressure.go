@@ -0,0 +1,91 @@
+package analytics
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/abrarahmad1510/k8s-lens/pkg/machinelearning"
+	"github.com/spf13/cobra"
+)
+
+var diskPressureCmd = &cobra.Command{
+	Use:   "disk-pressure",
+	Short: "Predict pod evictions caused by node disk pressure",
+	Long: `Forecast when each node's root filesystem reaches the kubelet eviction threshold, using
+node-exporter metrics from Prometheus, and list the pods the kubelet would evict first based on
+their ephemeral-storage requests, usage and priority.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		prometheusURL, _ := cmd.Flags().GetString("prometheus-url")
+		metricsBackend, _ := cmd.Flags().GetString("metrics-backend")
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		horizon, _ := cmd.Flags().GetDuration("horizon")
+
+		if threshold <= 0 || threshold >= 100 {
+			utils.PrintError("Threshold must be a percentage between 0 and 100, got %.1f", threshold)
+			os.Exit(1)
+		}
+
+		utils.PrintInfo("Predicting disk pressure evictions for the next %v", horizon)
+
+		k8sClient, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		promClient, err := integrations.NewMetricsBackend(metricsBackend, prometheusURL, integrations.MetricsAuthFromFlags(cmd.Flags()))
+		if err != nil {
+			utils.PrintError("Error creating metrics client: %v", err)
+			os.Exit(1)
+		}
+
+		predictor := machinelearning.NewDiskPressurePredictor(k8sClient, promClient)
+		predictor.SetEvictionThreshold(threshold / 100)
+		predictor.SetHorizon(horizon)
+		report, err := predictor.PredictEvictions(cmd.Context())
+		if err != nil {
+			utils.PrintError("Error predicting disk pressure: %v", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("K8s Lens Disk Pressure Prediction Report\n")
+		fmt.Printf("========================================\n")
+		fmt.Printf("Eviction Threshold: %.0f%% available\n", threshold)
+		fmt.Printf("Time Horizon: %v\n", report.TimeHorizon)
+		fmt.Printf("Generated: %s\n", report.GeneratedAt.Format("2006-01-02 15:04:05"))
+
+		utils.PrintSection("Predictions")
+		if len(report.Predictions) == 0 {
+			utils.PrintSuccess("No node is expected to reach disk pressure in the next %v!", report.TimeHorizon)
+			return
+		}
+
+		for i, prediction := range report.Predictions {
+			fmt.Printf("%d. [%s] %s\n", i+1, prediction.Impact, prediction.Message)
+			fmt.Printf("   Probability: %.0f%%, Expected: %s\n",
+				prediction.Probability*100,
+				prediction.ExpectedTime.Format("Jan 02, 15:04"))
+			if len(prediction.Pods) > 0 {
+				fmt.Printf("   Pods evicted first:\n")
+				for _, pod := range prediction.Pods {
+					fmt.Printf("     - %s\n", pod)
+				}
+			}
+			fmt.Printf("   Recommendation: %s\n", prediction.Recommendation)
+			fmt.Println()
+		}
+	},
+}
+
+func init() {
+	diskPressureCmd.Flags().StringP("prometheus-url", "p", "http://localhost:9090", "Prometheus URL providing node-exporter metrics")
+	diskPressureCmd.Flags().String("metrics-backend", integrations.BackendPrometheus, "Metrics backend serving the Prometheus query API: prometheus, thanos or victoriametrics")
+	integrations.AddMetricsAuthFlags(diskPressureCmd.Flags())
+	diskPressureCmd.Flags().Float64("threshold", 10, "Kubelet nodefs.available eviction threshold, in percent")
+	diskPressureCmd.Flags().Duration("horizon", 24*time.Hour, "How far ahead to predict disk pressure")
+}
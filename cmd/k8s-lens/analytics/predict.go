@@ -28,7 +28,7 @@ var predictCmd = &cobra.Command{
 		}
 
 		predictor := machinelearning.NewPredictiveAnalyzer(k8sClient)
-		report, err := predictor.PredictDeploymentFailures(deploymentName, namespace)
+		report, err := predictor.PredictDeploymentFailures(cmd.Context(), deploymentName, namespace)
 		if err != nil {
 			utils.PrintError("Error generating predictions: %v", err)
 			os.Exit(1)
@@ -3,10 +3,13 @@ package analytics
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/abrarahmad1510/k8s-lens/internal/utils"
 	"github.com/abrarahmad1510/k8s-lens/pkg/analytics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations"
 	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
 	"github.com/spf13/cobra"
 )
@@ -19,9 +22,14 @@ var trendCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		namespace := args[0]
 		periodStr, _ := cmd.Flags().GetString("period")
+		if cmd.Flags().Changed("since") {
+			periodStr, _ = cmd.Flags().GetString("since")
+		}
+		prometheusURL, _ := cmd.Flags().GetString("prometheus-url")
+		metricsBackend, _ := cmd.Flags().GetString("metrics-backend")
 
 		// Parse period
-		period, err := time.ParseDuration(periodStr)
+		period, err := parsePeriod(periodStr)
 		if err != nil {
 			utils.PrintError("Invalid period format: %v", err)
 			os.Exit(1)
@@ -36,7 +44,19 @@ var trendCmd = &cobra.Command{
 		}
 
 		analyzer := analytics.NewTrendAnalyzer(k8sClient)
-		report, err := analyzer.AnalyzeNamespaceTrends(namespace, period)
+		if prometheusURL != "" {
+			promClient, err := integrations.NewMetricsBackend(metricsBackend, prometheusURL, integrations.MetricsAuthFromFlags(cmd.Flags()))
+			if err != nil {
+				utils.PrintError("Error creating metrics client: %v", err)
+				os.Exit(1)
+			}
+			if err := promClient.TestConnection(); err != nil {
+				utils.PrintWarning("Prometheus unavailable, reporting current snapshot only: %v", err)
+			} else {
+				analyzer.SetMetricsHistory(promClient)
+			}
+		}
+		report, err := analyzer.AnalyzeNamespaceTrends(cmd.Context(), namespace, period)
 		if err != nil {
 			utils.PrintError("Error analyzing trends: %v", err)
 			os.Exit(1)
@@ -50,10 +70,18 @@ var trendCmd = &cobra.Command{
 		fmt.Printf("Generated: %s\n", report.GeneratedAt.Format("2006-01-02 15:04:05"))
 
 		utils.PrintSection("Resource Trends")
+		if !report.HasHistory {
+			utils.PrintWarning("Current snapshot only, no history - use --prometheus-url for real trends")
+		}
 		if len(report.ResourceTrends) == 0 {
 			fmt.Println("No resource trend data available")
 		} else {
 			for _, trend := range report.ResourceTrends {
+				if !trend.Historical {
+					fmt.Printf("• %s: %.1f (no history)\n", trend.Metric, trend.CurrentValue)
+					continue
+				}
+
 				trendIndicator := "→"
 				if trend.Trend == "Increasing" {
 					trendIndicator = "↑"
@@ -75,6 +103,9 @@ var trendCmd = &cobra.Command{
 				fmt.Printf("%d. [%s] %s\n", i+1, trend.Impact, trend.Pattern)
 				fmt.Printf("   Component: %s, Metric: %s\n", trend.Component, trend.Metric)
 				fmt.Printf("   Confidence: %.0f%%\n", trend.Confidence*100)
+				if trend.Period != "" {
+					fmt.Printf("   Period: %s, Amplitude: %.0f%%\n", trend.Period, trend.Amplitude*100)
+				}
 			}
 		}
 
@@ -87,4 +118,20 @@ var trendCmd = &cobra.Command{
 
 func init() {
 	trendCmd.Flags().StringP("period", "p", "24h", "Analysis period (e.g., 24h, 7d, 30d)")
+	trendCmd.Flags().String("since", "", "Compare current values with values this long ago (e.g., 24h, 7d); overrides --period")
+	trendCmd.Flags().String("prometheus-url", "", "Prometheus URL providing the metric history")
+	trendCmd.Flags().String("metrics-backend", integrations.BackendPrometheus, "Metrics backend serving the Prometheus query API: prometheus, thanos or victoriametrics")
+	integrations.AddMetricsAuthFlags(trendCmd.Flags())
+}
+
+// parsePeriod parses a duration, additionally accepting a number of days such as "7d"
+func parsePeriod(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid day count: %s", value)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
 }
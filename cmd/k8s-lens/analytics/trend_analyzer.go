@@ -53,7 +53,7 @@ type PerformanceTrend struct {
 }
 
 // AnalyzeNamespaceTrends analyzes trends in a namespace over time
-func (t *TrendAnalyzer) AnalyzeNamespaceTrends(namespace string, period time.Duration) (*TrendReport, error) {
+func (t *TrendAnalyzer) AnalyzeNamespaceTrends(ctx context.Context, namespace string, period time.Duration) (*TrendReport, error) {
 	report := &TrendReport{
 		Namespace:      namespace,
 		AnalysisPeriod: period,
@@ -61,13 +61,13 @@ func (t *TrendAnalyzer) AnalyzeNamespaceTrends(namespace string, period time.Dur
 	}
 
 	// Get current state
-	currentPods, err := t.client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	currentPods, err := t.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current pods: %v", err)
 	}
 
 	// Get deployments for workload analysis
-	deployments, err := t.client.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
+	deployments, err := t.client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get deployments: %v", err)
 	}
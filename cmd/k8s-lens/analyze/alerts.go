@@ -0,0 +1,91 @@
+package analyze
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// printActiveAlerts prints the firing Alertmanager alerts whose labels match any of
+// the label sets of the resource, when --alertmanager-url is set. Alertmanager errors
+// are reported as warnings so they never fail the analysis.
+func printActiveAlerts(cmd *cobra.Command, labelSets ...map[string]string) {
+	alertmanagerURL, _ := cmd.Flags().GetString("alertmanager-url")
+	if alertmanagerURL == "" {
+		return
+	}
+
+	utils.PrintSection("Active Alerts")
+	alerts, err := integrations.NewAlertmanagerClient(alertmanagerURL).GetActiveAlertsMatchingAny(labelSets)
+	if err != nil {
+		utils.PrintWarning("Could not fetch alerts from Alertmanager: %v", err)
+		return
+	}
+
+	if len(alerts) == 0 {
+		utils.PrintSuccess("No alerts are currently firing for this resource")
+		return
+	}
+
+	for _, alert := range alerts {
+		message := fmt.Sprintf("%s is currently firing (severity: %s, since %s)",
+			alert.Name(), alert.Severity(), time.Since(alert.StartsAt).Round(time.Minute))
+		if alert.Severity() == "critical" {
+			utils.PrintError("%s", message)
+		} else {
+			utils.PrintWarning("%s", message)
+		}
+		if summary := alert.Summary(); summary != "" {
+			fmt.Printf("  %s\n", summary)
+		}
+	}
+}
+
+// workloadAlertLabels returns the label sets alerts about a deployment or statefulset
+// carry: the workload's own labels, and the namespace and pod labels of each of its
+// pods, which most kube-prometheus alerts, e.g. KubePodCrashLooping, are raised on.
+// The pods are only listed when --alertmanager-url is set; when they cannot be, only
+// the workload's own alerts are matched.
+func workloadAlertLabels(cmd *cobra.Command, client kubernetes.Interface, kind, namespace, name string) []map[string]string {
+	labelSets := []map[string]string{{"namespace": namespace, kind: name}}
+	if alertmanagerURL, _ := cmd.Flags().GetString("alertmanager-url"); alertmanagerURL == "" {
+		return labelSets
+	}
+
+	var selector *metav1.LabelSelector
+	switch kind {
+	case "deployment":
+		deployment, err := client.AppsV1().Deployments(namespace).Get(cmd.Context(), name, metav1.GetOptions{})
+		if err != nil {
+			return labelSets
+		}
+		selector = deployment.Spec.Selector
+	case "statefulset":
+		statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(cmd.Context(), name, metav1.GetOptions{})
+		if err != nil {
+			return labelSets
+		}
+		selector = statefulSet.Spec.Selector
+	}
+	if selector == nil {
+		return labelSets
+	}
+	podSelector, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil || podSelector.Empty() {
+		return labelSets
+	}
+
+	pods, err := client.CoreV1().Pods(namespace).List(cmd.Context(), metav1.ListOptions{LabelSelector: podSelector.String()})
+	if err != nil {
+		return labelSets
+	}
+	for _, pod := range pods.Items {
+		labelSets = append(labelSets, map[string]string{"namespace": namespace, "pod": pod.Name})
+	}
+	return labelSets
+}
@@ -1,6 +1,7 @@
 package analyze
 
 import (
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -16,8 +17,32 @@ func init() {
 	AnalyzeCmd.AddCommand(podCmd)
 	AnalyzeCmd.AddCommand(deploymentCmd)
 	AnalyzeCmd.AddCommand(statefulsetCmd)
+	AnalyzeCmd.AddCommand(replicasetCmd)
 	AnalyzeCmd.AddCommand(serviceCmd)
 	AnalyzeCmd.AddCommand(networkCmd)
 	AnalyzeCmd.AddCommand(endpointCmd)
 	AnalyzeCmd.AddCommand(securityCmd)
+	AnalyzeCmd.AddCommand(nodeCmd)
+	AnalyzeCmd.AddCommand(diffCmd)
+	AnalyzeCmd.AddCommand(batchCmd)
+	AnalyzeCmd.AddCommand(eventsCmd)
+	AnalyzeCmd.AddCommand(orphansCmd)
+	AnalyzeCmd.AddCommand(pvCmd)
+	AnalyzeCmd.AddCommand(crdCmd)
+	AnalyzeCmd.AddCommand(pathCmd)
+	AnalyzeCmd.AddCommand(namespaceCmd)
+	AnalyzeCmd.AddCommand(findingsCmd)
+	AnalyzeCmd.AddCommand(canaryCmd)
+	AnalyzeCmd.AddCommand(controlPlaneCmd)
+	AnalyzeCmd.AddCommand(dnsCmd)
+	AnalyzeCmd.AddCommand(secretsCmd)
+
+	AnalyzeCmd.PersistentFlags().String("notify-webhook", "", "Post a summary of the findings to a Slack-compatible webhook URL")
+	AnalyzeCmd.PersistentFlags().String("notify-format", "slack", "Webhook payload format (slack, json)")
+	utils.AddFailOnFlag(AnalyzeCmd.PersistentFlags(), "warning")
+	AnalyzeCmd.PersistentFlags().String("checks-config", "", "YAML file registering external check scripts and required labels for the analyzed resource")
+	AnalyzeCmd.PersistentFlags().StringSlice("require-labels", nil, "Labels every analyzed workload must carry, e.g. team,cost-center")
+	AnalyzeCmd.PersistentFlags().StringSlice("require-annotations", nil, "Annotations every analyzed workload must carry")
+	AnalyzeCmd.PersistentFlags().Bool("explain", false, "Explain the probable cause, impact and fix of each issue")
+	AnalyzeCmd.PersistentFlags().String("alertmanager-url", "", "Alertmanager URL to show alerts currently firing for the analyzed resource")
 }
@@ -0,0 +1,506 @@
+package analyze
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// batchResource is a single resource listed in a batch file
+type batchResource struct {
+	Type      string
+	Name      string
+	Namespace string
+	// Labels are the resource's labels, when known, for --include and --exclude
+	Labels map[string]string
+}
+
+// BatchResult is the analysis outcome for one resource in a batch run
+type BatchResult struct {
+	Type            string   `json:"type"`
+	Name            string   `json:"name"`
+	Namespace       string   `json:"namespace,omitempty"`
+	Status          string   `json:"status"`
+	Score           *int     `json:"score,omitempty"`
+	Issues          []string `json:"issues"`
+	Recommendations []string `json:"recommendations"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// recordResults counts the issues of batch results toward the findings tally printed
+// when the command ends
+func recordResults(results []BatchResult) {
+	for _, result := range results {
+		diagnostics.RecordIssues(result.Type, result.Namespace, result.Name, result.Status, result.Issues)
+	}
+}
+
+var batchCmd = &cobra.Command{
+	Use:   "batch",
+	Short: "Analyze a list of resources from a file or stdin",
+	Long: `Analyze a list of resources in one run. Each line has the form
+"type name [namespace]"; blank lines and lines starting with # are ignored.
+Resources are read from the file given with -f, or from stdin when -f is omitted or "-".
+
+Supported types: pod, deployment, statefulset, service, endpoint, node.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		file, _ := cmd.Flags().GetString("file")
+		defaultNamespace, _ := cmd.Flags().GetString("namespace")
+		output, _ := cmd.Flags().GetString("output")
+
+		if output != "text" && output != "json" && output != "compact" && output != "markdown" {
+			utils.PrintError("Unsupported output format: %s (supported: text, json, compact, markdown)", output)
+			os.Exit(1)
+		}
+
+		var input io.Reader = os.Stdin
+		if file != "" && file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				utils.PrintError("Error opening resource file: %v", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			input = f
+		}
+
+		resources, err := parseBatchResources(input, defaultNamespace)
+		if err != nil {
+			utils.PrintError("Error reading resource list: %v", err)
+			os.Exit(1)
+		}
+
+		// A malformed --include or --exclude fails the batch rather than matching nothing
+		filter, err := utils.ResourceFilter(cmd.Flags())
+		if err != nil {
+			utils.PrintError("%v", err)
+			os.Exit(1)
+		}
+
+		k8sClient, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		if filter.HasSelectors() {
+			lookupResourceLabels(cmd.Context(), k8sClient, resources)
+		}
+		resources = filterResources(cmd, resources)
+
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		results := analyzeBatchResources(cmd.Context(), k8sClient, resources, concurrency)
+		recordResults(results)
+
+		severity := diagnostics.SeverityHealthy
+		for _, result := range results {
+			severity = diagnostics.MaxSeverity(severity, diagnostics.SeverityForStatus(result.Status, len(result.Issues)))
+		}
+
+		if output == "json" {
+			data, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				utils.PrintError("Error encoding results: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else if output == "compact" {
+			printCompactResults(results)
+		} else if output == "markdown" {
+			printMarkdownResults("K8s Lens Batch Analysis Report", results)
+		} else {
+			printBatchResults("K8s Lens Batch Analysis Report", results)
+		}
+
+		utils.ExitOnSeverity(cmd.Flags(), severity)
+	},
+}
+
+func parseBatchResources(input io.Reader, defaultNamespace string) ([]batchResource, error) {
+	var resources []batchResource
+	scanner := bufio.NewScanner(input)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("line %d: expected \"type name [namespace]\", got %q", lineNumber, line)
+		}
+
+		resource := batchResource{
+			Type:      strings.ToLower(fields[0]),
+			Name:      fields[1],
+			Namespace: defaultNamespace,
+		}
+		if len(fields) == 3 {
+			resource.Namespace = fields[2]
+		}
+		resources = append(resources, resource)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// batchListTypes maps the resource types and aliases accepted in batch files to the
+// type listResources takes
+var batchListTypes = map[string]string{
+	"pod": "pod", "po": "pod",
+	"deployment": "deployment", "deploy": "deployment",
+	"statefulset": "statefulset", "sts": "statefulset",
+	"service": "service", "svc": "service",
+	"endpoint": "endpoint", "endpoints": "endpoint", "ep": "endpoint",
+	"node": "node", "no": "node",
+}
+
+// lookupResourceLabels fills in the labels of resources listed in a batch file, so label
+// selectors in --include and --exclude can match them. Each type is listed once per
+// namespace rather than getting every resource. Resources whose labels cannot be read
+// are matched without labels.
+func lookupResourceLabels(ctx context.Context, client kubernetes.Interface, resources []batchResource) {
+	type listKey struct{ resourceType, namespace string }
+	listed := make(map[listKey]map[string]map[string]string)
+	for i := range resources {
+		resource := &resources[i]
+		resourceType, ok := batchListTypes[resource.Type]
+		if !ok {
+			continue
+		}
+		key := listKey{resourceType: resourceType, namespace: resource.Namespace}
+		if resourceType == "node" {
+			key.namespace = ""
+		}
+
+		labelsByName, ok := listed[key]
+		if !ok {
+			labelsByName = make(map[string]map[string]string)
+			if found, err := listResources(ctx, client, key.resourceType, key.namespace, metav1.ListOptions{}); err == nil {
+				for _, object := range found {
+					labelsByName[object.Name] = object.Labels
+				}
+			}
+			listed[key] = labelsByName
+		}
+		resource.Labels = labelsByName[resource.Name]
+	}
+}
+
+// analyzeBatchResources analyzes resources with up to concurrency workers at a time.
+// Results are returned in the order of resources, however the workers finish.
+func analyzeBatchResources(ctx context.Context, client kubernetes.Interface, resources []batchResource, concurrency int) []BatchResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	events := resourceEvents(ctx, client, resources)
+
+	results := make([]BatchResult, len(resources))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(resources); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = analyzeBatchResource(ctx, client, resources[index], events)
+			}
+		}()
+	}
+	for i := range resources {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// resourceEvents lists the events of each namespace the resources are in, one request
+// per namespace, so they are not listed per resource. It returns nil, and events are
+// listed per resource, for a single resource or when the events cannot be listed.
+func resourceEvents(ctx context.Context, client kubernetes.Interface, resources []batchResource) *diagnostics.EventCache {
+	if len(resources) < 2 {
+		return nil
+	}
+	var namespaces []string
+	seen := make(map[string]bool)
+	for _, resource := range resources {
+		// Cluster-scoped resources such as nodes do not use the cache
+		if resource.Namespace == "" || seen[resource.Namespace] {
+			continue
+		}
+		seen[resource.Namespace] = true
+		namespaces = append(namespaces, resource.Namespace)
+	}
+	if len(namespaces) == 0 {
+		return nil
+	}
+	events, err := diagnostics.NewNamespacesEventCache(ctx, client, namespaces)
+	if err != nil {
+		return nil
+	}
+	return events
+}
+
+// analyzeBatchResource analyzes one resource, looking its events up in events when
+// it is not nil
+func analyzeBatchResource(ctx context.Context, client kubernetes.Interface, resource batchResource, events *diagnostics.EventCache) BatchResult {
+	result := BatchResult{
+		Type:      resource.Type,
+		Name:      resource.Name,
+		Namespace: resource.Namespace,
+	}
+
+	var err error
+	switch resource.Type {
+	case "pod", "po":
+		var report *diagnostics.PodReport
+		analyzer := diagnostics.NewPodAnalyzer(client, resource.Namespace)
+		analyzer.SetEventCache(events)
+		if report, err = analyzer.Analyze(ctx, resource.Name); err == nil {
+			result.Status = "Healthy"
+			if len(report.Issues) > 0 {
+				result.Status = "Needs Attention"
+			}
+			result.Score = &report.HealthScore
+			result.Issues, result.Recommendations = report.Issues, report.Recommendations
+		}
+	case "deployment", "deploy":
+		var report *diagnostics.DeploymentReport
+		analyzer := diagnostics.NewDeploymentAnalyzer(client, resource.Namespace)
+		analyzer.SetEventCache(events)
+		if report, err = analyzer.Analyze(ctx, resource.Name); err == nil {
+			result.Status = report.Analysis.Status
+			result.Issues, result.Recommendations = report.Analysis.Issues, report.Analysis.Recommendations
+		}
+	case "statefulset", "sts":
+		var report *diagnostics.StatefulSetReport
+		analyzer := diagnostics.NewStatefulSetAnalyzer(client, resource.Namespace)
+		analyzer.SetEventCache(events)
+		if report, err = analyzer.Analyze(ctx, resource.Name); err == nil {
+			result.Status = report.Analysis.Status
+			result.Issues, result.Recommendations = report.Analysis.Issues, report.Analysis.Recommendations
+		}
+	case "service", "svc":
+		var report *diagnostics.ServiceReport
+		analyzer := diagnostics.NewServiceAnalyzer(client, resource.Namespace)
+		analyzer.SetEventCache(events)
+		if report, err = analyzer.Analyze(ctx, resource.Name); err == nil {
+			result.Status = report.Analysis.Status
+			result.Issues, result.Recommendations = report.Analysis.Issues, report.Analysis.Recommendations
+		}
+	case "endpoint", "endpoints", "ep":
+		var report *diagnostics.EndpointReport
+		analyzer := diagnostics.NewEndpointAnalyzer(client, resource.Namespace)
+		if report, err = analyzer.ValidateEndpoints(ctx, resource.Name); err == nil {
+			result.Status = report.Analysis.Status
+			result.Issues, result.Recommendations = report.Analysis.Issues, report.Analysis.Recommendations
+		}
+	case "node", "no":
+		var report *diagnostics.NodeReport
+		analyzer := diagnostics.NewNodeAnalyzer(client)
+		if report, err = analyzer.Analyze(ctx, resource.Name); err == nil {
+			result.Namespace = ""
+			result.Status = report.Analysis.Status
+			result.Issues, result.Recommendations = report.Analysis.Issues, report.Analysis.Recommendations
+		}
+	default:
+		err = fmt.Errorf("unsupported resource type: %s", resource.Type)
+	}
+
+	if err != nil {
+		result.Status = "Error"
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// printBatchResults prints the issues of each result. The recommendations of a single
+// result are printed with it; those of several are deduplicated into one section with
+// the number of resources each applies to.
+func printBatchResults(title string, results []BatchResult) {
+	fmt.Println(title)
+	fmt.Println("---")
+
+	summary := diagnostics.NewRecommendationSummary()
+	healthy, unhealthy, failed := 0, 0, 0
+	for _, result := range results {
+		resource := fmt.Sprintf("%s/%s", result.Type, result.Name)
+		if result.Namespace != "" {
+			resource = fmt.Sprintf("%s/%s/%s", result.Namespace, result.Type, result.Name)
+		}
+
+		utils.PrintSection(resource)
+		switch {
+		case result.Error != "":
+			failed++
+			utils.PrintError("Analysis failed: %s", result.Error)
+			continue
+		case len(result.Issues) == 0:
+			healthy++
+			utils.PrintSuccess("Status: %s", result.Status)
+		default:
+			unhealthy++
+			utils.PrintWarning("Status: %s", result.Status)
+		}
+
+		for _, issue := range result.Issues {
+			utils.PrintWarning("- %s", issue)
+		}
+		if len(results) > 1 {
+			summary.Add(resource, result.Recommendations...)
+			continue
+		}
+		for _, rec := range result.Recommendations {
+			utils.PrintInfo("- %s", rec)
+		}
+	}
+
+	if groups := summary.Groups(); len(groups) > 0 {
+		utils.PrintSection("Recommendations")
+		for _, group := range groups {
+			utils.PrintInfo("- %s", recommendationGroupText(group))
+		}
+	}
+
+	utils.PrintSection("Batch Summary")
+	fmt.Printf("Resources Analyzed: %d\n", len(results))
+	fmt.Printf("Healthy: %d\n", healthy)
+	fmt.Printf("With Issues: %d\n", unhealthy)
+	fmt.Printf("Failed: %d\n", failed)
+}
+
+// printMarkdownResults prints the results as a markdown document: a section per
+// resource with its issues and recommendations as bullet lists, and a summary table.
+// The recommendations of several results are deduplicated into one section.
+func printMarkdownResults(title string, results []BatchResult) {
+	utils.MarkdownHeading(1, "%s", title)
+
+	recommendations := diagnostics.NewRecommendationSummary()
+	var summary [][]string
+	healthy, unhealthy, failed := 0, 0, 0
+	for _, result := range results {
+		resource := fmt.Sprintf("%s/%s", result.Type, result.Name)
+		if result.Namespace != "" {
+			resource = fmt.Sprintf("%s/%s/%s", result.Namespace, result.Type, result.Name)
+		}
+
+		utils.MarkdownHeading(2, "%s", resource)
+		switch {
+		case result.Error != "":
+			failed++
+			fmt.Printf("**Analysis failed:** %s\n\n", result.Error)
+			summary = append(summary, []string{resource, "Failed", "-"})
+			continue
+		case len(result.Issues) == 0:
+			healthy++
+		default:
+			unhealthy++
+		}
+		fmt.Printf("**Status:** %s\n\n", result.Status)
+		summary = append(summary, []string{resource, result.Status, fmt.Sprintf("%d", len(result.Issues))})
+
+		if len(result.Issues) > 0 {
+			utils.MarkdownHeading(3, "Issues")
+			utils.MarkdownList(result.Issues)
+		}
+		if len(results) > 1 {
+			recommendations.Add(resource, result.Recommendations...)
+		} else if len(result.Recommendations) > 0 {
+			utils.MarkdownHeading(3, "Recommendations")
+			utils.MarkdownList(result.Recommendations)
+		}
+	}
+
+	printRecommendationGroupsMarkdown(recommendations.Groups())
+
+	utils.MarkdownHeading(2, "Summary")
+	utils.MarkdownTable([]string{"Resource", "Status", "Issues"}, summary)
+	fmt.Printf("%d analyzed: %d healthy, %d with issues, %d failed\n", len(results), healthy, unhealthy, failed)
+}
+
+// recommendationGroupText describes a deduplicated recommendation with the number of
+// resources it applies to, when more than one
+func recommendationGroupText(group diagnostics.RecommendationGroup) string {
+	if len(group.Resources) < 2 {
+		return group.Recommendation
+	}
+	return fmt.Sprintf("%s — %d resources affected", group.Recommendation, len(group.Resources))
+}
+
+func printRecommendationGroupsMarkdown(groups []diagnostics.RecommendationGroup) {
+	if len(groups) == 0 {
+		return
+	}
+	utils.MarkdownHeading(2, "Recommendations")
+	items := make([]string, len(groups))
+	for i, group := range groups {
+		items[i] = recommendationGroupText(group)
+	}
+	utils.MarkdownList(items)
+}
+
+// printCompactResults prints one line per result, followed by a one-line summary
+func printCompactResults(results []BatchResult) {
+	healthy, unhealthy, failed := 0, 0, 0
+	for _, result := range results {
+		printCompactResult(result)
+		switch {
+		case result.Error != "":
+			failed++
+		case len(result.Issues) == 0:
+			healthy++
+		default:
+			unhealthy++
+		}
+	}
+	if len(results) > 1 {
+		fmt.Printf("\n%d analyzed: %d healthy, %d with issues, %d failed\n", len(results), healthy, unhealthy, failed)
+	}
+}
+
+func printCompactResult(result BatchResult) {
+	resource := fmt.Sprintf("%s/%s", result.Type, result.Name)
+	if result.Namespace != "" {
+		resource = fmt.Sprintf("%s/%s/%s", result.Namespace, result.Type, result.Name)
+	}
+
+	score := -1
+	if result.Score != nil {
+		score = *result.Score
+	}
+
+	detail := result.Error
+	if detail == "" && len(result.Issues) > 0 {
+		detail = result.Issues[0]
+		if len(result.Issues) > 1 {
+			detail += fmt.Sprintf(" (+%d more)", len(result.Issues)-1)
+		}
+	}
+
+	utils.PrintCompact(diagnostics.SeverityForStatus(result.Status, len(result.Issues)), resource, result.Status, score, detail)
+}
+
+func init() {
+	batchCmd.Flags().StringP("file", "f", "", "File listing the resources to analyze (default: stdin)")
+	batchCmd.Flags().StringP("namespace", "n", "default", "Namespace for lines that don't specify one")
+	batchCmd.Flags().StringP("output", "o", "text", "Output format (text, json, compact, markdown)")
+	batchCmd.Flags().Int("concurrency", defaultConcurrency, "Number of resources analyzed in parallel")
+	utils.AddFilterFlags(batchCmd.Flags())
+}
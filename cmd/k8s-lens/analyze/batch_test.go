@@ -0,0 +1,56 @@
+package analyze
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBatchResources(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []batchResource
+		wantErr bool
+	}{
+		{
+			name:  "default namespace",
+			input: "pod web-1",
+			want:  []batchResource{{Type: "pod", Name: "web-1", Namespace: "default"}},
+		},
+		{
+			name:  "explicit namespace",
+			input: "deployment api shop",
+			want:  []batchResource{{Type: "deployment", Name: "api", Namespace: "shop"}},
+		},
+		{
+			name:  "type is lowercased",
+			input: "Service frontend",
+			want:  []batchResource{{Type: "service", Name: "frontend", Namespace: "default"}},
+		},
+		{
+			name:  "comments and blank lines are skipped",
+			input: "# resources\n\n  pod web-1  \n# done\nnode worker-1\n",
+			want: []batchResource{
+				{Type: "pod", Name: "web-1", Namespace: "default"},
+				{Type: "node", Name: "worker-1", Namespace: "default"},
+			},
+		},
+		{name: "empty input", input: ""},
+		{name: "missing name", input: "pod web-1\npod", wantErr: true},
+		{name: "too many fields", input: "pod web-1 shop extra", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resources, err := parseBatchResources(strings.NewReader(tt.input), "default")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resources)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package analyze
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+var canaryCmd = &cobra.Command{
+	Use:   "canary <stable-deployment> <canary-deployment>",
+	Short: "Compare the metrics of a canary deployment with the stable deployment",
+	Long: `Compare the error rate, CPU, memory and restarts of the pods of a canary deployment
+with those of the stable deployment over a window of Prometheus metrics, and report
+whether the canary is statistically worse.
+
+Each metric is sampled over the window for both deployments and compared with a
+one-sided Mann-Whitney U test. The canary fails when a metric is significantly
+higher (p < 0.05) by more than 10%, or when its deployment is unhealthy, and the
+result is inconclusive when no metric has enough samples. The command exits non-zero
+on a failed canary, so it can gate a progressive rollout:
+
+  k8s-lens analyze canary web web-canary -n shop --prometheus-url http://prometheus:9090 --window 30m
+
+The error rate defaults to the share of http_requests_total with a 5xx code; use
+--error-rate-query for other metrics, with $namespace and $pods placeholders.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, _ := cmd.Flags().GetString("namespace")
+		prometheusURL, _ := cmd.Flags().GetString("prometheus-url")
+		metricsBackend, _ := cmd.Flags().GetString("metrics-backend")
+		window, _ := cmd.Flags().GetDuration("window")
+		errorRateQuery, _ := cmd.Flags().GetString("error-rate-query")
+		output, _ := cmd.Flags().GetString("output")
+
+		if output != "text" && output != "json" {
+			utils.PrintError("Unsupported output format: %s (supported: text, json)", output)
+			os.Exit(1)
+		}
+		if prometheusURL == "" {
+			utils.PrintError("--prometheus-url is required to compare canary metrics")
+			os.Exit(1)
+		}
+		if window <= 0 {
+			utils.PrintError("--window must be positive")
+			os.Exit(1)
+		}
+
+		if output == "text" {
+			utils.PrintInfo("Comparing canary %s with %s in namespace %s over the last %s", args[1], args[0], namespace, window)
+		}
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+		promClient, err := integrations.NewMetricsBackend(metricsBackend, prometheusURL, integrations.MetricsAuthFromFlags(cmd.Flags()))
+		if err != nil {
+			utils.PrintError("Error creating metrics client: %v", err)
+			os.Exit(1)
+		}
+
+		analyzer := integrations.NewCanaryAnalyzer(client, promClient, namespace)
+		if errorRateQuery != "" {
+			analyzer.SetErrorRateQuery(errorRateQuery)
+		}
+		report, err := analyzer.Compare(cmd.Context(), args[0], args[1], window)
+		if err != nil {
+			utils.PrintError("Error comparing canary: %v", err)
+			os.Exit(1)
+		}
+
+		if output == "json" {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				utils.PrintError("Error encoding report: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			printCanaryReport(report)
+		}
+
+		utils.ExitOnSeverity(cmd.Flags(), report.Severity())
+	},
+}
+
+func init() {
+	canaryCmd.Flags().StringP("namespace", "n", "default", "Namespace of both deployments")
+	canaryCmd.Flags().StringP("prometheus-url", "p", "", "Prometheus URL providing the metrics of both deployments")
+	canaryCmd.Flags().String("metrics-backend", integrations.BackendPrometheus, "Metrics backend serving the Prometheus query API: prometheus, thanos or victoriametrics")
+	integrations.AddMetricsAuthFlags(canaryCmd.Flags())
+	canaryCmd.Flags().Duration("window", 30*time.Minute, "How far back to compare the metrics")
+	canaryCmd.Flags().String("error-rate-query", "", "PromQL query for the error rate, with $namespace and $pods placeholders (default: 5xx share of http_requests_total)")
+	canaryCmd.Flags().StringP("output", "o", "text", "Output format: text or json")
+}
+
+func printCanaryReport(report *integrations.CanaryReport) {
+	fmt.Printf("K8s Lens Canary Analysis: %s vs %s\n", report.Canary, report.Stable)
+	fmt.Println("---")
+	fmt.Printf("Namespace: %s\n", report.Namespace)
+	fmt.Printf("Window: %s\n", report.Window)
+	fmt.Printf("Stable Status: %s\n", report.StableStatus)
+	fmt.Printf("Canary Status: %s\n", report.CanaryStatus)
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METRIC\tSTABLE\tCANARY\tCHANGE\tP-VALUE\tRESULT")
+	for _, metric := range report.Metrics {
+		if !metric.Available {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t-\tNo data (%d/%d samples)\n", metric.Name, metric.StableSamples, metric.CanarySamples)
+			continue
+		}
+		result := "OK"
+		if metric.Worse {
+			result = "WORSE"
+		}
+		change := fmt.Sprintf("%+.1f%%", metric.ChangePercent)
+		if metric.FromZero {
+			change = "new"
+		}
+		fmt.Fprintf(w, "%s\t%.3f %s\t%.3f %s\t%s\t%.3f\t%s\n", metric.Name, metric.StableMean, metric.Unit,
+			metric.CanaryMean, metric.Unit, change, metric.PValue, result)
+	}
+	w.Flush()
+
+	if len(report.CanaryIssues) > 0 {
+		utils.PrintSection("Canary Issues")
+		for _, issue := range report.CanaryIssues {
+			fmt.Printf("  - %s\n", issue)
+		}
+	}
+
+	fmt.Println()
+	switch report.Verdict {
+	case integrations.CanaryPass:
+		utils.PrintSuccess("Verdict: %s", report.Verdict)
+	case integrations.CanaryFail:
+		utils.PrintError("Verdict: %s", report.Verdict)
+	default:
+		utils.PrintWarning("Verdict: %s", report.Verdict)
+	}
+	for _, reason := range report.Reasons {
+		fmt.Printf("  - %s\n", reason)
+	}
+}
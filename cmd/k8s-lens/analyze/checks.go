@@ -0,0 +1,104 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+// workloadTypes are the resource types checked for required labels and annotations
+var workloadTypes = map[string]bool{"pod": true, "deployment": true, "statefulset": true}
+
+// runCustomChecks checks the analyzed resource against the required labels and
+// annotations from --require-labels, --require-annotations and the --checks-config
+// file, then runs the check scripts registered in that file. Without any of them it
+// returns an empty, healthy result.
+func runCustomChecks(cmd *cobra.Command, client kubernetes.Interface, resourceType, namespace, name string) *diagnostics.CustomCheckResult {
+	result := &diagnostics.CustomCheckResult{Severity: diagnostics.SeverityHealthy}
+
+	config := &diagnostics.CustomChecksConfig{}
+	if path, _ := cmd.Flags().GetString("checks-config"); path != "" {
+		loaded, err := diagnostics.LoadCustomChecks(path)
+		if err != nil {
+			utils.PrintError("Error loading custom checks: %v", err)
+			os.Exit(1)
+		}
+		config = loaded
+	}
+	requiredLabels, _ := cmd.Flags().GetStringSlice("require-labels")
+	requiredAnnotations, _ := cmd.Flags().GetStringSlice("require-annotations")
+	requiredLabels = append(requiredLabels, config.RequiredLabels...)
+	requiredAnnotations = append(requiredAnnotations, config.RequiredAnnotations...)
+
+	checkMetadata := workloadTypes[resourceType] && (len(requiredLabels) > 0 || len(requiredAnnotations) > 0)
+	if !checkMetadata && len(config.Checks) == 0 {
+		return result
+	}
+
+	resource, err := fetchCheckResource(cmd.Context(), client, resourceType, namespace, name)
+	if err != nil {
+		utils.PrintWarning("Skipping custom checks: %v", err)
+		return result
+	}
+
+	if len(config.Checks) > 0 {
+		result = diagnostics.RunCustomChecks(cmd.Context(), config.Checks, resourceType, resource)
+		for _, checkErr := range result.Errors {
+			utils.PrintWarning("Custom check failed: %s", checkErr)
+		}
+	}
+
+	if object, ok := resource.(metav1.Object); ok && checkMetadata {
+		issues, recommendations := diagnostics.CheckRequiredMetadata(resourceType, object, requiredLabels, requiredAnnotations)
+		if len(issues) > 0 {
+			result.Issues = append(issues, result.Issues...)
+			result.Recommendations = append(recommendations, result.Recommendations...)
+			result.Severity = diagnostics.MaxSeverity(result.Severity, diagnostics.SeverityWarning)
+		}
+	}
+	return result
+}
+
+// fetchCheckResource gets the resource passed to custom checks, with its kind and API
+// version set as they would be in a manifest
+func fetchCheckResource(ctx context.Context, client kubernetes.Interface, resourceType, namespace, name string) (runtime.Object, error) {
+	var (
+		object     runtime.Object
+		apiVersion string
+		kind       string
+		err        error
+	)
+	switch resourceType {
+	case "pod":
+		object, err = client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		apiVersion, kind = "v1", "Pod"
+	case "deployment":
+		object, err = client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		apiVersion, kind = "apps/v1", "Deployment"
+	case "statefulset":
+		object, err = client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		apiVersion, kind = "apps/v1", "StatefulSet"
+	case "service":
+		object, err = client.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+		apiVersion, kind = "v1", "Service"
+	case "node":
+		object, err = client.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+		apiVersion, kind = "v1", "Node"
+	default:
+		return nil, fmt.Errorf("custom checks are not supported for %s", resourceType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %v", resourceType, name, err)
+	}
+
+	object.GetObjectKind().SetGroupVersionKind(schema.FromAPIVersionAndKind(apiVersion, kind))
+	return object, nil
+}
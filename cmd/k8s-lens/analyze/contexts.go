@@ -0,0 +1,53 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/multicluster"
+	"github.com/spf13/cobra"
+)
+
+// analyzePodAllContexts analyzes the named pod in every kubeconfig context and reports
+// where it exists and how it differs, to find the cluster where a workload misbehaves
+func analyzePodAllContexts(cmd *cobra.Command, namespace, name string) {
+	utils.PrintInfo("Analyzing pod %s/%s in all contexts", namespace, name)
+
+	manager := multicluster.NewClusterManager()
+	if err := manager.LoadContexts(); err != nil {
+		utils.PrintError("Error loading cluster contexts: %v", err)
+		os.Exit(1)
+	}
+
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	spinner := utils.NewSpinner(quiet)
+	manager.SetProgress(func(current, total int, contextName string) {
+		spinner.Update(fmt.Sprintf("Analyzing cluster %d/%d: %s", current, total, contextName))
+	})
+
+	spinner.Start("Preparing multi-cluster pod analysis")
+	report, err := manager.AnalyzePodAcrossContexts(cmd.Context(), name, namespace)
+	spinner.Stop()
+	if err != nil {
+		utils.PrintError("Error analyzing pod across contexts: %v", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(report.GeneratePodReport())
+
+	if len(report.Results) == 0 {
+		utils.PrintError("Pod %s/%s was not found in any context", namespace, name)
+		os.Exit(1)
+	}
+
+	severity := diagnostics.SeverityHealthy
+	for _, result := range report.Results {
+		severity = diagnostics.MaxSeverity(severity, diagnostics.SeverityForStatus("", len(result.Report.Issues)))
+		if diagnostics.HealthRating(result.Report.HealthScore) == diagnostics.SeverityCritical {
+			severity = diagnostics.SeverityCritical
+		}
+	}
+	utils.ExitOnSeverity(cmd.Flags(), severity)
+}
@@ -0,0 +1,126 @@
+package analyze
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+var controlPlaneCmd = &cobra.Command{
+	Use:   "control-plane",
+	Short: "Check the health of the control plane components",
+	Long: `Check the health of the core cluster components reachable from the API: the API
+server's /readyz checks, the componentstatuses API where the cluster still serves it,
+and the readiness of the kube-apiserver, kube-scheduler, kube-controller-manager, etcd,
+CoreDNS and metrics-server pods in kube-system.
+
+On managed clusters the provider runs the control plane outside the cluster, so its
+pods are reported as not visible rather than missing, and the verdict rests on the
+API server checks and the in-cluster components.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			utils.PrintError("Unsupported output format: %s (supported: text, json)", output)
+			os.Exit(1)
+		}
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		analyzer := diagnostics.NewControlPlaneAnalyzer(client)
+		report, err := analyzer.Analyze(cmd.Context())
+		if err != nil {
+			utils.PrintError("Error analyzing the control plane: %v", err)
+			os.Exit(1)
+		}
+
+		if output == "json" {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				utils.PrintError("Error encoding report: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			printControlPlaneReport(report)
+		}
+
+		notifyIssues(cmd, "cluster", "control-plane", "", report.Analysis.Status, report.Analysis.Issues)
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)))
+	},
+}
+
+func init() {
+	controlPlaneCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+}
+
+func printControlPlaneReport(report *diagnostics.ControlPlaneReport) {
+	fmt.Println("K8s Lens Control Plane Health Report")
+	fmt.Println("---")
+
+	switch {
+	case report.APIServerReady == nil:
+		fmt.Println("API Server Ready: unknown (/readyz not readable)")
+	case *report.APIServerReady:
+		fmt.Println("API Server Ready: yes")
+	default:
+		fmt.Println("API Server Ready: no")
+	}
+	if report.Managed {
+		fmt.Println("Control Plane: managed (not visible as pods)")
+	}
+
+	utils.PrintSection("Components")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPONENT\tSTATE\tREADY")
+	for _, component := range report.Components {
+		ready := "-"
+		if component.TotalPods > 0 {
+			ready = fmt.Sprintf("%d/%d", component.ReadyPods, component.TotalPods)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", component.Name, component.State, ready)
+	}
+	w.Flush()
+
+	if len(report.ComponentStatuses) > 0 {
+		utils.PrintSection("Component Statuses")
+		for _, status := range report.ComponentStatuses {
+			if status.Healthy {
+				utils.PrintSuccess("%s: Healthy", status.Name)
+			} else {
+				utils.PrintWarning("%s: Unhealthy %s", status.Name, status.Message)
+			}
+		}
+	}
+
+	if len(report.Analysis.Issues) > 0 {
+		utils.PrintSection("Issues")
+		for _, issue := range report.Analysis.Issues {
+			utils.PrintWarning("- %s", issue)
+		}
+	}
+
+	if len(report.Analysis.Recommendations) > 0 {
+		utils.PrintSection("Recommendations")
+		for _, rec := range report.Analysis.Recommendations {
+			utils.PrintInfo("- %s", rec)
+		}
+	}
+
+	utils.PrintSection("Verdict")
+	if report.Analysis.Status == "Healthy" {
+		utils.PrintSuccess("Control plane: %s", report.Analysis.Status)
+	} else {
+		utils.PrintWarning("Control plane: %s", report.Analysis.Status)
+	}
+}
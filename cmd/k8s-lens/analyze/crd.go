@@ -0,0 +1,112 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/dynamic"
+)
+
+var crdCmd = &cobra.Command{
+	Use:   "crd [resource] [name]",
+	Short: "Analyze an instance of any resource, including custom resources",
+	Long: `Analyze an instance of any resource through the dynamic client, e.g. an Argo
+Rollout, a Flux Kustomization or a cert-manager Certificate:
+
+  k8s-lens analyze crd rollouts.argoproj.io my-rollout
+  k8s-lens analyze crd certificates.cert-manager.io my-cert -n ingress
+
+The resource is given as kubectl accepts it. The analysis reads the status conditions,
+phase, observed generation and replica counts most controllers report, and the
+resource's events; it is not as deep as the analyzers for built-in types.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, _ := cmd.Flags().GetString("namespace")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		utils.PrintInfo("Starting analysis for %s: %s", args[0], args[1])
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+		dynamicClient, err := dynamic.NewForConfig(client.Config)
+		if err != nil {
+			utils.PrintError("Error creating dynamic Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		analyzer := diagnostics.NewCustomResourceAnalyzer(client, dynamicClient, namespace)
+		report, err := analyzer.Analyze(cmd.Context(), args[0], args[1])
+		if err != nil {
+			utils.PrintError("Error analyzing %s: %v", args[0], err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("K8s Lens Analysis Report For %s: %s\n", report.Kind, report.Name)
+		fmt.Println("---")
+		fmt.Printf("API Version: %s\n", report.APIVersion)
+		if report.Namespace != "" {
+			fmt.Printf("Namespace: %s\n", report.Namespace)
+		}
+		if report.Phase != "" {
+			fmt.Printf("Phase: %s\n", report.Phase)
+		}
+		if report.ObservedGeneration >= 0 {
+			fmt.Printf("Generation: %d (observed: %d)\n", report.Generation, report.ObservedGeneration)
+		}
+		for _, field := range []string{"spec.replicas", "status.replicas", "status.readyReplicas", "status.availableReplicas", "status.updatedReplicas"} {
+			if value, ok := report.Replicas[field]; ok {
+				fmt.Printf("%s: %d\n", field, value)
+			}
+		}
+		fmt.Printf("Status: %s\n", report.Analysis.Status)
+
+		if len(report.Conditions) > 0 {
+			fmt.Println("Conditions:")
+			for _, condition := range report.Conditions {
+				detail := strings.TrimSpace(strings.Join([]string{condition.Reason, condition.Message}, " "))
+				if detail != "" {
+					fmt.Printf("  - %s: %s (%s)\n", condition.Type, condition.Status, detail)
+				} else {
+					fmt.Printf("  - %s: %s\n", condition.Type, condition.Status)
+				}
+			}
+		}
+
+		if len(report.Analysis.Issues) > 0 {
+			fmt.Println("Issues:")
+			for _, issue := range report.Analysis.Issues {
+				fmt.Printf("  - %s\n", issue)
+			}
+		}
+
+		if len(report.Analysis.Recommendations) > 0 {
+			fmt.Println("Recommendations:")
+			for _, rec := range report.Analysis.Recommendations {
+				fmt.Printf("  - %s\n", rec)
+			}
+		}
+
+		if verbose && len(report.Events) > 0 {
+			fmt.Println("Recent Events:")
+			for _, event := range report.Events {
+				fmt.Printf("  - [%s] %s %s: %s\n", event.LastTimestamp.Format("15:04:05"), event.Type, event.Reason, event.Message)
+			}
+		}
+
+		notifyIssues(cmd, strings.ToLower(report.Kind), report.Name, report.Namespace, report.Analysis.Status, report.Analysis.Issues)
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)))
+	},
+}
+
+func init() {
+	crdCmd.Flags().StringP("namespace", "n", "default", "Namespace of the resource; ignored for cluster-scoped resources")
+	crdCmd.Flags().BoolP("verbose", "v", false, "Verbose output")
+}
@@ -3,19 +3,38 @@ package analyze
 import (
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
 	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
 	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
 	"github.com/spf13/cobra"
 )
 
 var deploymentCmd = &cobra.Command{
-	Use:   "deployment [name]",
+	Use:   "deployment [name | -l selector]",
 	Short: "Analyze a Kubernetes Deployment",
 	Long:  `Analyze a Kubernetes Deployment and provide diagnostic information.`,
-	Args:  cobra.ExactArgs(1),
+	Args:  outputArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		if file, _ := cmd.Flags().GetString("filename"); file != "" {
+			analyzeManifestFile(cmd, "Deployment", args)
+			return
+		}
+		if utils.MultipleNamespaces(cmd.Flags()) {
+			analyzeNamespaces(cmd, "deployment", args)
+			return
+		}
 		namespace, _ := cmd.Flags().GetString("namespace")
+		if selector, _ := cmd.Flags().GetString("selector"); selector != "" {
+			analyzeSelector(cmd, "deployment", namespace, selector)
+			return
+		}
+		if output, _ := cmd.Flags().GetString("output"); output == "compact" || output == "markdown" {
+			analyzeCompact(cmd, "deployment", namespace, args[0])
+			return
+		}
 		verbose, _ := cmd.Flags().GetBool("verbose")
 
 		client, err := k8s.NewClient()
@@ -25,12 +44,21 @@ var deploymentCmd = &cobra.Command{
 		}
 
 		analyzer := diagnostics.NewDeploymentAnalyzer(client, namespace)
-		report, err := analyzer.Analyze(args[0])
+		report, err := analyzer.Analyze(cmd.Context(), args[0])
 		if err != nil {
 			fmt.Printf("Error analyzing deployment: %v\n", err)
 			os.Exit(1)
 		}
 
+		if history, _ := cmd.Flags().GetBool("history"); history {
+			printRolloutHistory(report)
+			return
+		}
+
+		checks := runCustomChecks(cmd, client, "deployment", namespace, args[0])
+		report.Analysis.Issues = append(report.Analysis.Issues, checks.Issues...)
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations, checks.Recommendations...)
+
 		// Print the report
 		fmt.Printf("K8s Lens Analysis Report For Deployment: %s\n", report.Name)
 		fmt.Println("---")
@@ -42,6 +70,37 @@ var deploymentCmd = &cobra.Command{
 		fmt.Printf("Updated Replicas: %d\n", report.UpdatedReplicas)
 		fmt.Printf("Status: %s\n", report.Analysis.Status)
 		fmt.Printf("Rollout Status: %s\n", report.Analysis.RolloutStatus)
+		if report.Strategy != nil {
+			fmt.Printf("Strategy: %s\n", report.Strategy)
+		}
+		if report.Autoscaler != nil {
+			fmt.Printf("Autoscaler: %s (%d-%d replicas", report.Autoscaler.Name,
+				report.Autoscaler.MinReplicas, report.Autoscaler.MaxReplicas)
+			if len(report.Autoscaler.Metrics) > 0 {
+				fmt.Printf(", on %s", strings.Join(report.Autoscaler.Metrics, ", "))
+			}
+			fmt.Println(")")
+		}
+		if report.GitOps != nil {
+			fmt.Printf("Managed By: %s (change it in Git, not by hand)\n", report.GitOps)
+		}
+
+		if len(report.Probes) > 0 {
+			fmt.Println("Probes:")
+			for _, probe := range report.Probes {
+				fmt.Printf("  - %s/%s: %s %s (delay %ds, period %ds, timeout %ds)\n",
+					probe.Container, probe.Type, probe.Scheme, probe.Target,
+					probe.InitialDelaySeconds, probe.PeriodSeconds, probe.TimeoutSeconds)
+			}
+		}
+
+		if len(report.ExtendedResources) > 0 {
+			fmt.Println("Extended Resources (per replica):")
+			for _, resource := range report.ExtendedResources {
+				fmt.Printf("  - %s/%s: request %s, limit %s\n",
+					resource.Container, resource.Resource, valueOrNone(resource.Request), valueOrNone(resource.Limit))
+			}
+		}
 
 		if len(report.Analysis.Issues) > 0 {
 			fmt.Println("Issues:")
@@ -67,11 +126,66 @@ var deploymentCmd = &cobra.Command{
 				fmt.Printf("  - [%s] %s: %s\n", event.LastTimestamp.Format("15:04:05"), event.Reason, event.Message)
 			}
 		}
+
+		printExplanations(cmd, "deployment", report.Name, report.Namespace, report.Analysis.Issues)
+
+		printActiveAlerts(cmd, workloadAlertLabels(cmd, client, "deployment", report.Namespace, report.Name)...)
+
+		notifyIssues(cmd, "deployment", report.Name, report.Namespace, report.Analysis.Status, report.Analysis.Issues)
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.MaxSeverity(
+			diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)), checks.Severity))
 	},
 }
 
 func init() {
 	// Add flags
-	deploymentCmd.Flags().StringP("namespace", "n", "default", "Namespace")
+	utils.AddNamespaceFlags(deploymentCmd.Flags())
+	deploymentCmd.Flags().StringP("filename", "f", "", "Analyze the deployments in a manifest file, or - for stdin, instead of the cluster")
+	deploymentCmd.Flags().Int("concurrency", defaultConcurrency, "Number of deployments analyzed in parallel when analyzing many at once")
+	utils.AddFilterFlags(deploymentCmd.Flags())
 	deploymentCmd.Flags().BoolP("verbose", "v", false, "Verbose output")
+	deploymentCmd.Flags().StringP("output", "o", "text", "Output format (text, compact, markdown)")
+	deploymentCmd.Flags().StringP("selector", "l", "", "Analyze all deployments matching this label selector, e.g. app=payments")
+	deploymentCmd.Flags().Bool("history", false, "Show the rollout history reconstructed from the deployment's ReplicaSets")
+}
+
+// printRolloutHistory prints the deployment's revisions as a timeline, marking the
+// current revision and old revisions that still run pods
+func printRolloutHistory(report *diagnostics.DeploymentReport) {
+	fmt.Printf("K8s Lens Rollout History For Deployment: %s\n", report.Name)
+	fmt.Println("---")
+
+	history := report.RolloutHistory()
+	if len(history) == 0 {
+		utils.PrintWarning("No ReplicaSets owned by deployment %s were found", report.Name)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REVISION\tREPLICASET\tIMAGES\tREPLICAS\tCREATED\tCHANGE-CAUSE\tSTATE")
+	idle := 0
+	for _, revision := range history {
+		state := ""
+		switch {
+		case revision.Current:
+			state = "current"
+		case revision.Idle:
+			state = "old, still running"
+			idle++
+		}
+		changeCause := revision.ChangeCause
+		if changeCause == "" {
+			changeCause = "<none>"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d/%d\t%s\t%s\t%s\n",
+			revision.Revision, revision.ReplicaSet, strings.Join(revision.Images, ","),
+			revision.ReadyReplicas, revision.Replicas,
+			revision.Created.Format("2006-01-02 15:04:05"), changeCause, state)
+	}
+	w.Flush()
+
+	if idle > 0 {
+		fmt.Println()
+		utils.PrintWarning("%d old revision(s) still run pods; the rollout may be stuck or paused", idle)
+	}
 }
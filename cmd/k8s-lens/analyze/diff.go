@@ -0,0 +1,252 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// diffRow is a single field compared between two reports
+type diffRow struct {
+	Field string
+	A     string
+	B     string
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [type] [name-a] [name-b]",
+	Short: "Compare the analysis of two resources",
+	Long: `Analyze two resources of the same type and print their reports side by side,
+highlighting the differences. Supported types: deployment, pod.`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, _ := cmd.Flags().GetString("namespace")
+		showAll, _ := cmd.Flags().GetBool("all")
+		resourceType, nameA, nameB := args[0], args[1], args[2]
+
+		utils.PrintInfo("Comparing %s %s and %s in namespace: %s", resourceType, nameA, nameB, namespace)
+
+		k8sClient, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		var rowsA, rowsB []diffRow
+		var issuesA, issuesB []diagnostics.Finding
+		switch resourceType {
+		case "deployment", "deploy":
+			rowsA, issuesA, err = deploymentDiffRows(cmd.Context(), k8sClient, namespace, nameA)
+			if err == nil {
+				rowsB, issuesB, err = deploymentDiffRows(cmd.Context(), k8sClient, namespace, nameB)
+			}
+		case "pod", "po":
+			rowsA, issuesA, err = podDiffRows(cmd.Context(), k8sClient, namespace, nameA)
+			if err == nil {
+				rowsB, issuesB, err = podDiffRows(cmd.Context(), k8sClient, namespace, nameB)
+			}
+		default:
+			utils.PrintError("Unsupported resource type: %s (supported: deployment, pod)", resourceType)
+			os.Exit(1)
+		}
+		if err != nil {
+			utils.PrintError("Error analyzing %s: %v", resourceType, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("K8s Lens Diff Report: %s %s vs %s\n", resourceType, nameA, nameB)
+		fmt.Println("---")
+
+		utils.PrintSection("Report Comparison")
+		differences := printDiffRows(mergeDiffRows(rowsA, rowsB), nameA, nameB, showAll)
+		if differences == 0 {
+			utils.PrintSuccess("No differences found in compared fields")
+		}
+
+		onlyA, onlyB := diffFindings(issuesA, issuesB)
+		if len(onlyA) > 0 || len(onlyB) > 0 {
+			utils.PrintSection("Issue Differences")
+			for _, issue := range onlyA {
+				utils.PrintWarning("Only in %s: %s", nameA, issue.Message)
+			}
+			for _, issue := range onlyB {
+				utils.PrintWarning("Only in %s: %s", nameB, issue.Message)
+			}
+		}
+	},
+}
+
+func deploymentDiffRows(ctx context.Context, client kubernetes.Interface, namespace, name string) ([]diffRow, []diagnostics.Finding, error) {
+	analyzer := diagnostics.NewDeploymentAnalyzer(client, namespace)
+	report, err := analyzer.Analyze(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows := []diffRow{
+		{Field: "Status", A: report.Analysis.Status},
+		{Field: "Rollout", A: report.Analysis.RolloutStatus},
+		{Field: "Desired Replicas", A: fmt.Sprint(report.DesiredReplicas)},
+		{Field: "Ready Replicas", A: fmt.Sprint(report.ReadyReplicas)},
+		{Field: "Available Replicas", A: fmt.Sprint(report.AvailableReplicas)},
+		{Field: "Updated Replicas", A: fmt.Sprint(report.UpdatedReplicas)},
+		{Field: "ReplicaSets", A: fmt.Sprint(len(report.ReplicaSets))},
+		{Field: "Issues", A: fmt.Sprint(len(report.Analysis.Issues))},
+	}
+	rows = append(rows, containerSpecRows(report.PodTemplate.Spec.Containers)...)
+
+	return rows, report.Findings(), nil
+}
+
+func podDiffRows(ctx context.Context, client kubernetes.Interface, namespace, name string) ([]diffRow, []diagnostics.Finding, error) {
+	analyzer := diagnostics.NewPodAnalyzer(client, namespace)
+	report, err := analyzer.Analyze(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get pod %s: %v", name, err)
+	}
+
+	rows := []diffRow{
+		{Field: "Phase", A: report.Phase},
+		{Field: "Status", A: report.Status},
+		{Field: "Node", A: report.Node},
+		{Field: "Health Score", A: fmt.Sprint(report.HealthScore)},
+		{Field: "Restarts", A: fmt.Sprint(report.RestartCount)},
+		{Field: "Limits Set", A: fmt.Sprint(report.ResourceLimitsSet)},
+		{Field: "Requests Set", A: fmt.Sprint(report.ResourceRequestsSet)},
+		{Field: "Issues", A: fmt.Sprint(len(report.Issues))},
+	}
+	for _, container := range report.Containers {
+		rows = append(rows, diffRow{Field: container.Name + " state", A: container.Status})
+	}
+	rows = append(rows, containerSpecRows(pod.Spec.Containers)...)
+
+	return rows, report.Findings(), nil
+}
+
+// containerSpecRows describes the image, resources and probes of each container
+func containerSpecRows(containers []corev1.Container) []diffRow {
+	var rows []diffRow
+	for _, container := range containers {
+		rows = append(rows,
+			diffRow{Field: container.Name + " image", A: container.Image},
+			diffRow{Field: container.Name + " requests", A: formatResourceList(container.Resources.Requests)},
+			diffRow{Field: container.Name + " limits", A: formatResourceList(container.Resources.Limits)},
+			diffRow{Field: container.Name + " probes", A: formatProbes(container)},
+		)
+	}
+	return rows
+}
+
+// mergeDiffRows combines the rows of both reports by field, keeping the order of
+// first appearance so fields present on only one side are still shown
+func mergeDiffRows(rowsA, rowsB []diffRow) []diffRow {
+	var merged []diffRow
+	index := make(map[string]int)
+	for _, row := range rowsA {
+		index[row.Field] = len(merged)
+		merged = append(merged, diffRow{Field: row.Field, A: row.A, B: "-"})
+	}
+	for _, row := range rowsB {
+		if i, ok := index[row.Field]; ok {
+			merged[i].B = row.A
+		} else {
+			merged = append(merged, diffRow{Field: row.Field, A: "-", B: row.A})
+		}
+	}
+	return merged
+}
+
+// printDiffRows prints the rows side by side and returns the number of differences
+func printDiffRows(rows []diffRow, nameA, nameB string, showAll bool) int {
+	fmt.Printf("  %-28s %-32s %-32s\n", "FIELD", nameA, nameB)
+	differences := 0
+	for _, row := range rows {
+		if row.A != row.B {
+			differences++
+			// Pad before colorizing so the escape codes don't break the alignment
+			fmt.Printf("%s %-28s %s %s\n", utils.Colorize("*", "yellow"), row.Field,
+				utils.Colorize(fmt.Sprintf("%-32s", row.A), "yellow"),
+				utils.Colorize(fmt.Sprintf("%-32s", row.B), "yellow"))
+		} else if showAll {
+			fmt.Printf("  %-28s %-32s %-32s\n", row.Field, row.A, row.B)
+		}
+	}
+	return differences
+}
+
+// diffFindings returns the findings of each side whose problem the other side does not
+// have. Findings are matched on their check rather than their ID, as the ID includes the
+// resource, which differs between the two sides, and rather than their message, as a
+// changed count or pod name is not a different problem.
+func diffFindings(a, b []diagnostics.Finding) (onlyA, onlyB []diagnostics.Finding) {
+	inA := make(map[string]bool)
+	for _, finding := range a {
+		inA[finding.Check] = true
+	}
+	inB := make(map[string]bool)
+	for _, finding := range b {
+		inB[finding.Check] = true
+		if !inA[finding.Check] {
+			onlyB = append(onlyB, finding)
+		}
+	}
+	for _, finding := range a {
+		if !inB[finding.Check] {
+			onlyA = append(onlyA, finding)
+		}
+	}
+	return onlyA, onlyB
+}
+
+func formatResourceList(resources corev1.ResourceList) string {
+	if len(resources) == 0 {
+		return "none"
+	}
+	var parts []string
+	if cpu, ok := resources[corev1.ResourceCPU]; ok {
+		parts = append(parts, "cpu="+cpu.String())
+	}
+	if memory, ok := resources[corev1.ResourceMemory]; ok {
+		parts = append(parts, "memory="+memory.String())
+	}
+	if len(parts) == 0 {
+		return "other"
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatProbes(container corev1.Container) string {
+	var probes []string
+	if container.LivenessProbe != nil {
+		probes = append(probes, "liveness")
+	}
+	if container.ReadinessProbe != nil {
+		probes = append(probes, "readiness")
+	}
+	if container.StartupProbe != nil {
+		probes = append(probes, "startup")
+	}
+	if len(probes) == 0 {
+		return "none"
+	}
+	return strings.Join(probes, ",")
+}
+
+func init() {
+	diffCmd.Flags().StringP("namespace", "n", "default", "Namespace")
+	diffCmd.Flags().Bool("all", false, "Show all compared fields, not only the differences")
+}
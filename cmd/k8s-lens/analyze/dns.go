@@ -0,0 +1,131 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Diagnose cluster DNS resolution",
+	Long: `Check cluster DNS as seen from a namespace: the CoreDNS deployment and pods, the
+kube-dns service and its endpoints, and the pods of the namespace whose dnsPolicy keeps
+them from resolving service names.
+
+With --lookup a short-lived pod is started in the namespace to resolve a name with
+nslookup, which also catches network policies blocking DNS. The pod is deleted when
+the lookup completes:
+
+  k8s-lens analyze dns -n shop --lookup payments`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, _ := cmd.Flags().GetString("namespace")
+		lookup, _ := cmd.Flags().GetString("lookup")
+		image, _ := cmd.Flags().GetString("lookup-image")
+
+		utils.PrintInfo("Starting DNS analysis for namespace: %s", namespace)
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		analyzer := diagnostics.NewDNSAnalyzer(client, namespace)
+		if lookup != "" {
+			analyzer.SetLookup(lookup, image)
+			utils.PrintInfo("Resolving %s from a temporary pod, this can take a minute", lookup)
+		}
+		report, err := analyzer.Analyze(cmd.Context())
+		if err != nil {
+			utils.PrintError("Error analyzing DNS: %v", err)
+			os.Exit(1)
+		}
+
+		printDNSReport(report)
+
+		notifyIssues(cmd, "dns", namespace, namespace, report.Analysis.Status, report.Analysis.Issues)
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)))
+	},
+}
+
+func init() {
+	dnsCmd.Flags().StringP("namespace", "n", "default", "Namespace")
+	dnsCmd.Flags().String("lookup", "", "Resolve this name from a temporary pod in the namespace, e.g. a service name")
+	dnsCmd.Flags().String("lookup-image", diagnostics.DefaultDNSLookupImage, "Image providing nslookup for the lookup pod")
+}
+
+func printDNSReport(report *diagnostics.DNSReport) {
+	fmt.Printf("K8s Lens DNS Report For Namespace: %s\n", report.Namespace)
+	fmt.Println("---")
+
+	utils.PrintSection("CoreDNS")
+	if report.Deployment != "" {
+		fmt.Printf("Deployment: %s (%d/%d ready)\n", report.Deployment, report.ReadyReplicas, report.DesiredReplicas)
+	} else {
+		fmt.Println("Deployment: not found")
+	}
+	for _, pod := range report.Pods {
+		ready := "Ready"
+		if !pod.Ready {
+			ready = "Not Ready"
+		}
+		fmt.Printf("- Pod %s on %s: %s, %d restart(s)\n", pod.Name, valueOrNone(pod.Node), ready, pod.Restarts)
+	}
+
+	utils.PrintSection("Service")
+	fmt.Printf("kube-dns IP: %s\n", valueOrNone(report.ServiceIP))
+	fmt.Printf("Ready Endpoints: %d\n", report.ReadyEndpoints)
+
+	if len(report.CustomDNSPods) > 0 {
+		utils.PrintSection("Pods Not Using Cluster DNS")
+		names := make([]string, 0, len(report.CustomDNSPods))
+		for name := range report.CustomDNSPods {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("- %s: dnsPolicy %s\n", name, report.CustomDNSPods[name])
+		}
+	}
+
+	if report.Lookup != nil {
+		utils.PrintSection("Test Lookup")
+		switch {
+		case report.Lookup.Error != "":
+			utils.PrintWarning("Lookup of %s did not run: %s", report.Lookup.Name, report.Lookup.Error)
+		case report.Lookup.Succeeded:
+			utils.PrintSuccess("%s resolved from pod %s", report.Lookup.Name, report.Lookup.Pod)
+		default:
+			utils.PrintError("%s did not resolve from pod %s", report.Lookup.Name, report.Lookup.Pod)
+		}
+		for _, line := range strings.Split(report.Lookup.Output, "\n") {
+			if line != "" {
+				fmt.Printf("  %s\n", line)
+			}
+		}
+	}
+
+	if len(report.Analysis.Issues) > 0 {
+		utils.PrintSection("Issues")
+		for _, issue := range report.Analysis.Issues {
+			utils.PrintWarning("- %s", issue)
+		}
+	}
+
+	if len(report.Analysis.Recommendations) > 0 {
+		utils.PrintSection("Recommendations")
+		for _, rec := range report.Analysis.Recommendations {
+			utils.PrintInfo("- %s", rec)
+		}
+	}
+
+	fmt.Printf("\nStatus: %s\n", report.Analysis.Status)
+}
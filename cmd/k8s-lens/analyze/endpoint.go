@@ -3,12 +3,13 @@ package analyze
 import (
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/abrarahmad1510/k8s-lens/internal/utils"
 	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
 	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
 	"github.com/spf13/cobra"
-	corev1 "k8s.io/api/core/v1"
 )
 
 var endpointCmd = &cobra.Command{
@@ -28,7 +29,7 @@ var endpointCmd = &cobra.Command{
 		}
 
 		analyzer := diagnostics.NewEndpointAnalyzer(k8sClient, namespace)
-		report, err := analyzer.ValidateEndpoints(args[0])
+		report, err := analyzer.ValidateEndpoints(cmd.Context(), args[0])
 		if err != nil {
 			utils.PrintError("Error analyzing endpoints: %v", err)
 			os.Exit(1)
@@ -42,12 +43,43 @@ var endpointCmd = &cobra.Command{
 		fmt.Printf("Ready Pods: %d/%d\n", report.Analysis.ReadyPods, report.Analysis.TotalPods)
 		fmt.Printf("Status: %s\n", report.Analysis.Status)
 
+		utils.PrintSection("EndpointSlice Reconciliation")
+		fmt.Printf("EndpointSlices: %d\n", len(report.Slices))
+		fmt.Printf("Ready Addresses In Slices: %d\n", report.Analysis.SliceAddresses)
+		if report.Analysis.EndpointsTruncated {
+			utils.PrintWarning("Endpoints object is truncated; EndpointSlices are authoritative")
+		} else if len(report.Analysis.MissingFromEndpoints) == 0 && len(report.Analysis.MissingFromSlices) == 0 {
+			utils.PrintSuccess("Status: Endpoints And EndpointSlices Are Consistent")
+		} else {
+			for _, address := range report.Analysis.MissingFromEndpoints {
+				utils.PrintWarning("- %s is in EndpointSlices but missing from Endpoints", address)
+			}
+			for _, address := range report.Analysis.MissingFromSlices {
+				utils.PrintWarning("- %s is in Endpoints but missing from EndpointSlices", address)
+			}
+		}
+
+		if len(report.Analysis.Zones) > 0 {
+			utils.PrintSection("Topology")
+			if report.Analysis.TopologyAwareRouting {
+				fmt.Println("Topology-Aware Routing: enabled")
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "ZONE\tREADY\tNOT READY\tHINTED\tNODES")
+			for _, zone := range report.Analysis.Zones {
+				fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", zone.Zone, zone.Ready, zone.NotReady, zone.Hinted, strings.Join(zone.Nodes, ", "))
+			}
+			w.Flush()
+		}
+
 		utils.PrintSection("Pod Readiness")
 		if report.Analysis.TotalPods > 0 {
 			for i, pod := range report.Pods {
 				ready := "Not Ready"
-				if isPodReady(&pod) {
+				if diagnostics.IsPodReady(&pod) {
 					ready = "Ready"
+				} else if gates := diagnostics.UnmetReadinessGates(&pod); len(gates) > 0 {
+					ready = fmt.Sprintf("Not Ready, readiness gate false: %s", strings.Join(gates, ", "))
 				}
 				fmt.Printf("- Pod %d: %s (%s)\n", i+1, pod.Name, ready)
 			}
@@ -68,18 +100,12 @@ var endpointCmd = &cobra.Command{
 				utils.PrintInfo("- %s", rec)
 			}
 		}
+
+		notifyIssues(cmd, "endpoint", report.ServiceName, report.Namespace, report.Analysis.Status, report.Analysis.Issues)
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)))
 	},
 }
 
 func init() {
 	endpointCmd.Flags().StringP("namespace", "n", "default", "Namespace")
 }
-
-func isPodReady(pod *corev1.Pod) bool {
-	for _, condition := range pod.Status.Conditions {
-		if condition.Type == corev1.PodReady {
-			return condition.Status == corev1.ConditionTrue
-		}
-	}
-	return false
-}
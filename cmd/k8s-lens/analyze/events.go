@@ -0,0 +1,138 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	// maxEventMessageLength truncates event messages so the table stays readable
+	maxEventMessageLength = 80
+	// maxTopReasons is how many of the most frequent reasons are listed
+	maxTopReasons = 10
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Summarize recent events in a namespace",
+	Long: `List the recent events of a namespace grouped by reason and involved object, with the
+most frequent reasons first, for a quick view of what has been going wrong lately.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, _ := cmd.Flags().GetString("namespace")
+		all, _ := cmd.Flags().GetBool("all-namespaces")
+		since, _ := cmd.Flags().GetDuration("since")
+		warningsOnly, _ := cmd.Flags().GetBool("warnings-only")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		// Every namespace is summarized from a single list of all events
+		namespaces := []string{metav1.NamespaceAll}
+		if !all {
+			if namespaces, err = k8s.ResolveNamespaces(cmd.Context(), client, namespace, false); err != nil {
+				utils.PrintError("Error resolving namespaces: %v", err)
+				os.Exit(1)
+			}
+		}
+
+		severity := diagnostics.SeverityHealthy
+		for _, namespace := range namespaces {
+			analyzer := diagnostics.NewEventsAnalyzer(client, namespace)
+			summary, err := analyzer.SummarizeEvents(cmd.Context(), since, warningsOnly)
+			if err != nil {
+				utils.PrintError("Error summarizing events: %v", err)
+				os.Exit(1)
+			}
+
+			printEventSummary(summary, limit)
+			if summary.WarningEvents > 0 {
+				severity = diagnostics.MaxSeverity(severity, diagnostics.SeverityWarning)
+			}
+		}
+		utils.ExitOnSeverity(cmd.Flags(), severity)
+	},
+}
+
+func init() {
+	utils.AddNamespaceFlags(eventsCmd.Flags())
+	eventsCmd.Flags().Duration("since", time.Hour, "Only include events seen within this duration, e.g. 30m or 24h (0 for all)")
+	eventsCmd.Flags().Bool("warnings-only", false, "Only include Warning events")
+	eventsCmd.Flags().Int("limit", 20, "Maximum number of event groups to list (0 for all)")
+}
+
+func printEventSummary(summary *diagnostics.EventSummary, limit int) {
+	scope := summary.Namespace
+	if scope == metav1.NamespaceAll {
+		scope = "all namespaces"
+	}
+	fmt.Printf("K8s Lens Events Report: %s\n", scope)
+	fmt.Println("---")
+
+	window := "all retained events"
+	if summary.Since > 0 {
+		window = "last " + duration.HumanDuration(summary.Since)
+	}
+	fmt.Printf("Window: %s\n", window)
+	fmt.Printf("Total Events: %d\n", summary.TotalEvents)
+	fmt.Printf("Warning Events: %d\n", summary.WarningEvents)
+
+	if len(summary.Groups) == 0 {
+		utils.PrintSuccess("No events found")
+		fmt.Println()
+		return
+	}
+
+	utils.PrintSection("Top Reasons")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "REASON\tTYPE\tCOUNT")
+	for i, reason := range summary.TopReasons {
+		if i == maxTopReasons {
+			break
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\n", reason.Reason, reason.Type, reason.Count)
+	}
+	w.Flush()
+
+	utils.PrintSection("Events By Object")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LAST SEEN\tTYPE\tREASON\tOBJECT\tCOUNT\tMESSAGE")
+	for i, group := range summary.Groups {
+		if limit > 0 && i == limit {
+			break
+		}
+		object := strings.ToLower(group.Kind) + "/" + group.Name
+		if summary.Namespace == metav1.NamespaceAll && group.Namespace != "" {
+			object = group.Namespace + "/" + object
+		}
+		fmt.Fprintf(w, "%s ago\t%s\t%s\t%s\t%d\t%s\n", duration.HumanDuration(time.Since(group.LastSeen)),
+			group.Type, group.Reason, object, group.Count, truncateMessage(group.Message))
+	}
+	w.Flush()
+	if limit > 0 && len(summary.Groups) > limit {
+		utils.PrintInfo("%d more event groups not shown, use --limit 0 to list all", len(summary.Groups)-limit)
+	}
+	fmt.Println()
+}
+
+func truncateMessage(message string) string {
+	message = strings.Join(strings.Fields(message), " ")
+	if len(message) > maxEventMessageLength {
+		return message[:maxEventMessageLength-3] + "..."
+	}
+	return message
+}
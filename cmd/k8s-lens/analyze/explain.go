@@ -0,0 +1,32 @@
+package analyze
+
+import (
+	"fmt"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/spf13/cobra"
+)
+
+// printExplanations expands each issue with its cause, impact and fix when --explain is set
+func printExplanations(cmd *cobra.Command, resourceType, name, namespace string, issues []string) {
+	explain, _ := cmd.Flags().GetBool("explain")
+	if !explain || len(issues) == 0 {
+		return
+	}
+
+	utils.PrintSection("Issue Explanations")
+	for _, issue := range issues {
+		utils.PrintWarning("%s", issue)
+		explanation := diagnostics.Explain(issue, resourceType, name, namespace)
+		if explanation == nil {
+			fmt.Println("  No explanation available for this issue")
+			fmt.Println()
+			continue
+		}
+		fmt.Printf("  Probable Cause: %s\n", explanation.Cause)
+		fmt.Printf("  Impact: %s\n", explanation.Impact)
+		fmt.Printf("  Fix: %s\n", explanation.Fix)
+		fmt.Println()
+	}
+}
@@ -0,0 +1,246 @@
+package analyze
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/enterprise"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/abrarahmad1510/k8s-lens/pkg/optimization"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// findingCategories are the categories --category accepts
+var findingCategories = []string{
+	diagnostics.CategoryReliability,
+	diagnostics.CategorySecurity,
+	diagnostics.CategoryRBAC,
+	diagnostics.CategoryCost,
+}
+
+var findingsCmd = &cobra.Command{
+	Use:   "findings",
+	Short: "List the findings of every analyzer in one normalized list",
+	Long: `Run the pod, deployment and statefulset diagnostics, the security scan, the RBAC
+analysis and the resource optimizer over a namespace, and list everything they find
+as one list of findings, most severe first.
+
+Every finding has the same fields whichever analyzer produced it: a stable id, its
+category (reliability, security, rbac or cost), severity, resource, message,
+remediation and source analyzer. --category limits the analyzers that run:
+
+  k8s-lens analyze findings -n shop
+  k8s-lens analyze findings -A --category security,rbac -o json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, _ := cmd.Flags().GetString("namespace")
+		all, _ := cmd.Flags().GetBool("all-namespaces")
+		categories, _ := cmd.Flags().GetStringSlice("category")
+		output, _ := cmd.Flags().GetString("output")
+		quiet, _ := cmd.Flags().GetBool("quiet")
+
+		if output != "text" && output != "json" && output != "markdown" {
+			utils.PrintError("Unsupported output format: %s (supported: text, json, markdown)", output)
+			os.Exit(1)
+		}
+		selected := make(map[string]bool)
+		for _, category := range categories {
+			if !slices.Contains(findingCategories, category) {
+				utils.PrintError("Unknown finding category: %s (supported: %s)", category, strings.Join(findingCategories, ", "))
+				os.Exit(1)
+			}
+			selected[category] = true
+		}
+		if len(selected) == 0 {
+			for _, category := range findingCategories {
+				selected[category] = true
+			}
+		}
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		namespaces, err := k8s.ResolveNamespaces(cmd.Context(), client, namespace, all)
+		if err != nil {
+			utils.PrintError("Error resolving namespaces: %v", err)
+			os.Exit(1)
+		}
+
+		collector := diagnostics.NewFindingCollector()
+		spinner := utils.NewSpinner(quiet || output != "text")
+		spinner.Start("Collecting findings")
+		for i, namespace := range namespaces {
+			spinner.Update(fmt.Sprintf("Collecting findings in namespace %s %s", namespace, utils.ProgressBar(i, len(namespaces), 20)))
+			if err := collectFindings(cmd.Context(), client, namespace, selected, collector); err != nil {
+				spinner.Stop()
+				utils.PrintError("Error analyzing namespace %s: %v", namespace, err)
+				os.Exit(1)
+			}
+		}
+		spinner.Stop()
+
+		findings := collector.Findings()
+		switch output {
+		case "json":
+			data, err := json.MarshalIndent(findings, "", "  ")
+			if err != nil {
+				utils.PrintError("Error encoding findings: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		case "markdown":
+			printFindingsMarkdown(findings, len(namespaces))
+		default:
+			printFindings(findings, len(namespaces))
+		}
+
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.FindingsSeverity(findings))
+	},
+}
+
+func init() {
+	utils.AddNamespaceFlags(findingsCmd.Flags())
+	findingsCmd.Flags().StringSlice("category", nil, "Only run the analyzers of these categories: reliability, security, rbac, cost (default all)")
+	findingsCmd.Flags().StringP("output", "o", "text", "Output format: text, json or markdown")
+}
+
+// collectFindings runs the analyzers of the selected categories over a namespace,
+// adding what they find to the collector. Workloads that disappear while the namespace
+// is analyzed are skipped.
+func collectFindings(ctx context.Context, client kubernetes.Interface, namespace string, selected map[string]bool, collector *diagnostics.FindingCollector) error {
+	if selected[diagnostics.CategoryReliability] {
+		events, err := diagnostics.NewEventCache(ctx, client, namespace)
+		if err != nil {
+			return err
+		}
+
+		pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list pods: %v", err)
+		}
+		podAnalyzer := diagnostics.NewPodAnalyzer(client, namespace)
+		podAnalyzer.SetEventCache(events)
+		podAnalyzer.SetFindingCollector(collector)
+		for _, pod := range pods.Items {
+			podAnalyzer.Analyze(ctx, pod.Name)
+		}
+
+		deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list deployments: %v", err)
+		}
+		deploymentAnalyzer := diagnostics.NewDeploymentAnalyzer(client, namespace)
+		deploymentAnalyzer.SetEventCache(events)
+		deploymentAnalyzer.SetFindingCollector(collector)
+		for _, deployment := range deployments.Items {
+			deploymentAnalyzer.Analyze(ctx, deployment.Name)
+		}
+
+		statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list statefulsets: %v", err)
+		}
+		statefulSetAnalyzer := diagnostics.NewStatefulSetAnalyzer(client, namespace)
+		statefulSetAnalyzer.SetEventCache(events)
+		statefulSetAnalyzer.SetFindingCollector(collector)
+		for _, statefulSet := range statefulSets.Items {
+			statefulSetAnalyzer.Analyze(ctx, statefulSet.Name)
+		}
+	}
+
+	if selected[diagnostics.CategorySecurity] {
+		scanner := enterprise.NewSecurityScanner(client)
+		scanner.SetFindingCollector(collector)
+		if _, err := scanner.ScanNamespace(ctx, namespace); err != nil {
+			return err
+		}
+	}
+
+	if selected[diagnostics.CategoryRBAC] {
+		rbacAnalyzer := enterprise.NewRBACAnalyzer(client)
+		rbacAnalyzer.SetFindingCollector(collector)
+		if _, err := rbacAnalyzer.AnalyzeNamespaceRBAC(ctx, namespace); err != nil {
+			return err
+		}
+	}
+
+	if selected[diagnostics.CategoryCost] {
+		optimizer := optimization.NewResourceOptimizer(client)
+		optimizer.SetFindingCollector(collector)
+		if _, err := optimizer.AnalyzeNamespace(ctx, namespace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printFindings(findings []diagnostics.Finding, namespaces int) {
+	fmt.Println("K8s Lens Findings Report")
+	fmt.Println("---")
+
+	if len(findings) == 0 {
+		utils.PrintSuccess("No findings in %d namespace(s)", namespaces)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSEVERITY\tCATEGORY\tRESOURCE\tMESSAGE")
+	for _, finding := range findings {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", finding.ID, finding.Severity, finding.Category, finding.Resource, finding.Message)
+	}
+	w.Flush()
+
+	if groups := diagnostics.SummarizeRecommendations(findings); len(groups) > 0 {
+		utils.PrintSection("Recommendations")
+		for _, group := range groups {
+			utils.PrintInfo("- %s", recommendationGroupText(group))
+		}
+	}
+
+	fmt.Printf("\n%s\n", findingsSummary(findings, namespaces))
+}
+
+func printFindingsMarkdown(findings []diagnostics.Finding, namespaces int) {
+	utils.MarkdownHeading(1, "K8s Lens Findings Report")
+
+	var rows [][]string
+	for _, finding := range findings {
+		rows = append(rows, []string{"`" + finding.ID + "`", finding.Severity, finding.Category, finding.Resource, finding.Message})
+	}
+	if len(rows) > 0 {
+		utils.MarkdownTable([]string{"ID", "Severity", "Category", "Resource", "Message"}, rows)
+	}
+	printRecommendationGroupsMarkdown(diagnostics.SummarizeRecommendations(findings))
+	fmt.Println(findingsSummary(findings, namespaces))
+}
+
+// findingsSummary counts the findings by category, e.g. "5 finding(s) in 2 namespace(s): 3 security, 2 cost"
+func findingsSummary(findings []diagnostics.Finding, namespaces int) string {
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		counts[finding.Category]++
+	}
+	var parts []string
+	for _, category := range findingCategories {
+		if counts[category] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[category], category))
+		}
+	}
+	summary := fmt.Sprintf("%d finding(s) in %d namespace(s)", len(findings), namespaces)
+	if len(parts) > 0 {
+		summary += ": " + strings.Join(parts, ", ")
+	}
+	return summary
+}
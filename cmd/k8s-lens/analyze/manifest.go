@@ -0,0 +1,77 @@
+package analyze
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/spf13/cobra"
+)
+
+// analyzeManifestFile analyzes the workloads of one kind read from the manifest given
+// with --filename, or only the one named in args, without a cluster. Only the spec
+// based checks run: resources, security context and probes.
+func analyzeManifestFile(cmd *cobra.Command, kind string, args []string) {
+	file, _ := cmd.Flags().GetString("filename")
+
+	var input io.Reader = os.Stdin
+	source := "stdin"
+	if file != "-" {
+		source = file
+		f, err := os.Open(file)
+		if err != nil {
+			utils.PrintError("Error opening manifest: %v", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	objects, err := diagnostics.ReadManifests(input)
+	if err != nil {
+		utils.PrintError("Error reading manifest %s: %v", source, err)
+		os.Exit(1)
+	}
+
+	var results []BatchResult
+	for _, object := range objects {
+		report, ok := diagnostics.AnalyzeManifest(object)
+		if !ok || report.Kind != kind || (len(args) > 0 && report.Name != args[0]) {
+			continue
+		}
+		results = append(results, BatchResult{
+			Type:            strings.ToLower(report.Kind),
+			Name:            report.Name,
+			Namespace:       report.Namespace,
+			Status:          report.Analysis.Status,
+			Issues:          report.Analysis.Issues,
+			Recommendations: report.Analysis.Recommendations,
+		})
+	}
+	if len(results) == 0 {
+		if len(args) > 0 {
+			utils.PrintError("No %s named %s found in %s", kind, args[0], source)
+		} else {
+			utils.PrintError("No %s found in %s", kind, source)
+		}
+		os.Exit(1)
+	}
+
+	switch output, _ := cmd.Flags().GetString("output"); output {
+	case "compact":
+		printCompactResults(results)
+	case "markdown":
+		printMarkdownResults("K8s Lens Manifest Analysis Report For "+source, results)
+	default:
+		printBatchResults("K8s Lens Manifest Analysis Report For "+source, results)
+	}
+
+	recordResults(results)
+	severity := diagnostics.SeverityHealthy
+	for _, result := range results {
+		severity = diagnostics.MaxSeverity(severity, diagnostics.SeverityForStatus(result.Status, len(result.Issues)))
+	}
+	utils.ExitOnSeverity(cmd.Flags(), severity)
+}
@@ -0,0 +1,158 @@
+package analyze
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+var namespaceCmd = &cobra.Command{
+	Use:   "namespace [namespace]",
+	Short: "Analyze every workload in a namespace, and save or compare golden snapshots",
+	Long: `Analyze the deployments, statefulsets, services and pods of a namespace and print
+one line per workload, with the security findings of its pods.
+
+With --snapshot the analysis is saved as normalized JSON, a golden report of the
+namespace. With --compare the current state is checked against a saved snapshot and
+the regressions are reported: new issues, dropped ready replicas, new security
+findings and workloads that disappeared. Issues already in the snapshot are accepted,
+so the command fails only on regressions:
+
+  k8s-lens analyze namespace shop --snapshot shop.golden.json
+  k8s-lens analyze namespace shop --compare shop.golden.json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		snapshotFile, _ := cmd.Flags().GetString("snapshot")
+		compareFile, _ := cmd.Flags().GetString("compare")
+
+		var baseline *diagnostics.NamespaceSnapshot
+		if compareFile != "" {
+			data, err := os.ReadFile(compareFile)
+			if err != nil {
+				utils.PrintError("Error reading snapshot: %v", err)
+				os.Exit(1)
+			}
+			if err := json.Unmarshal(data, &baseline); err != nil || baseline == nil {
+				utils.PrintError("Error parsing snapshot %s: %v", compareFile, err)
+				os.Exit(1)
+			}
+			if baseline.Namespace != args[0] {
+				utils.PrintWarning("Snapshot %s was taken of namespace %s, not %s", compareFile, baseline.Namespace, args[0])
+			}
+		}
+
+		utils.PrintInfo("Starting analysis of namespace: %s", args[0])
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		snapshotter := diagnostics.NewNamespaceSnapshotter(client, args[0])
+		snapshot, err := snapshotter.Snapshot(cmd.Context())
+		if err != nil {
+			utils.PrintError("Error analyzing namespace: %v", err)
+			os.Exit(1)
+		}
+
+		printNamespaceSnapshot(snapshot)
+		for _, workload := range snapshot.Workloads {
+			diagnostics.RecordIssues(workload.Kind, args[0], workload.Name, workload.Status, workload.Issues)
+		}
+
+		if snapshotFile != "" {
+			data, err := json.MarshalIndent(snapshot, "", "  ")
+			if err != nil {
+				utils.PrintError("Error encoding snapshot: %v", err)
+				os.Exit(1)
+			}
+			if err := os.WriteFile(snapshotFile, append(data, '\n'), 0644); err != nil {
+				utils.PrintError("Error writing snapshot: %v", err)
+				os.Exit(1)
+			}
+			utils.PrintSuccess("Snapshot of %d workload(s) saved to %s", len(snapshot.Workloads), snapshotFile)
+		}
+
+		if baseline != nil {
+			comparison := diagnostics.CompareSnapshots(baseline, snapshot)
+			printSnapshotComparison(baseline, comparison)
+
+			severity := diagnostics.SeverityHealthy
+			if len(comparison.Regressions) > 0 {
+				severity = diagnostics.SeverityCritical
+			}
+			utils.ExitOnSeverity(cmd.Flags(), severity)
+			return
+		}
+
+		severity := diagnostics.SeverityHealthy
+		for _, workload := range snapshot.Workloads {
+			severity = diagnostics.MaxSeverity(severity, diagnostics.SeverityForStatus(workload.Status, len(workload.Issues)))
+		}
+		utils.ExitOnSeverity(cmd.Flags(), severity)
+	},
+}
+
+func printNamespaceSnapshot(snapshot *diagnostics.NamespaceSnapshot) {
+	fmt.Printf("K8s Lens Analysis Report For Namespace: %s\n", snapshot.Namespace)
+	fmt.Println("---")
+
+	if len(snapshot.Workloads) == 0 {
+		utils.PrintWarning("No workloads found in namespace %s", snapshot.Namespace)
+		return
+	}
+
+	keys := make([]string, 0, len(snapshot.Workloads))
+	for key := range snapshot.Workloads {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WORKLOAD\tSTATUS\tREADY\tISSUES\tSECURITY FINDINGS")
+	withIssues := 0
+	for _, key := range keys {
+		workload := snapshot.Workloads[key]
+		ready := "-"
+		if workload.ReadyReplicas != nil && workload.DesiredReplicas != nil {
+			ready = fmt.Sprintf("%d/%d", *workload.ReadyReplicas, *workload.DesiredReplicas)
+		}
+		if len(workload.Issues) > 0 || workload.Error != "" {
+			withIssues++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\n", key, workload.Status, ready, len(workload.Issues), len(workload.SecurityFindings))
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d workload(s) analyzed, %d with issues\n", len(keys), withIssues)
+}
+
+func printSnapshotComparison(baseline *diagnostics.NamespaceSnapshot, comparison *diagnostics.SnapshotComparison) {
+	utils.PrintSection(fmt.Sprintf("Comparison With Snapshot From %s", baseline.CreatedAt.Format("2006-01-02 15:04:05 MST")))
+
+	for _, change := range comparison.Improvements {
+		utils.PrintSuccess("%s: %s", change.Resource, change.Detail)
+	}
+
+	if len(comparison.Regressions) == 0 {
+		utils.PrintSuccess("No regressions since the snapshot")
+		return
+	}
+	for _, change := range comparison.Regressions {
+		utils.PrintWarning("%s: %s", change.Resource, change.Detail)
+	}
+	fmt.Printf("\n%d regression(s) since the snapshot\n", len(comparison.Regressions))
+}
+
+func init() {
+	namespaceCmd.Flags().String("snapshot", "", "Save the analysis as a JSON snapshot to this file")
+	namespaceCmd.Flags().String("compare", "", "Compare the current state against a JSON snapshot and report regressions")
+}
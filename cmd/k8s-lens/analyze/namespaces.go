@@ -0,0 +1,67 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// analyzeNamespaces analyzes the named resource, the resources matching --selector and
+// --field-selector, or without either every resource of the type, in each namespace selected with
+// -n ns1,ns2 or --all-namespaces, and prints them with a combined summary
+func analyzeNamespaces(cmd *cobra.Command, resourceType string, args []string) {
+	namespace, _ := cmd.Flags().GetString("namespace")
+	all, _ := cmd.Flags().GetBool("all-namespaces")
+	selector, _ := cmd.Flags().GetString("selector")
+	fieldSelector, _ := cmd.Flags().GetString("field-selector")
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		utils.PrintError("Error creating Kubernetes client: %v", err)
+		os.Exit(1)
+	}
+
+	options := metav1.ListOptions{LabelSelector: selector, FieldSelector: fieldSelector}
+	target := fmt.Sprintf("all %ss", resourceType)
+	if len(args) > 0 {
+		options.FieldSelector = "metadata.name=" + args[0]
+		target = fmt.Sprintf("%s %s", resourceType, args[0])
+	} else if selector != "" || fieldSelector != "" {
+		target = fmt.Sprintf("%ss matching %s", resourceType, describeSelectors(selector, fieldSelector))
+	}
+
+	// Every namespace is listed in a single request instead of one per namespace
+	scope := "all namespaces"
+	namespaces := []string{metav1.NamespaceAll}
+	if !all {
+		if namespaces, err = k8s.ResolveNamespaces(cmd.Context(), client, namespace, false); err != nil {
+			utils.PrintError("Error resolving namespaces: %v", err)
+			os.Exit(1)
+		}
+		scope = "namespaces " + strings.Join(namespaces, ", ")
+	}
+
+	utils.PrintInfo("Starting analysis of %s in %s", target, scope)
+
+	var resources []batchResource
+	for _, namespace := range namespaces {
+		found, err := listResources(cmd.Context(), client, resourceType, namespace, options)
+		if err != nil {
+			utils.PrintError("Error listing %ss: %v", resourceType, err)
+			os.Exit(1)
+		}
+		resources = append(resources, found...)
+	}
+	resources = filterResources(cmd, resources)
+	if len(resources) == 0 {
+		utils.PrintWarning("No %s found in %s", target, scope)
+		return
+	}
+
+	analyzeResources(cmd, client, fmt.Sprintf("K8s Lens Analysis Report For %s (%s)", target, scope), resources)
+}
@@ -29,7 +29,7 @@ var networkCmd = &cobra.Command{
 		if len(args) == 1 {
 			// Analyze specific network policy
 			utils.PrintInfo("Analyzing network policy: %s in namespace: %s", args[0], namespace)
-			report, err := analyzer.AnalyzeNetworkPolicy(args[0])
+			report, err := analyzer.AnalyzeNetworkPolicy(cmd.Context(), args[0])
 			if err != nil {
 				utils.PrintError("Error analyzing network policy: %v", err)
 				os.Exit(1)
@@ -68,10 +68,12 @@ var networkCmd = &cobra.Command{
 				}
 			}
 
+			notifyIssues(cmd, "networkpolicy", report.Name, report.Namespace, report.Analysis.Status, report.Analysis.Issues)
+			utils.ExitOnSeverity(cmd.Flags(), diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)))
 		} else {
 			// Analyze all network policies in namespace
 			utils.PrintInfo("Analyzing all network policies in namespace: %s", namespace)
-			report, err := analyzer.AnalyzeNamespaceNetworkPolicies()
+			report, err := analyzer.AnalyzeNamespaceNetworkPolicies(cmd.Context())
 			if err != nil {
 				utils.PrintError("Error analyzing network policies: %v", err)
 				os.Exit(1)
@@ -102,6 +104,15 @@ var networkCmd = &cobra.Command{
 					utils.PrintInfo("- %s", rec)
 				}
 			}
+
+			var issues []string
+			for _, policyReport := range report.PolicyReports {
+				for _, issue := range policyReport.Analysis.Issues {
+					issues = append(issues, fmt.Sprintf("%s: %s", policyReport.Name, issue))
+				}
+			}
+			notifyIssues(cmd, "networkpolicies", report.Namespace, report.Namespace, report.CoverageStatus, issues)
+			utils.ExitOnSeverity(cmd.Flags(), diagnostics.SeverityForStatus(report.CoverageStatus, len(issues)))
 		}
 	},
 }
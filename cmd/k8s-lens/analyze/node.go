@@ -0,0 +1,329 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+var nodeCmd = &cobra.Command{
+	Use:   "node [name | --fleet]",
+	Short: "Analyze a Kubernetes Node",
+	Long: `Analyze a Kubernetes Node, its conditions, and the pods scheduled on it.
+Use --allocation to see how much of the node is claimed by pod requests, which is what
+decides whether new pods can be scheduled there, regardless of actual usage.
+
+The kubelet is checked against the control plane version: kubelets newer than the
+API server, or more than two minor versions behind it, are reported, as are kubelets
+behind the rest of the cluster. Use --fleet to list the kubelet, container runtime,
+OS and kernel versions of every node and see how uniform the node fleet is.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if fleet, _ := cmd.Flags().GetBool("fleet"); fleet {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if fleet, _ := cmd.Flags().GetBool("fleet"); fleet {
+			analyzeNodeFleet(cmd)
+			return
+		}
+		topConsumers, _ := cmd.Flags().GetBool("top-consumers")
+		allocation, _ := cmd.Flags().GetBool("allocation")
+		limit, _ := cmd.Flags().GetInt("limit")
+		prometheusURL, _ := cmd.Flags().GetString("prometheus-url")
+		metricsBackend, _ := cmd.Flags().GetString("metrics-backend")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		utils.PrintInfo("Starting node analysis for: %s", args[0])
+
+		k8sClient, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		analyzer := diagnostics.NewNodeAnalyzer(k8sClient)
+		report, err := analyzer.Analyze(cmd.Context(), args[0])
+		if err != nil {
+			utils.PrintError("Error analyzing node: %v", err)
+			os.Exit(1)
+		}
+
+		checks := runCustomChecks(cmd, k8sClient, "node", "", args[0])
+		report.Analysis.Issues = append(report.Analysis.Issues, checks.Issues...)
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations, checks.Recommendations...)
+
+		fmt.Printf("K8s Lens Analysis Report For Node: %s\n", report.Name)
+		fmt.Println("---")
+
+		utils.PrintSection("Node Status")
+		fmt.Printf("Kubelet Version: %s\n", report.KubeletVersion)
+		if report.ControlPlaneVersion != "" {
+			fmt.Printf("Control Plane Version: %s\n", report.ControlPlaneVersion)
+		}
+		fmt.Printf("Container Runtime: %s\n", report.ContainerRuntime)
+		fmt.Printf("OS Image: %s (%s)\n", report.OSImage, report.Architecture)
+		fmt.Printf("Kernel Version: %s\n", report.KernelVersion)
+		fmt.Printf("Pods Scheduled: %d\n", len(report.Pods))
+		if report.Ready {
+			utils.PrintSuccess("Status: Node Is Ready")
+		} else {
+			utils.PrintWarning("Status: Node Is Not Ready")
+		}
+		if report.Unschedulable {
+			utils.PrintWarning("Scheduling: Node Is Cordoned")
+		}
+
+		utils.PrintSection("Capacity")
+		fmt.Printf("CPU: %s (allocatable: %s)\n", report.Capacity.Cpu().String(), report.Allocatable.Cpu().String())
+		fmt.Printf("Memory: %s (allocatable: %s)\n", report.Capacity.Memory().String(), report.Allocatable.Memory().String())
+		fmt.Printf("Pods: %s (allocatable: %s)\n", report.Capacity.Pods().String(), report.Allocatable.Pods().String())
+
+		if len(report.TaintExplanations) > 0 {
+			utils.PrintSection("Taints")
+			for _, taint := range report.TaintExplanations {
+				fmt.Printf("%s\n", taint.Taint)
+				fmt.Printf("  Why: %s\n", taint.Meaning)
+				fmt.Printf("  Effect: %s\n", taint.Effect)
+				fmt.Printf("  Tolerated by %d of the pods on this node", len(taint.ToleratingPods))
+				if verbose && len(taint.ToleratingPods) > 0 {
+					fmt.Printf(": %s", strings.Join(taint.ToleratingPods, ", "))
+				}
+				fmt.Println()
+			}
+		}
+
+		if len(report.PressureConditions) > 0 {
+			utils.PrintSection("Pressure Conditions")
+			for _, condition := range report.PressureConditions {
+				utils.PrintWarning("- %s", condition)
+			}
+		}
+
+		if len(report.Analysis.Issues) > 0 {
+			utils.PrintSection("Issues")
+			for _, issue := range report.Analysis.Issues {
+				utils.PrintWarning("- %s", issue)
+			}
+		}
+
+		if len(report.Analysis.Recommendations) > 0 {
+			utils.PrintSection("Recommendations")
+			for _, rec := range report.Analysis.Recommendations {
+				utils.PrintInfo("- %s", rec)
+			}
+		}
+
+		if allocation {
+			printNodeAllocation(report.Allocation())
+		}
+
+		if topConsumers {
+			var usage diagnostics.PodUsageSource
+			if prometheusURL != "" {
+				promClient, err := integrations.NewMetricsBackend(metricsBackend, prometheusURL, integrations.MetricsAuthFromFlags(cmd.Flags()))
+				if err != nil {
+					utils.PrintError("Error creating metrics client: %v", err)
+					os.Exit(1)
+				}
+				if err := promClient.TestConnection(); err != nil {
+					utils.PrintWarning("Prometheus unavailable, ranking pods by requests: %v", err)
+				} else {
+					usage = promClient
+				}
+			}
+
+			pressure, err := analyzer.AnalyzePressure(cmd.Context(), args[0], limit, usage)
+			if err != nil {
+				utils.PrintError("Error analyzing node pressure: %v", err)
+				os.Exit(1)
+			}
+			printNodePressureReport(pressure)
+		}
+
+		if verbose {
+			utils.PrintSection("Verbose Information")
+			fmt.Println("Conditions:")
+			for _, condition := range report.Conditions {
+				fmt.Printf("  - %s: %s (%s)\n", condition.Type, condition.Status, condition.Message)
+			}
+			fmt.Println("Taints:")
+			for _, taint := range report.Taints {
+				fmt.Printf("  - %s=%s:%s\n", taint.Key, taint.Value, taint.Effect)
+			}
+		}
+
+		printExplanations(cmd, "node", report.Name, "", report.Analysis.Issues)
+
+		printActiveAlerts(cmd, map[string]string{"node": report.Name})
+
+		notifyIssues(cmd, "node", report.Name, "", report.Analysis.Status, report.Analysis.Issues)
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.MaxSeverity(
+			diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)), checks.Severity))
+	},
+}
+
+// analyzeNodeFleet prints the software versions of every node, grouped by version,
+// with the skew found between them and the control plane
+func analyzeNodeFleet(cmd *cobra.Command) {
+	utils.PrintInfo("Starting node fleet analysis")
+
+	k8sClient, err := k8s.NewClient()
+	if err != nil {
+		utils.PrintError("Error creating Kubernetes client: %v", err)
+		os.Exit(1)
+	}
+
+	analyzer := diagnostics.NewNodeAnalyzer(k8sClient)
+	report, err := analyzer.AnalyzeFleet(cmd.Context())
+	if err != nil {
+		utils.PrintError("Error analyzing nodes: %v", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("K8s Lens Node Fleet Report")
+	fmt.Println("---")
+	if report.ControlPlaneVersion != "" {
+		fmt.Printf("Control Plane Version: %s\n", report.ControlPlaneVersion)
+	}
+	fmt.Printf("Nodes: %d\n", len(report.Nodes))
+
+	utils.PrintSection("Nodes")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tKUBELET\tRUNTIME\tOS IMAGE\tKERNEL\tARCH")
+	for _, node := range report.Nodes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", node.Name, node.KubeletVersion, node.ContainerRuntime,
+			node.OSImage, node.KernelVersion, node.Architecture)
+	}
+	w.Flush()
+
+	for _, group := range []struct {
+		title    string
+		versions map[string][]string
+	}{
+		{"Kubelet Versions", report.KubeletVersions},
+		{"Container Runtimes", report.ContainerRuntimes},
+		{"OS Images", report.OSImages},
+		{"Kernel Versions", report.KernelVersions},
+	} {
+		utils.PrintSection(group.title)
+		var versions []string
+		for version := range group.versions {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+		for _, version := range versions {
+			fmt.Printf("  %s: %d node(s)\n", version, len(group.versions[version]))
+		}
+	}
+
+	if len(report.Issues) > 0 {
+		utils.PrintSection("Issues")
+		for _, issue := range report.Issues {
+			utils.PrintWarning("- %s", issue)
+		}
+	} else {
+		utils.PrintSuccess("Every node runs a supported kubelet of the same minor version")
+	}
+	if len(report.Recommendations) > 0 {
+		utils.PrintSection("Recommendations")
+		for _, rec := range report.Recommendations {
+			utils.PrintInfo("- %s", rec)
+		}
+	}
+
+	severity := diagnostics.SeverityHealthy
+	if len(report.Issues) > 0 {
+		severity = diagnostics.SeverityWarning
+	}
+	utils.ExitOnSeverity(cmd.Flags(), severity)
+}
+
+func printNodePressureReport(pressure *diagnostics.NodePressureReport) {
+	utils.PrintSection("Top Resource Consumers")
+	if len(pressure.PressureConditions) == 0 {
+		utils.PrintSuccess("Node reports no pressure conditions")
+	}
+	fmt.Printf("Ranked By: %s\n", pressure.RankedBy)
+
+	if len(pressure.TopConsumers) == 0 {
+		fmt.Println("No running pods found on node")
+	}
+	for i, consumer := range pressure.TopConsumers {
+		fmt.Printf("%d. %s/%s - CPU: %dm, Memory: %.1f MB, Ephemeral Storage: %.1f MB\n",
+			i+1, consumer.Namespace, consumer.Name, consumer.CPUMillis,
+			float64(consumer.MemoryBytes)/(1024*1024),
+			float64(consumer.EphemeralStorageBytes)/(1024*1024))
+	}
+
+	if len(pressure.EvictedPods) > 0 {
+		utils.PrintSection("Evicted Pods")
+		for _, evicted := range pressure.EvictedPods {
+			if evicted.Time.IsZero() {
+				utils.PrintWarning("- %s/%s: %s", evicted.Namespace, evicted.Name, evicted.Message)
+			} else {
+				utils.PrintWarning("- %s/%s (evicted %s): %s", evicted.Namespace, evicted.Name,
+					evicted.Time.Format("2006-01-02 15:04:05"), evicted.Message)
+			}
+		}
+	}
+}
+
+// printNodeAllocation prints the requests and limits of the pods on a node against its
+// allocatable resources
+func printNodeAllocation(allocation *diagnostics.NodeAllocation) {
+	utils.PrintSection("Resource Allocation")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RESOURCE\tALLOCATABLE\tREQUESTS\tLIMITS\tFREE TO REQUEST")
+	fmt.Fprintf(w, "cpu\t%dm\t%dm (%.0f%%)\t%dm (%.0f%%)\t%dm\n",
+		allocation.CPU.Allocatable, allocation.CPU.Requests, allocation.CPU.RequestPercent(),
+		allocation.CPU.Limits, allocation.CPU.LimitRatio()*100, allocation.CPU.Free())
+	fmt.Fprintf(w, "memory\t%s\t%s (%.0f%%)\t%s (%.0f%%)\t%s\n",
+		formatMemory(allocation.Memory.Allocatable), formatMemory(allocation.Memory.Requests), allocation.Memory.RequestPercent(),
+		formatMemory(allocation.Memory.Limits), allocation.Memory.LimitRatio()*100, formatMemory(allocation.Memory.Free()))
+	fmt.Fprintf(w, "pods\t%d\t%d\t-\t%d\n",
+		allocation.AllocatablePods, allocation.Pods, allocation.AllocatablePods-int64(allocation.Pods))
+	for _, resource := range allocation.ExtendedResources {
+		fmt.Fprintf(w, "%s\t%d\t%d (%.0f%%)\t%d\t%d\n", resource.Resource,
+			resource.Allocatable, resource.Requests, resource.RequestPercent(), resource.Limits, resource.Free())
+	}
+	w.Flush()
+
+	if allocation.PodsWithoutLimits > 0 {
+		utils.PrintInfo("%d pod(s) have containers without CPU or memory limits, which the limit totals do not include", allocation.PodsWithoutLimits)
+	}
+	for _, issue := range allocation.Issues {
+		utils.PrintWarning("- %s", issue)
+	}
+	for _, rec := range allocation.Recommendations {
+		utils.PrintInfo("- %s", rec)
+	}
+	if len(allocation.Issues) == 0 {
+		utils.PrintSuccess("Node has scheduling headroom and its limits are not overcommitted")
+	}
+}
+
+// formatMemory formats a byte count in GiB, e.g. 1.5Gi
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%.1fGi", float64(bytes)/(1024*1024*1024))
+}
+
+func init() {
+	nodeCmd.Flags().Bool("fleet", false, "Report the kubelet, container runtime, OS and kernel versions of every node instead of analyzing one")
+	nodeCmd.Flags().Bool("allocation", false, "Sum the requests and limits of the pods on the node against its allocatable resources")
+	nodeCmd.Flags().Bool("top-consumers", false, "List the pods consuming the most resources on the node")
+	nodeCmd.Flags().Int("limit", 3, "Number of top consumers to show")
+	nodeCmd.Flags().StringP("prometheus-url", "p", "", "Prometheus URL for ranking by live usage instead of requests")
+	nodeCmd.Flags().String("metrics-backend", integrations.BackendPrometheus, "Metrics backend serving the Prometheus query API: prometheus, thanos or victoriametrics")
+	integrations.AddMetricsAuthFlags(nodeCmd.Flags())
+	nodeCmd.Flags().BoolP("verbose", "v", false, "Verbose output")
+}
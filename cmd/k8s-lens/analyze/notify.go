@@ -0,0 +1,35 @@
+package analyze
+
+import (
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations/notify"
+	"github.com/spf13/cobra"
+)
+
+// notifyIssues posts a summary of plain-text issues to the webhook set via --notify-webhook,
+// and counts them toward the findings tally printed when the command ends
+func notifyIssues(cmd *cobra.Command, resourceType, name, namespace, status string, issues []string) {
+	diagnostics.RecordIssues(resourceType, namespace, name, status, issues)
+	summary := notify.NewSummary(resourceType, name, namespace, status)
+	for _, issue := range issues {
+		summary.AddIssue("Warning", issue)
+	}
+	sendNotification(cmd, summary)
+}
+
+// sendNotification posts the summary if a webhook URL was configured
+func sendNotification(cmd *cobra.Command, summary *notify.Summary) {
+	webhookURL, _ := cmd.Flags().GetString("notify-webhook")
+	if webhookURL == "" {
+		return
+	}
+	format, _ := cmd.Flags().GetString("notify-format")
+
+	notifier := notify.NewWebhookNotifier(webhookURL, format)
+	if err := notifier.Notify(summary); err != nil {
+		utils.PrintWarning("Failed to send webhook notification: %v", err)
+		return
+	}
+	utils.PrintSuccess("Analysis summary sent to webhook")
+}
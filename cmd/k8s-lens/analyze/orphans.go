@@ -0,0 +1,92 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+var orphansCmd = &cobra.Command{
+	Use:   "orphans",
+	Short: "Find resources that are likely left behind",
+	Long: `Find cleanup candidates in a namespace: ReplicaSets with no pods and no owning
+Deployment, PersistentVolumeClaims no pod mounts, ConfigMaps and Secrets nothing
+references, and Services with neither a selector nor endpoints.
+
+Review each candidate before deleting it; resources can be used by things the
+analysis cannot see, such as applications reading them through the API.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, _ := cmd.Flags().GetString("namespace")
+		all, _ := cmd.Flags().GetBool("all-namespaces")
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		namespaces, err := k8s.ResolveNamespaces(cmd.Context(), client, namespace, all)
+		if err != nil {
+			utils.PrintError("Error resolving namespaces: %v", err)
+			os.Exit(1)
+		}
+
+		total := 0
+		for _, namespace := range namespaces {
+			utils.PrintInfo("Looking for orphaned resources in namespace: %s", namespace)
+			analyzer := diagnostics.NewOrphanAnalyzer(client, namespace)
+			report, err := analyzer.FindOrphans(cmd.Context())
+			if err != nil {
+				utils.PrintError("Error finding orphaned resources: %v", err)
+				os.Exit(1)
+			}
+			printOrphanReport(report)
+			issues := make([]string, 0, len(report.Orphans))
+			for _, orphan := range report.Orphans {
+				issues = append(issues, fmt.Sprintf("%s %s %s", orphan.Kind, orphan.Name, orphan.Reason))
+			}
+			diagnostics.RecordIssues("Namespace", "", namespace, "Healthy", issues)
+			total += len(report.Orphans)
+		}
+
+		if len(namespaces) > 1 {
+			utils.PrintSection("Summary")
+			fmt.Printf("Cleanup Candidates: %d across %d namespaces\n", total, len(namespaces))
+		}
+
+		severity := diagnostics.SeverityHealthy
+		if total > 0 {
+			severity = diagnostics.SeverityWarning
+		}
+		utils.ExitOnSeverity(cmd.Flags(), severity)
+	},
+}
+
+func init() {
+	utils.AddNamespaceFlags(orphansCmd.Flags())
+}
+
+func printOrphanReport(report *diagnostics.OrphanReport) {
+	fmt.Printf("K8s Lens Orphaned Resources Report: %s\n", report.Namespace)
+	fmt.Println("---")
+
+	if len(report.Orphans) == 0 {
+		utils.PrintSuccess("No orphaned resources found")
+		fmt.Println()
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tNAME\tREASON")
+	for _, orphan := range report.Orphans {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", orphan.Kind, orphan.Name, orphan.Reason)
+	}
+	w.Flush()
+	fmt.Printf("\nCleanup Candidates: %d\n\n", len(report.Orphans))
+}
@@ -0,0 +1,85 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+var pathCmd = &cobra.Command{
+	Use:   "path [ingress-name]",
+	Short: "Trace requests from an Ingress to the pods serving them",
+	Long: `Trace the request path of every rule of an Ingress: the rule, its backend Service,
+the service port and target port, the service's endpoints and the ready pods behind
+them. Each path stops at the first broken stage, which answers "why is my app
+returning 503".`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, _ := cmd.Flags().GetString("namespace")
+
+		utils.PrintInfo("Tracing request paths for ingress: %s in namespace: %s", args[0], namespace)
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		tracer := diagnostics.NewIngressPathTracer(client, namespace)
+		report, err := tracer.Trace(cmd.Context(), args[0])
+		if err != nil {
+			utils.PrintError("Error tracing ingress: %v", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("K8s Lens Request Path Trace For Ingress: %s\n", report.Name)
+		fmt.Println("---")
+		fmt.Printf("Namespace: %s\n", report.Namespace)
+		if report.IngressClass != "" {
+			fmt.Printf("Ingress Class: %s\n", report.IngressClass)
+		}
+		if report.Address != "" {
+			fmt.Printf("Address: %s\n", report.Address)
+		}
+		fmt.Printf("Status: %s\n", report.Analysis.Status)
+
+		for _, path := range report.Paths {
+			utils.PrintSection(path.Rule)
+			for _, step := range path.Steps {
+				marker := "✔"
+				if !step.OK {
+					marker = "✖"
+				}
+				fmt.Printf("  %s %-10s %s\n", marker, step.Stage, step.Detail)
+			}
+			for _, warning := range path.Warnings {
+				utils.PrintWarning("  %s", warning)
+			}
+		}
+
+		if len(report.Analysis.Issues) > 0 {
+			utils.PrintSection("Issues")
+			for _, issue := range report.Analysis.Issues {
+				utils.PrintWarning("- %s", issue)
+			}
+		}
+
+		if len(report.Analysis.Recommendations) > 0 {
+			utils.PrintSection("Recommendations")
+			for _, rec := range report.Analysis.Recommendations {
+				utils.PrintInfo("- %s", rec)
+			}
+		}
+
+		notifyIssues(cmd, "ingress", report.Name, report.Namespace, report.Analysis.Status, report.Analysis.Issues)
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)))
+	},
+}
+
+func init() {
+	pathCmd.Flags().StringP("namespace", "n", "default", "Namespace")
+}
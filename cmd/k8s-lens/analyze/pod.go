@@ -3,6 +3,8 @@ package analyze
 import (
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/abrarahmad1510/k8s-lens/internal/utils"
 	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
@@ -11,12 +13,33 @@ import (
 )
 
 var podCmd = &cobra.Command{
-	Use:   "pod [name]",
+	Use:   "pod [name | -l selector | --field-selector selector]",
 	Short: "Analyze a Kubernetes Pod",
 	Long:  `Analyze a Kubernetes Pod and provide diagnostic information.`,
-	Args:  cobra.ExactArgs(1),
+	Args:  outputArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		if file, _ := cmd.Flags().GetString("filename"); file != "" {
+			analyzeManifestFile(cmd, "Pod", args)
+			return
+		}
+		if utils.MultipleNamespaces(cmd.Flags()) {
+			analyzeNamespaces(cmd, "pod", args)
+			return
+		}
 		namespace, _ := cmd.Flags().GetString("namespace")
+		selector, _ := cmd.Flags().GetString("selector")
+		if fieldSelector, _ := cmd.Flags().GetString("field-selector"); selector != "" || fieldSelector != "" {
+			analyzeSelector(cmd, "pod", namespace, selector)
+			return
+		}
+		if allContexts, _ := cmd.Flags().GetBool("all-contexts"); allContexts {
+			analyzePodAllContexts(cmd, namespace, args[0])
+			return
+		}
+		if output, _ := cmd.Flags().GetString("output"); output == "compact" || output == "markdown" {
+			analyzeCompact(cmd, "pod", namespace, args[0])
+			return
+		}
 		verbose, _ := cmd.Flags().GetBool("verbose")
 
 		utils.PrintInfo("Starting pod analysis for: %s in namespace: %s", args[0], namespace)
@@ -28,12 +51,16 @@ var podCmd = &cobra.Command{
 		}
 
 		analyzer := diagnostics.NewPodAnalyzer(client, namespace)
-		report, err := analyzer.Analyze(args[0])
+		report, err := analyzer.Analyze(cmd.Context(), args[0])
 		if err != nil {
 			utils.PrintError("Error analyzing pod: %v", err)
 			os.Exit(1)
 		}
 
+		checks := runCustomChecks(cmd, client, "pod", namespace, args[0])
+		report.Issues = append(report.Issues, checks.Issues...)
+		report.Recommendations = append(report.Recommendations, checks.Recommendations...)
+
 		// Print the report
 		fmt.Printf("K8s Lens Analysis Report For Pod: %s\n", report.Name)
 		fmt.Println("---")
@@ -41,6 +68,15 @@ var podCmd = &cobra.Command{
 		utils.PrintSection("Pod Status Analysis")
 		fmt.Printf("Phase: %s\n", report.Phase)
 		fmt.Printf("Node: %s\n", report.Node)
+		if report.Owner != nil {
+			var chain []string
+			for _, owner := range report.OwnerChain {
+				chain = append(chain, fmt.Sprintf("%s/%s", owner.Kind, owner.Name))
+			}
+			fmt.Printf("Owned By: %s %s (%s)\n", report.Owner.Kind, report.Owner.Name, strings.Join(chain, " -> "))
+		} else {
+			fmt.Println("Owned By: none (bare pod)")
+		}
 		fmt.Printf("Created: %s\n", report.Created.Format("Mon, 02 Jan 2006 15:04:05 UTC"))
 
 		if report.Status == "Running" {
@@ -52,8 +88,18 @@ var podCmd = &cobra.Command{
 		utils.PrintSection("Container Status Analysis")
 		for _, container := range report.Containers {
 			fmt.Printf("Container: %s\n", container.Name)
-			fmt.Printf("Image: %s\n", container.Image)
+			if container.SpecImage != "" {
+				fmt.Printf("Image: %s\n", container.SpecImage)
+			} else {
+				fmt.Printf("Image: %s\n", container.Image)
+			}
+			if container.ImageID != "" {
+				fmt.Printf("Image ID: %s\n", container.ImageID)
+			}
 			fmt.Printf("Status: %s\n", container.Status)
+			if container.ExitCode != 0 || container.TerminationReason != "" {
+				fmt.Printf("Last Exit: %d (%s)\n", container.ExitCode, container.TerminationReason)
+			}
 
 			if container.Ready {
 				utils.PrintSuccess("Status: Container Is Ready")
@@ -63,6 +109,15 @@ var podCmd = &cobra.Command{
 			fmt.Println()
 		}
 
+		if len(report.Probes) > 0 {
+			utils.PrintSection("Probe Analysis")
+			for _, probe := range report.Probes {
+				fmt.Printf("%s/%s: %s %s (delay %ds, period %ds, timeout %ds)\n",
+					probe.Container, probe.Type, probe.Scheme, probe.Target,
+					probe.InitialDelaySeconds, probe.PeriodSeconds, probe.TimeoutSeconds)
+			}
+		}
+
 		utils.PrintSection("Resource Analysis")
 		if report.ResourceLimitsSet {
 			utils.PrintSuccess("Status: Resource Limits Configured")
@@ -75,6 +130,24 @@ var podCmd = &cobra.Command{
 		} else {
 			utils.PrintWarning("Warning: No Resource Requests Configured")
 		}
+		if len(report.Resources) > 0 {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "CONTAINER\tCPU REQUEST\tCPU LIMIT\tMEMORY REQUEST\tMEMORY LIMIT")
+			for _, resources := range report.Resources {
+				name := resources.Name
+				if resources.Init {
+					name += " (init)"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name,
+					valueOrNone(resources.CPURequest), valueOrNone(resources.CPULimit),
+					valueOrNone(resources.MemoryRequest), valueOrNone(resources.MemoryLimit))
+			}
+			w.Flush()
+		}
+		for _, resource := range report.ExtendedResources {
+			fmt.Printf("Extended Resource: %s/%s (request %s, limit %s)\n",
+				resource.Container, resource.Resource, valueOrNone(resource.Request), valueOrNone(resource.Limit))
+		}
 
 		utils.PrintSection("Recent Events Analysis")
 		if len(report.Events) > 0 {
@@ -88,11 +161,33 @@ var podCmd = &cobra.Command{
 			utils.PrintSuccess("Status: No Recent Events Found")
 		}
 
+		if len(report.SchedulerReasons) > 0 || len(report.SchedulingAnalysis) > 0 {
+			utils.PrintSection("Scheduling Analysis")
+			if len(report.SchedulerReasons) > 0 {
+				fmt.Println("Scheduler Reported:")
+				for _, reason := range report.SchedulerReasons {
+					fmt.Printf("• %s\n", reason)
+				}
+			}
+			for _, reason := range report.SchedulingAnalysis {
+				utils.PrintWarning("%s", reason)
+			}
+		}
+
 		utils.PrintSection("Summary And Recommendations")
+		rating := diagnostics.HealthRating(report.HealthScore)
+		if rating == "Healthy" {
+			utils.PrintSuccess("Health Score: %d/100 (%s)", report.HealthScore, rating)
+		} else {
+			utils.PrintWarning("Health Score: %d/100 (%s)", report.HealthScore, rating)
+		}
+
+		overallHealth := "Healthy"
 		if len(report.Issues) == 0 {
-			utils.PrintSuccess("Overall Health: Healthy")
+			utils.PrintSuccess("Overall Health: %s", overallHealth)
 		} else {
-			utils.PrintWarning("Overall Health: Needs Attention")
+			overallHealth = "Needs Attention"
+			utils.PrintWarning("Overall Health: %s", overallHealth)
 			fmt.Println("Warnings:")
 			for _, issue := range report.Issues {
 				fmt.Printf("• %s\n", issue)
@@ -113,10 +208,29 @@ var podCmd = &cobra.Command{
 			fmt.Printf("Service Account: %s\n", report.ServiceAccount)
 			fmt.Printf("Restart Count: %d\n", report.RestartCount)
 		}
+
+		printExplanations(cmd, "pod", report.Name, report.Namespace, report.Issues)
+
+		printActiveAlerts(cmd, map[string]string{"namespace": report.Namespace, "pod": report.Name})
+
+		notifyIssues(cmd, "pod", report.Name, report.Namespace, overallHealth, report.Issues)
+
+		severity := diagnostics.SeverityForStatus(overallHealth, len(report.Issues))
+		if rating == diagnostics.SeverityCritical {
+			severity = diagnostics.SeverityCritical
+		}
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.MaxSeverity(severity, checks.Severity))
 	},
 }
 
 func init() {
-	podCmd.Flags().StringP("namespace", "n", "default", "Namespace")
+	utils.AddNamespaceFlags(podCmd.Flags())
+	podCmd.Flags().StringP("filename", "f", "", "Analyze the pods in a manifest file, or - for stdin, instead of the cluster")
+	podCmd.Flags().Int("concurrency", defaultConcurrency, "Number of pods analyzed in parallel when analyzing many at once")
+	utils.AddFilterFlags(podCmd.Flags())
 	podCmd.Flags().BoolP("verbose", "v", false, "Verbose output")
+	podCmd.Flags().StringP("output", "o", "text", "Output format (text, compact, markdown)")
+	podCmd.Flags().StringP("selector", "l", "", "Analyze all pods matching this label selector, e.g. app=payments")
+	podCmd.Flags().String("field-selector", "", "Analyze all pods matching this field selector, e.g. status.phase=Pending or spec.nodeName=worker-3")
+	podCmd.Flags().Bool("all-contexts", false, "Analyze the pod in every kubeconfig context and report how it differs")
 }
@@ -0,0 +1,168 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var pvCmd = &cobra.Command{
+	Use:   "pv [name]",
+	Short: "Analyze PersistentVolumes",
+	Long: `Analyze a PersistentVolume, or every volume with --all: its phase, reclaim policy,
+claim and storage class.
+
+Released volumes whose claim was deleted keep their storage, and with the Retain policy
+they keep it until someone deletes them by hand, so they are reported along with their
+total capacity.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		if all && len(args) > 0 {
+			return fmt.Errorf("a volume name cannot be combined with --all")
+		}
+		if !all && len(args) != 1 {
+			return fmt.Errorf("requires a volume name, or --all")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		analyzer := diagnostics.NewPVAnalyzer(client)
+
+		if all {
+			utils.PrintInfo("Analyzing all persistent volumes")
+			summary, err := analyzer.AnalyzeAll(cmd.Context())
+			if err != nil {
+				utils.PrintError("Error analyzing persistent volumes: %v", err)
+				os.Exit(1)
+			}
+			printPVSummary(summary)
+
+			severity := diagnostics.SeverityHealthy
+			for _, report := range summary.Volumes {
+				diagnostics.RecordIssues("PersistentVolume", "", report.Name, report.Analysis.Status, report.Analysis.Issues)
+				severity = diagnostics.MaxSeverity(severity,
+					diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)))
+			}
+			utils.ExitOnSeverity(cmd.Flags(), severity)
+			return
+		}
+
+		utils.PrintInfo("Starting persistent volume analysis for: %s", args[0])
+		report, err := analyzer.Analyze(cmd.Context(), args[0])
+		if err != nil {
+			utils.PrintError("Error analyzing persistent volume: %v", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("K8s Lens Analysis Report For PersistentVolume: %s\n", report.Name)
+		fmt.Println("---")
+		fmt.Printf("Phase: %s\n", report.Phase)
+		fmt.Printf("Capacity: %s\n", report.Capacity.String())
+		fmt.Printf("Reclaim Policy: %s\n", report.ReclaimPolicy)
+		fmt.Printf("Storage Class: %s\n", valueOrNone(report.StorageClass))
+		fmt.Printf("Access Modes: %s\n", formatAccessModes(report.AccessModes))
+		fmt.Printf("Claim: %s\n", valueOrNone(report.Claim))
+		fmt.Printf("Status: %s\n", report.Analysis.Status)
+
+		if len(report.Analysis.Issues) > 0 {
+			utils.PrintSection("Issues")
+			for _, issue := range report.Analysis.Issues {
+				utils.PrintWarning("- %s", issue)
+			}
+		}
+
+		if len(report.Analysis.Recommendations) > 0 {
+			utils.PrintSection("Recommendations")
+			for _, rec := range report.Analysis.Recommendations {
+				utils.PrintInfo("- %s", rec)
+			}
+		}
+
+		diagnostics.RecordIssues("PersistentVolume", "", report.Name, report.Analysis.Status, report.Analysis.Issues)
+		utils.ExitOnSeverity(cmd.Flags(),
+			diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)))
+	},
+}
+
+func init() {
+	pvCmd.Flags().Bool("all", false, "Analyze every PersistentVolume in the cluster")
+}
+
+func printPVSummary(summary *diagnostics.PVSummary) {
+	fmt.Println("K8s Lens PersistentVolume Report")
+	fmt.Println("---")
+
+	if len(summary.Volumes) == 0 {
+		utils.PrintInfo("No persistent volumes found")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCAPACITY\tPHASE\tRECLAIM\tCLAIM\tSTORAGECLASS\tSTATUS")
+	for _, report := range summary.Volumes {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			report.Name, report.Capacity.String(), report.Phase, report.ReclaimPolicy,
+			valueOrNone(report.Claim), valueOrNone(report.StorageClass), report.Analysis.Status)
+	}
+	w.Flush()
+
+	var flagged []*diagnostics.PVReport
+	for _, report := range summary.Volumes {
+		if len(report.Analysis.Issues) > 0 {
+			flagged = append(flagged, report)
+		}
+	}
+	if len(flagged) > 0 {
+		utils.PrintSection("Issues")
+		for _, report := range flagged {
+			fmt.Printf("%s:\n", report.Name)
+			for _, issue := range report.Analysis.Issues {
+				utils.PrintWarning("  - %s", issue)
+			}
+			for _, rec := range report.Analysis.Recommendations {
+				utils.PrintInfo("  - %s", rec)
+			}
+		}
+	}
+
+	utils.PrintSection("Summary")
+	fmt.Printf("Volumes: %d (Bound: %d, Available: %d, Released: %d, Failed: %d)\n",
+		len(summary.Volumes), summary.Phases[corev1.VolumeBound], summary.Phases[corev1.VolumeAvailable],
+		summary.Phases[corev1.VolumeReleased], summary.Phases[corev1.VolumeFailed])
+	if summary.StrandedBytes > 0 {
+		utils.PrintWarning("Stranded Storage: %s in Released volumes", formatMemory(summary.StrandedBytes))
+	}
+}
+
+func formatAccessModes(modes []corev1.PersistentVolumeAccessMode) string {
+	if len(modes) == 0 {
+		return "none"
+	}
+	names := make([]string, len(modes))
+	for i, mode := range modes {
+		names[i] = string(mode)
+	}
+	return strings.Join(names, ", ")
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
@@ -0,0 +1,107 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+var replicasetCmd = &cobra.Command{
+	Use:     "replicaset [name]",
+	Aliases: []string{"rs"},
+	Short:   "Analyze a Kubernetes ReplicaSet",
+	Long: `Analyze a Kubernetes ReplicaSet: its desired, current, ready and available
+replicas, the Deployment that owns it and which revision it is, and the probes and
+resources of its pod template.
+
+Useful to inspect a specific revision during a rollout. A standalone ReplicaSet, one
+no Deployment owns, is flagged since it gets no rolling updates or rollbacks.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, _ := cmd.Flags().GetString("namespace")
+		verbose, _ := cmd.Flags().GetBool("verbose")
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			fmt.Printf("Error creating Kubernetes client: %v\n", err)
+			os.Exit(1)
+		}
+
+		analyzer := diagnostics.NewReplicaSetAnalyzer(client, namespace)
+		report, err := analyzer.Analyze(cmd.Context(), args[0])
+		if err != nil {
+			fmt.Printf("Error analyzing replicaset: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Print the report
+		fmt.Printf("K8s Lens Analysis Report For ReplicaSet: %s\n", report.Name)
+		fmt.Println("---")
+		fmt.Printf("Namespace: %s\n", report.Namespace)
+		if report.Owner != nil {
+			fmt.Printf("Owner: %s %s\n", report.Owner.Kind, report.Owner.Name)
+		} else {
+			fmt.Println("Owner: none (standalone)")
+		}
+		if report.Revision != "" {
+			if report.DeploymentRevision != "" && report.DeploymentRevision != report.Revision {
+				fmt.Printf("Revision: %s (deployment is at %s)\n", report.Revision, report.DeploymentRevision)
+			} else {
+				fmt.Printf("Revision: %s\n", report.Revision)
+			}
+		}
+		fmt.Printf("Desired Replicas: %d\n", report.DesiredReplicas)
+		fmt.Printf("Current Replicas: %d\n", report.CurrentReplicas)
+		fmt.Printf("Ready Replicas: %d\n", report.ReadyReplicas)
+		fmt.Printf("Available Replicas: %d\n", report.AvailableReplicas)
+		fmt.Printf("Status: %s\n", report.Analysis.Status)
+
+		if len(report.Probes) > 0 {
+			fmt.Println("Probes:")
+			for _, probe := range report.Probes {
+				fmt.Printf("  - %s/%s: %s %s (delay %ds, period %ds, timeout %ds)\n",
+					probe.Container, probe.Type, probe.Scheme, probe.Target,
+					probe.InitialDelaySeconds, probe.PeriodSeconds, probe.TimeoutSeconds)
+			}
+		}
+
+		if len(report.Analysis.Issues) > 0 {
+			fmt.Println("Issues:")
+			for _, issue := range report.Analysis.Issues {
+				fmt.Printf("  - %s\n", issue)
+			}
+		}
+
+		if len(report.Analysis.Recommendations) > 0 {
+			fmt.Println("Recommendations:")
+			for _, rec := range report.Analysis.Recommendations {
+				fmt.Printf("  - %s\n", rec)
+			}
+		}
+
+		if verbose {
+			fmt.Println("Conditions:")
+			for _, condition := range report.Conditions {
+				fmt.Printf("  - %s: %s (%s)\n", condition.Type, condition.Status, condition.Message)
+			}
+			fmt.Println("Recent Events:")
+			for _, event := range report.Events {
+				fmt.Printf("  - [%s] %s: %s\n", event.LastTimestamp.Format("15:04:05"), event.Reason, event.Message)
+			}
+		}
+
+		printExplanations(cmd, "replicaset", report.Name, report.Namespace, report.Analysis.Issues)
+
+		notifyIssues(cmd, "replicaset", report.Name, report.Namespace, report.Analysis.Status, report.Analysis.Issues)
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)))
+	},
+}
+
+func init() {
+	replicasetCmd.Flags().StringP("namespace", "n", "default", "Namespace of the replicaset")
+	replicasetCmd.Flags().BoolP("verbose", "v", false, "Verbose output")
+}
@@ -0,0 +1,114 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Audit which secrets are used and which references point at missing secrets",
+	Long: `Cross-reference the Secrets of a namespace with the pod volumes, envFrom and
+secretKeyRef variables, image pull secrets, ingress TLS and service accounts that use
+them. Secrets nothing uses are reported as cleanup candidates, and references to
+secrets that do not exist are flagged, since pods using them cannot start.
+
+Service account tokens, Helm release secrets and secrets owned by another resource
+are never reported as unused. Review each candidate before deleting it; applications
+can read secrets through the API, which the audit cannot see.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, _ := cmd.Flags().GetString("namespace")
+		all, _ := cmd.Flags().GetBool("all-namespaces")
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		namespaces, err := k8s.ResolveNamespaces(cmd.Context(), client, namespace, all)
+		if err != nil {
+			utils.PrintError("Error resolving namespaces: %v", err)
+			os.Exit(1)
+		}
+
+		severity := diagnostics.SeverityHealthy
+		unused, missing := 0, 0
+		for _, namespace := range namespaces {
+			utils.PrintInfo("Auditing secret usage in namespace: %s", namespace)
+			analyzer := diagnostics.NewSecretUsageAnalyzer(client, namespace)
+			report, err := analyzer.Analyze(cmd.Context())
+			if err != nil {
+				utils.PrintError("Error auditing secrets: %v", err)
+				os.Exit(1)
+			}
+			printSecretUsageReport(report)
+			unused += len(report.Unused)
+			missing += len(report.Missing)
+			notifyIssues(cmd, "namespace", namespace, namespace, report.Analysis.Status, report.Analysis.Issues)
+			severity = diagnostics.MaxSeverity(severity,
+				diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)))
+		}
+
+		if len(namespaces) > 1 {
+			utils.PrintSection("Summary")
+			fmt.Printf("Unused Secrets: %d, Missing Secrets: %d across %d namespaces\n", unused, missing, len(namespaces))
+		}
+		utils.ExitOnSeverity(cmd.Flags(), severity)
+	},
+}
+
+func init() {
+	utils.AddNamespaceFlags(secretsCmd.Flags())
+}
+
+func printSecretUsageReport(report *diagnostics.SecretUsageReport) {
+	fmt.Printf("K8s Lens Secret Usage Report: %s\n", report.Namespace)
+	fmt.Println("---")
+	fmt.Printf("Secrets: %d (%d unused)\n", len(report.Secrets), len(report.Unused))
+	fmt.Printf("Status: %s\n", report.Analysis.Status)
+
+	if len(report.Secrets) > 0 {
+		utils.PrintSection("Secrets")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tTYPE\tUSED BY")
+		for _, secret := range report.Secrets {
+			usedBy := "-"
+			switch {
+			case len(secret.UsedBy) > 0:
+				users := make([]string, 0, len(secret.UsedBy))
+				for _, reference := range secret.UsedBy {
+					users = append(users, reference.String())
+				}
+				usedBy = strings.Join(users, ", ")
+			case secret.Skipped:
+				usedBy = "(managed)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", secret.Name, secret.Type, usedBy)
+		}
+		w.Flush()
+	}
+
+	if len(report.Analysis.Issues) > 0 {
+		utils.PrintSection("Issues")
+		for _, issue := range report.Analysis.Issues {
+			utils.PrintWarning("- %s", issue)
+		}
+	}
+
+	if len(report.Analysis.Recommendations) > 0 {
+		utils.PrintSection("Recommendations")
+		for _, rec := range report.Analysis.Recommendations {
+			utils.PrintInfo("- %s", rec)
+		}
+	}
+	fmt.Println()
+}
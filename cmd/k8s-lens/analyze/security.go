@@ -6,6 +6,7 @@ import (
 
 	"github.com/abrarahmad1510/k8s-lens/internal/utils"
 	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations/notify"
 	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
 	"github.com/spf13/cobra"
 )
@@ -13,8 +14,10 @@ import (
 var securityCmd = &cobra.Command{
 	Use:   "security [pod-name]",
 	Short: "Analyze Pod Security",
-	Long:  `Perform security analysis of Kubernetes pods and containers.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Perform security analysis of Kubernetes pods and containers.
+Finding types listed in the pod's k8s-lens.io/ignore annotation, e.g.
+"WritableRootFilesystem,PrivilegeEscalationAllowed", are suppressed and only counted.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		namespace, _ := cmd.Flags().GetString("namespace")
 
@@ -27,7 +30,7 @@ var securityCmd = &cobra.Command{
 		}
 
 		analyzer := diagnostics.NewSecurityAnalyzer(k8sClient, namespace)
-		report, err := analyzer.AnalyzePodSecurity(args[0])
+		report, err := analyzer.AnalyzePodSecurity(cmd.Context(), args[0])
 		if err != nil {
 			utils.PrintError("Error analyzing pod security: %v", err)
 			os.Exit(1)
@@ -41,6 +44,9 @@ var securityCmd = &cobra.Command{
 		fmt.Printf("Security Status: %s\n", report.Analysis.Status)
 		fmt.Printf("Risk Level: %s\n", report.Analysis.RiskLevel)
 		fmt.Printf("Security Score: %d/100\n", report.Analysis.Score)
+		if report.Suppressed > 0 {
+			fmt.Printf("Suppressed Findings: %d (%s)\n", report.Suppressed, diagnostics.IgnoreAnnotation)
+		}
 
 		if len(report.Issues) > 0 {
 			utils.PrintSection("Security Issues")
@@ -97,6 +103,16 @@ var securityCmd = &cobra.Command{
 		} else {
 			utils.PrintError("Poor security posture - immediate action required")
 		}
+
+		summary := notify.NewSummary("pod-security", report.PodName, report.Namespace, report.Analysis.Status)
+		for _, issue := range report.Issues {
+			summary.AddIssue(issue.Level, fmt.Sprintf("[%s] %s", issue.Level, issue.Title))
+		}
+		for _, warning := range report.Warnings {
+			summary.AddIssue(warning.Level, fmt.Sprintf("[%s] %s", warning.Level, warning.Title))
+		}
+		sendNotification(cmd, summary)
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Issues)))
 	},
 }
 
@@ -0,0 +1,226 @@
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// defaultConcurrency is how many resources are analyzed in parallel when a command
+// analyzes many at once. Each analysis makes a few API calls, so this bounds the load
+// on the API server while keeping large namespaces fast.
+const defaultConcurrency = 10
+
+// nameOrSelectorArgs requires a resource name, unless a label or field selector is
+// given in which case no name is allowed, or a manifest file in which case it is optional
+func nameOrSelectorArgs(cmd *cobra.Command, args []string) error {
+	if file, _ := cmd.Flags().GetString("filename"); file != "" {
+		return cobra.MaximumNArgs(1)(cmd, args)
+	}
+	selector, _ := cmd.Flags().GetString("selector")
+	fieldSelector, _ := cmd.Flags().GetString("field-selector")
+	if selector != "" || fieldSelector != "" {
+		if len(args) > 0 {
+			return fmt.Errorf("a resource name cannot be combined with --selector or --field-selector")
+		}
+		return nil
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+// analyzeSelector analyzes every resource of the given type matching the label
+// selector and --field-selector, and prints a per-resource breakdown with an
+// aggregate summary
+func analyzeSelector(cmd *cobra.Command, resourceType, namespace, labelSelector string) {
+	fieldSelector, _ := cmd.Flags().GetString("field-selector")
+	selector := describeSelectors(labelSelector, fieldSelector)
+	utils.PrintInfo("Starting %s analysis for selector: %s in namespace: %s", resourceType, selector, namespace)
+
+	client, err := k8s.NewClient()
+	if err != nil {
+		utils.PrintError("Error creating Kubernetes client: %v", err)
+		os.Exit(1)
+	}
+
+	resources, err := listResources(cmd.Context(), client, resourceType, namespace, metav1.ListOptions{
+		LabelSelector: labelSelector,
+		FieldSelector: fieldSelector,
+	})
+	if err != nil {
+		utils.PrintError("Error listing %ss: %v", resourceType, err)
+		os.Exit(1)
+	}
+	resources = filterResources(cmd, resources)
+	if len(resources) == 0 {
+		utils.PrintWarning("No %ss match selector %s in namespace %s", resourceType, selector, namespace)
+		return
+	}
+
+	analyzeResources(cmd, client, fmt.Sprintf("K8s Lens Analysis Report For Selector: %s", selector), resources)
+}
+
+// describeSelectors joins a label and a field selector for messages, e.g.
+// "app=web,status.phase=Pending"
+func describeSelectors(labelSelector, fieldSelector string) string {
+	if labelSelector == "" || fieldSelector == "" {
+		return labelSelector + fieldSelector
+	}
+	return labelSelector + "," + fieldSelector
+}
+
+// analyzeResources analyzes a list of resources and prints a per-resource breakdown with
+// an aggregate summary, one compact line each with --output compact, or a markdown
+// document with --output markdown
+func analyzeResources(cmd *cobra.Command, client kubernetes.Interface, title string, resources []batchResource) {
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	results := analyzeBatchResources(cmd.Context(), client, resources, concurrency)
+
+	switch output, _ := cmd.Flags().GetString("output"); output {
+	case "compact":
+		printCompactResults(results)
+	case "markdown":
+		printMarkdownResults(title, results)
+	default:
+		printBatchResults(title, results)
+	}
+
+	recordResults(results)
+	severity := diagnostics.SeverityHealthy
+	for _, result := range results {
+		if len(result.Issues) > 0 {
+			notifyIssues(cmd, result.Type, result.Name, result.Namespace, result.Status, result.Issues)
+		}
+		severity = diagnostics.MaxSeverity(severity, diagnostics.SeverityForStatus(result.Status, len(result.Issues)))
+	}
+	utils.ExitOnSeverity(cmd.Flags(), severity)
+}
+
+// outputArgs validates the --output flag of commands printing full reports, compact
+// lines or markdown
+func outputArgs(cmd *cobra.Command, args []string) error {
+	if output, _ := cmd.Flags().GetString("output"); output != "text" && output != "compact" && output != "markdown" {
+		return fmt.Errorf("unsupported output format: %s (supported: text, compact, markdown)", output)
+	}
+	return nameOrSelectorArgs(cmd, args)
+}
+
+// analyzeCompact analyzes a single resource and prints it as one compact line, or as a
+// markdown document with --output markdown
+func analyzeCompact(cmd *cobra.Command, resourceType, namespace, name string) {
+	client, err := k8s.NewClient()
+	if err != nil {
+		utils.PrintError("Error creating Kubernetes client: %v", err)
+		os.Exit(1)
+	}
+
+	result := analyzeBatchResource(cmd.Context(), client, batchResource{Type: resourceType, Name: name, Namespace: namespace}, nil)
+	if output, _ := cmd.Flags().GetString("output"); output == "markdown" {
+		printMarkdownResults(fmt.Sprintf("K8s Lens Analysis Report For %s: %s", strings.ToUpper(resourceType[:1])+resourceType[1:], name), []BatchResult{result})
+	} else {
+		printCompactResult(result)
+	}
+
+	if len(result.Issues) > 0 {
+		notifyIssues(cmd, resourceType, result.Name, result.Namespace, result.Status, result.Issues)
+	}
+	utils.ExitOnSeverity(cmd.Flags(), diagnostics.SeverityForStatus(result.Status, len(result.Issues)))
+}
+
+// filterResources drops the resources excluded by --include and --exclude, matching
+// label selectors against the labels of each resource
+func filterResources(cmd *cobra.Command, resources []batchResource) []batchResource {
+	filter, err := utils.ResourceFilter(cmd.Flags())
+	if err != nil {
+		utils.PrintError("%v", err)
+		os.Exit(1)
+	}
+	if filter.Empty() {
+		return resources
+	}
+
+	var kept []batchResource
+	for _, resource := range resources {
+		if filter.Matches(resource.Name, resource.Namespace, resource.Labels) {
+			kept = append(kept, resource)
+		}
+	}
+	if skipped := len(resources) - len(kept); skipped > 0 && !jsonOutput(cmd) {
+		utils.PrintInfo("Skipping %d resource(s) filtered out by --include/--exclude", skipped)
+	}
+	return kept
+}
+
+func jsonOutput(cmd *cobra.Command) bool {
+	output, _ := cmd.Flags().GetString("output")
+	return output == "json"
+}
+
+// listResources lists the resources of a type in a namespace matching the list options
+func listResources(ctx context.Context, client kubernetes.Interface, resourceType, namespace string, options metav1.ListOptions) ([]batchResource, error) {
+	var resources []batchResource
+	add := func(object metav1.Object) {
+		resources = append(resources, batchResource{Type: resourceType, Name: object.GetName(), Namespace: object.GetNamespace(), Labels: object.GetLabels()})
+	}
+
+	switch resourceType {
+	case "pod":
+		pods, err := client.CoreV1().Pods(namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pods.Items {
+			add(&pods.Items[i])
+		}
+	case "deployment":
+		deployments, err := client.AppsV1().Deployments(namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		for i := range deployments.Items {
+			add(&deployments.Items[i])
+		}
+	case "statefulset":
+		statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		for i := range statefulSets.Items {
+			add(&statefulSets.Items[i])
+		}
+	case "service":
+		services, err := client.CoreV1().Services(namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		for i := range services.Items {
+			add(&services.Items[i])
+		}
+	case "endpoint":
+		endpoints, err := client.CoreV1().Endpoints(namespace).List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		for i := range endpoints.Items {
+			add(&endpoints.Items[i])
+		}
+	case "node":
+		nodes, err := client.CoreV1().Nodes().List(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		for i := range nodes.Items {
+			add(&nodes.Items[i])
+		}
+	default:
+		return nil, fmt.Errorf("listing not supported for %s", resourceType)
+	}
+	return resources, nil
+}
@@ -3,24 +3,30 @@ package analyze
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/abrarahmad1510/k8s-lens/internal/utils"
 	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
 	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 )
 
 var serviceCmd = &cobra.Command{
 	Use:   "service [name]",
 	Short: "Analyze a Kubernetes Service",
-	Long:  `Analyze a Kubernetes Service and provide diagnostic information.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Analyze a Kubernetes Service and provide diagnostic information.
+Without a name, all services in the namespace are checked for selectors that overlap.
+With several namespaces or --all-namespaces, every service in them is analyzed instead.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		if utils.MultipleNamespaces(cmd.Flags()) {
+			analyzeNamespaces(cmd, "service", args)
+			return
+		}
 		namespace, _ := cmd.Flags().GetString("namespace")
 		verbose, _ := cmd.Flags().GetBool("verbose")
 
-		utils.PrintInfo("Starting service analysis for: %s in namespace: %s", args[0], namespace)
-
 		k8sClient, err := k8s.NewClient()
 		if err != nil {
 			utils.PrintError("Error creating Kubernetes client: %v", err)
@@ -28,12 +34,25 @@ var serviceCmd = &cobra.Command{
 		}
 
 		analyzer := diagnostics.NewServiceAnalyzer(k8sClient, namespace)
-		report, err := analyzer.Analyze(args[0])
+		resolve, _ := cmd.Flags().GetBool("resolve")
+		analyzer.SetDNSLookup(resolve)
+
+		if len(args) == 0 {
+			analyzeNamespaceServices(cmd, analyzer, namespace)
+			return
+		}
+
+		utils.PrintInfo("Starting service analysis for: %s in namespace: %s", args[0], namespace)
+		report, err := analyzer.Analyze(cmd.Context(), args[0])
 		if err != nil {
 			utils.PrintError("Error analyzing service: %v", err)
 			os.Exit(1)
 		}
 
+		checks := runCustomChecks(cmd, k8sClient, "service", namespace, args[0])
+		report.Analysis.Issues = append(report.Analysis.Issues, checks.Issues...)
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations, checks.Recommendations...)
+
 		// Print the report
 		fmt.Printf("K8s Lens Analysis Report For Service: %s\n", report.Name)
 		fmt.Println("---")
@@ -41,47 +60,38 @@ var serviceCmd = &cobra.Command{
 		utils.PrintSection("Service Configuration")
 		fmt.Printf("Namespace: %s\n", report.Namespace)
 		fmt.Printf("Type: %s\n", report.Type)
-		fmt.Printf("Cluster IP: %s\n", report.ClusterIP)
+		externalName := report.Type == corev1.ServiceTypeExternalName
+		if externalName {
+			fmt.Printf("External Name: %s\n", report.ExternalName)
+		} else if report.Headless {
+			fmt.Printf("Cluster IP: None (headless)\n")
+			if len(report.StatefulSets) > 0 {
+				fmt.Printf("StatefulSets: %s\n", strings.Join(report.StatefulSets, ", "))
+			}
+		} else {
+			fmt.Printf("Cluster IP: %s\n", report.ClusterIP)
+		}
 		if report.ExternalIP != "" {
 			fmt.Printf("External IP: %s\n", report.ExternalIP)
 		}
-
-		utils.PrintSection("Port Configuration")
-		if len(report.Ports) > 0 {
-			for _, port := range report.Ports {
-				fmt.Printf("- Port: %d/%s -> TargetPort: %v\n", port.Port, port.Protocol, port.TargetPort)
-			}
-		} else {
-			utils.PrintWarning("No ports configured")
+		if report.SessionAffinity != "" {
+			fmt.Printf("Session Affinity: %s\n", report.SessionAffinity)
+		}
+		if report.ExternalTrafficPolicy != "" {
+			fmt.Printf("External Traffic Policy: %s\n", report.ExternalTrafficPolicy)
 		}
 
-		utils.PrintSection("Selector")
-		if len(report.Selector) > 0 {
-			for key, value := range report.Selector {
-				fmt.Printf("- %s: %s\n", key, value)
-			}
-		} else {
-			utils.PrintWarning("No selector configured")
+		// ExternalName services are DNS aliases, so ports, selector and endpoints only
+		// matter when they are set by mistake, which the issues below report
+		if !externalName {
+			printServiceRouting(report)
 		}
 
-		utils.PrintSection("Endpoints Analysis")
-		if report.Endpoints != nil {
-			totalAddresses := 0
-			for _, subset := range report.Endpoints.Subsets {
-				totalAddresses += len(subset.Addresses)
+		if len(report.TrafficAdvisories) > 0 {
+			utils.PrintSection("Traffic Policy")
+			for _, advisory := range report.TrafficAdvisories {
+				utils.PrintWarning("%s", advisory)
 			}
-			if totalAddresses > 0 {
-				utils.PrintSuccess("Active endpoints: %d", totalAddresses)
-				for _, subset := range report.Endpoints.Subsets {
-					for _, address := range subset.Addresses {
-						fmt.Printf("- %s\n", address.IP)
-					}
-				}
-			} else {
-				utils.PrintWarning("No active endpoints found")
-			}
-		} else {
-			utils.PrintWarning("No endpoints found")
 		}
 
 		utils.PrintSection("Status")
@@ -115,10 +125,111 @@ var serviceCmd = &cobra.Command{
 				fmt.Println("  No recent events")
 			}
 		}
+
+		printExplanations(cmd, "service", report.Name, report.Namespace, report.Analysis.Issues)
+
+		notifyIssues(cmd, "service", report.Name, report.Namespace, report.Analysis.Status, report.Analysis.Issues)
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.MaxSeverity(
+			diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)), checks.Severity))
 	},
 }
 
 func init() {
-	serviceCmd.Flags().StringP("namespace", "n", "default", "Namespace")
+	utils.AddNamespaceFlags(serviceCmd.Flags())
+	serviceCmd.Flags().Int("concurrency", defaultConcurrency, "Number of services analyzed in parallel when analyzing many at once")
+	utils.AddFilterFlags(serviceCmd.Flags())
 	serviceCmd.Flags().BoolP("verbose", "v", false, "Verbose output")
+	serviceCmd.Flags().Bool("resolve", false, "Resolve the target of ExternalName services from this machine")
+}
+
+func printServiceRouting(report *diagnostics.ServiceReport) {
+	utils.PrintSection("Port Configuration")
+	if len(report.Ports) > 0 {
+		for _, port := range report.Ports {
+			fmt.Printf("- Port: %d/%s -> TargetPort: %v\n", port.Port, port.Protocol, port.TargetPort)
+		}
+	} else {
+		utils.PrintWarning("No ports configured")
+	}
+
+	utils.PrintSection("Selector")
+	if len(report.Selector) > 0 {
+		for key, value := range report.Selector {
+			fmt.Printf("- %s: %s\n", key, value)
+		}
+	} else {
+		utils.PrintWarning("No selector configured")
+	}
+
+	utils.PrintSection("Endpoints Analysis")
+	if report.Endpoints != nil {
+		totalAddresses := 0
+		for _, subset := range report.Endpoints.Subsets {
+			totalAddresses += len(subset.Addresses)
+		}
+		if totalAddresses > 0 {
+			utils.PrintSuccess("Active endpoints: %d", totalAddresses)
+			for _, subset := range report.Endpoints.Subsets {
+				for _, address := range subset.Addresses {
+					fmt.Printf("- %s\n", address.IP)
+				}
+			}
+		} else {
+			utils.PrintWarning("No active endpoints found")
+		}
+	} else {
+		utils.PrintWarning("No endpoints found")
+	}
+}
+
+func analyzeNamespaceServices(cmd *cobra.Command, analyzer *diagnostics.ServiceAnalyzer, namespace string) {
+	utils.PrintInfo("Checking services for overlapping selectors in namespace: %s", namespace)
+	report, err := analyzer.AnalyzeNamespaceServices(cmd.Context())
+	if err != nil {
+		utils.PrintError("Error analyzing services: %v", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("K8s Lens Service Overlap Analysis - Namespace: %s\n", report.Namespace)
+	fmt.Println("---")
+
+	utils.PrintSection("Namespace Overview")
+	fmt.Printf("Total Services: %d\n", report.TotalServices)
+	fmt.Printf("Status: %s\n", report.Analysis.Status)
+
+	if len(report.SameSelection) > 0 || len(report.PartialOverlaps) > 0 {
+		utils.PrintSection("Overlapping Services")
+		for _, overlap := range report.SameSelection {
+			utils.PrintWarning("%s", strings.Join(overlap.Services, ", "))
+			if overlap.IdenticalSelectors {
+				fmt.Println("  Identical selectors")
+			}
+			fmt.Printf("  Pods: %s\n", strings.Join(overlap.Pods, ", "))
+		}
+		for _, overlap := range report.PartialOverlaps {
+			utils.PrintWarning("%s (partial overlap)", strings.Join(overlap.Services, ", "))
+			fmt.Printf("  Shared pods: %s\n", strings.Join(overlap.Pods, ", "))
+		}
+	}
+
+	if len(report.MixedApps) > 0 {
+		utils.PrintSection("Services Spanning Multiple Apps")
+		for _, mixed := range report.MixedApps {
+			utils.PrintWarning("%s selects apps: %s", mixed.Service, strings.Join(mixed.Apps, ", "))
+		}
+	}
+
+	if len(report.Analysis.Issues) == 0 {
+		utils.PrintSuccess("No overlapping service selectors found")
+	}
+
+	if len(report.Analysis.Recommendations) > 0 {
+		utils.PrintSection("Recommendations")
+		for _, rec := range report.Analysis.Recommendations {
+			utils.PrintInfo("- %s", rec)
+		}
+	}
+
+	notifyIssues(cmd, "services", report.Namespace, report.Namespace, report.Analysis.Status, report.Analysis.Issues)
+	utils.ExitOnSeverity(cmd.Flags(), diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)))
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
 	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
 	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
 	"github.com/spf13/cobra"
@@ -13,8 +14,16 @@ var statefulsetCmd = &cobra.Command{
 	Use:   "statefulset [name]",
 	Short: "Analyze a Kubernetes StatefulSet",
 	Long:  `Analyze a Kubernetes StatefulSet and provide diagnostic information.`,
-	Args:  cobra.ExactArgs(1),
+	Args:  nameOrSelectorArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		if file, _ := cmd.Flags().GetString("filename"); file != "" {
+			analyzeManifestFile(cmd, "StatefulSet", args)
+			return
+		}
+		if utils.MultipleNamespaces(cmd.Flags()) {
+			analyzeNamespaces(cmd, "statefulset", args)
+			return
+		}
 		namespace, _ := cmd.Flags().GetString("namespace")
 		verbose, _ := cmd.Flags().GetBool("verbose")
 
@@ -25,12 +34,16 @@ var statefulsetCmd = &cobra.Command{
 		}
 
 		analyzer := diagnostics.NewStatefulSetAnalyzer(client, namespace)
-		report, err := analyzer.Analyze(args[0])
+		report, err := analyzer.Analyze(cmd.Context(), args[0])
 		if err != nil {
 			fmt.Printf("Error analyzing statefulset: %v\n", err)
 			os.Exit(1)
 		}
 
+		checks := runCustomChecks(cmd, client, "statefulset", namespace, args[0])
+		report.Analysis.Issues = append(report.Analysis.Issues, checks.Issues...)
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations, checks.Recommendations...)
+
 		// Print the report
 		fmt.Printf("K8s Lens Analysis Report For StatefulSet: %s\n", report.Name)
 		fmt.Println("---")
@@ -41,6 +54,9 @@ var statefulsetCmd = &cobra.Command{
 		fmt.Printf("Updated Replicas: %d\n", report.UpdatedReplicas)
 		fmt.Printf("Status: %s\n", report.Analysis.Status)
 		fmt.Printf("Update Strategy: %s\n", report.Analysis.UpdateStrategy)
+		if report.GitOps != nil {
+			fmt.Printf("Managed By: %s (change it in Git, not by hand)\n", report.GitOps)
+		}
 
 		if len(report.Analysis.Issues) > 0 {
 			fmt.Println("Issues:")
@@ -66,10 +82,21 @@ var statefulsetCmd = &cobra.Command{
 				fmt.Printf("  - [%s] %s: %s\n", event.LastTimestamp.Format("15:04:05"), event.Reason, event.Message)
 			}
 		}
+
+		printExplanations(cmd, "statefulset", report.Name, report.Namespace, report.Analysis.Issues)
+
+		printActiveAlerts(cmd, workloadAlertLabels(cmd, client, "statefulset", report.Namespace, report.Name)...)
+
+		notifyIssues(cmd, "statefulset", report.Name, report.Namespace, report.Analysis.Status, report.Analysis.Issues)
+		utils.ExitOnSeverity(cmd.Flags(), diagnostics.MaxSeverity(
+			diagnostics.SeverityForStatus(report.Analysis.Status, len(report.Analysis.Issues)), checks.Severity))
 	},
 }
 
 func init() {
-	statefulsetCmd.Flags().StringP("namespace", "n", "default", "Namespace")
+	utils.AddNamespaceFlags(statefulsetCmd.Flags())
+	statefulsetCmd.Flags().StringP("filename", "f", "", "Analyze the statefulsets in a manifest file, or - for stdin, instead of the cluster")
+	statefulsetCmd.Flags().Int("concurrency", defaultConcurrency, "Number of statefulsets analyzed in parallel when analyzing many at once")
+	utils.AddFilterFlags(statefulsetCmd.Flags())
 	statefulsetCmd.Flags().BoolP("verbose", "v", false, "Verbose output")
 }
@@ -3,6 +3,7 @@ package automation
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/abrarahmad1510/k8s-lens/internal/utils"
 	"github.com/abrarahmad1510/k8s-lens/pkg/automation"
@@ -22,6 +23,21 @@ func init() {
 	
 	remediateCmd.AddCommand(podRemediateCmd)
 
+	deploymentRemediateCmd := &cobra.Command{
+		Use:   "deployment [deployment-name] [issue-type]",
+		Short: "Remediate deployment issues automatically",
+		Long: `Remediate deployment issues by patching the deployment itself, so the fix
+applies to every pod it manages. Supported issue types: MissingResourceLimits.`,
+		Args:  cobra.ExactArgs(2),
+		Run:   remediateDeployment,
+	}
+	deploymentRemediateCmd.Flags().StringP("namespace", "n", "default", "Namespace of the deployment")
+	deploymentRemediateCmd.Flags().Bool("dry-run", false, "Validate the patch with the API server without applying it")
+
+	remediateCmd.AddCommand(deploymentRemediateCmd)
+
+	remediateCmd.PersistentFlags().String("backup-dir", "k8s-lens-backups", "Directory to save resources to before remediating them (empty disables backups)")
+
 	remediateCmd.AddCommand(&cobra.Command{
 		Use:   "list-actions",
 		Short: "List available remediation actions",
@@ -33,6 +49,7 @@ func remediatePod(cmd *cobra.Command, args []string) {
 	podName := args[0]
 	issueType := args[1]
 	namespace, _ := cmd.Flags().GetString("namespace")
+	backupDir, _ := cmd.Flags().GetString("backup-dir")
 
 	utils.PrintInfo("Attempting automated remediation for pod %s (issue: %s) in namespace %s", podName, issueType, namespace)
 	
@@ -42,18 +59,59 @@ func remediatePod(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Create automation engine and register remediators
+	// Create automation engine and register the remediators acting on pods
 	engine := automation.NewAutomationEngine(k8sClient)
-	engine.RegisterRemediator(remediators.NewPodRestartRemediator(k8sClient))
+	engine.SetBackupDir(backupDir)
+	remediators.RegisterAll(engine, k8sClient, false, "pod")
 
 	result, err := engine.AutoRemediate(cmd.Context(), issueType, podName, namespace)
+	printRemediationResult(result, err, "Remediation successful!")
+}
+
+func remediateDeployment(cmd *cobra.Command, args []string) {
+	deploymentName := args[0]
+	issueType := args[1]
+	namespace, _ := cmd.Flags().GetString("namespace")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	backupDir, _ := cmd.Flags().GetString("backup-dir")
+
+	utils.PrintInfo("Attempting automated remediation for deployment %s (issue: %s) in namespace %s", deploymentName, issueType, namespace)
+
+	k8sClient, err := k8s.NewClient()
+	if err != nil {
+		utils.PrintError("Error creating Kubernetes client: %v", err)
+		os.Exit(1)
+	}
+
+	engine := automation.NewAutomationEngine(k8sClient)
+	if !dryRun {
+		engine.SetBackupDir(backupDir)
+	}
+	remediators.RegisterAll(engine, k8sClient, dryRun, "deployment")
+
+	result, err := engine.AutoRemediate(cmd.Context(), issueType, deploymentName, namespace)
+	successMessage := "Remediation successful!"
+	if dryRun {
+		successMessage = "Dry run successful - no changes were made"
+	}
+	printRemediationResult(result, err, successMessage)
+}
+
+// printRemediationResult prints the outcome of a remediation, including how to restore
+// the backup taken beforehand if the remediation failed
+func printRemediationResult(result *automation.RemediationResult, err error, successMessage string) {
+	if result != nil && result.BackupPath != "" {
+		fmt.Printf("Backup: %s\n", result.BackupPath)
+	}
+
 	if err != nil {
 		utils.PrintError("Remediation failed: %v", err)
+		printRestoreCommand(result)
 		os.Exit(1)
 	}
 
 	if result.Success {
-		utils.PrintSuccess("Remediation successful!")
+		utils.PrintSuccess("%s", successMessage)
 		fmt.Printf("Action: %s\n", result.Action)
 		fmt.Printf("Resource: %s\n", result.Resource)
 		fmt.Printf("Message: %s\n", result.Message)
@@ -61,26 +119,30 @@ func remediatePod(cmd *cobra.Command, args []string) {
 	} else {
 		utils.PrintWarning("Remediation attempted but didn't succeed")
 		fmt.Printf("Message: %s\n", result.Message)
+		printRestoreCommand(result)
+	}
+}
+
+func printRestoreCommand(result *automation.RemediationResult) {
+	if result == nil || result.BackupPath == "" {
+		return
 	}
+	utils.PrintInfo("To restore the previous state, run: %s", automation.RestoreCommand(result.BackupPath))
 }
 
 func listRemediationActions(cmd *cobra.Command, args []string) {
 	fmt.Printf("Available Remediation Actions\n")
 	fmt.Printf("=============================\n")
 	
-	k8sClient, err := k8s.NewClient()
-	if err != nil {
-		utils.PrintError("Error creating Kubernetes client: %v", err)
-		return
-	}
-
-	engine := automation.NewAutomationEngine(k8sClient)
-	engine.RegisterRemediator(remediators.NewPodRestartRemediator(k8sClient))
+	// Listing actions doesn't touch the cluster, so no client is needed
+	engine := automation.NewAutomationEngine(nil)
+	remediators.RegisterAll(engine, nil, false)
 
-	fmt.Printf("\nPod Remediation Actions:\n")
-	podRemediator := remediators.NewPodRestartRemediator(k8sClient)
-	for _, action := range podRemediator.GetRemediationActions() {
-		fmt.Printf("  • %s: %s (Risk: %s)\n", action.Type, action.Description, action.Risk)
-		fmt.Printf("    Command: %s\n", action.Command)
+	for _, remediator := range engine.Remediators() {
+		fmt.Printf("\nIssues: %s\n", strings.Join(remediator.GetSupportedIssues(), ", "))
+		for _, action := range remediator.GetRemediationActions() {
+			fmt.Printf("  • %s: %s (Risk: %s)\n", action.Type, action.Description, action.Risk)
+			fmt.Printf("    Command: %s\n", action.Command)
+		}
 	}
 }
@@ -0,0 +1,16 @@
+package debug
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// DebugCmd represents the debug command
+var DebugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Start interactive debug sessions",
+	Long:  `Start interactive debug sessions inside running workloads using ephemeral containers.`,
+}
+
+func init() {
+	DebugCmd.AddCommand(podCmd)
+}
@@ -0,0 +1,71 @@
+package debug
+
+import (
+	"context"
+	"os"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var podCmd = &cobra.Command{
+	Use:   "pod [name]",
+	Short: "Attach an ephemeral debug container to a running pod",
+	Long: `Add an ephemeral debug container to a running pod and attach a shell to it,
+similar to kubectl debug. Use --target to share the process namespace of one of
+the pod's containers so its processes are visible from the debug shell.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		podName := args[0]
+		namespace, _ := cmd.Flags().GetString("namespace")
+		image, _ := cmd.Flags().GetString("image")
+		target, _ := cmd.Flags().GetString("target")
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		utils.PrintInfo("Adding debug container (image: %s) to pod %s in namespace %s", image, podName, namespace)
+		container, err := client.AddDebugContainer(cmd.Context(), namespace, podName, image, target)
+		if err != nil {
+			utils.PrintError("Error starting debug container: %v", err)
+			os.Exit(1)
+		}
+		utils.PrintSuccess("Debug container %s is running", container)
+
+		tty := utils.IsTerminal(os.Stdin) && utils.IsTerminal(os.Stdout)
+		if tty {
+			utils.PrintInfo("Attaching to %s - press Enter if you don't see a prompt, exit the shell to end the session", container)
+		}
+
+		if err := attach(cmd.Context(), client, namespace, podName, container, tty); err != nil {
+			utils.PrintError("Error attaching to debug container: %v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// attach connects the terminal to the debug container, putting it in raw mode for
+// the duration of the session when it is interactive
+func attach(ctx context.Context, client *k8s.Client, namespace, podName, container string, tty bool) error {
+	if tty {
+		fd := int(os.Stdin.Fd())
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer term.Restore(fd, state)
+	}
+
+	return client.Attach(ctx, namespace, podName, container, os.Stdin, os.Stdout, os.Stderr, tty)
+}
+
+func init() {
+	podCmd.Flags().StringP("namespace", "n", "default", "Namespace")
+	podCmd.Flags().String("image", "busybox", "Image for the debug container")
+	podCmd.Flags().String("target", "", "Container whose process namespace the debug container joins")
+}
@@ -1,6 +1,13 @@
 package enterprise
 
-import "github.com/spf13/cobra"
+import (
+	"os"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
+)
 
 // EnterpriseCmd represents the enterprise command
 var EnterpriseCmd = &cobra.Command{
@@ -23,3 +30,40 @@ func init() {
 	EnterpriseCmd.AddCommand(rbacCmd)
 	EnterpriseCmd.AddCommand(securityCmd)
 }
+
+// namespacesFromArgs returns the namespaces named by the optional [namespace] argument,
+// which may be a comma-separated list, or every namespace with --all-namespaces,
+// scoped by --include and --exclude
+func namespacesFromArgs(cmd *cobra.Command, client kubernetes.Interface, args []string) []string {
+	namespace := "default"
+	if len(args) > 0 {
+		namespace = args[0]
+	}
+	all, _ := cmd.Flags().GetBool("all-namespaces")
+
+	namespaces, err := k8s.ResolveNamespaces(cmd.Context(), client, namespace, all)
+	if err != nil {
+		utils.PrintError("Error resolving namespaces: %v", err)
+		os.Exit(1)
+	}
+	return filterNamespaces(cmd, namespaces)
+}
+
+// filterNamespaces drops the namespaces excluded by the name globs of --include and --exclude
+func filterNamespaces(cmd *cobra.Command, namespaces []string) []string {
+	filter, err := utils.ResourceFilter(cmd.Flags())
+	if err != nil {
+		utils.PrintError("%v", err)
+		os.Exit(1)
+	}
+	namespaces, err = k8s.FilterNamespaces(namespaces, filter)
+	if err != nil {
+		utils.PrintError("%v", err)
+		os.Exit(1)
+	}
+	if len(namespaces) == 0 {
+		utils.PrintError("No namespace left after --include/--exclude")
+		os.Exit(1)
+	}
+	return namespaces
+}
@@ -0,0 +1,178 @@
+package enterprise
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/enterprise"
+)
+
+// markdownIssues formats security issues as bullet list items
+func markdownIssues(issues []enterprise.SecurityIssue) []string {
+	items := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		item := fmt.Sprintf("**[%s]** %s: %s", issue.Severity, issue.Type, issue.Description)
+		if issue.Resource != "" {
+			item += fmt.Sprintf(" (`%s`)", issue.Resource)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func printSecurityReportMarkdown(report *enterprise.SecurityScanReport) {
+	utils.MarkdownHeading(1, "K8s Lens Security Scan Report: %s", report.Namespace)
+
+	utils.MarkdownHeading(2, "Summary")
+	utils.MarkdownTable([]string{"Metric", "Value"}, [][]string{
+		{"Compliance Score", fmt.Sprintf("%d/100", report.ComplianceScore)},
+		{"Risk Level", report.RiskLevel},
+		{"Pods Scanned", fmt.Sprintf("%d", report.TotalPods)},
+		{"Services Scanned", fmt.Sprintf("%d", report.TotalServices)},
+		{"Security Issues", fmt.Sprintf("%d", len(report.SecurityIssues))},
+		{"Suppressed Issues", fmt.Sprintf("%d", len(report.SuppressedIssues))},
+	})
+
+	if len(report.SecurityIssues) > 0 {
+		utils.MarkdownHeading(2, "Security Issues")
+		utils.MarkdownList(markdownIssues(report.SecurityIssues))
+	}
+
+	if len(report.SuppressedIssues) > 0 {
+		counts := make(map[string]int)
+		for _, issue := range report.SuppressedIssues {
+			counts[issue.Type]++
+		}
+		var items []string
+		for issueType, count := range counts {
+			items = append(items, fmt.Sprintf("%s: %d", issueType, count))
+		}
+		sort.Strings(items)
+		utils.MarkdownHeading(2, "Suppressed Issues (%s)", diagnostics.IgnoreAnnotation)
+		utils.MarkdownList(items)
+	}
+
+	if len(report.Recommendations) > 0 {
+		utils.MarkdownHeading(2, "Recommendations")
+		utils.MarkdownList(report.Recommendations)
+	}
+}
+
+// printCombinedSecurityReportMarkdown prints the scan results of several namespaces as
+// a markdown table, followed by the totals
+func printCombinedSecurityReportMarkdown(reports []*enterprise.SecurityScanReport) {
+	utils.MarkdownHeading(1, "K8s Lens Combined Security Scan Report")
+
+	var rows [][]string
+	totalPods, totalIssues := 0, 0
+	for _, report := range reports {
+		rows = append(rows, []string{report.Namespace, fmt.Sprintf("%d/100", report.ComplianceScore), report.RiskLevel,
+			fmt.Sprintf("%d", report.TotalPods), fmt.Sprintf("%d", len(report.SecurityIssues)), fmt.Sprintf("%d", len(report.SuppressedIssues))})
+		totalPods += report.TotalPods
+		totalIssues += len(report.SecurityIssues)
+	}
+	utils.MarkdownTable([]string{"Namespace", "Score", "Risk", "Pods", "Issues", "Suppressed"}, rows)
+	fmt.Printf("%d namespace(s) scanned: %d pods, %d security issues, combined compliance score %d/100\n",
+		len(reports), totalPods, totalIssues, enterprise.WeightedComplianceScore(reports))
+}
+
+func printRBACReportMarkdown(report *enterprise.RBACReport) {
+	utils.MarkdownHeading(1, "K8s Lens RBAC Security Analysis Report: %s", report.Namespace)
+
+	utils.MarkdownHeading(2, "Summary")
+	utils.MarkdownTable([]string{"Metric", "Value"}, [][]string{
+		{"Risk Level", report.RiskLevel},
+		{"Cluster Roles", fmt.Sprintf("%d", report.ClusterRoles)},
+		{"Roles", fmt.Sprintf("%d", report.Roles)},
+		{"Cluster Role Bindings", fmt.Sprintf("%d", report.ClusterRoleBindings)},
+		{"Role Bindings", fmt.Sprintf("%d", report.RoleBindings)},
+		{"Service Accounts", fmt.Sprintf("%d", report.ServiceAccounts)},
+		{"Security Issues", fmt.Sprintf("%d", len(report.SecurityIssues))},
+	})
+
+	if len(report.ServiceAccountUsage) > 0 {
+		utils.MarkdownHeading(2, "Service Account Usage")
+		var items []string
+		for _, sa := range report.ServiceAccountUsage {
+			item := fmt.Sprintf("`%s`: %d pods, %d bindings", sa.Name, sa.Pods, len(sa.Bindings))
+			if sa.Unused() {
+				item = fmt.Sprintf("`%s`: unused", sa.Name)
+			}
+			if len(sa.Bindings) > 0 {
+				item += " (" + strings.Join(sa.Bindings, ", ") + ")"
+			}
+			if len(sa.LegacyTokenSecrets) > 0 {
+				item += "; long-lived tokens: " + strings.Join(sa.LegacyTokenSecrets, ", ")
+			}
+			items = append(items, item)
+		}
+		utils.MarkdownList(items)
+	}
+
+	if len(report.SecurityIssues) > 0 {
+		utils.MarkdownHeading(2, "Security Issues")
+		utils.MarkdownList(markdownIssues(report.SecurityIssues))
+	}
+
+	if len(report.Recommendations) > 0 {
+		utils.MarkdownHeading(2, "Recommendations")
+		utils.MarkdownList(report.Recommendations)
+	}
+}
+
+// printCombinedRBACReportMarkdown prints the RBAC analysis of several namespaces as a
+// markdown table, followed by the totals
+func printCombinedRBACReportMarkdown(reports []*enterprise.RBACReport) {
+	utils.MarkdownHeading(1, "K8s Lens Combined RBAC Analysis Report")
+
+	var rows [][]string
+	totalIssues, unusedAccounts := 0, 0
+	for _, report := range reports {
+		rows = append(rows, []string{report.Namespace, report.RiskLevel, fmt.Sprintf("%d", report.Roles),
+			fmt.Sprintf("%d", report.RoleBindings), fmt.Sprintf("%d", report.ServiceAccounts), fmt.Sprintf("%d", len(report.SecurityIssues))})
+		totalIssues += len(report.SecurityIssues)
+		for _, sa := range report.ServiceAccountUsage {
+			if sa.Unused() {
+				unusedAccounts++
+			}
+		}
+	}
+	utils.MarkdownTable([]string{"Namespace", "Risk", "Roles", "Bindings", "Service Accounts", "Issues"}, rows)
+	fmt.Printf("%d namespace(s) analyzed: %d security issues, %d unused service accounts\n", len(reports), totalIssues, unusedAccounts)
+}
+
+func printPostureReportMarkdown(posture *enterprise.PostureReport) {
+	utils.MarkdownHeading(1, "K8s Lens Cluster Security Posture")
+
+	utils.MarkdownHeading(2, "Summary")
+	utils.MarkdownTable([]string{"Metric", "Value"}, [][]string{
+		{"Posture Score", fmt.Sprintf("%d/100", posture.Score)},
+		{"Risk Level", posture.RiskLevel},
+		{"Namespaces Scanned", fmt.Sprintf("%d", posture.Namespaces)},
+		{"Pods Scanned", fmt.Sprintf("%d", posture.TotalPods)},
+		{"Security Issues", fmt.Sprintf("%d", posture.Issues)},
+		{"Suppressed Issues", fmt.Sprintf("%d", posture.Suppressed)},
+	})
+
+	if len(posture.Worst) > 0 {
+		utils.MarkdownHeading(2, "Worst Offending Namespaces")
+		var items []string
+		for _, namespace := range posture.Worst {
+			items = append(items, fmt.Sprintf("`%s`: %d/100 (%s risk), %d issues across %d pods",
+				namespace.Namespace, namespace.Score, namespace.RiskLevel, namespace.Issues, namespace.Pods))
+		}
+		utils.MarkdownList(items)
+	}
+
+	if len(posture.TopIssues) > 0 {
+		utils.MarkdownHeading(2, "Top Recurring Issues")
+		var items []string
+		for _, issue := range posture.TopIssues {
+			items = append(items, fmt.Sprintf("%s: %d occurrences in %d namespace(s)", issue.Type, issue.Count, issue.Namespaces))
+		}
+		utils.MarkdownList(items)
+	}
+}
@@ -3,8 +3,11 @@ package enterprise
 import (
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
 	"github.com/abrarahmad1510/k8s-lens/pkg/enterprise"
 	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
 	"github.com/spf13/cobra"
@@ -12,12 +15,18 @@ import (
 
 func init() {
 	// Add analyze and report subcommands to rbacCmd
-	rbacCmd.AddCommand(&cobra.Command{
+	analyzeCmd := &cobra.Command{
 		Use:   "analyze [namespace]",
 		Short: "Analyze RBAC configuration",
-		Args:  cobra.RangeArgs(0, 1),
-		Run:   analyzeRBAC,
-	})
+		Long: `Analyze the roles, bindings and service accounts of a namespace. The namespace may
+be a comma-separated list, or use --all-namespaces; several namespaces are followed by a combined report.`,
+		Args: cobra.RangeArgs(0, 1),
+		Run:  analyzeRBAC,
+	}
+	analyzeCmd.Flags().BoolP("all-namespaces", "A", false, "Analyze every namespace")
+	analyzeCmd.Flags().StringP("output", "o", "text", "Output format (text, markdown)")
+	utils.AddFailOnFlag(analyzeCmd.Flags(), "warning")
+	rbacCmd.AddCommand(analyzeCmd)
 
 	rbacCmd.AddCommand(&cobra.Command{
 		Use:   "report [namespace]",
@@ -28,13 +37,13 @@ func init() {
 }
 
 func analyzeRBAC(cmd *cobra.Command, args []string) {
-	namespace := "default"
-	if len(args) > 0 {
-		namespace = args[0]
+	output, _ := cmd.Flags().GetString("output")
+	if output != "text" && output != "markdown" {
+		utils.PrintError("Unsupported output format: %s (supported: text, markdown)", output)
+		os.Exit(1)
 	}
+	markdown := output == "markdown"
 
-	utils.PrintInfo("Starting RBAC analysis for namespace: %s", namespace)
-	
 	k8sClient, err := k8s.NewClient()
 	if err != nil {
 		utils.PrintError("Error creating Kubernetes client: %v", err)
@@ -42,13 +51,41 @@ func analyzeRBAC(cmd *cobra.Command, args []string) {
 	}
 
 	analyzer := enterprise.NewRBACAnalyzer(k8sClient)
-	report, err := analyzer.AnalyzeNamespaceRBAC(namespace)
-	if err != nil {
-		utils.PrintError("Error analyzing RBAC: %v", err)
-		os.Exit(1)
+
+	namespaces := namespacesFromArgs(cmd, k8sClient, args)
+	severity := diagnostics.SeverityHealthy
+	var reports []*enterprise.RBACReport
+	for _, namespace := range namespaces {
+		if !markdown {
+			utils.PrintInfo("Starting RBAC analysis for namespace: %s", namespace)
+		}
+		report, err := analyzer.AnalyzeNamespaceRBAC(cmd.Context(), namespace)
+		if err != nil {
+			if len(namespaces) == 1 {
+				utils.PrintError("Error analyzing RBAC: %v", err)
+				os.Exit(1)
+			}
+			utils.PrintWarning("Skipping namespace %s: %v", namespace, err)
+			continue
+		}
+
+		if markdown {
+			printRBACReportMarkdown(report)
+		} else {
+			printRBACReport(report)
+		}
+		reports = append(reports, report)
+		severity = diagnostics.MaxSeverity(severity, diagnostics.SeverityForRiskLevel(report.RiskLevel))
 	}
 
-	printRBACReport(report)
+	if len(namespaces) > 1 {
+		if markdown {
+			printCombinedRBACReportMarkdown(reports)
+		} else {
+			printCombinedRBACReport(reports)
+		}
+	}
+	utils.ExitOnSeverity(cmd.Flags(), severity)
 }
 
 func generateRBACReport(cmd *cobra.Command, args []string) {
@@ -73,6 +110,23 @@ func printRBACReport(report *enterprise.RBACReport) {
 	fmt.Printf("  Role Bindings: %d\n", report.RoleBindings)
 	fmt.Printf("  Service Accounts: %d\n", report.ServiceAccounts)
 
+	if len(report.ServiceAccountUsage) > 0 {
+		fmt.Printf("\nService Account Usage:\n")
+		for _, sa := range report.ServiceAccountUsage {
+			status := fmt.Sprintf("%d pods, %d bindings", sa.Pods, len(sa.Bindings))
+			if sa.Unused() {
+				status = "unused"
+			}
+			fmt.Printf("  %s (%s)\n", sa.Name, status)
+			for _, binding := range sa.Bindings {
+				fmt.Printf("    - %s\n", binding)
+			}
+			if len(sa.LegacyTokenSecrets) > 0 {
+				fmt.Printf("    Long-lived tokens: %s\n", strings.Join(sa.LegacyTokenSecrets, ", "))
+			}
+		}
+	}
+
 	if len(report.SecurityIssues) > 0 {
 		fmt.Printf("\nSecurity Issues Found:\n")
 		for i, issue := range report.SecurityIssues {
@@ -87,3 +141,28 @@ func printRBACReport(report *enterprise.RBACReport) {
 		}
 	}
 }
+
+// printCombinedRBACReport prints the RBAC analysis of several namespaces side by side
+func printCombinedRBACReport(reports []*enterprise.RBACReport) {
+	fmt.Printf("\nK8s Lens Combined RBAC Analysis Report\n")
+	fmt.Printf("======================================\n")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tRISK\tROLES\tBINDINGS\tSERVICE ACCOUNTS\tISSUES")
+	totalIssues, unusedAccounts := 0, 0
+	for _, report := range reports {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\n", report.Namespace, report.RiskLevel,
+			report.Roles, report.RoleBindings, report.ServiceAccounts, len(report.SecurityIssues))
+		totalIssues += len(report.SecurityIssues)
+		for _, sa := range report.ServiceAccountUsage {
+			if sa.Unused() {
+				unusedAccounts++
+			}
+		}
+	}
+	w.Flush()
+
+	fmt.Printf("\nNamespaces Analyzed: %d\n", len(reports))
+	fmt.Printf("Total Security Issues: %d\n", totalIssues)
+	fmt.Printf("Unused Service Accounts: %d\n", unusedAccounts)
+}
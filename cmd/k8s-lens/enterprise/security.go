@@ -1,23 +1,84 @@
 package enterprise
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"text/tabwriter"
 
 	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
 	"github.com/abrarahmad1510/k8s-lens/pkg/enterprise"
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations/notify"
 	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	// Add scan and audit subcommands to securityCmd
-	securityCmd.AddCommand(&cobra.Command{
+	scanCmd := &cobra.Command{
 		Use:   "scan [namespace]",
 		Short: "Scan for security vulnerabilities",
-		Args:  cobra.RangeArgs(0, 1),
-		Run:   scanSecurity,
-	})
+		Long: `Scan a namespace for security vulnerabilities. The namespace may be a comma-separated
+list, or use --all-namespaces; several namespaces are followed by a combined report.
+
+Accepted risks can be acknowledged per object with the k8s-lens.io/ignore annotation,
+listing the issue types to suppress, e.g. "WritableRootFilesystem,LoadBalancerService" on
+a pod template or service, or "NoNetworkPolicies" on the namespace. Suppressed issues do
+not count toward the score and are reported separately.
+
+To adopt scanning on an existing cluster, save its current findings with
+--save-baseline and scan later with --baseline: only findings missing from the baseline
+are reported, along with the baseline findings that were resolved, and only new
+findings fail the scan. Findings are identified by type and resource, with pods
+replaced by their owning workload so restarts do not make them new.
+
+  k8s-lens enterprise security scan shop --save-baseline shop.baseline.json
+  k8s-lens enterprise security scan shop --baseline shop.baseline.json`,
+		Args: cobra.RangeArgs(0, 1),
+		Run:  scanSecurity,
+	}
+	scanCmd.Flags().BoolP("all-namespaces", "A", false, "Scan every namespace")
+	utils.AddNamespaceFilterFlags(scanCmd.Flags())
+	scanCmd.Flags().String("notify-webhook", "", "Post a summary of the findings to a Slack-compatible webhook URL")
+	scanCmd.Flags().String("notify-format", "slack", "Webhook payload format (slack, json)")
+	scanCmd.Flags().String("baseline", "", "Only report and fail on findings not in this saved baseline file")
+	scanCmd.Flags().String("save-baseline", "", "Save the findings to this file as a baseline for later scans")
+	scanCmd.Flags().StringP("output", "o", "text", "Output format (text, markdown)")
+	utils.AddFailOnFlag(scanCmd.Flags(), "warning")
+	securityCmd.AddCommand(scanCmd)
+
+	scoreCmd := &cobra.Command{
+		Use:   "score",
+		Short: "Compute a single security posture score for the whole cluster",
+		Long: `Scan every namespace and roll the results up into one cluster posture score from
+0 to 100: the compliance score of each namespace weighted by its number of pods. The
+worst offending namespaces and the most frequent issue types are listed below it.
+
+Use --output json to record the score over time, e.g. from a scheduled job.`,
+		Args: cobra.NoArgs,
+		Run:  scoreSecurityPosture,
+	}
+	scoreCmd.Flags().Int("top", 5, "Number of worst namespaces and issue types to list")
+	utils.AddNamespaceFilterFlags(scoreCmd.Flags())
+	scoreCmd.Flags().StringP("output", "o", "text", "Output format (text, json, markdown)")
+	utils.AddFailOnFlag(scoreCmd.Flags(), "warning")
+	securityCmd.AddCommand(scoreCmd)
+
+	pssCmd := &cobra.Command{
+		Use:   "pss [namespace]",
+		Short: "Evaluate pods against the Pod Security Standards",
+		Long: `Evaluate every pod in a namespace against the Kubernetes Pod Security Standards
+(privileged, baseline, restricted), report the highest level each pod satisfies and
+list the fields that block it from the next level up. The namespace may be a
+comma-separated list, or use --all-namespaces.`,
+		Args: cobra.RangeArgs(0, 1),
+		Run:  evaluatePodSecurityStandards,
+	}
+	pssCmd.Flags().BoolP("all-namespaces", "A", false, "Evaluate every namespace")
+	utils.AddNamespaceFilterFlags(pssCmd.Flags())
+	securityCmd.AddCommand(pssCmd)
 
 	securityCmd.AddCommand(&cobra.Command{
 		Use:   "audit [namespace]",
@@ -28,13 +89,13 @@ func init() {
 }
 
 func scanSecurity(cmd *cobra.Command, args []string) {
-	namespace := "default"
-	if len(args) > 0 {
-		namespace = args[0]
+	output, _ := cmd.Flags().GetString("output")
+	if output != "text" && output != "markdown" {
+		utils.PrintError("Unsupported output format: %s (supported: text, markdown)", output)
+		os.Exit(1)
 	}
+	markdown := output == "markdown"
 
-	utils.PrintInfo("Starting security scan for namespace: %s", namespace)
-	
 	k8sClient, err := k8s.NewClient()
 	if err != nil {
 		utils.PrintError("Error creating Kubernetes client: %v", err)
@@ -42,13 +103,164 @@ func scanSecurity(cmd *cobra.Command, args []string) {
 	}
 
 	scanner := enterprise.NewSecurityScanner(k8sClient)
-	report, err := scanner.ScanNamespace(namespace)
+
+	var baseline *enterprise.ScanBaseline
+	if baselineFile, _ := cmd.Flags().GetString("baseline"); baselineFile != "" {
+		data, err := os.ReadFile(baselineFile)
+		if err != nil {
+			utils.PrintError("Error reading baseline: %v", err)
+			os.Exit(1)
+		}
+		if err := json.Unmarshal(data, &baseline); err != nil || baseline == nil {
+			utils.PrintError("Error parsing baseline %s: %v", baselineFile, err)
+			os.Exit(1)
+		}
+		if markdown {
+			utils.PrintError("--baseline cannot be combined with --output markdown")
+			os.Exit(1)
+		}
+	}
+
+	namespaces := namespacesFromArgs(cmd, k8sClient, args)
+	severity := diagnostics.SeverityHealthy
+	var reports []*enterprise.SecurityScanReport
+	for _, namespace := range namespaces {
+		if !markdown {
+			utils.PrintInfo("Starting security scan for namespace: %s", namespace)
+		}
+		report, err := scanner.ScanNamespace(cmd.Context(), namespace)
+		if err != nil {
+			if len(namespaces) == 1 {
+				utils.PrintError("Error scanning security: %v", err)
+				os.Exit(1)
+			}
+			utils.PrintWarning("Skipping namespace %s: %v", namespace, err)
+			continue
+		}
+
+		if markdown {
+			printSecurityReportMarkdown(report)
+		} else if baseline == nil {
+			printSecurityReport(report)
+		}
+		notifyScanResults(cmd, report)
+		reports = append(reports, report)
+		severity = diagnostics.MaxSeverity(severity, diagnostics.SeverityForRiskLevel(report.RiskLevel))
+	}
+
+	if len(namespaces) > 1 {
+		if markdown {
+			printCombinedSecurityReportMarkdown(reports)
+		} else {
+			printCombinedSecurityReport(reports)
+		}
+	}
+
+	if saveFile, _ := cmd.Flags().GetString("save-baseline"); saveFile != "" {
+		saved := enterprise.NewScanBaseline(reports)
+		data, err := json.MarshalIndent(saved, "", "  ")
+		if err != nil {
+			utils.PrintError("Error encoding baseline: %v", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(saveFile, append(data, '\n'), 0644); err != nil {
+			utils.PrintError("Error writing baseline: %v", err)
+			os.Exit(1)
+		}
+		utils.PrintSuccess("Baseline of %d finding(s) saved to %s", len(saved.Findings), saveFile)
+	}
+
+	if baseline != nil {
+		comparison := enterprise.CompareBaseline(baseline, reports)
+		printBaselineComparison(baseline, comparison)
+		// Every new finding fails the scan, however low its severity
+		severity = diagnostics.SeverityHealthy
+		for _, finding := range comparison.New {
+			severity = diagnostics.MaxSeverity(severity,
+				diagnostics.MaxSeverity(diagnostics.SeverityWarning, diagnostics.SeverityForRiskLevel(finding.Severity)))
+		}
+	}
+	utils.ExitOnSeverity(cmd.Flags(), severity)
+}
+
+func scoreSecurityPosture(cmd *cobra.Command, args []string) {
+	output, _ := cmd.Flags().GetString("output")
+	if output != "text" && output != "json" && output != "markdown" {
+		utils.PrintError("Unsupported output format: %s (supported: text, json, markdown)", output)
+		os.Exit(1)
+	}
+	top, _ := cmd.Flags().GetInt("top")
+
+	k8sClient, err := k8s.NewClient()
+	if err != nil {
+		utils.PrintError("Error creating Kubernetes client: %v", err)
+		os.Exit(1)
+	}
+
+	namespaces, err := k8s.ResolveNamespaces(cmd.Context(), k8sClient, "", true)
+	if err != nil {
+		utils.PrintError("Error resolving namespaces: %v", err)
+		os.Exit(1)
+	}
+	namespaces = filterNamespaces(cmd, namespaces)
+
+	scanner := enterprise.NewSecurityScanner(k8sClient)
+
+	var reports []*enterprise.SecurityScanReport
+	for _, namespace := range namespaces {
+		report, err := scanner.ScanNamespace(cmd.Context(), namespace)
+		if err != nil {
+			utils.PrintWarning("Skipping namespace %s: %v", namespace, err)
+			continue
+		}
+		reports = append(reports, report)
+	}
+	if len(reports) == 0 {
+		utils.PrintError("No namespace could be scanned")
+		os.Exit(1)
+	}
+
+	posture := enterprise.NewPostureReport(reports, top)
+	switch output {
+	case "json":
+		data, err := json.MarshalIndent(posture, "", "  ")
+		if err != nil {
+			utils.PrintError("Error encoding posture score: %v", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	case "markdown":
+		printPostureReportMarkdown(posture)
+	default:
+		printPostureReport(posture)
+	}
+	utils.ExitOnSeverity(cmd.Flags(), diagnostics.SeverityForRiskLevel(posture.RiskLevel))
+}
+
+func evaluatePodSecurityStandards(cmd *cobra.Command, args []string) {
+	k8sClient, err := k8s.NewClient()
 	if err != nil {
-		utils.PrintError("Error scanning security: %v", err)
+		utils.PrintError("Error creating Kubernetes client: %v", err)
 		os.Exit(1)
 	}
 
-	printSecurityReport(report)
+	scanner := enterprise.NewSecurityScanner(k8sClient)
+
+	namespaces := namespacesFromArgs(cmd, k8sClient, args)
+	for _, namespace := range namespaces {
+		utils.PrintInfo("Evaluating Pod Security Standards for namespace: %s", namespace)
+		report, err := scanner.EvaluatePodSecurityStandards(cmd.Context(), namespace)
+		if err != nil {
+			if len(namespaces) == 1 {
+				utils.PrintError("Error evaluating Pod Security Standards: %v", err)
+				os.Exit(1)
+			}
+			utils.PrintWarning("Skipping namespace %s: %v", namespace, err)
+			continue
+		}
+
+		printPSSReport(report)
+	}
 }
 
 func runSecurityAudit(cmd *cobra.Command, args []string) {
@@ -60,6 +272,27 @@ func runSecurityAudit(cmd *cobra.Command, args []string) {
 	fmt.Printf("Comprehensive security audit for %s - Feature coming soon!\n", namespace)
 }
 
+func notifyScanResults(cmd *cobra.Command, report *enterprise.SecurityScanReport) {
+	webhookURL, _ := cmd.Flags().GetString("notify-webhook")
+	if webhookURL == "" {
+		return
+	}
+	format, _ := cmd.Flags().GetString("notify-format")
+
+	summary := notify.NewSummary("namespace", report.Namespace, report.Namespace,
+		fmt.Sprintf("Risk %s (score %d/100)", report.RiskLevel, report.ComplianceScore))
+	for _, issue := range report.SecurityIssues {
+		summary.AddIssue(issue.Severity, fmt.Sprintf("[%s] %s: %s", issue.Severity, issue.Resource, issue.Description))
+	}
+
+	notifier := notify.NewWebhookNotifier(webhookURL, format)
+	if err := notifier.Notify(summary); err != nil {
+		utils.PrintWarning("Failed to send webhook notification: %v", err)
+		return
+	}
+	utils.PrintSuccess("Scan summary sent to webhook")
+}
+
 func printSecurityReport(report *enterprise.SecurityScanReport) {
 	fmt.Printf("K8s Lens Security Scan Report\n")
 	fmt.Printf("=============================\n")
@@ -78,6 +311,22 @@ func printSecurityReport(report *enterprise.SecurityScanReport) {
 		}
 	}
 
+	if len(report.SuppressedIssues) > 0 {
+		counts := make(map[string]int)
+		var types []string
+		for _, issue := range report.SuppressedIssues {
+			if counts[issue.Type] == 0 {
+				types = append(types, issue.Type)
+			}
+			counts[issue.Type]++
+		}
+		sort.Strings(types)
+		fmt.Printf("\nSuppressed Issues (%s): %d\n", diagnostics.IgnoreAnnotation, len(report.SuppressedIssues))
+		for _, issueType := range types {
+			fmt.Printf("  %s: %d\n", issueType, counts[issueType])
+		}
+	}
+
 	if len(report.Recommendations) > 0 {
 		fmt.Printf("\nRecommendations:\n")
 		for i, rec := range report.Recommendations {
@@ -85,3 +334,119 @@ func printSecurityReport(report *enterprise.SecurityScanReport) {
 		}
 	}
 }
+
+// printBaselineComparison prints the findings that are not in the baseline and the
+// baseline findings that were resolved
+func printBaselineComparison(baseline *enterprise.ScanBaseline, comparison *enterprise.BaselineComparison) {
+	fmt.Printf("\nK8s Lens Security Scan Against Baseline\n")
+	fmt.Printf("=======================================\n")
+	fmt.Printf("Baseline: %d finding(s) from %s\n", len(baseline.Findings), baseline.CreatedAt.Format("2006-01-02 15:04:05"))
+
+	if len(comparison.New) == 0 {
+		utils.PrintSuccess("No new security issues since the baseline")
+	} else {
+		fmt.Printf("\nNew Security Issues: %d\n", len(comparison.New))
+		for i, finding := range comparison.New {
+			fmt.Printf("  %d. [%s] %s/%s %s: %s\n", i+1, finding.Severity, finding.Namespace, finding.Resource, finding.Type, finding.Description)
+		}
+	}
+
+	if len(comparison.Resolved) > 0 {
+		fmt.Printf("\nResolved Since Baseline: %d\n", len(comparison.Resolved))
+		for _, finding := range comparison.Resolved {
+			fmt.Printf("  - %s/%s %s\n", finding.Namespace, finding.Resource, finding.Type)
+		}
+		utils.PrintInfo("Save a new baseline with --save-baseline to stop tracking resolved findings")
+	}
+}
+
+// printCombinedSecurityReport prints the scan results of several namespaces side by side,
+// with a compliance score weighted by the number of pods in each namespace
+func printCombinedSecurityReport(reports []*enterprise.SecurityScanReport) {
+	fmt.Printf("\nK8s Lens Combined Security Scan Report\n")
+	fmt.Printf("======================================\n")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tSCORE\tRISK\tPODS\tISSUES\tSUPPRESSED")
+	totalPods, totalIssues := 0, 0
+	for _, report := range reports {
+		fmt.Fprintf(w, "%s\t%d/100\t%s\t%d\t%d\t%d\n",
+			report.Namespace, report.ComplianceScore, report.RiskLevel, report.TotalPods, len(report.SecurityIssues), len(report.SuppressedIssues))
+		totalPods += report.TotalPods
+		totalIssues += len(report.SecurityIssues)
+	}
+	w.Flush()
+
+	fmt.Printf("\nNamespaces Scanned: %d\n", len(reports))
+	fmt.Printf("Total Pods: %d\n", totalPods)
+	fmt.Printf("Total Security Issues: %d\n", totalIssues)
+	if len(reports) > 0 {
+		fmt.Printf("Combined Compliance Score: %d/100\n", enterprise.WeightedComplianceScore(reports))
+	}
+}
+
+// printPostureReport prints the cluster posture score with the worst namespaces and the
+// most frequent issue types
+func printPostureReport(posture *enterprise.PostureReport) {
+	fmt.Printf("K8s Lens Cluster Security Posture\n")
+	fmt.Printf("=================================\n")
+	fmt.Printf("Posture Score: %d/100\n", posture.Score)
+	fmt.Printf("Risk Level: %s\n", posture.RiskLevel)
+	fmt.Printf("Namespaces Scanned: %d\n", posture.Namespaces)
+	fmt.Printf("Pods Scanned: %d\n", posture.TotalPods)
+	fmt.Printf("Security Issues: %d (Critical %d, High %d, Medium %d, Low %d)\n", posture.Issues,
+		posture.Severities["Critical"], posture.Severities["High"], posture.Severities["Medium"], posture.Severities["Low"])
+	if posture.Suppressed > 0 {
+		fmt.Printf("Suppressed Issues: %d\n", posture.Suppressed)
+	}
+
+	if len(posture.Worst) > 0 {
+		utils.PrintSection("Worst Offending Namespaces")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAMESPACE\tSCORE\tRISK\tPODS\tISSUES")
+		for _, namespace := range posture.Worst {
+			fmt.Fprintf(w, "%s\t%d/100\t%s\t%d\t%d\n", namespace.Namespace, namespace.Score, namespace.RiskLevel, namespace.Pods, namespace.Issues)
+		}
+		w.Flush()
+	}
+
+	if len(posture.TopIssues) > 0 {
+		utils.PrintSection("Top Recurring Issues")
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tCOUNT\tNAMESPACES")
+		for _, issue := range posture.TopIssues {
+			fmt.Fprintf(w, "%s\t%d\t%d\n", issue.Type, issue.Count, issue.Namespaces)
+		}
+		w.Flush()
+	} else {
+		utils.PrintSuccess("No security issues found in the cluster")
+	}
+}
+
+func printPSSReport(report *enterprise.PSSReport) {
+	fmt.Printf("K8s Lens Pod Security Standards Report\n")
+	fmt.Printf("======================================\n")
+	fmt.Printf("Namespace: %s\n", report.Namespace)
+	if report.EnforcedLevel != "" {
+		fmt.Printf("Enforced Level: %s\n", report.EnforcedLevel)
+	} else {
+		fmt.Printf("Enforced Level: none (namespace has no pod-security.kubernetes.io/enforce label)\n")
+	}
+
+	fmt.Printf("\nPods By Highest Satisfied Level:\n")
+	fmt.Printf("  Restricted: %d\n", report.LevelCounts[enterprise.PSSRestricted])
+	fmt.Printf("  Baseline: %d\n", report.LevelCounts[enterprise.PSSBaseline])
+	fmt.Printf("  Privileged: %d\n", report.LevelCounts[enterprise.PSSPrivileged])
+
+	for _, pod := range report.Pods {
+		fmt.Printf("\nPod: %s\n", pod.Pod)
+		fmt.Printf("  Level: %s\n", pod.Level)
+		if len(pod.Violations) == 0 {
+			continue
+		}
+		fmt.Printf("  Blocking %s:\n", pod.Violations[0].Level)
+		for _, violation := range pod.Violations {
+			fmt.Printf("    - %s: %s\n", violation.Field, violation.Reason)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package health
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/cobra"
+)
+
+// HealthCmd represents the cluster health triage command
+var HealthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Triage the health of the whole cluster",
+	Long: `Run a fast cluster-wide triage: node readiness, unhealthy pods grouped by namespace
+and reason, pending PersistentVolumeClaims and recent Warning event hotspots.
+
+Exits with 0 when the cluster is healthy, 1 when there are warnings and 2 when
+critical problems were found. Use --fail-on to change which findings fail.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		analyzer := diagnostics.NewClusterHealthAnalyzer(client)
+		report, err := analyzer.Analyze(cmd.Context())
+		if err != nil {
+			utils.PrintError("Error analyzing cluster health: %v", err)
+			os.Exit(1)
+		}
+
+		printHealthReport(report)
+		diagnostics.RecordFindings(report.NormalizedFindings())
+		utils.ExitOnSeverity(cmd.Flags(), report.Severity)
+	},
+}
+
+func init() {
+	utils.AddFailOnFlag(HealthCmd.Flags(), "warning")
+}
+
+func printHealthReport(report *diagnostics.ClusterHealthReport) {
+	fmt.Println("K8s Lens Cluster Health Report")
+	fmt.Println("---")
+
+	utils.PrintSection("What's Broken Right Now")
+	if len(report.Findings) == 0 {
+		utils.PrintSuccess("No problems found")
+	}
+	for i, finding := range report.Findings {
+		if finding.Severity == diagnostics.SeverityCritical {
+			utils.PrintError("%d. [%s] %s", i+1, finding.Severity, finding.Message)
+		} else {
+			utils.PrintWarning("%d. [%s] %s", i+1, finding.Severity, finding.Message)
+		}
+	}
+
+	utils.PrintSection("Nodes")
+	fmt.Printf("Ready: %d/%d\n", report.ReadyNodes, report.TotalNodes)
+	for _, node := range report.NotReadyNodes {
+		fmt.Printf("• %s: NotReady\n", node)
+	}
+
+	utils.PrintSection("Pods")
+	unhealthy := 0
+	for _, group := range report.UnhealthyPods {
+		unhealthy += len(group.Pods)
+	}
+	fmt.Printf("Unhealthy: %d/%d\n", unhealthy, report.TotalPods)
+	for _, group := range report.UnhealthyPods {
+		fmt.Printf("• %s - %s (%d): %s\n", group.Namespace, group.Reason, len(group.Pods), strings.Join(group.Pods, ", "))
+	}
+
+	utils.PrintSection("Persistent Volume Claims")
+	fmt.Printf("Pending: %d\n", len(report.PendingPVCs))
+	for _, pvc := range report.PendingPVCs {
+		fmt.Printf("• %s\n", pvc)
+	}
+
+	utils.PrintSection("Warning Event Hotspots (Last Hour)")
+	if len(report.WarningHotspots) == 0 {
+		fmt.Println("No recent Warning events")
+	}
+	for _, hotspot := range report.WarningHotspots {
+		fmt.Printf("• %s %s: %s x%d\n", hotspot.Namespace, hotspot.Object, hotspot.Reason, hotspot.Count)
+	}
+
+	utils.PrintSection("Summary")
+	switch report.Severity {
+	case diagnostics.SeverityCritical:
+		utils.PrintError("Cluster Health: %s", report.Severity)
+	case diagnostics.SeverityWarning:
+		utils.PrintWarning("Cluster Health: %s", report.Severity)
+	default:
+		utils.PrintSuccess("Cluster Health: %s", report.Severity)
+	}
+}
@@ -24,6 +24,7 @@ var metricsCmd = &cobra.Command{
 
 		namespace, _ := cmd.Flags().GetString("namespace")
 		prometheusURL, _ := cmd.Flags().GetString("prometheus-url")
+		metricsBackend, _ := cmd.Flags().GetString("metrics-backend")
 
 		utils.PrintInfo("Starting metrics analysis for %s: %s", resourceType, resourceName)
 
@@ -33,7 +34,13 @@ var metricsCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		analyzer := integrations.NewMetricsAnalyzer(k8sClient, prometheusURL)
+		promClient, err := integrations.NewMetricsBackend(metricsBackend, prometheusURL, integrations.MetricsAuthFromFlags(cmd.Flags()))
+		if err != nil {
+			utils.PrintError("Error creating metrics client: %v", err)
+			os.Exit(1)
+		}
+
+		analyzer := integrations.NewMetricsAnalyzer(k8sClient, promClient)
 
 		switch resourceType {
 		case "pod", "pods":
@@ -41,7 +48,7 @@ var metricsCmd = &cobra.Command{
 				utils.PrintError("Pod name is required for pod metrics analysis")
 				os.Exit(1)
 			}
-			report, err := analyzer.AnalyzePodWithMetrics(resourceName, namespace)
+			report, err := analyzer.AnalyzePodWithMetrics(cmd.Context(), resourceName, namespace)
 			if err != nil {
 				utils.PrintError("Error analyzing pod with metrics: %v", err)
 				os.Exit(1)
@@ -95,6 +102,16 @@ func printPodMetricsReport(report *integrations.EnhancedPodReport) {
 		fmt.Printf("Memory Usage: %.2f MB\n", report.PodMetrics.MemoryUsage/(1024*1024))
 		fmt.Printf("Network Receive: %.2f KB/s\n", report.PodMetrics.NetworkRx/1024)
 		fmt.Printf("Network Transmit: %.2f KB/s\n", report.PodMetrics.NetworkTx/1024)
+		if report.PodMetrics.RestartRateAvailable {
+			fmt.Printf("Restart Rate: %.1f restarts/hour\n", report.PodMetrics.RestartRate)
+		}
+		if len(report.PodMetrics.CPUThrottling) > 0 {
+			fmt.Println("CPU Throttling:")
+			for _, throttling := range report.PodMetrics.CPUThrottling {
+				fmt.Printf("  - %s: %.1f%% of periods throttled (usage %.3f of %.3f cores)\n",
+					throttling.Container, throttling.ThrottledPercent, throttling.CPUUsage, throttling.CPULimit)
+			}
+		}
 	}
 
 	utils.PrintSection("Pod Status")
@@ -226,4 +243,6 @@ func printClusterMetricsReport(metrics *integrations.ClusterMetrics) {
 func init() {
 	metricsCmd.Flags().StringP("namespace", "n", "default", "Namespace (for pods)")
 	metricsCmd.Flags().StringP("prometheus-url", "p", "http://localhost:9090", "Prometheus URL")
+	metricsCmd.Flags().String("metrics-backend", integrations.BackendPrometheus, "Metrics backend serving the Prometheus query API: prometheus, thanos or victoriametrics")
+	integrations.AddMetricsAuthFlags(metricsCmd.Flags())
 }
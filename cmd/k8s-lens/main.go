@@ -1,20 +1,32 @@
 package main
 
 import (
+        "context"
         "fmt"
         "os"
+        "os/signal"
+        "path/filepath"
+        "strings"
+        "syscall"
 
         "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/analytics"
         "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/automation"
         "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/analyze"
+        "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/debug"
         "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/enterprise"
+        "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/health"
         "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/integrations"
         "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/multicluster"
         "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/optimize"
+        "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/report"
         "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/setup"
         "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/test"
+        "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/tui"
+        "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/validate"
         "github.com/abrarahmad1510/k8s-lens/cmd/k8s-lens/version"
         "github.com/abrarahmad1510/k8s-lens/internal/utils"
+        "github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+        "github.com/abrarahmad1510/k8s-lens/pkg/k8s"
         "github.com/common-nighthawk/go-figure"
         "github.com/fatih/color"
         "github.com/spf13/cobra"
@@ -29,8 +41,8 @@ var (
 )
 
 func main() {
-        // Print ASCII Art Banner For Non-Completion Commands
-        if len(os.Args) > 1 && os.Args[1] != "completion" {
+        // Print ASCII Art Banner For Interactive Non-Completion Commands
+        if shouldPrintBanner(os.Args[1:]) {
                 fig := figure.NewFigure("K8s Lens", "slant", true)
                 fig.Print()
                 fmt.Println()
@@ -78,13 +90,125 @@ Examples:
         rootCmd.AddCommand(integrations.IntegrationsCmd)
         rootCmd.AddCommand(enterprise.EnterpriseCmd)
         rootCmd.AddCommand(automation.AutomationCmd)
+        rootCmd.AddCommand(tui.TUICmd)
+        rootCmd.AddCommand(debug.DebugCmd)
+        rootCmd.AddCommand(health.HealthCmd)
+        rootCmd.AddCommand(validate.ValidateCmd)
+        rootCmd.AddCommand(report.ReportCmd)
 
-        if err := rootCmd.Execute(); err != nil {
+        var outputFile *os.File
+        rootCmd.PersistentFlags().String("output-file", "", "Write the command output to a file instead of stdout")
+        rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress the banner and progress output")
+        rootCmd.PersistentFlags().Duration("timeout", 0, "Abort Kubernetes API calls after this long, e.g. 30s (0 means no limit)")
+        rootCmd.PersistentFlags().String("kubeconfig", "", "Path to the kubeconfig file (defaults to $KUBECONFIG or ~/.kube/config)")
+        rootCmd.PersistentFlags().String("context", "", "Name of the kubeconfig context to use (defaults to the current context)")
+        rootCmd.PersistentFlags().String("as", "", "Username to impersonate for the operation, e.g. to check what that user can see")
+        rootCmd.PersistentFlags().StringArray("as-group", nil, "Group to impersonate for the operation; repeat for multiple groups (requires --as)")
+        usePluginName(rootCmd, os.Args[0])
+        cancelTimeout := func() {}
+        rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+                kubeconfig, _ := cmd.Flags().GetString("kubeconfig")
+                kubeContext, _ := cmd.Flags().GetString("context")
+                k8s.SetKubeconfig(kubeconfig, kubeContext)
+                user, _ := cmd.Flags().GetString("as")
+                groups, _ := cmd.Flags().GetStringArray("as-group")
+                if err := k8s.SetImpersonation(user, groups); err != nil {
+                        return err
+                }
+
+                if timeout, _ := cmd.Flags().GetDuration("timeout"); timeout > 0 {
+                        ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+                        cancelTimeout = cancel
+                        cmd.SetContext(ctx)
+                }
+
+                path, _ := cmd.Flags().GetString("output-file")
+                if path == "" {
+                        return nil
+                }
+                file, err := utils.RedirectOutput(path)
+                if err != nil {
+                        return err
+                }
+                outputFile = file
+                fmt.Fprintf(os.Stderr, "Writing output to %s\n", path)
+                return nil
+        }
+
+        // Cancel in-flight API calls on Ctrl-C or SIGTERM. Once cancelled the default signal
+        // handling is restored, so a second Ctrl-C exits immediately.
+        ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+        go func() {
+                <-ctx.Done()
+                stop()
+        }()
+
+        utils.SetSummaryLine(diagnostics.RunSummary)
+        err := rootCmd.ExecuteContext(ctx)
+        cancelTimeout()
+        stop()
+        if err == nil {
+                utils.PrintSummaryLine()
+        }
+        if outputFile != nil {
+                outputFile.Close()
+        }
+        if err != nil {
                 utils.PrintError("Command Execution Failed: %s", err)
                 os.Exit(1)
         }
 }
 
+// shouldPrintBanner decides whether to print the banner. It runs before flag parsing,
+// so the relevant flags are read from the raw arguments.
+func shouldPrintBanner(args []string) bool {
+        if len(args) == 0 || args[0] == "completion" {
+                return false
+        }
+        if hasFlag(args, "--quiet") || hasFlag(args, "-q") || hasFlag(args, "--output-file") {
+                return false
+        }
+        // Keep piped output and logs free of the banner
+        return utils.IsTerminal(os.Stdout)
+}
+
+// usePluginName makes help output show the command as kubectl shows it when the binary
+// is installed as a kubectl plugin, e.g. through krew: kubectl runs "kubectl lens" as the
+// kubectl-lens binary, so usage reads "kubectl lens analyze pod" instead of
+// "k8s-lens analyze pod". Plugin binaries name subcommands with "_" for "-".
+func usePluginName(rootCmd *cobra.Command, binary string) {
+        name := strings.TrimSuffix(filepath.Base(binary), ".exe")
+        if !strings.HasPrefix(name, "kubectl-") {
+                return
+        }
+        pluginName := "kubectl " + strings.ReplaceAll(strings.TrimPrefix(name, "kubectl-"), "_", "-")
+
+        cobra.AddTemplateFunc("pluginPath", func(path string) string {
+                if strings.HasPrefix(path, rootCmd.Name()) {
+                        return pluginName + strings.TrimPrefix(path, rootCmd.Name())
+                }
+                return path
+        })
+        rootCmd.SetUsageTemplate(strings.NewReplacer(
+                "{{.CommandPath}}", "{{pluginPath .CommandPath}}",
+                "{{.UseLine}}", "{{pluginPath .UseLine}}",
+        ).Replace(rootCmd.UsageTemplate()))
+}
+
+// hasFlag reports whether a flag is present in the raw command-line arguments,
+// in either "--flag value" or "--flag=value" form
+func hasFlag(args []string, name string) bool {
+        for _, arg := range args {
+                if arg == "--" {
+                        return false
+                }
+                if arg == name || strings.HasPrefix(arg, name+"=") {
+                        return true
+                }
+        }
+        return false
+}
+
 func createCompletionCommand() *cobra.Command {
         return &cobra.Command{
                 Use:   "completion [bash|zsh|fish|powershell]",
@@ -26,7 +26,15 @@ var compareCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		comparison, err := manager.CompareClusters(resourceType)
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		spinner := utils.NewSpinner(quiet)
+		manager.SetProgress(func(current, total int, contextName string) {
+			spinner.Update(fmt.Sprintf("Analyzing cluster %d/%d: %s", current, total, contextName))
+		})
+
+		spinner.Start("Preparing analysis")
+		comparison, err := manager.CompareClusters(cmd.Context(), resourceType)
+		spinner.Stop()
 		if err != nil {
 			utils.PrintError("Error comparing clusters: %v", err)
 			os.Exit(1)
@@ -0,0 +1,62 @@
+package multicluster
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/multicluster"
+	"github.com/spf13/cobra"
+)
+
+var driftCmd = &cobra.Command{
+	Use:   "drift [resource-type] [name]",
+	Short: "Detect configuration drift of a resource across clusters",
+	Long: `Compare a resource in every available cluster against a baseline cluster and report
+image, replica, env var and resource differences. Supported types: deployment.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		resourceType, name := args[0], args[1]
+		namespace, _ := cmd.Flags().GetString("namespace")
+		baseline, _ := cmd.Flags().GetString("baseline")
+
+		if resourceType != "deployment" && resourceType != "deploy" {
+			utils.PrintError("Unsupported resource type: %s (supported: deployment)", resourceType)
+			os.Exit(1)
+		}
+		if baseline == "" {
+			utils.PrintError("A baseline context is required (--baseline)")
+			os.Exit(1)
+		}
+
+		utils.PrintInfo("Detecting drift of deployment %s/%s against baseline %s", namespace, name, baseline)
+
+		manager := multicluster.NewClusterManager()
+		err := manager.LoadContexts()
+		if err != nil {
+			utils.PrintError("Error loading cluster contexts: %v", err)
+			os.Exit(1)
+		}
+
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		spinner := utils.NewSpinner(quiet)
+		manager.SetProgress(func(current, total int, contextName string) {
+			spinner.Update(fmt.Sprintf("Checking cluster %d/%d: %s", current, total, contextName))
+		})
+
+		spinner.Start("Preparing drift detection")
+		report, err := manager.DetectDeploymentDrift(cmd.Context(), name, namespace, baseline)
+		spinner.Stop()
+		if err != nil {
+			utils.PrintError("Error detecting drift: %v", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(report.GenerateDriftReport())
+	},
+}
+
+func init() {
+	driftCmd.Flags().StringP("namespace", "n", "default", "Namespace")
+	driftCmd.Flags().String("baseline", "", "Context to use as the baseline, e.g. prod")
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations"
 	"github.com/abrarahmad1510/k8s-lens/pkg/multicluster"
 	"github.com/spf13/cobra"
 )
@@ -23,7 +24,25 @@ var federatedCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		report, err := manager.FederatedAnalysis()
+		prometheusURLs, _ := cmd.Flags().GetStringToString("prometheus-url-map")
+		for contextName := range prometheusURLs {
+			if _, err := manager.GetContext(contextName); err != nil {
+				utils.PrintWarning("Prometheus URL given for unknown context %s", contextName)
+			}
+		}
+		manager.SetPrometheusURLs(prometheusURLs)
+		metricsBackend, _ := cmd.Flags().GetString("metrics-backend")
+		manager.SetMetricsBackend(metricsBackend, integrations.MetricsAuthFromFlags(cmd.Flags()))
+
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		spinner := utils.NewSpinner(quiet)
+		manager.SetProgress(func(current, total int, contextName string) {
+			spinner.Update(fmt.Sprintf("Analyzing cluster %d/%d: %s", current, total, contextName))
+		})
+
+		spinner.Start("Preparing analysis")
+		report, err := manager.FederatedAnalysis(cmd.Context())
+		spinner.Stop()
 		if err != nil {
 			utils.PrintError("Error running federated analysis: %v", err)
 			os.Exit(1)
@@ -32,3 +51,9 @@ var federatedCmd = &cobra.Command{
 		fmt.Println(report.GenerateFederatedReport())
 	},
 }
+
+func init() {
+	federatedCmd.Flags().StringToString("prometheus-url-map", nil, "Prometheus URL per context, e.g. prod=http://prom-prod:9090,staging=http://prom-staging:9090")
+	federatedCmd.Flags().String("metrics-backend", integrations.BackendPrometheus, "Metrics backend serving the Prometheus query API: prometheus, thanos or victoriametrics")
+	integrations.AddMetricsAuthFlags(federatedCmd.Flags())
+}
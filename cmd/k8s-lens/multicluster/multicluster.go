@@ -15,4 +15,5 @@ func init() {
 	MulticlusterCmd.AddCommand(contextsCmd)
 	MulticlusterCmd.AddCommand(compareCmd)
 	MulticlusterCmd.AddCommand(federatedCmd)
+	MulticlusterCmd.AddCommand(driftCmd)
 }
@@ -0,0 +1,123 @@
+package optimize
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/abrarahmad1510/k8s-lens/pkg/optimization"
+	"github.com/spf13/cobra"
+)
+
+var costCmd = &cobra.Command{
+	Use:   "cost [namespace]",
+	Short: "Rank workloads by their estimated monthly cost",
+	Long: `Estimate what each Deployment and StatefulSet costs per month from the CPU and memory
+its pods request times its replicas, and rank the workloads by spend. Requests are priced
+with --cpu-price and --memory-price, so the breakdown shows what is costing money whether
+or not the resources are used; see 'optimize resource' for how to save.
+The namespace may be a comma-separated list, or use --all-namespaces.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if all, _ := cmd.Flags().GetBool("all-namespaces"); all {
+			return cobra.MaximumNArgs(0)(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			utils.PrintError("Unsupported output format: %s (supported: text, json)", output)
+			os.Exit(1)
+		}
+		cpuPrice, _ := cmd.Flags().GetFloat64("cpu-price")
+		memoryPrice, _ := cmd.Flags().GetFloat64("memory-price")
+
+		k8sClient, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		namespaces := namespacesFromArgs(cmd, k8sClient, args)
+
+		optimizer := optimization.NewResourceOptimizer(k8sClient)
+		calculator := optimization.NewCostCalculator(cpuPrice, memoryPrice)
+
+		var reports []*optimization.CostAllocationReport
+		for _, namespace := range namespaces {
+			report, err := optimizer.CostAllocation(cmd.Context(), namespace, calculator)
+			if err != nil {
+				if len(namespaces) == 1 {
+					utils.PrintError("Error estimating costs: %v", err)
+					os.Exit(1)
+				}
+				utils.PrintWarning("Skipping namespace %s: %v", namespace, err)
+				continue
+			}
+			reports = append(reports, report)
+		}
+
+		if output == "json" {
+			data, err := json.MarshalIndent(reports, "", "  ")
+			if err != nil {
+				utils.PrintError("Error encoding cost allocation: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		printCostAllocation(reports, len(namespaces) > 1)
+	},
+}
+
+func init() {
+	costCmd.Flags().BoolP("all-namespaces", "A", false, "Estimate the costs of every namespace")
+	utils.AddNamespaceFilterFlags(costCmd.Flags())
+	costCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	costCmd.Flags().Float64("cpu-price", optimization.DefaultCPUCostPerHour, "Price of one requested CPU core per hour, in dollars")
+	costCmd.Flags().Float64("memory-price", optimization.DefaultMemoryGBCostPerHour, "Price of one requested GiB of memory per hour, in dollars")
+}
+
+// printCostAllocation prints the workloads of every namespace ranked by monthly cost,
+// with the namespace column shown when there are several
+func printCostAllocation(reports []*optimization.CostAllocationReport, multipleNamespaces bool) {
+	var workloads []optimization.WorkloadCost
+	total := 0.0
+	for _, report := range reports {
+		workloads = append(workloads, report.Workloads...)
+		total += report.TotalMonthlyCost
+	}
+	sort.SliceStable(workloads, func(i, j int) bool { return workloads[i].MonthlyCost > workloads[j].MonthlyCost })
+
+	fmt.Println("K8s Lens Cost Allocation")
+	fmt.Println("===")
+
+	utils.PrintSection("Workloads by Monthly Cost")
+	if len(workloads) == 0 {
+		utils.PrintWarning("No deployments or statefulsets found")
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tWORKLOAD\tREPLICAS\tCPU REQ\tMEM REQ\tMONTHLY COST\tSHARE")
+	for i, workload := range workloads {
+		name := workload.Kind + "/" + workload.Name
+		if multipleNamespaces {
+			name = workload.Namespace + "/" + name
+		}
+		share := 0.0
+		if total > 0 {
+			share = workload.MonthlyCost / total * 100
+		}
+		fmt.Fprintf(w, "%d\t%s\t%d\t%.3f\t%.0fMi\t$%.2f\t%.1f%%\n", i+1, name, workload.Replicas,
+			workload.CPURequestCores, workload.MemoryRequestBytes/(1024*1024), workload.MonthlyCost, share)
+	}
+	w.Flush()
+
+	fmt.Println()
+	fmt.Printf("Total Estimated Monthly Cost: $%.2f\n", total)
+}
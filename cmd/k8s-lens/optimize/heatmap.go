@@ -0,0 +1,126 @@
+package optimize
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/abrarahmad1510/k8s-lens/pkg/optimization"
+	"github.com/spf13/cobra"
+)
+
+var heatmapCmd = &cobra.Command{
+	Use:   "heatmap [namespace]",
+	Short: "Export requested vs used resources per pod and workload",
+	Long: `Compare the CPU and memory requested by each running pod with its usage from Prometheus.
+The efficiency of a pod or workload is its usage divided by its requests; workloads are listed
+least efficient first. Use -o json to feed heatmaps and dashboards.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace := args[0]
+		output, _ := cmd.Flags().GetString("output")
+		prometheusURL, _ := cmd.Flags().GetString("prometheus-url")
+		metricsBackend, _ := cmd.Flags().GetString("metrics-backend")
+
+		if output != "text" && output != "json" {
+			utils.PrintError("Unsupported output format: %s (supported: text, json)", output)
+			os.Exit(1)
+		}
+
+		k8sClient, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		promClient, err := integrations.NewMetricsBackend(metricsBackend, prometheusURL, integrations.MetricsAuthFromFlags(cmd.Flags()))
+		if err != nil {
+			utils.PrintError("Error creating metrics client: %v", err)
+			os.Exit(1)
+		}
+		if err := promClient.TestConnection(); err != nil {
+			utils.PrintError("Prometheus is required for usage data: %v", err)
+			os.Exit(1)
+		}
+
+		optimizer := optimization.NewResourceOptimizer(k8sClient)
+		report, err := optimizer.BuildHeatmap(cmd.Context(), namespace, promClient)
+		if err != nil {
+			utils.PrintError("Error building resource heatmap: %v", err)
+			os.Exit(1)
+		}
+
+		if output == "json" {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				utils.PrintError("Error encoding heatmap: %v", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		printHeatmap(report)
+	},
+}
+
+func init() {
+	heatmapCmd.Flags().StringP("output", "o", "text", "Output format (text, json)")
+	heatmapCmd.Flags().StringP("prometheus-url", "p", "http://localhost:9090", "Prometheus URL")
+	heatmapCmd.Flags().String("metrics-backend", integrations.BackendPrometheus, "Metrics backend serving the Prometheus query API: prometheus, thanos or victoriametrics")
+	integrations.AddMetricsAuthFlags(heatmapCmd.Flags())
+}
+
+func printHeatmap(report *optimization.HeatmapReport) {
+	fmt.Printf("K8s Lens Resource Heatmap: %s\n", report.Namespace)
+	fmt.Println("===")
+
+	utils.PrintSection("Workload Efficiency")
+	if len(report.Workloads) == 0 {
+		utils.PrintWarning("No usage data found for running pods")
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "WORKLOAD\tPODS\tCPU REQ\tCPU USED\tMEM REQ\tMEM USED\tEFFICIENCY")
+		for _, workload := range report.Workloads {
+			fmt.Fprintf(w, "%s\t%d\t%.3f\t%.3f\t%.0fMi\t%.0fMi\t%.0f%%\n",
+				workload.Workload, workload.Pods,
+				workload.CPURequestCores, workload.CPUUsageCores,
+				workload.MemoryRequestBytes/(1024*1024), workload.MemoryUsageBytes/(1024*1024),
+				workload.Efficiency*100)
+		}
+		w.Flush()
+
+		for _, workload := range report.Workloads {
+			if workload.CPUUnrequestedCores > 0 || workload.MemoryUnrequestedBytes > 0 {
+				utils.PrintWarning("%s has pods without requests using %.3f CPU and %.0fMi memory, left out of its efficiency",
+					workload.Workload, workload.CPUUnrequestedCores, workload.MemoryUnrequestedBytes/(1024*1024))
+			}
+		}
+	}
+
+	utils.PrintSection("Pods")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POD\tWORKLOAD\tCPU\tMEMORY")
+	for _, pod := range report.Pods {
+		if pod.Error != "" {
+			fmt.Fprintf(w, "%s\t%s\tusage unavailable\t\n", pod.Pod, pod.Workload)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", pod.Pod, pod.Workload,
+			efficiencyCell(pod.CPUEfficiency, pod.CPURequestCores),
+			efficiencyCell(pod.MemoryEfficiency, pod.MemoryRequestBytes))
+	}
+	w.Flush()
+}
+
+// efficiencyCell formats an efficiency ratio as a percentage of requests
+func efficiencyCell(efficiency, requested float64) string {
+	if requested <= 0 {
+		return "no request"
+	}
+	return fmt.Sprintf("%.0f%% of request", efficiency*100)
+}
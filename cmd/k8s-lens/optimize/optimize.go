@@ -1,7 +1,12 @@
 package optimize
 
 import (
+	"os"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/kubernetes"
 )
 
 // OptimizeCmd represents the optimize command
@@ -16,4 +21,38 @@ func init() {
 	OptimizeCmd.AddCommand(resourceCmd)
 	OptimizeCmd.AddCommand(predictCmd)
 	OptimizeCmd.AddCommand(fixCmd)
+	OptimizeCmd.AddCommand(heatmapCmd)
+	OptimizeCmd.AddCommand(recommendRequestsCmd)
+	OptimizeCmd.AddCommand(costCmd)
+}
+
+// namespacesFromArgs returns the namespaces named by the optional [namespace] argument,
+// which may be a comma-separated list, or every namespace with --all-namespaces,
+// scoped by --include and --exclude
+func namespacesFromArgs(cmd *cobra.Command, client kubernetes.Interface, args []string) []string {
+	namespace := ""
+	if len(args) > 0 {
+		namespace = args[0]
+	}
+	all, _ := cmd.Flags().GetBool("all-namespaces")
+	namespaces, err := k8s.ResolveNamespaces(cmd.Context(), client, namespace, all)
+	if err != nil {
+		utils.PrintError("Error resolving namespaces: %v", err)
+		os.Exit(1)
+	}
+
+	filter, err := utils.ResourceFilter(cmd.Flags())
+	if err != nil {
+		utils.PrintError("%v", err)
+		os.Exit(1)
+	}
+	if namespaces, err = k8s.FilterNamespaces(namespaces, filter); err != nil {
+		utils.PrintError("%v", err)
+		os.Exit(1)
+	}
+	if len(namespaces) == 0 {
+		utils.PrintError("No namespace left after --include/--exclude")
+		os.Exit(1)
+	}
+	return namespaces
 }
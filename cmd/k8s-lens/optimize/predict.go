@@ -28,7 +28,7 @@ var predictCmd = &cobra.Command{
 		}
 
 		analyzer := ai.NewPredictiveAnalyzer(k8sClient)
-		report, err := analyzer.PredictFailures(deploymentName, namespace)
+		report, err := analyzer.PredictFailures(cmd.Context(), deploymentName, namespace)
 		if err != nil {
 			utils.PrintError("Error performing predictive analysis: %v", err)
 			os.Exit(1)
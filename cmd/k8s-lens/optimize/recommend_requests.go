@@ -0,0 +1,75 @@
+package optimize
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/abrarahmad1510/k8s-lens/pkg/optimization"
+	"github.com/spf13/cobra"
+)
+
+var recommendRequestsCmd = &cobra.Command{
+	Use:   "recommend-requests",
+	Short: "Print kubectl commands applying the resource recommendations",
+	Long: `Turn the resource optimization recommendations for a namespace into ready-to-run
+kubectl set resources commands, one per workload container. Each command is preceded
+by a comment listing the values it changes.
+
+Applying a command rolls out the workload's pods again. Review the values before
+running them, since the recommendations are estimates.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, _ := cmd.Flags().GetString("namespace")
+
+		k8sClient, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		optimizer := optimization.NewResourceOptimizer(k8sClient)
+
+		utils.PrintInfo("Generating resource commands for namespace: %s", namespace)
+		report, err := optimizer.AnalyzeNamespace(cmd.Context(), namespace)
+		if err != nil {
+			utils.PrintError("Error analyzing resource optimization: %v", err)
+			os.Exit(1)
+		}
+		plan, err := optimizer.RecommendResourceCommands(cmd.Context(), report)
+		if err != nil {
+			utils.PrintError("Error generating resource commands: %v", err)
+			os.Exit(1)
+		}
+
+		if len(plan.Commands) == 0 {
+			utils.PrintSuccess("No resource changes recommended for namespace %s", namespace)
+		}
+		for _, command := range plan.Commands {
+			fmt.Printf("\n# %s, container %s\n", command.Workload, command.Container)
+			for _, change := range command.Changes {
+				fmt.Printf("#   %s\n", change)
+			}
+			fmt.Println(command.Command)
+		}
+
+		if len(plan.Skipped) > 0 {
+			pods := make([]string, 0, len(plan.Skipped))
+			for pod := range plan.Skipped {
+				pods = append(pods, pod)
+			}
+			sort.Strings(pods)
+
+			fmt.Println()
+			for _, pod := range pods {
+				utils.PrintWarning("Skipped pod %s: %s", pod, plan.Skipped[pod])
+			}
+		}
+	},
+}
+
+func init() {
+	recommendRequestsCmd.Flags().StringP("namespace", "n", "default", "Namespace")
+}
@@ -3,6 +3,8 @@ package optimize
 import (
 	"fmt"
 	"os"
+	"sort"
+	"text/tabwriter"
 
 	"github.com/abrarahmad1510/k8s-lens/internal/utils"
 	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
@@ -13,12 +15,25 @@ import (
 var resourceCmd = &cobra.Command{
 	Use:   "resource [namespace]",
 	Short: "Optimize resource allocation and reduce costs",
-	Long:  `Analyze and optimize Kubernetes resource allocation for cost savings.`,
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		namespace := args[0]
+	Long: `Analyze and optimize Kubernetes resource allocation for cost savings.
+The namespace may be a comma-separated list. Several namespaces are analyzed in
+parallel and followed by the combined savings, with the namespaces ranked by how much
+they could save:
 
-		utils.PrintInfo("Starting resource optimization analysis for namespace: %s", namespace)
+  k8s-lens optimize resource --all-namespaces`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if all, _ := cmd.Flags().GetBool("all-namespaces"); all {
+			return cobra.MaximumNArgs(0)(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "markdown" {
+			utils.PrintError("Unsupported output format: %s (supported: text, markdown)", output)
+			os.Exit(1)
+		}
+		markdown := output == "markdown"
 
 		k8sClient, err := k8s.NewClient()
 		if err != nil {
@@ -26,46 +41,206 @@ var resourceCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		namespaces := namespacesFromArgs(cmd, k8sClient, args)
+
 		optimizer := optimization.NewResourceOptimizer(k8sClient)
-		report, err := optimizer.AnalyzeNamespace(namespace)
-		if err != nil {
-			utils.PrintError("Error analyzing resource optimization: %v", err)
-			os.Exit(1)
+
+		if len(namespaces) == 1 {
+			if !markdown {
+				utils.PrintInfo("Starting resource optimization analysis for namespace: %s", namespaces[0])
+			}
+			report, err := optimizer.AnalyzeNamespace(cmd.Context(), namespaces[0])
+			if err != nil {
+				utils.PrintError("Error analyzing resource optimization: %v", err)
+				os.Exit(1)
+			}
+			if markdown {
+				printOptimizationReportMarkdown(report)
+			} else {
+				printOptimizationReport(report)
+			}
+			return
 		}
 
-		fmt.Printf("K8s Lens Resource Optimization Report: %s\n", namespace)
-		fmt.Println("===")
-
-		utils.PrintSection("Namespace Overview")
-		fmt.Printf("Total Pods: %d\n", report.TotalPods)
-		fmt.Printf("Analyzed Pods: %d\n", report.AnalyzedPods)
-		fmt.Printf("Total Optimizations: %d\n", report.Summary.TotalOptimizations)
-		fmt.Printf("Estimated Monthly Savings: $%.2f\n", report.Summary.TotalMonthlySavings)
-		fmt.Printf("Overall Confidence: %d%%\n", report.Summary.OverallConfidence)
-		fmt.Printf("Risk Level: %s\n", report.Summary.RiskLevel)
-
-		if len(report.Optimizations) > 0 {
-			utils.PrintSection("Optimization Recommendations")
-			for i, opt := range report.Optimizations {
-				fmt.Printf("\nOptimization %d:\n", i+1)
-				fmt.Printf("  Pod: %s | Container: %s\n", opt.PodName, opt.ContainerName)
-				fmt.Printf("  Type: %s\n", opt.Type)
-				fmt.Printf("  Current: CPU=%s, Memory=%s\n", opt.Current.CPU, opt.Current.Memory)
-				fmt.Printf("  Recommended: CPU=%s, Memory=%s\n", opt.Recommended.CPU, opt.Recommended.Memory)
-				fmt.Printf("  Monthly Savings: $%.2f (%.1f%%)\n", opt.Savings.MonthlySavings, opt.Savings.PercentSavings)
-				fmt.Printf("  Confidence: %d%%\n", opt.Confidence)
-				fmt.Printf("  Description: %s\n", opt.Description)
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		spinner := utils.NewSpinner(quiet)
+		spinner.Start(fmt.Sprintf("Analyzing %d namespaces", len(namespaces)))
+		reports, errs := optimizer.AnalyzeNamespaces(cmd.Context(), namespaces, concurrency, func(done, total int, namespace string) {
+			spinner.Update(fmt.Sprintf("%s %d/%d namespaces analyzed, last: %s", utils.ProgressBar(done, total, 20), done, total, namespace))
+		})
+		spinner.Stop()
+
+		for _, namespace := range namespaces {
+			if err, ok := errs[namespace]; ok {
+				utils.PrintWarning("Skipping namespace %s: %v", namespace, err)
+			}
+		}
+		for _, report := range reports {
+			if markdown {
+				printOptimizationReportMarkdown(report)
+			} else {
+				printOptimizationReport(report)
 			}
-		} else {
-			utils.PrintSuccess("No optimization opportunities found. Resources are well configured!")
 		}
 
-		utils.PrintSection("Next Steps")
-		if report.Summary.TotalMonthlySavings > 0 {
-			utils.PrintInfo("Apply optimizations to save approximately $%.2f per month", report.Summary.TotalMonthlySavings)
-			utils.PrintInfo("Use 'k8s-lens optimize fix' to generate automated patches")
+		if markdown {
+			printCombinedOptimizationMarkdown(reports)
 		} else {
-			utils.PrintSuccess("Your resource configuration is optimal!")
+			printCombinedOptimization(reports)
 		}
 	},
 }
+
+func init() {
+	resourceCmd.Flags().BoolP("all-namespaces", "A", false, "Analyze every namespace")
+	utils.AddNamespaceFilterFlags(resourceCmd.Flags())
+	resourceCmd.Flags().Int("concurrency", 10, "Number of namespaces analyzed in parallel when analyzing several")
+	resourceCmd.Flags().StringP("output", "o", "text", "Output format (text, markdown)")
+}
+
+func printOptimizationReport(report *optimization.OptimizationReport) {
+	fmt.Printf("K8s Lens Resource Optimization Report: %s\n", report.Namespace)
+	fmt.Println("===")
+
+	utils.PrintSection("Namespace Overview")
+	fmt.Printf("Total Pods: %d\n", report.TotalPods)
+	fmt.Printf("Analyzed Pods: %d\n", report.AnalyzedPods)
+	if len(report.ExtendedResources) > 0 {
+		names := make([]string, 0, len(report.ExtendedResources))
+		for name := range report.ExtendedResources {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("Requested %s: %d\n", name, report.ExtendedResources[name])
+		}
+	}
+	fmt.Printf("Total Optimizations: %d\n", report.Summary.TotalOptimizations)
+	fmt.Printf("Estimated Monthly Savings: $%.2f\n", report.Summary.TotalMonthlySavings)
+	fmt.Printf("Overall Confidence: %d%%\n", report.Summary.OverallConfidence)
+	fmt.Printf("Risk Level: %s\n", report.Summary.RiskLevel)
+
+	if len(report.Optimizations) > 0 {
+		utils.PrintSection("Optimization Recommendations")
+		for i, opt := range report.Optimizations {
+			fmt.Printf("\nOptimization %d:\n", i+1)
+			fmt.Printf("  Pod: %s | Container: %s\n", opt.PodName, opt.ContainerName)
+			fmt.Printf("  Type: %s\n", opt.Type)
+			fmt.Printf("  Current: CPU=%s, Memory=%s\n", opt.Current.CPU, opt.Current.Memory)
+			fmt.Printf("  Recommended: CPU=%s, Memory=%s\n", opt.Recommended.CPU, opt.Recommended.Memory)
+			fmt.Printf("  Monthly Savings: $%.2f (%.1f%%)\n", opt.Savings.MonthlySavings, opt.Savings.PercentSavings)
+			fmt.Printf("  Confidence: %d%%\n", opt.Confidence)
+			fmt.Printf("  Description: %s\n", opt.Description)
+		}
+	} else {
+		utils.PrintSuccess("No optimization opportunities found. Resources are well configured!")
+	}
+
+	utils.PrintSection("Next Steps")
+	if report.Summary.TotalMonthlySavings > 0 {
+		utils.PrintInfo("Apply optimizations to save approximately $%.2f per month", report.Summary.TotalMonthlySavings)
+		utils.PrintInfo("Use 'k8s-lens optimize fix' to generate automated patches")
+	} else {
+		utils.PrintSuccess("Your resource configuration is optimal!")
+	}
+}
+
+// printCombinedOptimization prints the savings of several namespaces, ranked from the
+// highest, and their total
+func printCombinedOptimization(reports []*optimization.OptimizationReport) {
+	fmt.Println()
+	fmt.Println("K8s Lens Combined Resource Optimization Report")
+	fmt.Println("===")
+
+	totalPods, totalOptimizations, totalSavings := 0, 0, 0.0
+	for _, report := range reports {
+		totalPods += report.TotalPods
+		totalOptimizations += report.Summary.TotalOptimizations
+		totalSavings += report.Summary.TotalMonthlySavings
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tNAMESPACE\tPODS\tOPTIMIZATIONS\tMONTHLY SAVINGS\tSHARE\tRISK")
+	for i, report := range optimization.RankBySavings(reports) {
+		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t$%.2f\t%s\t%s\n", i+1, report.Namespace, report.TotalPods,
+			report.Summary.TotalOptimizations, report.Summary.TotalMonthlySavings,
+			savingsShare(report.Summary.TotalMonthlySavings, totalSavings), report.Summary.RiskLevel)
+	}
+	w.Flush()
+
+	utils.PrintSection("Totals")
+	fmt.Printf("Namespaces Analyzed: %d\n", len(reports))
+	fmt.Printf("Total Pods: %d\n", totalPods)
+	fmt.Printf("Total Optimizations: %d\n", totalOptimizations)
+	fmt.Printf("Estimated Monthly Savings: $%.2f\n", totalSavings)
+}
+
+func printOptimizationReportMarkdown(report *optimization.OptimizationReport) {
+	utils.MarkdownHeading(1, "K8s Lens Resource Optimization Report: %s", report.Namespace)
+
+	utils.MarkdownHeading(2, "Summary")
+	rows := [][]string{
+		{"Total Pods", fmt.Sprintf("%d", report.TotalPods)},
+		{"Analyzed Pods", fmt.Sprintf("%d", report.AnalyzedPods)},
+	}
+	names := make([]string, 0, len(report.ExtendedResources))
+	for name := range report.ExtendedResources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		rows = append(rows, []string{"Requested " + name, fmt.Sprintf("%d", report.ExtendedResources[name])})
+	}
+	rows = append(rows,
+		[]string{"Total Optimizations", fmt.Sprintf("%d", report.Summary.TotalOptimizations)},
+		[]string{"Estimated Monthly Savings", fmt.Sprintf("$%.2f", report.Summary.TotalMonthlySavings)},
+		[]string{"Overall Confidence", fmt.Sprintf("%d%%", report.Summary.OverallConfidence)},
+		[]string{"Risk Level", report.Summary.RiskLevel},
+	)
+	utils.MarkdownTable([]string{"Metric", "Value"}, rows)
+
+	if len(report.Optimizations) == 0 {
+		fmt.Println("No optimization opportunities found. Resources are well configured!")
+		return
+	}
+
+	utils.MarkdownHeading(2, "Optimization Recommendations")
+	items := make([]string, 0, len(report.Optimizations))
+	for _, opt := range report.Optimizations {
+		items = append(items, fmt.Sprintf("**%s/%s** (%s): CPU %s -> %s, memory %s -> %s, saves $%.2f/month (%.1f%%), confidence %d%%. %s",
+			opt.PodName, opt.ContainerName, opt.Type, opt.Current.CPU, opt.Recommended.CPU, opt.Current.Memory, opt.Recommended.Memory,
+			opt.Savings.MonthlySavings, opt.Savings.PercentSavings, opt.Confidence, opt.Description))
+	}
+	utils.MarkdownList(items)
+}
+
+// printCombinedOptimizationMarkdown prints the savings of several namespaces, ranked
+// from the highest, as a markdown table followed by the totals
+func printCombinedOptimizationMarkdown(reports []*optimization.OptimizationReport) {
+	utils.MarkdownHeading(1, "K8s Lens Combined Resource Optimization Report")
+
+	totalPods, totalOptimizations, totalSavings := 0, 0, 0.0
+	for _, report := range reports {
+		totalPods += report.TotalPods
+		totalOptimizations += report.Summary.TotalOptimizations
+		totalSavings += report.Summary.TotalMonthlySavings
+	}
+
+	var rows [][]string
+	for i, report := range optimization.RankBySavings(reports) {
+		rows = append(rows, []string{fmt.Sprintf("%d", i+1), report.Namespace, fmt.Sprintf("%d", report.TotalPods),
+			fmt.Sprintf("%d", report.Summary.TotalOptimizations), fmt.Sprintf("$%.2f", report.Summary.TotalMonthlySavings),
+			savingsShare(report.Summary.TotalMonthlySavings, totalSavings), report.Summary.RiskLevel})
+	}
+	rows = append(rows, []string{"", "**Total**", fmt.Sprintf("%d", totalPods), fmt.Sprintf("%d", totalOptimizations), fmt.Sprintf("$%.2f", totalSavings), "100%", ""})
+	utils.MarkdownTable([]string{"Rank", "Namespace", "Pods", "Optimizations", "Monthly Savings", "Share", "Risk"}, rows)
+}
+
+// savingsShare formats a namespace's share of the total savings as a percentage
+func savingsShare(savings, total float64) string {
+	if total <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", savings/total*100)
+}
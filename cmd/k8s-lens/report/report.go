@@ -0,0 +1,216 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/abrarahmad1510/k8s-lens/pkg/enterprise"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/abrarahmad1510/k8s-lens/pkg/optimization"
+	"github.com/spf13/cobra"
+)
+
+// ReportCmd groups the commands producing report artifacts
+var ReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate report artifacts for auditing cluster state over time",
+	Long:  `Generate report artifacts that are written to files instead of the terminal.`,
+}
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Run the diagnostics, security and optimization analyses and write a timestamped bundle",
+	Long: `Run the workload diagnostics, the security scan and the resource optimizer over a
+namespace and write the results to a timestamped JSON file and a Markdown file of the
+same name in --out-dir, e.g. reports/shop-20261016T020000Z.json.
+
+The command is meant to run on a schedule, e.g. from a CronJob (see
+deploy/k8s-lens-report-cronjob.yaml), so the reports build an auditable history of
+the namespace over time:
+
+  k8s-lens report generate --namespace shop --out-dir ./reports`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, _ := cmd.Flags().GetString("namespace")
+		outDir, _ := cmd.Flags().GetString("out-dir")
+
+		utils.PrintInfo("Generating report for namespace: %s", namespace)
+
+		client, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		bundle := &Bundle{
+			GeneratedAt: time.Now().UTC(),
+			Namespace:   namespace,
+		}
+
+		snapshotter := diagnostics.NewNamespaceSnapshotter(client, namespace)
+		if bundle.Diagnostics, err = snapshotter.Snapshot(cmd.Context()); err != nil {
+			utils.PrintError("Error analyzing namespace: %v", err)
+			os.Exit(1)
+		}
+
+		scanner := enterprise.NewSecurityScanner(client)
+		if bundle.Security, err = scanner.ScanNamespace(cmd.Context(), namespace); err != nil {
+			utils.PrintError("Error scanning namespace security: %v", err)
+			os.Exit(1)
+		}
+
+		optimizer := optimization.NewResourceOptimizer(client)
+		if bundle.Optimization, err = optimizer.AnalyzeNamespace(cmd.Context(), namespace); err != nil {
+			utils.PrintError("Error analyzing resource optimizations: %v", err)
+			os.Exit(1)
+		}
+
+		bundle.Severity = bundle.severity()
+
+		jsonPath, markdownPath, err := writeBundle(bundle, outDir)
+		if err != nil {
+			utils.PrintError("Error writing report: %v", err)
+			os.Exit(1)
+		}
+		utils.PrintSuccess("Report written to %s and %s", jsonPath, markdownPath)
+
+		utils.ExitOnSeverity(cmd.Flags(), bundle.Severity)
+	},
+}
+
+func init() {
+	ReportCmd.AddCommand(generateCmd)
+
+	generateCmd.Flags().StringP("namespace", "n", "default", "Namespace to report on")
+	generateCmd.Flags().String("out-dir", "./reports", "Directory the report files are written to; created if missing")
+	utils.AddFailOnFlag(generateCmd.Flags(), "warning")
+}
+
+// Bundle is the content of a generated report
+type Bundle struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	Namespace   string    `json:"namespace"`
+	// Severity is the most severe outcome of the analyses
+	Severity     string                           `json:"severity"`
+	Diagnostics  *diagnostics.NamespaceSnapshot   `json:"diagnostics"`
+	Security     *enterprise.SecurityScanReport   `json:"security"`
+	Optimization *optimization.OptimizationReport `json:"optimization"`
+}
+
+// severity combines the statuses of the workloads with the security risk level
+func (b *Bundle) severity() string {
+	severity := diagnostics.SeverityForRiskLevel(b.Security.RiskLevel)
+	for _, workload := range b.Diagnostics.Workloads {
+		severity = diagnostics.MaxSeverity(severity, diagnostics.SeverityForStatus(workload.Status, len(workload.Issues)))
+	}
+	return severity
+}
+
+// writeBundle writes the bundle as JSON and Markdown files named after the namespace
+// and generation time, returning their paths
+func writeBundle(bundle *Bundle, outDir string) (string, string, error) {
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create %s: %v", outDir, err)
+	}
+	base := filepath.Join(outDir, fmt.Sprintf("%s-%s", bundle.Namespace, bundle.GeneratedAt.Format("20060102T150405Z")))
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode report: %v", err)
+	}
+	jsonPath := base + ".json"
+	if err := os.WriteFile(jsonPath, append(data, '\n'), 0644); err != nil {
+		return "", "", fmt.Errorf("failed to write %s: %v", jsonPath, err)
+	}
+
+	// The markdown helpers print to stdout, so stdout points at the file while they run
+	markdownPath := base + ".md"
+	file, err := os.Create(markdownPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create %s: %v", markdownPath, err)
+	}
+	stdout := os.Stdout
+	os.Stdout = file
+	printMarkdown(bundle)
+	os.Stdout = stdout
+	if err := file.Close(); err != nil {
+		return "", "", fmt.Errorf("failed to write %s: %v", markdownPath, err)
+	}
+	return jsonPath, markdownPath, nil
+}
+
+func printMarkdown(bundle *Bundle) {
+	utils.MarkdownHeading(1, "K8s Lens Report: %s", bundle.Namespace)
+	fmt.Printf("Generated at %s\n\n", bundle.GeneratedAt.Format(time.RFC3339))
+
+	withIssues := 0
+	for _, workload := range bundle.Diagnostics.Workloads {
+		if len(workload.Issues) > 0 || workload.Error != "" {
+			withIssues++
+		}
+	}
+	utils.MarkdownHeading(2, "Summary")
+	utils.MarkdownTable([]string{"Metric", "Value"}, [][]string{
+		{"Severity", bundle.Severity},
+		{"Workloads Analyzed", fmt.Sprintf("%d", len(bundle.Diagnostics.Workloads))},
+		{"Workloads With Issues", fmt.Sprintf("%d", withIssues)},
+		{"Compliance Score", fmt.Sprintf("%d/100", bundle.Security.ComplianceScore)},
+		{"Security Risk Level", bundle.Security.RiskLevel},
+		{"Security Issues", fmt.Sprintf("%d", len(bundle.Security.SecurityIssues))},
+		{"Optimizations", fmt.Sprintf("%d", bundle.Optimization.Summary.TotalOptimizations)},
+		{"Estimated Monthly Savings", fmt.Sprintf("$%.2f", bundle.Optimization.Summary.TotalMonthlySavings)},
+	})
+
+	if len(bundle.Diagnostics.Workloads) > 0 {
+		keys := make([]string, 0, len(bundle.Diagnostics.Workloads))
+		for key := range bundle.Diagnostics.Workloads {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		utils.MarkdownHeading(2, "Workloads")
+		var rows [][]string
+		var issues []string
+		for _, key := range keys {
+			workload := bundle.Diagnostics.Workloads[key]
+			ready := "-"
+			if workload.ReadyReplicas != nil && workload.DesiredReplicas != nil {
+				ready = fmt.Sprintf("%d/%d", *workload.ReadyReplicas, *workload.DesiredReplicas)
+			}
+			rows = append(rows, []string{key, workload.Status, ready, fmt.Sprintf("%d", len(workload.Issues))})
+			for _, issue := range workload.Issues {
+				issues = append(issues, fmt.Sprintf("`%s`: %s", key, issue))
+			}
+		}
+		utils.MarkdownTable([]string{"Workload", "Status", "Ready", "Issues"}, rows)
+		if len(issues) > 0 {
+			utils.MarkdownHeading(3, "Workload Issues")
+			utils.MarkdownList(issues)
+		}
+	}
+
+	if len(bundle.Security.SecurityIssues) > 0 {
+		utils.MarkdownHeading(2, "Security Issues")
+		var items []string
+		for _, issue := range bundle.Security.SecurityIssues {
+			items = append(items, fmt.Sprintf("**[%s]** %s: %s (`%s`)", issue.Severity, issue.Type, issue.Description, issue.Resource))
+		}
+		utils.MarkdownList(items)
+	}
+
+	if len(bundle.Optimization.Optimizations) > 0 {
+		utils.MarkdownHeading(2, "Resource Optimizations")
+		var rows [][]string
+		for _, opt := range bundle.Optimization.Optimizations {
+			rows = append(rows, []string{opt.PodName + "/" + opt.ContainerName, opt.Type, opt.Description,
+				fmt.Sprintf("$%.2f", opt.Savings.MonthlySavings)})
+		}
+		utils.MarkdownTable([]string{"Container", "Type", "Recommendation", "Monthly Savings"}, rows)
+	}
+}
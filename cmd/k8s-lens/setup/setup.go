@@ -26,7 +26,7 @@ var SetupCmd = &cobra.Command{
 		utils.PrintInfo("Testing Cluster Access")
 
 		// Test Basic Operations
-		if err := analyzer.TestConnection(); err != nil {
+		if err := analyzer.TestConnection(cmd.Context()); err != nil {
 			utils.PrintError("Cluster Access Test Failed: %s", err)
 			os.Exit(1)
 		}
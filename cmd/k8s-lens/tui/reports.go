@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/fatih/color"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	sectionColor = color.New(color.FgCyan, color.Bold)
+	healthyColor = color.New(color.FgGreen)
+	warningColor = color.New(color.FgYellow)
+	errorColor   = color.New(color.FgRed)
+	infoColor    = color.New(color.FgBlue)
+)
+
+// analyze runs the analyzer for the resource kind and renders its report as lines
+func analyze(ctx context.Context, client kubernetes.Interface, kind, name, namespace string) ([]line, error) {
+	switch kind {
+	case "pods":
+		analyzer := diagnostics.NewPodAnalyzer(client, namespace)
+		report, err := analyzer.Analyze(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return podReportLines(report), nil
+	case "deployments":
+		analyzer := diagnostics.NewDeploymentAnalyzer(client, namespace)
+		report, err := analyzer.Analyze(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		lines := []line{
+			section("Deployment Overview"),
+			{text: fmt.Sprintf("Replicas: %d desired, %d ready, %d available, %d updated",
+				report.DesiredReplicas, report.ReadyReplicas, report.AvailableReplicas, report.UpdatedReplicas)},
+			{text: fmt.Sprintf("Rollout: %s", report.Analysis.RolloutStatus)},
+		}
+		return append(lines, analysisLines(report.Analysis.Status, report.Analysis.Issues, report.Analysis.Recommendations)...), nil
+	case "statefulsets":
+		analyzer := diagnostics.NewStatefulSetAnalyzer(client, namespace)
+		report, err := analyzer.Analyze(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		lines := []line{
+			section("StatefulSet Overview"),
+			{text: fmt.Sprintf("Replicas: %d desired, %d ready, %d updated",
+				report.DesiredReplicas, report.ReadyReplicas, report.UpdatedReplicas)},
+			{text: fmt.Sprintf("Update Strategy: %s", report.Analysis.UpdateStrategy)},
+		}
+		return append(lines, analysisLines(report.Analysis.Status, report.Analysis.Issues, report.Analysis.Recommendations)...), nil
+	case "services":
+		analyzer := diagnostics.NewServiceAnalyzer(client, namespace)
+		report, err := analyzer.Analyze(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		var ports []string
+		for _, port := range report.Ports {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.Port, port.Protocol))
+		}
+		lines := []line{
+			section("Service Overview"),
+			{text: fmt.Sprintf("Type: %s", report.Type)},
+			{text: fmt.Sprintf("Cluster IP: %s", report.ClusterIP)},
+			{text: fmt.Sprintf("Ports: %s", strings.Join(ports, ", "))},
+			{text: fmt.Sprintf("Selector: %s", formatSelector(report.Selector))},
+		}
+		return append(lines, analysisLines(report.Analysis.Status, report.Analysis.Issues, report.Analysis.Recommendations)...), nil
+	}
+	return nil, fmt.Errorf("unsupported resource type: %s", kind)
+}
+
+func podReportLines(report *diagnostics.PodReport) []line {
+	status := "Healthy"
+	if len(report.Issues) > 0 {
+		status = "Needs Attention"
+	}
+
+	lines := []line{
+		section("Pod Overview"),
+		{text: fmt.Sprintf("Phase: %s", report.Phase)},
+		{text: fmt.Sprintf("Node: %s", report.Node)},
+		{text: fmt.Sprintf("Pod IP: %s", report.PodIP)},
+		{text: fmt.Sprintf("Restarts: %d", report.RestartCount)},
+		{
+			text:  fmt.Sprintf("Health Score: %d/100 (%s)", report.HealthScore, diagnostics.HealthRating(report.HealthScore)),
+			color: statusColor(diagnostics.HealthRating(report.HealthScore)),
+		},
+	}
+	if report.Owner != nil {
+		lines = append(lines, line{text: fmt.Sprintf("Owned By: %s %s", report.Owner.Kind, report.Owner.Name)})
+	}
+
+	lines = append(lines, blank(), section("Containers"))
+	for _, container := range report.Containers {
+		containerColor := healthyColor
+		if !container.Ready {
+			containerColor = errorColor
+		}
+		text := fmt.Sprintf("%s (%s): %s, ready=%t", container.Name, container.Image, container.Status, container.Ready)
+		if container.Reason != "" {
+			text += fmt.Sprintf(", reason=%s", container.Reason)
+		}
+		lines = append(lines, line{text: text, color: containerColor})
+	}
+
+	if len(report.SchedulingAnalysis) > 0 {
+		lines = append(lines, blank(), section("Scheduling Analysis"))
+		for _, reason := range report.SchedulingAnalysis {
+			lines = append(lines, line{text: "- " + reason, color: warningColor})
+		}
+	}
+
+	lines = append(lines, analysisLines(status, report.Issues, report.Recommendations)...)
+
+	if len(report.Events) > 0 {
+		lines = append(lines, blank(), section("Recent Events"))
+		for _, event := range report.Events {
+			eventColor := (*color.Color)(nil)
+			if event.Type == "Warning" {
+				eventColor = warningColor
+			}
+			lines = append(lines, line{text: fmt.Sprintf("%s: %s", event.Reason, event.Message), color: eventColor})
+		}
+	}
+
+	return lines
+}
+
+// analysisLines renders the status, issues and recommendations shared by all reports
+func analysisLines(status string, issues, recommendations []string) []line {
+	lines := []line{
+		blank(),
+		section("Analysis"),
+		{text: fmt.Sprintf("Status: %s", status), color: statusColor(status)},
+	}
+
+	if len(issues) > 0 {
+		lines = append(lines, blank(), section("Issues"))
+		for _, issue := range issues {
+			lines = append(lines, line{text: "- " + issue, color: errorColor})
+		}
+	}
+
+	if len(recommendations) > 0 {
+		lines = append(lines, blank(), section("Recommendations"))
+		for _, rec := range recommendations {
+			lines = append(lines, line{text: "- " + rec, color: infoColor})
+		}
+	}
+
+	return lines
+}
+
+// statusColor maps an analyzer status to a severity color
+func statusColor(status string) *color.Color {
+	switch status {
+	case "Healthy", "Complete", "Secure":
+		return healthyColor
+	case "Degraded", "Progressing", "Needs Attention", "Needs Review":
+		return warningColor
+	}
+	return errorColor
+}
+
+func formatSelector(selector map[string]string) string {
+	if len(selector) == 0 {
+		return "<none>"
+	}
+
+	var pairs []string
+	for key, value := range selector {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func section(title string) line {
+	return line{text: title, color: sectionColor}
+}
+
+func blank() line {
+	return line{}
+}
@@ -0,0 +1,397 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TUICmd represents the interactive terminal UI command
+var TUICmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Interactive terminal UI for exploring and analyzing resources",
+	Long: `Browse namespaces and resources interactively and run the analyzers on them.
+
+Keys:
+  up/down, k/j    Move the selection or scroll the report
+  pgup/pgdn       Scroll a page
+  enter, l        Open the selected item
+  esc, h, bksp    Go back
+  r               Refresh the current view
+  q, ctrl-c       Quit`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !utils.IsTerminal(os.Stdin) || !utils.IsTerminal(os.Stdout) {
+			utils.PrintError("The TUI requires an interactive terminal")
+			os.Exit(1)
+		}
+
+		k8sClient, err := k8s.NewClient()
+		if err != nil {
+			utils.PrintError("Error creating Kubernetes client: %v", err)
+			os.Exit(1)
+		}
+
+		if err := run(cmd.Context(), k8sClient); err != nil {
+			utils.PrintError("TUI error: %v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+type screen int
+
+const (
+	screenNamespaces screen = iota
+	screenKinds
+	screenResources
+	screenReport
+)
+
+// resourceKinds are the resource types the TUI can analyze
+var resourceKinds = []string{"pods", "deployments", "statefulsets", "services"}
+
+// app holds the navigation state of the TUI
+type app struct {
+	client kubernetes.Interface
+	// ctx bounds the API calls of the session, so --timeout applies to it
+	ctx    context.Context
+	screen screen
+
+	namespaces []string
+	resources  []string
+	report     []line
+
+	namespace string
+	kind      string
+	resource  string
+
+	// cursors remembers the selection on each screen so going back restores it
+	cursors map[screen]int
+	// offset is the first visible row of the current screen
+	offset  int
+	message string
+
+	width  int
+	height int
+}
+
+// line is a single row of a report with an optional color
+type line struct {
+	text  string
+	color *color.Color
+}
+
+func run(ctx context.Context, client kubernetes.Interface) error {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to enter raw mode: %v", err)
+	}
+	defer term.Restore(fd, state)
+
+	// Use the alternate screen so the terminal is left as it was on exit
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+
+	a := &app{
+		client:  client,
+		ctx:     ctx,
+		screen:  screenNamespaces,
+		cursors: make(map[screen]int),
+	}
+	a.load()
+
+	for {
+		a.render()
+		key, err := readKey()
+		if err != nil {
+			return err
+		}
+		if key == "quit" {
+			return nil
+		}
+		a.handleKey(key)
+	}
+}
+
+// readKey reads a single key press and maps it to an action name
+func readKey() (string, error) {
+	buf := make([]byte, 8)
+	n, err := os.Stdin.Read(buf)
+	if err != nil {
+		return "", err
+	}
+
+	switch input := string(buf[:n]); input {
+	case "\x1b[A", "k":
+		return "up", nil
+	case "\x1b[B", "j":
+		return "down", nil
+	case "\x1b[5~":
+		return "pgup", nil
+	case "\x1b[6~", " ":
+		return "pgdn", nil
+	case "\r", "\n", "\x1b[C", "l":
+		return "enter", nil
+	case "\x1b", "\x7f", "\x1b[D", "h":
+		return "back", nil
+	case "r":
+		return "refresh", nil
+	case "q", "\x03":
+		return "quit", nil
+	default:
+		return input, nil
+	}
+}
+
+func (a *app) handleKey(key string) {
+	page := a.visibleRows()
+	switch key {
+	case "up":
+		a.move(-1)
+	case "down":
+		a.move(1)
+	case "pgup":
+		a.move(-page)
+	case "pgdn":
+		a.move(page)
+	case "enter":
+		a.open()
+	case "back":
+		a.back()
+	case "refresh":
+		a.load()
+	}
+}
+
+// move changes the selection, or scrolls the report on the report screen
+func (a *app) move(delta int) {
+	if a.screen == screenReport {
+		a.offset = clamp(a.offset+delta, 0, len(a.report)-a.visibleRows())
+		return
+	}
+
+	cursor := clamp(a.cursors[a.screen]+delta, 0, len(a.items())-1)
+	a.cursors[a.screen] = cursor
+	if cursor < a.offset {
+		a.offset = cursor
+	} else if cursor >= a.offset+a.visibleRows() {
+		a.offset = cursor - a.visibleRows() + 1
+	}
+}
+
+func (a *app) open() {
+	items := a.items()
+	if a.screen == screenReport || len(items) == 0 {
+		return
+	}
+
+	selected := items[a.cursors[a.screen]]
+	switch a.screen {
+	case screenNamespaces:
+		a.namespace = selected
+		a.screen = screenKinds
+	case screenKinds:
+		a.kind = selected
+		a.screen = screenResources
+	case screenResources:
+		a.resource = selected
+		a.screen = screenReport
+	}
+	a.cursors[a.screen] = 0
+	a.load()
+}
+
+func (a *app) back() {
+	if a.screen == screenNamespaces {
+		return
+	}
+	a.screen--
+	a.offset = 0
+	a.message = ""
+	a.move(0)
+}
+
+// load fetches the data for the current screen
+func (a *app) load() {
+	a.offset = 0
+	a.message = "Loading..."
+	a.render()
+	a.message = ""
+
+	var err error
+	switch a.screen {
+	case screenNamespaces:
+		a.namespaces, err = listNamespaces(a.ctx, a.client)
+	case screenResources:
+		a.resources, err = listResources(a.ctx, a.client, a.kind, a.namespace)
+		if err == nil && len(a.resources) == 0 {
+			a.message = fmt.Sprintf("No %s found in namespace %s", a.kind, a.namespace)
+		}
+	case screenReport:
+		a.report, err = analyze(a.ctx, a.client, a.kind, a.resource, a.namespace)
+	}
+
+	if err != nil {
+		a.message = fmt.Sprintf("Error: %v", err)
+	}
+	a.move(0)
+}
+
+func (a *app) items() []string {
+	switch a.screen {
+	case screenNamespaces:
+		return a.namespaces
+	case screenKinds:
+		return resourceKinds
+	case screenResources:
+		return a.resources
+	}
+	return nil
+}
+
+func (a *app) title() string {
+	switch a.screen {
+	case screenKinds:
+		return fmt.Sprintf("K8s Lens > %s", a.namespace)
+	case screenResources:
+		return fmt.Sprintf("K8s Lens > %s > %s", a.namespace, a.kind)
+	case screenReport:
+		return fmt.Sprintf("K8s Lens > %s > %s > %s", a.namespace, a.kind, a.resource)
+	}
+	return "K8s Lens > namespaces"
+}
+
+// visibleRows is the number of content rows between the header and the footer
+func (a *app) visibleRows() int {
+	if a.height < 5 {
+		return 1
+	}
+	return a.height - 4
+}
+
+func (a *app) render() {
+	a.width, a.height = 80, 24
+	if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		a.width, a.height = width, height
+	}
+
+	var out strings.Builder
+	out.WriteString("\x1b[H\x1b[2J")
+	out.WriteString(color.New(color.FgCyan, color.Bold).Sprint(truncate(a.title(), a.width)))
+	out.WriteString("\r\n\r\n")
+
+	rows := a.visibleRows()
+	if a.screen == screenReport {
+		for i := a.offset; i < len(a.report) && i < a.offset+rows; i++ {
+			text := truncate(a.report[i].text, a.width)
+			if a.report[i].color != nil {
+				text = a.report[i].color.Sprint(text)
+			}
+			out.WriteString(text + "\r\n")
+		}
+	} else {
+		items := a.items()
+		for i := a.offset; i < len(items) && i < a.offset+rows; i++ {
+			text := truncate("  "+items[i], a.width)
+			if i == a.cursors[a.screen] {
+				text = "\x1b[7m" + fmt.Sprintf("%-*s", a.width, text) + "\x1b[0m"
+			}
+			out.WriteString(text + "\r\n")
+		}
+	}
+
+	footer := "↑/↓ move  enter open  esc back  r refresh  q quit"
+	if a.screen == screenReport {
+		footer = fmt.Sprintf("↑/↓ scroll  pgup/pgdn page  esc back  r refresh  q quit  (%d lines)", len(a.report))
+	}
+	if a.message != "" {
+		footer = a.message
+	}
+	out.WriteString(fmt.Sprintf("\x1b[%d;1H", a.height))
+	out.WriteString(color.New(color.Faint).Sprint(truncate(footer, a.width)))
+
+	fmt.Print(out.String())
+}
+
+func listNamespaces(ctx context.Context, client kubernetes.Interface) ([]string, error) {
+	namespaces, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %v", err)
+	}
+
+	var names []string
+	for _, namespace := range namespaces.Items {
+		names = append(names, namespace.Name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func listResources(ctx context.Context, client kubernetes.Interface, kind, namespace string) ([]string, error) {
+	var names []string
+	switch kind {
+	case "pods":
+		pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods: %v", err)
+		}
+		for _, pod := range pods.Items {
+			names = append(names, pod.Name)
+		}
+	case "deployments":
+		deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deployments: %v", err)
+		}
+		for _, deployment := range deployments.Items {
+			names = append(names, deployment.Name)
+		}
+	case "statefulsets":
+		statefulSets, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list statefulsets: %v", err)
+		}
+		for _, statefulSet := range statefulSets.Items {
+			names = append(names, statefulSet.Name)
+		}
+	case "services":
+		services, err := client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list services: %v", err)
+		}
+		for _, service := range services.Items {
+			names = append(names, service.Name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if width <= 0 || len(runes) <= width {
+		return text
+	}
+	return string(runes[:width])
+}
+
+func clamp(value, low, high int) int {
+	if value > high {
+		value = high
+	}
+	if value < low {
+		value = low
+	}
+	return value
+}
@@ -0,0 +1,140 @@
+package validate
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/abrarahmad1510/k8s-lens/internal/utils"
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"github.com/spf13/cobra"
+)
+
+// ValidateCmd checks manifests against the security and metadata policies
+var ValidateCmd = &cobra.Command{
+	Use:   "validate -f manifest.yaml",
+	Short: "Check manifests against security and labeling policies before they are applied",
+	Long: `Check every object of one or more manifests against the policies an admission
+webhook would enforce, without a cluster:
+
+  - security: the security scanner's checks on the pods a workload runs, such as
+    privileged containers, privilege escalation or running as root. High and
+    Critical findings fail as critical.
+  - required-metadata: the labels and annotations given with --require-labels and
+    --require-annotations or in the --checks-config file. Missing ones fail as
+    warnings.
+
+Prints PASS or FAIL per object and exits 0 when everything passes, 1 on warnings
+and 2 on critical violations, so it can gate a CI pipeline or pre-commit hook:
+
+  k8s-lens validate -f deploy.yaml --require-labels team,cost-center
+  helm template ./chart | k8s-lens validate -f -`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		files, _ := cmd.Flags().GetStringSlice("filename")
+		if len(files) == 0 {
+			utils.PrintError("No manifest given; pass one or more with -f")
+			os.Exit(1)
+		}
+
+		requiredLabels, _ := cmd.Flags().GetStringSlice("require-labels")
+		requiredAnnotations, _ := cmd.Flags().GetStringSlice("require-annotations")
+		if path, _ := cmd.Flags().GetString("checks-config"); path != "" {
+			config, err := diagnostics.LoadCustomChecks(path)
+			if err != nil {
+				utils.PrintError("Error loading checks config: %v", err)
+				os.Exit(1)
+			}
+			requiredLabels = append(requiredLabels, config.RequiredLabels...)
+			requiredAnnotations = append(requiredAnnotations, config.RequiredAnnotations...)
+		}
+
+		severity := diagnostics.SeverityHealthy
+		objects, failed := 0, 0
+		for _, file := range files {
+			validations, err := validateFile(file, requiredLabels, requiredAnnotations)
+			if err != nil {
+				utils.PrintError("Error reading manifest %s: %v", file, err)
+				os.Exit(1)
+			}
+			for _, validation := range validations {
+				objects++
+				if len(validation.Violations) > 0 {
+					failed++
+				}
+				printValidation(file, validation)
+				severity = diagnostics.MaxSeverity(severity, validation.Severity)
+			}
+		}
+
+		fmt.Println()
+		if objects == 0 {
+			utils.PrintWarning("No Kubernetes objects found in the manifests")
+		} else if failed == 0 {
+			utils.PrintSuccess("All %d object(s) passed", objects)
+		} else {
+			utils.PrintWarning("%d of %d object(s) failed validation", failed, objects)
+		}
+		utils.ExitOnSeverity(cmd.Flags(), severity)
+	},
+}
+
+// validateFile validates every object of a manifest file, or of stdin for "-"
+func validateFile(file string, requiredLabels, requiredAnnotations []string) ([]*diagnostics.ManifestValidation, error) {
+	var input io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		input = f
+	}
+
+	objects, err := diagnostics.ReadManifests(input)
+	if err != nil {
+		return nil, err
+	}
+	var validations []*diagnostics.ManifestValidation
+	for _, object := range objects {
+		validations = append(validations, diagnostics.ValidateManifest(object, requiredLabels, requiredAnnotations))
+	}
+	return validations, nil
+}
+
+func printValidation(file string, validation *diagnostics.ManifestValidation) {
+	resource := fmt.Sprintf("%s/%s", validation.Kind, validation.Name)
+	if validation.Namespace != "" {
+		resource = fmt.Sprintf("%s/%s", validation.Namespace, resource)
+	}
+	if file == "-" {
+		file = "stdin"
+	}
+
+	if validation.Suppressed > 0 {
+		file = fmt.Sprintf("%s, %d suppressed", file, validation.Suppressed)
+	}
+	if len(validation.Violations) == 0 {
+		utils.PrintSuccess("PASS %s (%s)", resource, file)
+		return
+	}
+	if validation.Severity == diagnostics.SeverityCritical {
+		utils.PrintError("FAIL %s (%s)", resource, file)
+	} else {
+		utils.PrintWarning("FAIL %s (%s)", resource, file)
+	}
+	for _, violation := range validation.Violations {
+		fmt.Printf("  - [%s] %s: %s\n", violation.Severity, violation.Policy, violation.Message)
+		if violation.Remediation != "" {
+			fmt.Printf("    fix: %s\n", violation.Remediation)
+		}
+	}
+}
+
+func init() {
+	ValidateCmd.Flags().StringSliceP("filename", "f", nil, "Manifest file to validate, or - for stdin; repeat for several files")
+	ValidateCmd.Flags().StringSlice("require-labels", nil, "Labels every object must carry, e.g. team,cost-center")
+	ValidateCmd.Flags().StringSlice("require-annotations", nil, "Annotations every object must carry")
+	ValidateCmd.Flags().String("checks-config", "", "YAML file listing required labels and annotations")
+	utils.AddFailOnFlag(ValidateCmd.Flags(), "warning")
+}
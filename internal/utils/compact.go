@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrintCompact prints a single color-coded line summarizing an analyzed resource: an
+// icon for its Healthy, Warning or Critical severity, the resource, its status, its
+// score when known (a negative score is omitted) and its most important finding
+func PrintCompact(severity, resource, status string, score int, detail string) {
+	icon, code := "✔", "32"
+	switch {
+	case strings.EqualFold(severity, "Critical"):
+		icon, code = "✖", "31"
+	case strings.EqualFold(severity, "Warning"):
+		icon, code = "!", "33"
+	}
+
+	scoreText := "-"
+	if score >= 0 {
+		scoreText = fmt.Sprintf("%d", score)
+	}
+
+	line := fmt.Sprintf("%s %-45s %-20s %5s", ansi(code, icon), resource, ansi(code, fmt.Sprintf("%-20s", status)), scoreText)
+	if detail != "" {
+		line += "  " + detail
+	}
+	fmt.Println(line)
+}
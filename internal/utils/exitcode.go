@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// Exit codes used by commands that report findings
+const (
+	ExitWarning  = 1
+	ExitCritical = 2
+)
+
+// failOnValue is a --fail-on flag value restricted to warning, critical or never
+type failOnValue string
+
+func (f *failOnValue) String() string { return string(*f) }
+
+func (f *failOnValue) Set(value string) error {
+	switch value {
+	case "warning", "critical", "never":
+		*f = failOnValue(value)
+		return nil
+	}
+	return fmt.Errorf("must be one of warning, critical, never")
+}
+
+// Type is reported as string so the flag can be read with GetString
+func (f *failOnValue) Type() string { return "string" }
+
+// AddFailOnFlag registers the --fail-on flag, which controls which finding severity
+// makes a command exit non-zero
+func AddFailOnFlag(flags *pflag.FlagSet, defaultValue string) {
+	value := failOnValue(defaultValue)
+	flags.Var(&value, "fail-on", "Exit non-zero when findings reach this severity: warning (exit 1, or 2 if critical), critical (exit 2), never")
+}
+
+// ExitCodeForSeverity returns the exit code for a Healthy, Warning or Critical
+// severity under the given --fail-on policy
+func ExitCodeForSeverity(failOn, severity string) int {
+	switch {
+	case failOn == "never":
+		return 0
+	case strings.EqualFold(severity, "Critical"):
+		return ExitCritical
+	case strings.EqualFold(severity, "Warning") && failOn == "warning":
+		return ExitWarning
+	}
+	return 0
+}
+
+// summaryLine returns the findings tally printed to stderr when a command ends, set
+// with SetSummaryLine
+var (
+	summaryLine    func() string
+	summaryPrinted bool
+)
+
+// SetSummaryLine sets the function producing the findings tally printed when a command
+// ends, such as "findings: 2 critical, 5 warning"
+func SetSummaryLine(fn func() string) {
+	summaryLine = fn
+}
+
+// PrintSummaryLine prints the findings tally to stderr, keeping stdout parseable. It
+// prints nothing when the command analyzed nothing, and only once per run.
+func PrintSummaryLine() {
+	if summaryLine == nil || summaryPrinted {
+		return
+	}
+	if line := summaryLine(); line != "" {
+		fmt.Fprintln(os.Stderr, line)
+		summaryPrinted = true
+	}
+}
+
+// ExitOnSeverity prints the findings tally, then exits with the code for severity under
+// the command's --fail-on policy, and returns normally when that code is zero
+func ExitOnSeverity(flags *pflag.FlagSet, severity string) {
+	PrintSummaryLine()
+	failOn, _ := flags.GetString("fail-on")
+	if code := ExitCodeForSeverity(failOn, severity); code != 0 {
+		os.Exit(code)
+	}
+}
@@ -10,30 +10,39 @@ import (
 // PrintInfo prints an info message
 func PrintInfo(format string, a ...interface{}) {
 	message := fmt.Sprintf(format, a...)
-	fmt.Printf("\033[36mINFO:\033[0m %s\n", message)
+	fmt.Printf("%s %s\n", ansi("36", "INFO:"), message)
 }
 
 // PrintSuccess prints a success message
 func PrintSuccess(format string, a ...interface{}) {
 	message := fmt.Sprintf(format, a...)
-	fmt.Printf("\033[32mSUCCESS:\033[0m %s\n", message)
+	fmt.Printf("%s %s\n", ansi("32", "SUCCESS:"), message)
 }
 
 // PrintWarning prints a warning message
 func PrintWarning(format string, a ...interface{}) {
 	message := fmt.Sprintf(format, a...)
-	fmt.Printf("\033[33mWARNING:\033[0m %s\n", message)
+	fmt.Printf("%s %s\n", ansi("33", "WARNING:"), message)
 }
 
 // PrintError prints an error message
 func PrintError(format string, a ...interface{}) {
 	message := fmt.Sprintf(format, a...)
-	fmt.Printf("\033[31mERROR:\033[0m %s\n", message)
+	fmt.Printf("%s %s\n", ansi("31", "ERROR:"), message)
 }
 
 // PrintSection prints a section header
 func PrintSection(title string) {
-	fmt.Printf("\n\033[1;34m=== %s ===\033[0m\n", title)
+	fmt.Printf("\n%s\n", ansi("1;34", fmt.Sprintf("=== %s ===", title)))
+}
+
+// ansi wraps text in an ANSI escape sequence unless color output is disabled,
+// e.g. because stdout is not a terminal or output is redirected to a file
+func ansi(code, text string) string {
+	if color.NoColor {
+		return text
+	}
+	return "\033[" + code + "m" + text + "\033[0m"
 }
 
 // GetGoVersion returns the Go version
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MarkdownHeading prints a markdown heading of the given level, e.g. 2 for "## title"
+func MarkdownHeading(level int, format string, a ...interface{}) {
+	fmt.Printf("%s %s\n\n", strings.Repeat("#", level), fmt.Sprintf(format, a...))
+}
+
+// MarkdownList prints items as a markdown bullet list
+func MarkdownList(items []string) {
+	if len(items) == 0 {
+		return
+	}
+	for _, item := range items {
+		fmt.Printf("- %s\n", markdownLine(item))
+	}
+	fmt.Println()
+}
+
+// MarkdownTable prints rows as a markdown table under the header row. Pipes in cells
+// are escaped so they do not split columns.
+func MarkdownTable(header []string, rows [][]string) {
+	cells := func(row []string) string {
+		escaped := make([]string, len(row))
+		for i, cell := range row {
+			escaped[i] = strings.ReplaceAll(markdownLine(cell), "|", `\|`)
+		}
+		return "| " + strings.Join(escaped, " | ") + " |"
+	}
+
+	fmt.Println(cells(header))
+	separator := make([]string, len(header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	fmt.Println("| " + strings.Join(separator, " | ") + " |")
+	for _, row := range rows {
+		fmt.Println(cells(row))
+	}
+	fmt.Println()
+}
+
+// markdownLine folds a multi-line message onto one line, so it stays in its list item
+// or table cell
+func markdownLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
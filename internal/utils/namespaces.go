@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+	"github.com/spf13/pflag"
+)
+
+// AddNamespaceFlags registers -n/--namespace, which accepts a namespace, a comma-separated
+// list of namespaces or all, and -A/--all-namespaces
+func AddNamespaceFlags(flags *pflag.FlagSet) {
+	flags.StringP("namespace", "n", "default", "Namespace or comma-separated namespaces")
+	flags.BoolP("all-namespaces", "A", false, "Analyze every namespace")
+}
+
+// MultipleNamespaces reports whether the namespace flags select more than one namespace
+func MultipleNamespaces(flags *pflag.FlagSet) bool {
+	if all, _ := flags.GetBool("all-namespaces"); all {
+		return true
+	}
+	namespace, _ := flags.GetString("namespace")
+	return strings.Contains(namespace, ",")
+}
+
+// AddFilterFlags registers --include and --exclude, which scope bulk runs by label
+// selector or name glob. Both may be repeated.
+func AddFilterFlags(flags *pflag.FlagSet) {
+	flags.StringArray("include", nil, "Only keep resources matching this label selector or name glob, e.g. app=payments (repeatable)")
+	flags.StringArray("exclude", nil, "Skip resources matching this label selector or name glob, e.g. kube-* (repeatable)")
+}
+
+// AddNamespaceFilterFlags registers --include and --exclude for commands that scan whole
+// namespaces, where they only take namespace name globs. Both may be repeated.
+func AddNamespaceFilterFlags(flags *pflag.FlagSet) {
+	flags.StringArray("include", nil, "Only scan namespaces matching this name glob, e.g. team-* (repeatable; label selectors are not supported)")
+	flags.StringArray("exclude", nil, "Skip namespaces matching this name glob, e.g. kube-* (repeatable; label selectors are not supported)")
+}
+
+// ResourceFilter parses the --include and --exclude flags
+func ResourceFilter(flags *pflag.FlagSet) (*k8s.ResourceFilter, error) {
+	include, _ := flags.GetStringArray("include")
+	exclude, _ := flags.GetStringArray("exclude")
+	return k8s.NewResourceFilter(include, exclude)
+}
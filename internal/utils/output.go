@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+)
+
+// RedirectOutput sends everything written to stdout to the file at path instead,
+// creating parent directories as needed. Color output is disabled so the file
+// contains plain text. The caller is responsible for closing the returned file.
+func RedirectOutput(path string) (*os.File, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory %s: %v", dir, err)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file %s: %v", path, err)
+	}
+
+	os.Stdout = file
+	color.Output = file
+	color.NoColor = true
+
+	return file, nil
+}
+
+// IsTerminal reports whether the file is attached to a terminal
+func IsTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// Spinner shows an animated progress message on stderr for long-running operations.
+// A disabled spinner is a no-op, so callers don't need to check before using it.
+type Spinner struct {
+	out     io.Writer
+	enabled bool
+
+	mu      sync.Mutex
+	message string
+	width   int
+	done    chan struct{}
+	stopped sync.WaitGroup
+}
+
+// NewSpinner creates a spinner writing to stderr. It is disabled when quiet is set
+// or stderr is not a terminal.
+func NewSpinner(quiet bool) *Spinner {
+	return &Spinner{
+		out:     os.Stderr,
+		enabled: !quiet && IsTerminal(os.Stderr),
+	}
+}
+
+// Start begins animating the spinner with the given message
+func (s *Spinner) Start(message string) {
+	if !s.enabled || s.done != nil {
+		return
+	}
+
+	s.message = message
+	s.done = make(chan struct{})
+	s.stopped.Add(1)
+
+	go func() {
+		defer s.stopped.Done()
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+
+		for frame := 0; ; frame++ {
+			s.render(spinnerFrames[frame%len(spinnerFrames)])
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Update changes the message shown next to the spinner
+func (s *Spinner) Update(message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.message = message
+}
+
+// Stop halts the spinner and clears its line
+func (s *Spinner) Stop() {
+	if !s.enabled || s.done == nil {
+		return
+	}
+
+	close(s.done)
+	s.stopped.Wait()
+	s.done = nil
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fmt.Fprintf(s.out, "\r%s\r", strings.Repeat(" ", s.width))
+}
+
+func (s *Spinner) render(frame string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line := fmt.Sprintf("%s %s", frame, s.message)
+	// Pad with spaces to overwrite a longer previous message
+	padding := ""
+	if len(line) < s.width {
+		padding = strings.Repeat(" ", s.width-len(line))
+	}
+	s.width = len(line)
+	fmt.Fprintf(s.out, "\r%s%s", line, padding)
+}
+
+// ProgressBar renders how far an operation is as a bar of the given width, e.g.
+// "[#####-----]" when half done
+func ProgressBar(done, total, width int) string {
+	filled := width
+	if total > 0 {
+		filled = done * width / total
+	}
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
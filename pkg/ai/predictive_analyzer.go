@@ -42,15 +42,15 @@ type Prediction struct {
 }
 
 // PredictFailures analyzes a deployment for potential failures
-func (p *PredictiveAnalyzer) PredictFailures(deploymentName, namespace string) (*PredictionReport, error) {
+func (p *PredictiveAnalyzer) PredictFailures(ctx context.Context, deploymentName, namespace string) (*PredictionReport, error) {
 	// Get the deployment
-	deployment, err := p.client.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName, metav1.GetOptions{})
+	deployment, err := p.client.AppsV1().Deployments(namespace).Get(ctx, deploymentName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get deployment %s: %v", deploymentName, err)
 	}
 
 	// Get pods for the deployment
-	pods, err := p.client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+	pods, err := p.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: metav1.FormatLabelSelector(deployment.Spec.Selector),
 	})
 	if err != nil {
@@ -58,7 +58,7 @@ func (p *PredictiveAnalyzer) PredictFailures(deploymentName, namespace string) (
 	}
 
 	// Get events for the namespace
-	events, err := p.client.CoreV1().Events(namespace).List(context.TODO(), metav1.ListOptions{})
+	events, err := p.client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events for namespace %s: %v", namespace, err)
 	}
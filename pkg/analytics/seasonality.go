@@ -0,0 +1,216 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations"
+)
+
+const (
+	// seasonalityWindow is how much history is pulled to detect recurring patterns
+	seasonalityWindow = 7 * 24 * time.Hour
+	// seasonalityStep is the resolution of the history used for pattern detection
+	seasonalityStep = time.Hour
+	// minSeasonalAmplitude is the minimum relative swing around the mean that counts as a pattern
+	minSeasonalAmplitude = 0.3
+)
+
+// seasonalPattern describes a recurring load pattern found in a metric's history
+type seasonalPattern struct {
+	Period     string
+	Peak       string
+	Amplitude  float64
+	Confidence float64
+}
+
+// analyzeSeasonalTrends pulls a week of CPU and memory history for the namespace and
+// reports recurring daily and weekly load patterns
+func (t *TrendAnalyzer) analyzeSeasonalTrends(namespace string) []PerformanceTrend {
+	if t.history == nil {
+		return nil
+	}
+
+	metrics := []struct {
+		name  string
+		query string
+	}{
+		{"CPU Usage", fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{namespace="%s", container!=""}[5m]))`, namespace)},
+		{"Memory Usage", fmt.Sprintf(`sum(container_memory_working_set_bytes{namespace="%s", container!=""})`, namespace)},
+	}
+
+	var trends []PerformanceTrend
+	end := time.Now()
+	start := end.Add(-seasonalityWindow)
+	for _, metric := range metrics {
+		samples, err := t.history.QueryRange(metric.query, start, end, seasonalityStep)
+		if err != nil {
+			continue
+		}
+
+		patterns := []*seasonalPattern{detectDailyPattern(samples), detectWeeklyPattern(samples)}
+		for _, pattern := range patterns {
+			if pattern == nil {
+				continue
+			}
+			trends = append(trends, PerformanceTrend{
+				Component:  "Namespace",
+				Metric:     metric.name,
+				Pattern:    fmt.Sprintf("Recurring %s peak %s (%.0f%% swing around the mean)", pattern.Period, pattern.Peak, pattern.Amplitude*100),
+				Confidence: pattern.Confidence,
+				Impact:     "Medium - Size for peak load rather than average",
+				Period:     pattern.Period,
+				Amplitude:  pattern.Amplitude,
+			})
+		}
+	}
+
+	return trends
+}
+
+// detectDailyPattern looks for a consistent hour-of-day peak. The confidence is the
+// share of days whose own peak falls within an hour of the overall peak hour.
+func detectDailyPattern(samples []integrations.Sample) *seasonalPattern {
+	var hourTotals [24]float64
+	var hourCounts [24]int
+	dailyPeaks := make(map[string]int)
+	dailyPeakValues := make(map[string]float64)
+
+	for _, sample := range samples {
+		hour := sample.Timestamp.Hour()
+		hourTotals[hour] += sample.Value
+		hourCounts[hour]++
+
+		day := sample.Timestamp.Format("2006-01-02")
+		if value, ok := dailyPeakValues[day]; !ok || sample.Value > value {
+			dailyPeakValues[day] = sample.Value
+			dailyPeaks[day] = hour
+		}
+	}
+
+	// Require at least three days of data to call a pattern recurring
+	if len(dailyPeaks) < 3 {
+		return nil
+	}
+
+	var hourMeans []float64
+	peakHour := -1
+	for hour := 0; hour < 24; hour++ {
+		if hourCounts[hour] == 0 {
+			continue
+		}
+		mean := hourTotals[hour] / float64(hourCounts[hour])
+		hourMeans = append(hourMeans, mean)
+		if peakHour == -1 || mean > hourTotals[peakHour]/float64(hourCounts[peakHour]) {
+			peakHour = hour
+		}
+	}
+
+	amplitude := relativeAmplitude(hourMeans)
+	if peakHour == -1 || amplitude < minSeasonalAmplitude {
+		return nil
+	}
+
+	matchingDays := 0
+	for _, hour := range dailyPeaks {
+		distance := int(math.Abs(float64(hour - peakHour)))
+		if distance > 12 {
+			distance = 24 - distance
+		}
+		if distance <= 1 {
+			matchingDays++
+		}
+	}
+	confidence := float64(matchingDays) / float64(len(dailyPeaks))
+	if confidence < 0.5 {
+		return nil
+	}
+
+	return &seasonalPattern{
+		Period:     "daily",
+		Peak:       fmt.Sprintf("at %02d:00", peakHour),
+		Amplitude:  amplitude,
+		Confidence: confidence,
+	}
+}
+
+// detectWeeklyPattern compares weekday and weekend load. It needs a full week of history.
+func detectWeeklyPattern(samples []integrations.Sample) *seasonalPattern {
+	if len(samples) == 0 || samples[len(samples)-1].Timestamp.Sub(samples[0].Timestamp) < seasonalityWindow-seasonalityStep {
+		return nil
+	}
+
+	var dayTotals [7]float64
+	var dayCounts [7]int
+	for _, sample := range samples {
+		day := sample.Timestamp.Weekday()
+		dayTotals[day] += sample.Value
+		dayCounts[day]++
+	}
+
+	var dayMeans []float64
+	peakDay := -1
+	weekdayTotal, weekdayCount := 0.0, 0
+	weekendTotal, weekendCount := 0.0, 0
+	for day := 0; day < 7; day++ {
+		if dayCounts[day] == 0 {
+			return nil
+		}
+		mean := dayTotals[day] / float64(dayCounts[day])
+		dayMeans = append(dayMeans, mean)
+		if peakDay == -1 || mean > dayMeans[peakDay] {
+			peakDay = day
+		}
+
+		if time.Weekday(day) == time.Saturday || time.Weekday(day) == time.Sunday {
+			weekendTotal += dayTotals[day]
+			weekendCount += dayCounts[day]
+		} else {
+			weekdayTotal += dayTotals[day]
+			weekdayCount += dayCounts[day]
+		}
+	}
+
+	amplitude := relativeAmplitude(dayMeans)
+	if amplitude < minSeasonalAmplitude {
+		return nil
+	}
+
+	peak := fmt.Sprintf("on %s", time.Weekday(peakDay))
+	weekdayMean := weekdayTotal / float64(weekdayCount)
+	weekendMean := weekendTotal / float64(weekendCount)
+	if weekendMean > 0 && weekdayMean/weekendMean > 1+minSeasonalAmplitude {
+		peak = "on weekdays"
+	} else if weekdayMean > 0 && weekendMean/weekdayMean > 1+minSeasonalAmplitude {
+		peak = "on weekends"
+	}
+
+	// A single week of data only shows one cycle, so confidence is capped
+	return &seasonalPattern{
+		Period:     "weekly",
+		Peak:       peak,
+		Amplitude:  amplitude,
+		Confidence: 0.6,
+	}
+}
+
+// relativeAmplitude returns half the peak-to-trough range relative to the mean
+func relativeAmplitude(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	minValue, maxValue, total := values[0], values[0], 0.0
+	for _, value := range values {
+		minValue = math.Min(minValue, value)
+		maxValue = math.Max(maxValue, value)
+		total += value
+	}
+
+	mean := total / float64(len(values))
+	if mean == 0 {
+		return 0
+	}
+	return (maxValue - minValue) / 2 / mean
+}
@@ -3,17 +3,37 @@ package analytics
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	// trendSamples is how many samples over the period a trend is fitted through
+	trendSamples = 60
+	// minTrendStep is the finest resolution queried, a few Prometheus scrape intervals
+	minTrendStep = time.Minute
+	// minTrendSamples is the fewest samples a trend is fitted through
+	minTrendSamples = 3
+	// minTrendSignificance is the t-statistic a fitted slope needs to be reported as a
+	// trend, about 95% confidence
+	minTrendSignificance = 2.0
+)
+
 // TrendAnalyzer analyzes historical trends and patterns
 type TrendAnalyzer struct {
-	client kubernetes.Interface
+	client  kubernetes.Interface
+	history MetricsHistory
+}
+
+// MetricsHistory provides historical metric values, e.g. from Prometheus
+type MetricsHistory interface {
+	QueryRange(query string, start, end time.Time, step time.Duration) ([]integrations.Sample, error)
 }
 
 // NewTrendAnalyzer creates a new trend analyzer
@@ -23,10 +43,17 @@ func NewTrendAnalyzer(client kubernetes.Interface) *TrendAnalyzer {
 	}
 }
 
+// SetMetricsHistory configures the source used to compare current values with past ones.
+// Without it, trends are reported as a current snapshot only.
+func (t *TrendAnalyzer) SetMetricsHistory(history MetricsHistory) {
+	t.history = history
+}
+
 // TrendReport contains trend analysis results
 type TrendReport struct {
 	Namespace         string
 	AnalysisPeriod    time.Duration
+	HasHistory        bool
 	ResourceTrends    []ResourceTrend
 	PerformanceTrends []PerformanceTrend
 	Recommendations   []string
@@ -35,12 +62,16 @@ type TrendReport struct {
 
 // ResourceTrend shows resource usage trends
 type ResourceTrend struct {
-	ResourceType  string
-	Metric        string
-	CurrentValue  float64
+	ResourceType string
+	Metric       string
+	CurrentValue float64
+	// PreviousValue is where the line fitted through the history starts, and
+	// ChangePercent the change along that line over the period
 	PreviousValue float64
 	ChangePercent float64
-	Trend         string // Increasing, Decreasing, Stable
+	Trend         string // Increasing, Decreasing, Stable, Unknown
+	// Historical is false when only the current snapshot value is known
+	Historical bool
 }
 
 // PerformanceTrend shows performance patterns
@@ -50,10 +81,13 @@ type PerformanceTrend struct {
 	Pattern    string
 	Confidence float64
 	Impact     string
+	// Period and Amplitude are set for recurring patterns, e.g. "daily" with a 0.4 relative swing
+	Period    string
+	Amplitude float64
 }
 
 // AnalyzeNamespaceTrends analyzes trends in a namespace over time
-func (t *TrendAnalyzer) AnalyzeNamespaceTrends(namespace string, period time.Duration) (*TrendReport, error) {
+func (t *TrendAnalyzer) AnalyzeNamespaceTrends(ctx context.Context, namespace string, period time.Duration) (*TrendReport, error) {
 	report := &TrendReport{
 		Namespace:      namespace,
 		AnalysisPeriod: period,
@@ -61,23 +95,25 @@ func (t *TrendAnalyzer) AnalyzeNamespaceTrends(namespace string, period time.Dur
 	}
 
 	// Get current state
-	currentPods, err := t.client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	currentPods, err := t.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current pods: %v", err)
 	}
 
 	// Get deployments for workload analysis
-	deployments, err := t.client.AppsV1().Deployments(namespace).List(context.TODO(), metav1.ListOptions{})
+	deployments, err := t.client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get deployments: %v", err)
 	}
 
 	// Analyze resource trends
-	resourceTrends := t.analyzeResourceTrends(currentPods.Items, deployments.Items)
+	resourceTrends := t.analyzeResourceTrends(namespace, period, currentPods.Items)
 	report.ResourceTrends = resourceTrends
+	report.HasHistory = hasHistoricalTrends(resourceTrends)
 
 	// Analyze performance trends
 	performanceTrends := t.analyzePerformanceTrends(currentPods.Items, deployments.Items)
+	performanceTrends = append(performanceTrends, t.analyzeSeasonalTrends(namespace)...)
 	report.PerformanceTrends = performanceTrends
 
 	// Generate recommendations
@@ -86,40 +122,12 @@ func (t *TrendAnalyzer) AnalyzeNamespaceTrends(namespace string, period time.Dur
 	return report, nil
 }
 
-func (t *TrendAnalyzer) analyzeResourceTrends(pods []corev1.Pod, deployments []appsv1.Deployment) []ResourceTrend {
+func (t *TrendAnalyzer) analyzeResourceTrends(namespace string, period time.Duration, pods []corev1.Pod) []ResourceTrend {
 	var trends []ResourceTrend
 
-	// Analyze pod count trend
-	podCount := len(pods)
-	// In a real implementation, you'd compare with historical data
-	// For now, we'll use a simulated previous value
-	previousPodCount := podCount - 1 // Simulate decrease
-	if previousPodCount < 0 {
-		previousPodCount = 0
-	}
+	// Snapshot values from the current cluster state
+	podCount := float64(len(pods))
 
-	podChangePercent := 0.0
-	if previousPodCount > 0 {
-		podChangePercent = (float64(podCount) - float64(previousPodCount)) / float64(previousPodCount) * 100
-	}
-
-	podTrend := "Stable"
-	if podChangePercent > 5 {
-		podTrend = "Increasing"
-	} else if podChangePercent < -5 {
-		podTrend = "Decreasing"
-	}
-
-	trends = append(trends, ResourceTrend{
-		ResourceType:  "Pods",
-		Metric:        "Count",
-		CurrentValue:  float64(podCount),
-		PreviousValue: float64(previousPodCount),
-		ChangePercent: podChangePercent,
-		Trend:         podTrend,
-	})
-
-	// Analyze resource requests trend
 	totalCPU := int64(0)
 	totalMemory := int64(0)
 	containerCount := 0
@@ -137,51 +145,128 @@ func (t *TrendAnalyzer) analyzeResourceTrends(pods []corev1.Pod, deployments []a
 		}
 	}
 
+	trends = append(trends, t.resourceTrend("Pods", "Count", podCount, period,
+		fmt.Sprintf(`count(kube_pod_info{namespace="%s"})`, namespace)))
+
 	if containerCount > 0 {
 		avgCPU := float64(totalCPU) / float64(containerCount)
 		avgMemory := float64(totalMemory) / float64(containerCount)
 
-		// Simulate previous values (in real implementation, fetch historical data)
-		previousAvgCPU := avgCPU * 0.9
-		previousAvgMemory := avgMemory * 0.95
-
-		cpuChangePercent := (avgCPU - previousAvgCPU) / previousAvgCPU * 100
-		memoryChangePercent := (avgMemory - previousAvgMemory) / previousAvgMemory * 100
+		trends = append(trends, t.resourceTrend("Containers", "Average CPU Request (millicores)", avgCPU, period,
+			fmt.Sprintf(`avg(kube_pod_container_resource_requests{namespace="%s", resource="cpu"}) * 1000`, namespace)))
+		trends = append(trends, t.resourceTrend("Containers", "Average Memory Request (MB)", avgMemory, period,
+			fmt.Sprintf(`avg(kube_pod_container_resource_requests{namespace="%s", resource="memory"}) / (1024 * 1024)`, namespace)))
+	}
 
-		cpuTrend := "Stable"
-		if cpuChangePercent > 5 {
-			cpuTrend = "Increasing"
-		} else if cpuChangePercent < -5 {
-			cpuTrend = "Decreasing"
+	// Actual usage is only available from history
+	if t.history != nil {
+		usageQueries := []struct {
+			metric string
+			query  string
+		}{
+			{"Total CPU Usage (cores)", fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{namespace="%s", container!=""}[5m]))`, namespace)},
+			{"Total Memory Usage (MB)", fmt.Sprintf(`sum(container_memory_working_set_bytes{namespace="%s", container!=""}) / (1024 * 1024)`, namespace)},
+		}
+		for _, usage := range usageQueries {
+			trend := t.resourceTrend("Containers", usage.metric, 0, period, usage.query)
+			if trend.Historical {
+				trends = append(trends, trend)
+			}
 		}
+	}
+
+	return trends
+}
+
+// resourceTrend fits a line through the samples of a metric over the period and reports
+// the change along it, so a single noisy sample at either end does not make a trend.
+// Without history, or when the query returns too few samples, only the current
+// snapshot value is reported.
+func (t *TrendAnalyzer) resourceTrend(resourceType, metric string, snapshot float64, period time.Duration, query string) ResourceTrend {
+	trend := ResourceTrend{
+		ResourceType: resourceType,
+		Metric:       metric,
+		CurrentValue: snapshot,
+		Trend:        "Unknown",
+	}
+
+	if t.history == nil {
+		return trend
+	}
 
-		memoryTrend := "Stable"
-		if memoryChangePercent > 5 {
-			memoryTrend = "Increasing"
-		} else if memoryChangePercent < -5 {
-			memoryTrend = "Decreasing"
+	end := time.Now()
+	step := period / trendSamples
+	if step < minTrendStep {
+		step = minTrendStep
+	}
+	samples, err := t.history.QueryRange(query, end.Add(-period), end, step)
+	if err != nil || len(samples) < minTrendSamples {
+		return trend
+	}
+
+	start, fittedEnd, significance := fitLine(samples)
+	trend.CurrentValue = samples[len(samples)-1].Value
+	trend.PreviousValue = start
+	trend.Historical = true
+
+	if start > 0 {
+		trend.ChangePercent = (fittedEnd - start) / start * 100
+	}
+
+	// A slope the scatter of the samples could explain is noise
+	trend.Trend = "Stable"
+	if significance >= minTrendSignificance {
+		if trend.ChangePercent > 5 {
+			trend.Trend = "Increasing"
+		} else if trend.ChangePercent < -5 {
+			trend.Trend = "Decreasing"
 		}
+	}
 
-		trends = append(trends, ResourceTrend{
-			ResourceType:  "Containers",
-			Metric:        "Average CPU Request (millicores)",
-			CurrentValue:  avgCPU,
-			PreviousValue: previousAvgCPU,
-			ChangePercent: cpuChangePercent,
-			Trend:         cpuTrend,
-		})
-
-		trends = append(trends, ResourceTrend{
-			ResourceType:  "Containers",
-			Metric:        "Average Memory Request (MB)",
-			CurrentValue:  avgMemory,
-			PreviousValue: previousAvgMemory,
-			ChangePercent: memoryChangePercent,
-			Trend:         memoryTrend,
-		})
+	return trend
+}
+
+// fitLine fits a least-squares line through the samples and returns its values at the
+// first and last sample, and the t-statistic of its slope: how many standard errors
+// the slope is away from flat
+func fitLine(samples []integrations.Sample) (start, end, significance float64) {
+	origin := samples[0].Timestamp
+	n := float64(len(samples))
+	var meanX, meanY float64
+	for _, sample := range samples {
+		meanX += sample.Timestamp.Sub(origin).Seconds() / n
+		meanY += sample.Value / n
 	}
 
-	return trends
+	var sxx, sxy float64
+	for _, sample := range samples {
+		dx := sample.Timestamp.Sub(origin).Seconds() - meanX
+		sxx += dx * dx
+		sxy += dx * (sample.Value - meanY)
+	}
+	if sxx == 0 {
+		return meanY, meanY, 0
+	}
+	slope := sxy / sxx
+	intercept := meanY - slope*meanX
+
+	var squares float64
+	for _, sample := range samples {
+		residual := sample.Value - (intercept + slope*sample.Timestamp.Sub(origin).Seconds())
+		squares += residual * residual
+	}
+
+	last := samples[len(samples)-1].Timestamp.Sub(origin).Seconds()
+	start, end = intercept, intercept+slope*last
+	switch standardError := math.Sqrt(squares/(n-2)) / math.Sqrt(sxx); {
+	case slope == 0:
+		significance = 0
+	case standardError == 0:
+		significance = math.Inf(1)
+	default:
+		significance = math.Abs(slope) / standardError
+	}
+	return start, end, significance
 }
 
 func (t *TrendAnalyzer) analyzePerformanceTrends(pods []corev1.Pod, deployments []appsv1.Deployment) []PerformanceTrend {
@@ -254,6 +339,11 @@ func (t *TrendAnalyzer) analyzePerformanceTrends(pods []corev1.Pod, deployments
 func (t *TrendAnalyzer) generateTrendRecommendations(resourceTrends []ResourceTrend, performanceTrends []PerformanceTrend) []string {
 	recommendations := []string{}
 
+	if len(resourceTrends) > 0 && !hasHistoricalTrends(resourceTrends) {
+		recommendations = append(recommendations,
+			"Trends are a current snapshot only - connect Prometheus to compare against historical values")
+	}
+
 	// Analyze resource trends for recommendations
 	for _, trend := range resourceTrends {
 		if trend.Trend == "Increasing" && trend.ChangePercent > 20 {
@@ -265,6 +355,12 @@ func (t *TrendAnalyzer) generateTrendRecommendations(resourceTrends []ResourceTr
 
 	// Analyze performance trends for recommendations
 	for _, trend := range performanceTrends {
+		if trend.Period != "" {
+			recommendations = append(recommendations,
+				fmt.Sprintf("%s follows a %s pattern - right-size requests and autoscaling for the peak rather than the average",
+					trend.Metric, trend.Period))
+			continue
+		}
 		if trend.Impact == "High" {
 			recommendations = append(recommendations,
 				fmt.Sprintf("Address %s: %s", trend.Component, trend.Pattern))
@@ -278,3 +374,12 @@ func (t *TrendAnalyzer) generateTrendRecommendations(resourceTrends []ResourceTr
 
 	return recommendations
 }
+
+func hasHistoricalTrends(trends []ResourceTrend) bool {
+	for _, trend := range trends {
+		if trend.Historical {
+			return true
+		}
+	}
+	return false
+}
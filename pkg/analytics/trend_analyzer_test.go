@@ -0,0 +1,63 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeHistory answers every range query with the same values, one per step
+type fakeHistory struct {
+	values []float64
+}
+
+func (f fakeHistory) QueryRange(query string, start, end time.Time, step time.Duration) ([]integrations.Sample, error) {
+	samples := make([]integrations.Sample, len(f.values))
+	for i, value := range f.values {
+		samples[i] = integrations.Sample{Timestamp: start.Add(time.Duration(i) * step), Value: value}
+	}
+	return samples, nil
+}
+
+func TestResourceTrend(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     []float64
+		wantTrend  string
+		historical bool
+	}{
+		{name: "steady growth", values: []float64{100, 110, 120, 130, 140}, wantTrend: "Increasing", historical: true},
+		{name: "steady decline", values: []float64{140, 130, 120, 110, 100}, wantTrend: "Decreasing", historical: true},
+		{name: "noisy growth", values: []float64{100, 101, 99, 103, 104, 106, 105, 108, 107, 110}, wantTrend: "Increasing", historical: true},
+		{name: "flat", values: []float64{100, 100, 100, 100}, wantTrend: "Stable", historical: true},
+		// Two points would read these as +50% and -33%; the fit sees noise around 100
+		{name: "spike at the end", values: []float64{100, 90, 110, 95, 105, 100, 150}, wantTrend: "Stable", historical: true},
+		{name: "dip at the start", values: []float64{60, 100, 95, 105, 100, 98, 102}, wantTrend: "Stable", historical: true},
+		{name: "too few samples", values: []float64{100, 200}, wantTrend: "Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &TrendAnalyzer{history: fakeHistory{values: tt.values}}
+			trend := analyzer.resourceTrend("Pods", "Count", 0, time.Hour, "query")
+			assert.Equal(t, tt.wantTrend, trend.Trend)
+			assert.Equal(t, tt.historical, trend.Historical)
+		})
+	}
+}
+
+func TestFitLine(t *testing.T) {
+	origin := time.Now()
+	samples := []integrations.Sample{
+		{Timestamp: origin, Value: 10},
+		{Timestamp: origin.Add(time.Minute), Value: 20},
+		{Timestamp: origin.Add(2 * time.Minute), Value: 30},
+	}
+	start, end, significance := fitLine(samples)
+	assert.InDelta(t, 10, start, 1e-9)
+	assert.InDelta(t, 30, end, 1e-9)
+	assert.True(t, math.IsInf(significance, 1), "a perfect fit is infinitely significant")
+}
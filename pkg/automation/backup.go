@@ -0,0 +1,93 @@
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// BackupResource fetches the current state of a resource and writes it as JSON to a
+// timestamped file under dir. Server-populated metadata is stripped so the file can
+// be restored with kubectl apply. It returns the path of the backup file.
+func BackupResource(ctx context.Context, client kubernetes.Interface, kind, name, namespace, dir string) (string, error) {
+	object, err := getBackupObject(ctx, client, kind, name, namespace)
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s %s for backup: %v", kind, name, err)
+	}
+
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return "", err
+	}
+	accessor.SetResourceVersion("")
+	accessor.SetUID("")
+	accessor.SetCreationTimestamp(metav1.Time{})
+	accessor.SetManagedFields(nil)
+
+	data, err := json.MarshalIndent(object, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize %s %s: %v", kind, name, err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create backup directory: %v", err)
+	}
+
+	filename := fmt.Sprintf("%s-%s-%s-%s.json", namespace, strings.ToLower(kind), name, time.Now().Format("20060102-150405"))
+	path := filepath.Join(dir, filename)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write backup file: %v", err)
+	}
+
+	return path, nil
+}
+
+// RestoreCommand returns the command that restores a backup written by BackupResource
+func RestoreCommand(path string) string {
+	return fmt.Sprintf("kubectl apply -f %s", path)
+}
+
+// getBackupObject fetches a resource with its apiVersion and kind set, since typed
+// clients leave them empty and kubectl apply needs them
+func getBackupObject(ctx context.Context, client kubernetes.Interface, kind, name, namespace string) (runtime.Object, error) {
+	switch strings.ToLower(kind) {
+	case "pod":
+		pod, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		pod.APIVersion, pod.Kind = "v1", "Pod"
+		return pod, nil
+	case "deployment":
+		deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		deployment.APIVersion, deployment.Kind = "apps/v1", "Deployment"
+		return deployment, nil
+	case "statefulset":
+		statefulSet, err := client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		statefulSet.APIVersion, statefulSet.Kind = "apps/v1", "StatefulSet"
+		return statefulSet, nil
+	case "daemonset":
+		daemonSet, err := client.AppsV1().DaemonSets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		daemonSet.APIVersion, daemonSet.Kind = "apps/v1", "DaemonSet"
+		return daemonSet, nil
+	}
+	return nil, fmt.Errorf("backup not supported for resource kind: %s", kind)
+}
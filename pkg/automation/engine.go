@@ -14,6 +14,7 @@ type AutomationEngine struct {
 	remediators []Remediator
 	scalers    []Scaler
 	healers    []Healer
+	backupDir  string
 }
 
 // NewAutomationEngine creates a new automation engine
@@ -33,6 +34,12 @@ type RemediationResult struct {
 	Resource   string
 	Message    string
 	Duration   time.Duration
+	BackupPath string
+}
+
+// SetBackupDir enables backing up resources to dir before they are remediated
+func (a *AutomationEngine) SetBackupDir(dir string) {
+	a.backupDir = dir
 }
 
 // RegisterRemediator adds a new remediation capability
@@ -40,6 +47,28 @@ func (a *AutomationEngine) RegisterRemediator(remediator Remediator) {
 	a.remediators = append(a.remediators, remediator)
 }
 
+// Remediators returns the registered remediators in registration order
+func (a *AutomationEngine) Remediators() []Remediator {
+	remediators := make([]Remediator, len(a.remediators))
+	copy(remediators, a.remediators)
+	return remediators
+}
+
+// backup saves the remediator's target resource when a backup directory is set.
+// Remediators that don't report their target kind are not backed up.
+func (a *AutomationEngine) backup(ctx context.Context, remediator Remediator, resource, namespace string) (string, error) {
+	if a.backupDir == "" {
+		return "", nil
+	}
+
+	target, ok := remediator.(TargetKinder)
+	if !ok {
+		return "", nil
+	}
+
+	return BackupResource(ctx, a.client, target.TargetKind(), resource, namespace, a.backupDir)
+}
+
 // RegisterScaler adds a new scaling capability
 func (a *AutomationEngine) RegisterScaler(scaler Scaler) {
 	a.scalers = append(a.scalers, scaler)
@@ -54,7 +83,21 @@ func (a *AutomationEngine) RegisterHealer(healer Healer) {
 func (a *AutomationEngine) AutoRemediate(ctx context.Context, issueType, resource, namespace string) (*RemediationResult, error) {
 	for _, remediator := range a.remediators {
 		if remediator.CanFix(issueType) {
-			return remediator.Remediate(ctx, resource, namespace)
+			backupPath, err := a.backup(ctx, remediator, resource, namespace)
+			if err != nil {
+				return &RemediationResult{
+					Success:  false,
+					Action:   "none",
+					Resource: resource,
+					Message:  fmt.Sprintf("Remediation skipped because the backup failed: %v", err),
+				}, err
+			}
+
+			result, err := remediator.Remediate(ctx, resource, namespace)
+			if result != nil {
+				result.BackupPath = backupPath
+			}
+			return result, err
 		}
 	}
 	
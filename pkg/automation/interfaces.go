@@ -21,6 +21,12 @@ type Remediator interface {
 	GetRemediationActions() []RemediationAction
 }
 
+// TargetKinder is implemented by remediators that report the kind of resource they
+// mutate, so the engine can back it up before remediating
+type TargetKinder interface {
+	TargetKind() string
+}
+
 // Scaler defines the interface for predictive scaling
 type Scaler interface {
 	CanScale(resource string) bool
@@ -66,6 +66,11 @@ func (p *PodRestartRemediator) Remediate(ctx context.Context, resource, namespac
 	}, nil
 }
 
+// TargetKind returns the kind of resource this remediator mutates
+func (p *PodRestartRemediator) TargetKind() string {
+	return "pod"
+}
+
 // GetSupportedIssues returns the types of issues this remediator can fix
 func (p *PodRestartRemediator) GetSupportedIssues() []string {
 	return []string{
@@ -0,0 +1,40 @@
+package remediators
+
+import (
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/pkg/automation"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RegisterAll registers every built-in remediator with the engine. New remediators
+// should be added here so they are discoverable through the engine. When kinds are
+// given, only the remediators acting on those kinds of resource are registered, so a
+// remediator for another kind never runs against the named resource.
+func RegisterAll(engine *automation.AutomationEngine, client kubernetes.Interface, dryRun bool, kinds ...string) {
+	all := []automation.Remediator{
+		NewPodRestartRemediator(client),
+		NewResourceLimitRemediator(client, dryRun),
+	}
+	for _, remediator := range all {
+		if targetsKind(remediator, kinds) {
+			engine.RegisterRemediator(remediator)
+		}
+	}
+}
+
+func targetsKind(remediator automation.Remediator, kinds []string) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	target, ok := remediator.(automation.TargetKinder)
+	if !ok {
+		return false
+	}
+	for _, kind := range kinds {
+		if strings.EqualFold(target.TargetKind(), kind) {
+			return true
+		}
+	}
+	return false
+}
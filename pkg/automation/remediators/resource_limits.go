@@ -0,0 +1,169 @@
+package remediators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/abrarahmad1510/k8s-lens/pkg/automation"
+	"github.com/abrarahmad1510/k8s-lens/pkg/optimization"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResourceLimitRemediator adds missing resource limits to a deployment's containers
+type ResourceLimitRemediator struct {
+	client kubernetes.Interface
+	dryRun bool
+}
+
+// NewResourceLimitRemediator creates a new resource limit remediator. In dry-run mode
+// the patch is validated by the API server but not persisted.
+func NewResourceLimitRemediator(client kubernetes.Interface, dryRun bool) *ResourceLimitRemediator {
+	return &ResourceLimitRemediator{
+		client: client,
+		dryRun: dryRun,
+	}
+}
+
+// CanFix checks if this remediator can fix the given issue type
+func (r *ResourceLimitRemediator) CanFix(issueType string) bool {
+	for _, issue := range r.GetSupportedIssues() {
+		if issue == issueType {
+			return true
+		}
+	}
+	return false
+}
+
+// Remediate patches the deployment so every container has CPU and memory limits.
+// The deployment is patched rather than its pods, so the fix survives pod restarts.
+func (r *ResourceLimitRemediator) Remediate(ctx context.Context, resource, namespace string) (*automation.RemediationResult, error) {
+	startTime := time.Now()
+
+	deployment, err := r.client.AppsV1().Deployments(namespace).Get(ctx, resource, metav1.GetOptions{})
+	if err != nil {
+		return &automation.RemediationResult{
+			Success:  false,
+			Action:   "set-limits",
+			Resource: resource,
+			Message:  fmt.Sprintf("Failed to get deployment: %v", err),
+			Duration: time.Since(startTime),
+		}, err
+	}
+
+	patch, patched := buildLimitsPatch(deployment.Spec.Template.Spec.Containers)
+	if len(patched) == 0 {
+		return &automation.RemediationResult{
+			Success:  true,
+			Action:   "none",
+			Resource: resource,
+			Message:  fmt.Sprintf("All containers in deployment %s already have resource limits", resource),
+			Duration: time.Since(startTime),
+		}, nil
+	}
+
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode patch: %v", err)
+	}
+
+	options := metav1.PatchOptions{}
+	if r.dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+
+	_, err = r.client.AppsV1().Deployments(namespace).Patch(ctx, resource, types.StrategicMergePatchType, patchBytes, options)
+	if err != nil {
+		return &automation.RemediationResult{
+			Success:  false,
+			Action:   "set-limits",
+			Resource: resource,
+			Message:  fmt.Sprintf("Failed to patch deployment: %v", err),
+			Duration: time.Since(startTime),
+		}, err
+	}
+
+	message := fmt.Sprintf("Added resource limits to containers %v of deployment %s in namespace %s", patched, resource, namespace)
+	if r.dryRun {
+		message = fmt.Sprintf("Dry run: would apply patch %s to deployment %s in namespace %s", string(patchBytes), resource, namespace)
+	}
+
+	return &automation.RemediationResult{
+		Success:  true,
+		Action:   "set-limits",
+		Resource: resource,
+		Message:  message,
+		Duration: time.Since(startTime),
+	}, nil
+}
+
+// buildLimitsPatch returns a strategic merge patch that sets the missing CPU and memory
+// limits of each container, along with the names of the containers it touches
+func buildLimitsPatch(containers []corev1.Container) (map[string]interface{}, []string) {
+	var containerPatches []map[string]interface{}
+	var patched []string
+
+	for _, container := range containers {
+		recommended := optimization.RecommendLimits(container.Resources.Requests)
+		limits := make(map[string]string)
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if _, ok := container.Resources.Limits[name]; ok {
+				continue
+			}
+			quantity := recommended[name]
+			limits[string(name)] = quantity.String()
+		}
+
+		if len(limits) == 0 {
+			continue
+		}
+
+		patched = append(patched, container.Name)
+		containerPatches = append(containerPatches, map[string]interface{}{
+			"name": container.Name,
+			"resources": map[string]interface{}{
+				"limits": limits,
+			},
+		})
+	}
+
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": containerPatches,
+				},
+			},
+		},
+	}
+	return patch, patched
+}
+
+// TargetKind returns the kind of resource this remediator mutates
+func (r *ResourceLimitRemediator) TargetKind() string {
+	return "deployment"
+}
+
+// GetSupportedIssues returns the types of issues this remediator can fix
+func (r *ResourceLimitRemediator) GetSupportedIssues() []string {
+	return []string{
+		"MissingResourceLimits",
+		"Missing resource limits",
+	}
+}
+
+// GetRemediationActions returns available remediation actions
+func (r *ResourceLimitRemediator) GetRemediationActions() []automation.RemediationAction {
+	return []automation.RemediationAction{
+		{
+			Type:        "SetResourceLimits",
+			Description: "Patch the deployment's containers with limits derived from their requests, or defaults",
+			Command:     "kubectl patch deployment <deployment-name> -n <namespace> --type strategic -p '<limits-patch>'",
+			Risk:        "medium",
+		},
+	}
+}
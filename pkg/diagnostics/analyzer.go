@@ -4,9 +4,10 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 // ResourceAnalyzer is the main analyzer struct
@@ -25,11 +26,7 @@ func NewResourceAnalyzer() (*ResourceAnalyzer, error) {
 
 // NewKubernetesClient creates a Kubernetes client
 func NewKubernetesClient() (kubernetes.Interface, error) {
-	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	configOverrides := &clientcmd.ConfigOverrides{}
-	kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-
-	config, err := kubeConfig.ClientConfig()
+	config, err := k8s.LoadConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Kubernetes config: %v", err)
 	}
@@ -43,8 +40,8 @@ func NewKubernetesClient() (kubernetes.Interface, error) {
 }
 
 // TestConnection tests the Kubernetes connection
-func (r *ResourceAnalyzer) TestConnection() error {
-	_, err := r.client.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+func (r *ResourceAnalyzer) TestConnection(ctx context.Context) error {
+	_, err := r.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	return err
 }
 
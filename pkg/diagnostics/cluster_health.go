@@ -0,0 +1,311 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// hotspotWindow is how far back Warning events are considered for hotspots
+	hotspotWindow = time.Hour
+	// hotspotThreshold is the number of Warning events that makes an object a hotspot finding
+	hotspotThreshold = 10
+	// maxHotspots is the number of hotspots kept in the report
+	maxHotspots = 10
+)
+
+// criticalPodReasons are pod failure reasons that mean a workload is broken rather than starting up
+var criticalPodReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+	"OOMKilled":                  true,
+	"Error":                      true,
+	"Evicted":                    true,
+	"Failed":                     true,
+}
+
+// ClusterHealthAnalyzer performs a fast cluster-wide triage
+type ClusterHealthAnalyzer struct {
+	client kubernetes.Interface
+}
+
+// NewClusterHealthAnalyzer creates a new ClusterHealthAnalyzer
+func NewClusterHealthAnalyzer(client kubernetes.Interface) *ClusterHealthAnalyzer {
+	return &ClusterHealthAnalyzer{
+		client: client,
+	}
+}
+
+// ClusterHealthReport summarizes what is broken in the cluster right now
+type ClusterHealthReport struct {
+	TotalNodes      int
+	ReadyNodes      int
+	NotReadyNodes   []string
+	TotalPods       int
+	UnhealthyPods   []UnhealthyPodGroup
+	PendingPVCs     []string
+	WarningHotspots []EventHotspot
+	// Findings are ordered most severe first
+	Findings []HealthFinding
+	Severity string
+}
+
+// UnhealthyPodGroup is a set of unhealthy pods in a namespace sharing the same reason
+type UnhealthyPodGroup struct {
+	Namespace string
+	Reason    string
+	Pods      []string
+}
+
+// EventHotspot is an object that has recently produced many Warning events
+type EventHotspot struct {
+	Namespace string
+	Object    string
+	Reason    string
+	Count     int32
+}
+
+// HealthFinding is a single prioritized problem found during triage
+type HealthFinding struct {
+	Severity string
+	Message  string
+	// Count is the number of affected objects, used to rank findings of equal severity
+	Count int
+}
+
+// Analyze runs the cluster-wide triage
+func (c *ClusterHealthAnalyzer) Analyze(ctx context.Context) (*ClusterHealthReport, error) {
+	report := &ClusterHealthReport{}
+
+	nodes, err := c.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+	c.analyzeNodes(report, nodes.Items)
+
+	pods, err := c.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+	c.analyzePods(report, pods.Items)
+
+	pvcs, err := c.client.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %v", err)
+	}
+	c.analyzePVCs(report, pvcs.Items)
+
+	events, err := c.client.CoreV1().Events("").List(ctx, metav1.ListOptions{FieldSelector: "type=Warning"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %v", err)
+	}
+	c.analyzeEvents(report, events.Items, time.Now())
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		if report.Findings[i].Severity != report.Findings[j].Severity {
+			return report.Findings[i].Severity == SeverityCritical
+		}
+		return report.Findings[i].Count > report.Findings[j].Count
+	})
+
+	report.Severity = SeverityHealthy
+	if len(report.Findings) > 0 {
+		report.Severity = report.Findings[0].Severity
+	}
+
+	return report, nil
+}
+
+func (c *ClusterHealthAnalyzer) analyzeNodes(report *ClusterHealthReport, nodes []corev1.Node) {
+	report.TotalNodes = len(nodes)
+	for _, node := range nodes {
+		ready := false
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				ready = true
+			}
+		}
+		if ready {
+			report.ReadyNodes++
+		} else {
+			report.NotReadyNodes = append(report.NotReadyNodes, node.Name)
+		}
+	}
+
+	if len(report.NotReadyNodes) > 0 {
+		report.addFinding(SeverityCritical, len(report.NotReadyNodes),
+			fmt.Sprintf("%d of %d nodes are NotReady: %v", len(report.NotReadyNodes), report.TotalNodes, report.NotReadyNodes))
+	}
+}
+
+func (c *ClusterHealthAnalyzer) analyzePods(report *ClusterHealthReport, pods []corev1.Pod) {
+	report.TotalPods = len(pods)
+	groups := make(map[string]*UnhealthyPodGroup)
+	for i := range pods {
+		pod := &pods[i]
+		reason := unhealthyPodReason(pod)
+		if reason == "" {
+			continue
+		}
+
+		key := pod.Namespace + "/" + reason
+		if groups[key] == nil {
+			groups[key] = &UnhealthyPodGroup{Namespace: pod.Namespace, Reason: reason}
+		}
+		groups[key].Pods = append(groups[key].Pods, pod.Name)
+	}
+
+	for _, group := range groups {
+		report.UnhealthyPods = append(report.UnhealthyPods, *group)
+	}
+	sort.Slice(report.UnhealthyPods, func(i, j int) bool {
+		if len(report.UnhealthyPods[i].Pods) != len(report.UnhealthyPods[j].Pods) {
+			return len(report.UnhealthyPods[i].Pods) > len(report.UnhealthyPods[j].Pods)
+		}
+		if report.UnhealthyPods[i].Namespace != report.UnhealthyPods[j].Namespace {
+			return report.UnhealthyPods[i].Namespace < report.UnhealthyPods[j].Namespace
+		}
+		return report.UnhealthyPods[i].Reason < report.UnhealthyPods[j].Reason
+	})
+
+	for _, group := range report.UnhealthyPods {
+		severity := SeverityWarning
+		if criticalPodReasons[group.Reason] {
+			severity = SeverityCritical
+		}
+		report.addFinding(severity, len(group.Pods),
+			fmt.Sprintf("%d pods in namespace %s are %s", len(group.Pods), group.Namespace, group.Reason))
+	}
+}
+
+// unhealthyPodReason returns why a pod is not Running and Ready, or "" if it is healthy.
+// Completed pods are healthy.
+func unhealthyPodReason(pod *corev1.Pod) string {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return ""
+	case corev1.PodFailed:
+		if pod.Status.Reason != "" {
+			return pod.Status.Reason
+		}
+		return "Failed"
+	}
+
+	if pod.Status.Phase == corev1.PodRunning && IsPodReady(pod) {
+		return ""
+	}
+
+	var statuses []corev1.ContainerStatus
+	statuses = append(statuses, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			return status.State.Waiting.Reason
+		}
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 && status.State.Terminated.Reason != "" {
+			return status.State.Terminated.Reason
+		}
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse && condition.Reason != "" {
+			return condition.Reason
+		}
+	}
+
+	if pod.Status.Phase == corev1.PodRunning {
+		return "NotReady"
+	}
+	return string(pod.Status.Phase)
+}
+
+func (c *ClusterHealthAnalyzer) analyzePVCs(report *ClusterHealthReport, pvcs []corev1.PersistentVolumeClaim) {
+	for _, pvc := range pvcs {
+		if pvc.Status.Phase == corev1.ClaimPending {
+			report.PendingPVCs = append(report.PendingPVCs, fmt.Sprintf("%s/%s", pvc.Namespace, pvc.Name))
+		}
+	}
+	sort.Strings(report.PendingPVCs)
+
+	if len(report.PendingPVCs) > 0 {
+		report.addFinding(SeverityWarning, len(report.PendingPVCs),
+			fmt.Sprintf("%d PersistentVolumeClaims are Pending: %v", len(report.PendingPVCs), report.PendingPVCs))
+	}
+}
+
+func (c *ClusterHealthAnalyzer) analyzeEvents(report *ClusterHealthReport, events []corev1.Event, now time.Time) {
+	hotspots := make(map[string]*EventHotspot)
+	for _, event := range events {
+		if event.Type != corev1.EventTypeWarning || now.Sub(eventTime(event)) > hotspotWindow {
+			continue
+		}
+
+		object := fmt.Sprintf("%s/%s", event.InvolvedObject.Kind, event.InvolvedObject.Name)
+		key := event.Namespace + "/" + object + "/" + event.Reason
+		if hotspots[key] == nil {
+			hotspots[key] = &EventHotspot{Namespace: event.Namespace, Object: object, Reason: event.Reason}
+		}
+		count := event.Count
+		if count == 0 {
+			count = 1
+		}
+		hotspots[key].Count += count
+	}
+
+	for _, hotspot := range hotspots {
+		report.WarningHotspots = append(report.WarningHotspots, *hotspot)
+	}
+	sort.Slice(report.WarningHotspots, func(i, j int) bool {
+		if report.WarningHotspots[i].Count != report.WarningHotspots[j].Count {
+			return report.WarningHotspots[i].Count > report.WarningHotspots[j].Count
+		}
+		return report.WarningHotspots[i].Object < report.WarningHotspots[j].Object
+	})
+	if len(report.WarningHotspots) > maxHotspots {
+		report.WarningHotspots = report.WarningHotspots[:maxHotspots]
+	}
+
+	for _, hotspot := range report.WarningHotspots {
+		if hotspot.Count >= hotspotThreshold {
+			report.addFinding(SeverityWarning, int(hotspot.Count),
+				fmt.Sprintf("%s in namespace %s has %d %s warnings in the last hour", hotspot.Object, hotspot.Namespace, hotspot.Count, hotspot.Reason))
+		}
+	}
+}
+
+// eventTime returns the most recent time an event was observed
+func eventTime(event corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// NormalizedFindings returns the problems found by the triage as findings of the cluster
+func (r *ClusterHealthReport) NormalizedFindings() []Finding {
+	findings := make([]Finding, 0, len(r.Findings))
+	for _, finding := range r.Findings {
+		findings = append(findings, NewFinding("health", CategoryReliability, finding.Severity, "Cluster", finding.Message, ""))
+	}
+	return findings
+}
+
+func (r *ClusterHealthReport) addFinding(severity string, count int, message string) {
+	r.Findings = append(r.Findings, HealthFinding{Severity: severity, Message: message, Count: count})
+}
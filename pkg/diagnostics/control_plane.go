@@ -0,0 +1,276 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Control plane component states
+const (
+	ComponentHealthy    = "Healthy"
+	ComponentDegraded   = "Degraded"
+	ComponentDown       = "Down"
+	ComponentMissing    = "Missing"
+	ComponentNotVisible = "Not Visible"
+)
+
+// controlPlaneComponent is a core component looked up among the kube-system pods by
+// label, or by pod name prefix for clusters that do not label them
+type controlPlaneComponent struct {
+	name       string
+	labelKey   string
+	labelValue string
+	namePrefix string
+	// hosted components run on the control plane nodes, which managed clusters hide
+	hosted bool
+	// optional components only produce a warning when missing
+	optional bool
+}
+
+var controlPlaneComponents = []controlPlaneComponent{
+	{name: "kube-apiserver", labelKey: "component", labelValue: "kube-apiserver", namePrefix: "kube-apiserver-", hosted: true},
+	{name: "kube-scheduler", labelKey: "component", labelValue: "kube-scheduler", namePrefix: "kube-scheduler-", hosted: true},
+	{name: "kube-controller-manager", labelKey: "component", labelValue: "kube-controller-manager", namePrefix: "kube-controller-manager-", hosted: true},
+	{name: "etcd", labelKey: "component", labelValue: "etcd", namePrefix: "etcd-", hosted: true},
+	{name: "coredns", labelKey: "k8s-app", labelValue: "kube-dns", namePrefix: "coredns-"},
+	{name: "metrics-server", labelKey: "k8s-app", labelValue: "metrics-server", namePrefix: "metrics-server-", optional: true},
+}
+
+// ControlPlaneAnalyzer checks the health of the core cluster components visible
+// through the API
+type ControlPlaneAnalyzer struct {
+	client kubernetes.Interface
+}
+
+// NewControlPlaneAnalyzer creates a new ControlPlaneAnalyzer
+func NewControlPlaneAnalyzer(client kubernetes.Interface) *ControlPlaneAnalyzer {
+	return &ControlPlaneAnalyzer{
+		client: client,
+	}
+}
+
+// ComponentHealth is the health of one control plane component
+type ComponentHealth struct {
+	Name      string
+	State     string
+	ReadyPods int
+	TotalPods int
+	// NotReady lists the component pods that are not ready
+	NotReady []string
+}
+
+// ComponentStatusHealth is a component as reported by the deprecated componentstatuses API
+type ComponentStatusHealth struct {
+	Name    string
+	Healthy bool
+	Message string
+}
+
+// ControlPlaneReport contains the control plane health verdict
+type ControlPlaneReport struct {
+	// APIServerReady is the result of the API server's /readyz check, nil when it
+	// could not be queried
+	APIServerReady *bool
+	// FailedReadyzChecks are the /readyz checks that did not pass
+	FailedReadyzChecks []string
+	Components         []ComponentHealth
+	// ComponentStatuses is empty when the API is unavailable, as on recent or managed clusters
+	ComponentStatuses []ComponentStatusHealth
+	// Managed is set when the control plane pods are not visible, as on managed
+	// clusters where the provider runs them outside the cluster
+	Managed  bool
+	Analysis ControlPlaneAnalysis
+}
+
+// ControlPlaneAnalysis contains diagnostic results
+type ControlPlaneAnalysis struct {
+	Status          string
+	Issues          []string
+	Recommendations []string
+}
+
+// Analyze checks the API server readiness, the componentstatuses API where available
+// and the readiness of the core component pods in kube-system
+func (c *ControlPlaneAnalyzer) Analyze(ctx context.Context) (*ControlPlaneReport, error) {
+	pods, err := c.client.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in %s: %v", metav1.NamespaceSystem, err)
+	}
+
+	report := &ControlPlaneReport{}
+	c.checkReadyz(ctx, report)
+	c.checkComponentStatuses(ctx, report)
+
+	hostedVisible := false
+	for _, component := range controlPlaneComponents {
+		health := componentHealth(component, pods.Items)
+		if component.hosted && health.TotalPods > 0 {
+			hostedVisible = true
+		}
+		report.Components = append(report.Components, health)
+	}
+	report.Managed = !hostedVisible
+	if report.Managed {
+		for i, component := range controlPlaneComponents {
+			if component.hosted {
+				report.Components[i].State = ComponentNotVisible
+			}
+		}
+	}
+
+	c.analyze(report)
+	return report, nil
+}
+
+// componentHealth finds the pods of a component and counts how many are ready
+func componentHealth(component controlPlaneComponent, pods []corev1.Pod) ComponentHealth {
+	health := ComponentHealth{Name: component.name}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Labels[component.labelKey] != component.labelValue && !strings.HasPrefix(pod.Name, component.namePrefix) {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodSucceeded {
+			continue
+		}
+		health.TotalPods++
+		if IsPodReady(pod) {
+			health.ReadyPods++
+		} else {
+			health.NotReady = append(health.NotReady, pod.Name)
+		}
+	}
+
+	switch {
+	case health.TotalPods == 0:
+		health.State = ComponentMissing
+	case health.ReadyPods == 0:
+		health.State = ComponentDown
+	case health.ReadyPods < health.TotalPods:
+		health.State = ComponentDegraded
+	default:
+		health.State = ComponentHealthy
+	}
+	return health
+}
+
+// checkReadyz queries the API server's verbose /readyz endpoint. It is left unknown
+// when the endpoint cannot be reached, e.g. without the permission to read it.
+func (c *ControlPlaneAnalyzer) checkReadyz(ctx context.Context, report *ControlPlaneReport) {
+	restClient, ok := c.client.CoreV1().RESTClient().(*rest.RESTClient)
+	if !ok || restClient == nil {
+		return
+	}
+
+	// A failing check makes the endpoint return an error status with the same body
+	result := restClient.Get().AbsPath("/readyz").Param("verbose", "true").Do(ctx)
+	var statusCode int
+	result.StatusCode(&statusCode)
+	data, err := result.Raw()
+	if statusCode == 0 || (err != nil && !strings.Contains(string(data), "[-]")) {
+		return
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if check, ok := strings.CutPrefix(strings.TrimSpace(line), "[-]"); ok {
+			report.FailedReadyzChecks = append(report.FailedReadyzChecks, check)
+		}
+	}
+	ready := err == nil && len(report.FailedReadyzChecks) == 0
+	report.APIServerReady = &ready
+}
+
+// checkComponentStatuses reads the deprecated componentstatuses API, which is missing
+// or incomplete on recent and managed clusters
+func (c *ControlPlaneAnalyzer) checkComponentStatuses(ctx context.Context, report *ControlPlaneReport) {
+	statuses, err := c.client.CoreV1().ComponentStatuses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return
+	}
+	for _, status := range statuses.Items {
+		health := ComponentStatusHealth{Name: status.Name}
+		for _, condition := range status.Conditions {
+			if condition.Type == corev1.ComponentHealthy {
+				health.Healthy = condition.Status == corev1.ConditionTrue
+				health.Message = condition.Message
+				if condition.Error != "" {
+					health.Message = condition.Error
+				}
+			}
+		}
+		report.ComponentStatuses = append(report.ComponentStatuses, health)
+	}
+}
+
+func (c *ControlPlaneAnalyzer) analyze(report *ControlPlaneReport) {
+	critical := false
+
+	if report.APIServerReady != nil && !*report.APIServerReady {
+		critical = true
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("API server is not ready: failing checks %s", strings.Join(report.FailedReadyzChecks, ", ")))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"kubectl get --raw '/readyz?verbose'")
+	}
+
+	for _, status := range report.ComponentStatuses {
+		if !status.Healthy {
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				fmt.Sprintf("Component status %s is unhealthy: %s", status.Name, status.Message))
+		}
+	}
+
+	for i, component := range controlPlaneComponents {
+		health := report.Components[i]
+		switch health.State {
+		case ComponentDown:
+			critical = true
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				fmt.Sprintf("%s is down: none of its %d pod(s) are ready", health.Name, health.TotalPods))
+		case ComponentDegraded:
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				fmt.Sprintf("%s is degraded: %d/%d pod(s) ready, not ready: %s",
+					health.Name, health.ReadyPods, health.TotalPods, strings.Join(health.NotReady, ", ")))
+		case ComponentMissing:
+			switch {
+			case component.optional:
+				report.Analysis.Issues = append(report.Analysis.Issues,
+					fmt.Sprintf("%s was not found in %s; kubectl top and HorizontalPodAutoscalers on resource metrics will not work",
+						health.Name, metav1.NamespaceSystem))
+				report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+					"Install metrics-server, or check it runs in another namespace")
+			case component.hosted:
+				report.Analysis.Issues = append(report.Analysis.Issues,
+					fmt.Sprintf("%s pods were not found in %s while other control plane pods were", health.Name, metav1.NamespaceSystem))
+			default:
+				critical = true
+				report.Analysis.Issues = append(report.Analysis.Issues,
+					fmt.Sprintf("%s was not found in %s; in-cluster DNS resolution will fail", health.Name, metav1.NamespaceSystem))
+			}
+		}
+		if health.State == ComponentDown || health.State == ComponentDegraded {
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+				fmt.Sprintf("kubectl describe pod -n %s %s", metav1.NamespaceSystem, health.NotReady[0]))
+		}
+	}
+
+	if report.Managed {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"The API server, scheduler, controller manager and etcd are not visible as pods, as on managed clusters; check their health with your provider")
+	}
+
+	switch {
+	case critical:
+		report.Analysis.Status = "Unhealthy"
+	case len(report.Analysis.Issues) > 0:
+		report.Analysis.Status = "Needs Attention"
+	default:
+		report.Analysis.Status = "Healthy"
+	}
+}
@@ -0,0 +1,182 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// defaultCheckTimeout bounds how long a custom check may run when it sets no timeout
+const defaultCheckTimeout = 10 * time.Second
+
+// CustomChecksConfig is the file registering external check scripts and the labels and
+// annotations every analyzed workload must carry, e.g.
+//
+//	requiredLabels: [team, cost-center]
+//	requiredAnnotations: [owner]
+//	checks:
+//	  - name: naming-convention
+//	    command: /opt/checks/naming.sh
+//	    resources: [pod, deployment]
+//	    timeout: 5s
+type CustomChecksConfig struct {
+	Checks              []CustomCheck `json:"checks"`
+	RequiredLabels      []string      `json:"requiredLabels,omitempty"`
+	RequiredAnnotations []string      `json:"requiredAnnotations,omitempty"`
+}
+
+// CustomCheck is an external program run against each analyzed resource.
+//
+// The program receives the resource as JSON on stdin, with the resource type also in the
+// K8S_LENS_RESOURCE_TYPE environment variable, and writes a CustomCheckOutput as JSON to
+// stdout. A check that exits non-zero without printing valid output is reported as failed.
+type CustomCheck struct {
+	Name    string   `json:"name"`
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	// Resources limits the check to these resource types; empty runs it for every type
+	Resources []string `json:"resources,omitempty"`
+	// Timeout is a duration such as 5s, defaulting to 10s
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// CustomCheckOutput is what a check prints to stdout
+type CustomCheckOutput struct {
+	Issues          []CustomCheckIssue `json:"issues"`
+	Recommendations []string           `json:"recommendations,omitempty"`
+}
+
+// CustomCheckIssue is a single finding of a check. Severity is warning or critical,
+// defaulting to warning.
+type CustomCheckIssue struct {
+	Severity string `json:"severity,omitempty"`
+	Message  string `json:"message"`
+}
+
+// CustomCheckResult is the merged outcome of all checks run against a resource
+type CustomCheckResult struct {
+	Issues          []string
+	Recommendations []string
+	// Severity is the highest severity of the issues found
+	Severity string
+	// Errors lists checks that could not be run or returned invalid output
+	Errors []string
+}
+
+// LoadCustomChecks reads and validates a custom checks config file
+func LoadCustomChecks(path string) (*CustomChecksConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read custom checks config: %v", err)
+	}
+
+	var config CustomChecksConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse custom checks config %s: %v", path, err)
+	}
+
+	for i, check := range config.Checks {
+		if check.Command == "" {
+			return nil, fmt.Errorf("custom check %d (%s) has no command", i+1, check.Name)
+		}
+		if check.Name == "" {
+			config.Checks[i].Name = check.Command
+		}
+		if check.Timeout != "" {
+			if _, err := time.ParseDuration(check.Timeout); err != nil {
+				return nil, fmt.Errorf("custom check %s has an invalid timeout %q: %v", check.Name, check.Timeout, err)
+			}
+		}
+	}
+	return &config, nil
+}
+
+// RunCustomChecks runs every check registered for the resource type against the
+// resource and merges their findings. Issues are prefixed with the check name. The checks
+// are killed when ctx is done, on top of their own timeouts.
+func RunCustomChecks(ctx context.Context, checks []CustomCheck, resourceType string, resource interface{}) *CustomCheckResult {
+	result := &CustomCheckResult{Severity: SeverityHealthy}
+
+	input, err := json.Marshal(resource)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("failed to encode %s: %v", resourceType, err))
+		return result
+	}
+
+	for _, check := range checks {
+		if !checkApplies(check, resourceType) {
+			continue
+		}
+
+		output, err := runCustomCheck(ctx, check, resourceType, input)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", check.Name, err))
+			continue
+		}
+
+		for _, issue := range output.Issues {
+			severity := SeverityWarning
+			if strings.EqualFold(issue.Severity, "critical") {
+				severity = SeverityCritical
+			}
+			result.Severity = MaxSeverity(result.Severity, severity)
+			result.Issues = append(result.Issues, fmt.Sprintf("[%s] %s", check.Name, issue.Message))
+		}
+		result.Recommendations = append(result.Recommendations, output.Recommendations...)
+	}
+	return result
+}
+
+func checkApplies(check CustomCheck, resourceType string) bool {
+	if len(check.Resources) == 0 {
+		return true
+	}
+	for _, resource := range check.Resources {
+		if strings.EqualFold(resource, resourceType) {
+			return true
+		}
+	}
+	return false
+}
+
+func runCustomCheck(parent context.Context, check CustomCheck, resourceType string, input []byte) (*CustomCheckOutput, error) {
+	timeout := defaultCheckTimeout
+	if check.Timeout != "" {
+		timeout, _ = time.ParseDuration(check.Timeout)
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	command := exec.CommandContext(ctx, check.Command, check.Args...)
+	command.Stdin = bytes.NewReader(input)
+	command.Env = append(os.Environ(), "K8S_LENS_RESOURCE_TYPE="+resourceType)
+	// Scripts may leave children holding stdout open; stop waiting for them after the timeout
+	command.WaitDelay = time.Second
+	var stdout, stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	runErr := command.Run()
+	if err := parent.Err(); err != nil {
+		return nil, err
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("timed out after %v", timeout)
+	}
+
+	var output CustomCheckOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("%v: %s", runErr, strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("invalid output: %v", err)
+	}
+	return &output, nil
+}
@@ -0,0 +1,64 @@
+package diagnostics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadCustomChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    *CustomChecksConfig
+		wantErr bool
+	}{
+		{
+			name: "full config",
+			config: `requiredLabels: [team]
+requiredAnnotations: [owner]
+checks:
+  - name: naming
+    command: /opt/checks/naming.sh
+    args: [--strict]
+    resources: [pod, deployment]
+    timeout: 5s
+`,
+			want: &CustomChecksConfig{
+				RequiredLabels:      []string{"team"},
+				RequiredAnnotations: []string{"owner"},
+				Checks: []CustomCheck{{Name: "naming", Command: "/opt/checks/naming.sh", Args: []string{"--strict"},
+					Resources: []string{"pod", "deployment"}, Timeout: "5s"}},
+			},
+		},
+		{
+			name:   "name defaults to the command",
+			config: "checks:\n  - command: /opt/checks/labels.sh\n",
+			want:   &CustomChecksConfig{Checks: []CustomCheck{{Name: "/opt/checks/labels.sh", Command: "/opt/checks/labels.sh"}}},
+		},
+		{name: "labels only", config: "requiredLabels: [team]\n", want: &CustomChecksConfig{RequiredLabels: []string{"team"}}},
+		{name: "missing command", config: "checks:\n  - name: naming\n", wantErr: true},
+		{name: "invalid timeout", config: "checks:\n  - command: check.sh\n    timeout: soon\n", wantErr: true},
+		{name: "invalid yaml", config: "checks: [", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "checks.yaml")
+			assert.NoError(t, os.WriteFile(path, []byte(tt.config), 0o600))
+
+			config, err := LoadCustomChecks(path)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, config)
+		})
+	}
+
+	_, err := LoadCustomChecks(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.Error(t, err, "a missing file is an error")
+}
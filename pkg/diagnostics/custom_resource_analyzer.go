@@ -0,0 +1,290 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+)
+
+// negativeConditions are condition types that report a problem when True, unlike
+// Ready or Available which report one when False
+var negativeConditions = map[string]bool{
+	"Degraded": true,
+	"Stalled":  true,
+	"Failed":   true,
+	"Error":    true,
+}
+
+// informationalConditions describe what a controller is doing rather than whether the
+// resource is healthy, so neither status is reported
+var informationalConditions = map[string]bool{
+	"Reconciling": true,
+	"Suspended":   true,
+	"Paused":      true,
+	"Completed":   true,
+}
+
+// failedPhases are status.phase values meaning the resource is broken
+var failedPhases = map[string]bool{
+	"Failed":   true,
+	"Error":    true,
+	"Degraded": true,
+}
+
+// CustomResourceAnalyzer provides basic analysis for any resource, including those
+// defined by CustomResourceDefinitions, through the dynamic client. It relies on the
+// conventions most controllers follow: status.conditions, status.phase,
+// status.observedGeneration and replica counts.
+type CustomResourceAnalyzer struct {
+	client    kubernetes.Interface
+	dynamic   dynamic.Interface
+	namespace string
+}
+
+// NewCustomResourceAnalyzer creates a new CustomResourceAnalyzer
+func NewCustomResourceAnalyzer(client kubernetes.Interface, dynamicClient dynamic.Interface, namespace string) *CustomResourceAnalyzer {
+	return &CustomResourceAnalyzer{
+		client:    client,
+		dynamic:   dynamicClient,
+		namespace: namespace,
+	}
+}
+
+// CustomResourceReport contains the analysis report for a custom resource
+type CustomResourceReport struct {
+	Name       string
+	Namespace  string
+	Kind       string
+	APIVersion string
+	// Generation and ObservedGeneration show whether the controller has seen the
+	// latest spec; ObservedGeneration is -1 when the resource does not report it
+	Generation         int64
+	ObservedGeneration int64
+	Phase              string
+	Conditions         []CustomResourceCondition
+	// Replicas holds whichever replica counts the resource reports, e.g.
+	// "spec.replicas" or "status.readyReplicas"
+	Replicas map[string]int64
+	Events   []corev1.Event
+	Analysis CustomResourceAnalysis
+}
+
+// CustomResourceCondition is a condition from status.conditions
+type CustomResourceCondition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+// CustomResourceAnalysis contains diagnostic results
+type CustomResourceAnalysis struct {
+	Status          string
+	Issues          []string
+	Recommendations []string
+}
+
+// replicaFields are the replica counts reported when a resource has them
+var replicaFields = [][]string{
+	{"spec", "replicas"},
+	{"status", "replicas"},
+	{"status", "readyReplicas"},
+	{"status", "availableReplicas"},
+	{"status", "updatedReplicas"},
+}
+
+// Analyze fetches and analyzes a resource. The resource is given the way kubectl
+// accepts it: a plural, singular, kind or short name, optionally qualified with its
+// group and version, e.g. rollouts.argoproj.io or certificates.v1.cert-manager.io.
+func (c *CustomResourceAnalyzer) Analyze(ctx context.Context, resource, name string) (*CustomResourceReport, error) {
+	gvr, namespaced, err := c.resolveResource(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var object *unstructured.Unstructured
+	if namespaced {
+		object, err = c.dynamic.Resource(gvr).Namespace(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	} else {
+		object, err = c.dynamic.Resource(gvr).Get(ctx, name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %v", gvr.GroupResource(), name, err)
+	}
+
+	report := &CustomResourceReport{
+		Name:               object.GetName(),
+		Namespace:          object.GetNamespace(),
+		Kind:               object.GetKind(),
+		APIVersion:         object.GetAPIVersion(),
+		Generation:         object.GetGeneration(),
+		ObservedGeneration: -1,
+		Replicas:           make(map[string]int64),
+	}
+	if observed, found, _ := unstructured.NestedInt64(object.Object, "status", "observedGeneration"); found {
+		report.ObservedGeneration = observed
+	}
+	report.Phase, _, _ = unstructured.NestedString(object.Object, "status", "phase")
+	for _, field := range replicaFields {
+		if value, found, _ := unstructured.NestedInt64(object.Object, field...); found {
+			report.Replicas[strings.Join(field, ".")] = value
+		}
+	}
+	report.Conditions = customResourceConditions(object)
+
+	// Events of cluster-scoped resources are recorded in the default namespace
+	eventNamespace := report.Namespace
+	if eventNamespace == "" {
+		eventNamespace = metav1.NamespaceDefault
+	}
+	events, err := c.client.CoreV1().Events(eventNamespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=%s", report.Name, report.Kind),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events for %s %s: %v", report.Kind, name, err)
+	}
+	report.Events = events.Items
+
+	c.analyze(report)
+	return report, nil
+}
+
+// resolveResource maps a kubectl-style resource argument to its group, version and
+// resource through API discovery, and reports whether the resource is namespaced
+func (c *CustomResourceAnalyzer) resolveResource(resource string) (schema.GroupVersionResource, bool, error) {
+	groupResources, err := restmapper.GetAPIGroupResources(c.client.Discovery())
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("failed to discover API resources: %v", err)
+	}
+	mapper := restmapper.NewShortcutExpander(restmapper.NewDiscoveryRESTMapper(groupResources), c.client.Discovery(), nil)
+
+	// Like kubectl, an argument with three or more parts is first tried as
+	// resource.version.group, then as resource.group
+	fullySpecified, groupResource := schema.ParseResourceArg(resource)
+	gvr, err := mapper.ResourceFor(groupResource.WithVersion(""))
+	if fullySpecified != nil {
+		if specified, specifiedErr := mapper.ResourceFor(*fullySpecified); specifiedErr == nil {
+			gvr, err = specified, nil
+		}
+	}
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("unknown resource %s: %v", resource, err)
+	}
+	gvk, err := mapper.KindFor(gvr)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("unknown resource %s: %v", resource, err)
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return schema.GroupVersionResource{}, false, fmt.Errorf("unknown resource %s: %v", resource, err)
+	}
+	return gvr, mapping.Scope.Name() == meta.RESTScopeNameNamespace, nil
+}
+
+func customResourceConditions(object *unstructured.Unstructured) []CustomResourceCondition {
+	items, _, _ := unstructured.NestedSlice(object.Object, "status", "conditions")
+	var conditions []CustomResourceCondition
+	for _, item := range items {
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condition := CustomResourceCondition{}
+		condition.Type, _, _ = unstructured.NestedString(fields, "type")
+		condition.Status, _, _ = unstructured.NestedString(fields, "status")
+		condition.Reason, _, _ = unstructured.NestedString(fields, "reason")
+		condition.Message, _, _ = unstructured.NestedString(fields, "message")
+		if condition.Type != "" {
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions
+}
+
+func (c *CustomResourceAnalyzer) analyze(report *CustomResourceReport) {
+	analysis := &report.Analysis
+	broken := false
+
+	for _, condition := range report.Conditions {
+		if informationalConditions[condition.Type] {
+			continue
+		}
+		failing := condition.Status == string(metav1.ConditionFalse)
+		if negativeConditions[condition.Type] {
+			failing = condition.Status == string(metav1.ConditionTrue)
+		}
+		if !failing {
+			continue
+		}
+		broken = true
+		issue := fmt.Sprintf("Condition %s is %s", condition.Type, condition.Status)
+		if condition.Reason != "" {
+			issue += fmt.Sprintf(" (%s)", condition.Reason)
+		}
+		if condition.Message != "" {
+			issue += ": " + condition.Message
+		}
+		analysis.Issues = append(analysis.Issues, issue)
+	}
+
+	if failedPhases[report.Phase] {
+		broken = true
+		analysis.Issues = append(analysis.Issues, fmt.Sprintf("Resource is in phase %s", report.Phase))
+	}
+
+	if report.ObservedGeneration >= 0 && report.ObservedGeneration < report.Generation {
+		analysis.Issues = append(analysis.Issues,
+			fmt.Sprintf("The controller has not processed the latest spec yet (generation %d, observed %d)",
+				report.Generation, report.ObservedGeneration))
+		analysis.Recommendations = append(analysis.Recommendations,
+			fmt.Sprintf("Check that the controller managing %s resources is running and look at its logs", report.Kind))
+	}
+
+	if desired, ok := report.Replicas["spec.replicas"]; ok {
+		if ready, ok := report.Replicas["status.readyReplicas"]; ok && ready < desired {
+			analysis.Issues = append(analysis.Issues,
+				fmt.Sprintf("Ready replicas (%d) does not match desired replicas (%d)", ready, desired))
+		} else if !ok && desired > 0 {
+			if available, ok := report.Replicas["status.availableReplicas"]; ok && available < desired {
+				analysis.Issues = append(analysis.Issues,
+					fmt.Sprintf("Available replicas (%d) does not match desired replicas (%d)", available, desired))
+			}
+		}
+	}
+
+	warnings := 0
+	for _, event := range report.Events {
+		if event.Type == corev1.EventTypeWarning {
+			warnings++
+		}
+	}
+	if warnings > 0 {
+		analysis.Issues = append(analysis.Issues, fmt.Sprintf("%d warning event(s) recorded for the resource", warnings))
+	}
+
+	if broken {
+		describe := fmt.Sprintf("kubectl describe %s %s", strings.ToLower(report.Kind), report.Name)
+		if report.Namespace != "" {
+			describe += " -n " + report.Namespace
+		}
+		analysis.Recommendations = append(analysis.Recommendations, "Describe the resource for details: "+describe)
+	}
+
+	switch {
+	case broken:
+		analysis.Status = "Unhealthy"
+	case len(analysis.Issues) > 0:
+		analysis.Status = "Needs Attention"
+	default:
+		analysis.Status = "Healthy"
+	}
+}
@@ -3,6 +3,7 @@ package diagnostics
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -15,6 +16,8 @@ import (
 type DeploymentAnalyzer struct {
 	client    kubernetes.Interface
 	namespace string
+	events    *EventCache
+	findings  *FindingCollector
 }
 
 // NewDeploymentAnalyzer creates a new DeploymentAnalyzer
@@ -25,10 +28,24 @@ func NewDeploymentAnalyzer(client kubernetes.Interface, namespace string) *Deplo
 	}
 }
 
+// SetEventCache makes the analyzer look events up in a cache shared by the analyses
+// of a namespace instead of listing them for every deployment
+func (d *DeploymentAnalyzer) SetEventCache(cache *EventCache) {
+	d.events = cache
+}
+
+// SetFindingCollector makes the analyzer emit the issues of each deployment as findings
+func (d *DeploymentAnalyzer) SetFindingCollector(collector *FindingCollector) {
+	d.findings = collector
+}
+
 // DeploymentReport contains the analysis report for a Deployment
 type DeploymentReport struct {
 	Name              string
 	Namespace         string
+	UID               string
+	Revision          string
+	Paused            bool
 	DesiredReplicas   int32
 	CurrentReplicas   int32
 	ReadyReplicas     int32
@@ -38,7 +55,15 @@ type DeploymentReport struct {
 	PodTemplate       corev1.PodTemplateSpec
 	ReplicaSets       []appsv1.ReplicaSet
 	Events            []corev1.Event
+	Probes            []ProbeInfo
+	ExtendedResources []ExtendedResource
 	Analysis          DeploymentAnalysis
+	// Autoscaler is the HPA targeting the deployment, nil when there is none
+	Autoscaler *Autoscaler
+	// GitOps is the GitOps application managing the deployment, nil when there is none
+	GitOps *GitOpsSource
+	// Strategy is how the deployment rolls out new pods
+	Strategy *RolloutStrategy
 }
 
 // DeploymentAnalysis contains diagnostic results
@@ -50,15 +75,15 @@ type DeploymentAnalysis struct {
 }
 
 // Analyze performs the analysis of a Deployment
-func (d *DeploymentAnalyzer) Analyze(deploymentName string) (*DeploymentReport, error) {
+func (d *DeploymentAnalyzer) Analyze(ctx context.Context, deploymentName string) (*DeploymentReport, error) {
 	// Get deployment
-	deployment, err := d.client.AppsV1().Deployments(d.namespace).Get(context.TODO(), deploymentName, metav1.GetOptions{})
+	deployment, err := d.client.AppsV1().Deployments(d.namespace).Get(ctx, deploymentName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get deployment %s: %v", deploymentName, err)
 	}
 
 	// Get related ReplicaSets
-	rsList, err := d.client.AppsV1().ReplicaSets(d.namespace).List(context.TODO(), metav1.ListOptions{
+	rsList, err := d.client.AppsV1().ReplicaSets(d.namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: metav1.FormatLabelSelector(deployment.Spec.Selector),
 	})
 	if err != nil {
@@ -66,9 +91,7 @@ func (d *DeploymentAnalyzer) Analyze(deploymentName string) (*DeploymentReport,
 	}
 
 	// Get events
-	events, err := d.client.CoreV1().Events(d.namespace).List(context.TODO(), metav1.ListOptions{
-		FieldSelector: "involvedObject.name=" + deploymentName,
-	})
+	events, err := objectEvents(ctx, d.client, d.events, d.namespace, deploymentName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events for deployment %s: %v", deploymentName, err)
 	}
@@ -76,6 +99,9 @@ func (d *DeploymentAnalyzer) Analyze(deploymentName string) (*DeploymentReport,
 	report := &DeploymentReport{
 		Name:              deployment.Name,
 		Namespace:         deployment.Namespace,
+		UID:               string(deployment.UID),
+		Revision:          deployment.Annotations[revisionAnnotation],
+		Paused:            deployment.Spec.Paused,
 		DesiredReplicas:   *deployment.Spec.Replicas,
 		CurrentReplicas:   deployment.Status.Replicas,
 		ReadyReplicas:     deployment.Status.ReadyReplicas,
@@ -84,16 +110,55 @@ func (d *DeploymentAnalyzer) Analyze(deploymentName string) (*DeploymentReport,
 		Conditions:        deployment.Status.Conditions,
 		PodTemplate:       deployment.Spec.Template,
 		ReplicaSets:       rsList.Items,
-		Events:            events.Items,
+		Events:            events,
 	}
 
 	d.analyzeConditions(report)
+	if replicaSets, pods, err := d.deploymentPods(ctx, report, deployment); err == nil {
+		d.analyzeMissingReplicas(report, replicaSets, pods)
+		d.analyzeSecurityDrift(report, replicaSets, pods)
+	}
 	d.analyzeReplicaSets(report)
 	d.analyzeRolloutStatus(report)
+	d.analyzeProbes(report)
+	d.analyzeResourceRatios(report)
+	d.analyzeExtendedResources(report)
+	d.analyzeAutoscaling(ctx, report)
+	d.analyzeDeprecatedAPIs(ctx, report, deployment)
+	d.analyzeGitOps(report, deployment)
+	d.analyzeStrategy(ctx, report, deployment)
 
+	d.findings.Add(report.Findings()...)
 	return report, nil
 }
 
+// deploymentPods returns the deployment's own ReplicaSets by name and their pods, leaving
+// out other workloads whose labels happen to match its selector
+func (d *DeploymentAnalyzer) deploymentPods(ctx context.Context, report *DeploymentReport, deployment *appsv1.Deployment) (map[string]*appsv1.ReplicaSet, []corev1.Pod, error) {
+	replicaSets := make(map[string]*appsv1.ReplicaSet)
+	for i := range report.ReplicaSets {
+		rs := &report.ReplicaSets[i]
+		if ref := metav1.GetControllerOf(rs); ref != nil && ref.UID == deployment.UID {
+			replicaSets[rs.Name] = rs
+		}
+	}
+
+	pods, err := d.client.CoreV1().Pods(d.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(deployment.Spec.Selector),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list pods for deployment %s: %v", deployment.Name, err)
+	}
+
+	var owned []corev1.Pod
+	for _, pod := range pods.Items {
+		if ref := metav1.GetControllerOf(&pod); ref != nil && replicaSets[ref.Name] != nil {
+			owned = append(owned, pod)
+		}
+	}
+	return replicaSets, owned, nil
+}
+
 func (d *DeploymentAnalyzer) analyzeConditions(report *DeploymentReport) {
 	for _, condition := range report.Conditions {
 		switch condition.Type {
@@ -132,6 +197,79 @@ func (d *DeploymentAnalyzer) analyzeReplicaSets(report *DeploymentReport) {
 			}
 		}
 	}
+
+	d.analyzeActiveReplicaSets(report)
+}
+
+// analyzeActiveReplicaSets detects a wedged rollout: more than one ReplicaSet owned by the
+// deployment running pods at the same time, so old and new versions serve side by side
+func (d *DeploymentAnalyzer) analyzeActiveReplicaSets(report *DeploymentReport) {
+	var active []string
+	for _, revision := range report.RolloutHistory() {
+		if revision.Replicas > 0 {
+			active = append(active, fmt.Sprintf("%s (revision %d, %s, %d replicas)",
+				revision.ReplicaSet, revision.Revision, strings.Join(revision.Images, ", "), revision.Replicas))
+		}
+	}
+	if len(active) < 2 {
+		return
+	}
+
+	report.Analysis.Issues = append(report.Analysis.Issues,
+		fmt.Sprintf("%d ReplicaSets are running pods simultaneously, the rollout may be stuck: %s",
+			len(active), strings.Join(active, "; ")))
+	if report.Paused {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("The rollout is paused; resume it with 'kubectl rollout resume deployment/%s -n %s'", report.Name, report.Namespace))
+	} else {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("Check why the new pods are not becoming ready, or roll back with 'kubectl rollout undo deployment/%s -n %s'", report.Name, report.Namespace))
+	}
+}
+
+// analyzeProbes checks the probes of the pod template. Probe misconfigurations are
+// reported as issues without changing the status, which reflects the rollout itself.
+func (d *DeploymentAnalyzer) analyzeProbes(report *DeploymentReport) {
+	probes, issues, recommendations := analyzeProbes(report.PodTemplate.Spec.Containers)
+	report.Probes = probes
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+}
+
+// analyzeResourceRatios checks the limits of the pod template against its requests,
+// reported like probe issues without changing the status
+func (d *DeploymentAnalyzer) analyzeResourceRatios(report *DeploymentReport) {
+	issues, recommendations := analyzeResourceRatios(report.PodTemplate.Spec.Containers)
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+}
+
+// analyzeExtendedResources lists the GPUs and other extended resources of the pod
+// template, reported like probe issues without changing the status
+func (d *DeploymentAnalyzer) analyzeExtendedResources(report *DeploymentReport) {
+	resources, issues, recommendations := analyzeExtendedResources(report.PodTemplate.Spec.Containers)
+	report.ExtendedResources = resources
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+}
+
+func (d *DeploymentAnalyzer) analyzeDeprecatedAPIs(ctx context.Context, report *DeploymentReport, deployment *appsv1.Deployment) {
+	issues, recommendations := analyzeDeprecatedAPIs(ctx, d.client, deployment, "Deployment")
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+}
+
+// analyzeGitOps reports the GitOps application managing the deployment and changes made
+// by hand since it was synced. The replica count is left out under an autoscaler.
+func (d *DeploymentAnalyzer) analyzeGitOps(report *DeploymentReport, deployment *appsv1.Deployment) {
+	var ignored []string
+	if report.Autoscaler != nil {
+		ignored = append(ignored, "spec.replicas")
+	}
+	source, issues, recommendations := analyzeGitOps(deployment, "Deployment", ignored...)
+	report.GitOps = source
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
 }
 
 func (d *DeploymentAnalyzer) analyzeRolloutStatus(report *DeploymentReport) {
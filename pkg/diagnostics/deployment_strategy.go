@@ -0,0 +1,152 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// minRolloutAvailability is the share of the desired replicas that should stay
+// available during a rolling update
+const minRolloutAvailability = 0.5
+
+// RolloutStrategy describes how a deployment replaces its pods on a rollout
+type RolloutStrategy struct {
+	// Type is RollingUpdate or Recreate
+	Type string
+	// MaxSurge and MaxUnavailable are the configured values, e.g. 25% or 1, and
+	// SurgePods and UnavailablePods what they resolve to for the desired replicas
+	MaxSurge        string
+	MaxUnavailable  string
+	SurgePods       int32
+	UnavailablePods int32
+}
+
+// String describes the strategy, e.g. "RollingUpdate (maxSurge 25% = 1 pod(s), maxUnavailable 25% = 0 pod(s))"
+func (s *RolloutStrategy) String() string {
+	if s.Type != string(appsv1.RollingUpdateDeploymentStrategyType) {
+		return s.Type
+	}
+	return fmt.Sprintf("%s (maxSurge %s = %d pod(s), maxUnavailable %s = %d pod(s))",
+		s.Type, s.MaxSurge, s.SurgePods, s.MaxUnavailable, s.UnavailablePods)
+}
+
+// analyzeStrategy reports the rollout strategy and flags rolling updates that can take
+// too many pods down at once, and Recreate rollouts of pods serving users, which stop
+// every pod before starting the new ones
+func (d *DeploymentAnalyzer) analyzeStrategy(ctx context.Context, report *DeploymentReport, deployment *appsv1.Deployment) {
+	strategy := deployment.Spec.Strategy
+	if strategy.Type == appsv1.RecreateDeploymentStrategyType {
+		report.Strategy = &RolloutStrategy{Type: string(appsv1.RecreateDeploymentStrategyType)}
+		if services := d.userFacingServices(ctx, deployment); len(services) > 0 {
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				fmt.Sprintf("Deployment uses the Recreate strategy while serving users through %s; every rollout causes downtime",
+					strings.Join(services, ", ")))
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+				"Switch to the RollingUpdate strategy, unless the pods cannot run two versions side by side")
+		}
+		return
+	}
+
+	// The API server defaults both values to 25%
+	maxSurge := intstr.FromString("25%")
+	maxUnavailable := intstr.FromString("25%")
+	if strategy.RollingUpdate != nil {
+		if strategy.RollingUpdate.MaxSurge != nil {
+			maxSurge = *strategy.RollingUpdate.MaxSurge
+		}
+		if strategy.RollingUpdate.MaxUnavailable != nil {
+			maxUnavailable = *strategy.RollingUpdate.MaxUnavailable
+		}
+	}
+
+	replicas := int(report.DesiredReplicas)
+	// Surge rounds up and unavailability rounds down, as in the deployment controller
+	surge, errSurge := intstr.GetScaledValueFromIntOrPercent(&maxSurge, replicas, true)
+	unavailable, errUnavailable := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, replicas, false)
+	if errSurge != nil || errUnavailable != nil {
+		return
+	}
+	// The controller keeps one pod unavailable when both would otherwise be zero
+	if surge == 0 && unavailable == 0 {
+		unavailable = 1
+	}
+	report.Strategy = &RolloutStrategy{
+		Type:            string(appsv1.RollingUpdateDeploymentStrategyType),
+		MaxSurge:        maxSurge.String(),
+		MaxUnavailable:  maxUnavailable.String(),
+		SurgePods:       int32(surge),
+		UnavailablePods: int32(unavailable),
+	}
+	if replicas == 0 || unavailable == 0 {
+		return
+	}
+
+	available := replicas - unavailable
+	if available < 0 {
+		available = 0
+	}
+	switch {
+	case available == 0:
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Rolling update maxUnavailable %s lets all %d replica(s) be down at once during a rollout",
+				maxUnavailable.String(), replicas))
+	case float64(available) < float64(replicas)*minRolloutAvailability:
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Rolling update maxUnavailable %s leaves only %d of %d replica(s) available during a rollout",
+				maxUnavailable.String(), available, replicas))
+	default:
+		return
+	}
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+		fmt.Sprintf("Lower maxUnavailable so at least %d%% of the replicas keep serving during rollouts, e.g. maxUnavailable: 0 with maxSurge: %d",
+			int(minRolloutAvailability*100), max(surge, 1)))
+}
+
+// userFacingServices returns the LoadBalancer and NodePort services, and the services
+// behind an ingress, that select the deployment's pods
+func (d *DeploymentAnalyzer) userFacingServices(ctx context.Context, deployment *appsv1.Deployment) []string {
+	services, err := d.client.CoreV1().Services(d.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	exposed := make(map[string]bool)
+	if ingresses, err := d.client.NetworkingV1().Ingresses(d.namespace).List(ctx, metav1.ListOptions{}); err == nil {
+		for _, ingress := range ingresses.Items {
+			if backend := ingress.Spec.DefaultBackend; backend != nil && backend.Service != nil {
+				exposed[backend.Service.Name] = true
+			}
+			for _, rule := range ingress.Spec.Rules {
+				if rule.HTTP == nil {
+					continue
+				}
+				for _, path := range rule.HTTP.Paths {
+					if path.Backend.Service != nil {
+						exposed[path.Backend.Service.Name] = true
+					}
+				}
+			}
+		}
+	}
+
+	podLabels := labels.Set(deployment.Spec.Template.Labels)
+	var names []string
+	for _, service := range services.Items {
+		if len(service.Spec.Selector) == 0 || !labels.SelectorFromSet(service.Spec.Selector).Matches(podLabels) {
+			continue
+		}
+		switch {
+		case service.Spec.Type == corev1.ServiceTypeLoadBalancer, service.Spec.Type == corev1.ServiceTypeNodePort:
+			names = append(names, fmt.Sprintf("%s service %s", service.Spec.Type, service.Name))
+		case exposed[service.Name]:
+			names = append(names, fmt.Sprintf("service %s behind an ingress", service.Name))
+		}
+	}
+	return names
+}
@@ -0,0 +1,180 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DeprecatedAPI is an API version of a kind that Kubernetes deprecated and removes
+type DeprecatedAPI struct {
+	APIVersion   string
+	Kind         string
+	DeprecatedIn string
+	RemovedIn    string
+	// Replacement is the API version to migrate to, empty when the kind is gone
+	Replacement string
+}
+
+// deprecatedAPIs lists the deprecated API versions of built-in kinds by the release
+// that removes them, following the Kubernetes deprecated API migration guide
+var deprecatedAPIs = map[string][]DeprecatedAPI{
+	"1.16": {
+		{APIVersion: "extensions/v1beta1", Kind: "Deployment", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "extensions/v1beta1", Kind: "DaemonSet", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "extensions/v1beta1", Kind: "ReplicaSet", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "extensions/v1beta1", Kind: "NetworkPolicy", DeprecatedIn: "1.9", Replacement: "networking.k8s.io/v1"},
+		{APIVersion: "apps/v1beta1", Kind: "Deployment", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "apps/v1beta1", Kind: "StatefulSet", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "apps/v1beta2", Kind: "Deployment", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "apps/v1beta2", Kind: "StatefulSet", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "apps/v1beta2", Kind: "DaemonSet", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "apps/v1beta2", Kind: "ReplicaSet", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+	},
+	"1.22": {
+		{APIVersion: "extensions/v1beta1", Kind: "Ingress", DeprecatedIn: "1.14", Replacement: "networking.k8s.io/v1"},
+		{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress", DeprecatedIn: "1.19", Replacement: "networking.k8s.io/v1"},
+		{APIVersion: "networking.k8s.io/v1beta1", Kind: "IngressClass", DeprecatedIn: "1.19", Replacement: "networking.k8s.io/v1"},
+		{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "Role", DeprecatedIn: "1.17", Replacement: "rbac.authorization.k8s.io/v1"},
+		{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "RoleBinding", DeprecatedIn: "1.17", Replacement: "rbac.authorization.k8s.io/v1"},
+		{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRole", DeprecatedIn: "1.17", Replacement: "rbac.authorization.k8s.io/v1"},
+		{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRoleBinding", DeprecatedIn: "1.17", Replacement: "rbac.authorization.k8s.io/v1"},
+		{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition", DeprecatedIn: "1.16", Replacement: "apiextensions.k8s.io/v1"},
+		{APIVersion: "scheduling.k8s.io/v1beta1", Kind: "PriorityClass", DeprecatedIn: "1.14", Replacement: "scheduling.k8s.io/v1"},
+		{APIVersion: "storage.k8s.io/v1beta1", Kind: "StorageClass", DeprecatedIn: "1.19", Replacement: "storage.k8s.io/v1"},
+		{APIVersion: "coordination.k8s.io/v1beta1", Kind: "Lease", DeprecatedIn: "1.19", Replacement: "coordination.k8s.io/v1"},
+	},
+	"1.25": {
+		{APIVersion: "batch/v1beta1", Kind: "CronJob", DeprecatedIn: "1.21", Replacement: "batch/v1"},
+		{APIVersion: "discovery.k8s.io/v1beta1", Kind: "EndpointSlice", DeprecatedIn: "1.21", Replacement: "discovery.k8s.io/v1"},
+		{APIVersion: "autoscaling/v2beta1", Kind: "HorizontalPodAutoscaler", DeprecatedIn: "1.22", Replacement: "autoscaling/v2"},
+		{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", DeprecatedIn: "1.21", Replacement: "policy/v1"},
+		{APIVersion: "policy/v1beta1", Kind: "PodSecurityPolicy", DeprecatedIn: "1.21"},
+		{APIVersion: "node.k8s.io/v1beta1", Kind: "RuntimeClass", DeprecatedIn: "1.20", Replacement: "node.k8s.io/v1"},
+	},
+	"1.26": {
+		{APIVersion: "autoscaling/v2beta2", Kind: "HorizontalPodAutoscaler", DeprecatedIn: "1.23", Replacement: "autoscaling/v2"},
+	},
+	"1.27": {
+		{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSIStorageCapacity", DeprecatedIn: "1.24", Replacement: "storage.k8s.io/v1"},
+	},
+}
+
+// FindDeprecatedAPI returns the deprecation of an API version of a kind, or nil when
+// it is not deprecated
+func FindDeprecatedAPI(apiVersion, kind string) *DeprecatedAPI {
+	for removedIn, apis := range deprecatedAPIs {
+		for _, api := range apis {
+			if api.APIVersion == apiVersion && api.Kind == kind {
+				api.RemovedIn = removedIn
+				return &api
+			}
+		}
+	}
+	return nil
+}
+
+// lastAppliedAnnotation holds the configuration last applied with kubectl apply
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// apiUse is an API version a resource was written with, or refers to an owner through
+type apiUse struct {
+	APIVersion string
+	Kind       string
+	Source     string
+	// Object is the resource whose manifests use the API version, e.g. "Deployment web"
+	Object string
+}
+
+// objectAPIUses returns the API versions a resource was written with, from its field
+// managers and last applied configuration, and those of its owner references
+func objectAPIUses(object metav1.Object, kind string) []apiUse {
+	name := kind + " " + object.GetName()
+	var uses []apiUse
+	for _, field := range object.GetManagedFields() {
+		if field.APIVersion != "" {
+			uses = append(uses, apiUse{APIVersion: field.APIVersion, Kind: kind, Source: "written by " + field.Manager, Object: name})
+		}
+	}
+	if applied := object.GetAnnotations()[lastAppliedAnnotation]; applied != "" {
+		var typeMeta metav1.TypeMeta
+		if json.Unmarshal([]byte(applied), &typeMeta) == nil && typeMeta.APIVersion != "" {
+			uses = append(uses, apiUse{APIVersion: typeMeta.APIVersion, Kind: kind, Source: "last applied", Object: name})
+		}
+	}
+	for _, owner := range object.GetOwnerReferences() {
+		uses = append(uses, apiUse{APIVersion: owner.APIVersion, Kind: owner.Kind,
+			Source: fmt.Sprintf("owner %s/%s", owner.Kind, owner.Name), Object: owner.Kind + " " + owner.Name})
+	}
+	return uses
+}
+
+// analyzeDeprecatedAPIs flags the API versions a resource and its owners use that the
+// cluster's server version has deprecated or removed. Nothing is reported when the
+// server version cannot be determined.
+func analyzeDeprecatedAPIs(ctx context.Context, client kubernetes.Interface, object metav1.Object, kind string) ([]string, []string) {
+	info, err := serverVersion(ctx, client)
+	if err != nil {
+		return nil, nil
+	}
+	server, ok := parseMinorVersion(info.Major + "." + info.Minor)
+	if !ok {
+		return nil, nil
+	}
+
+	var issues, recommendations []string
+	seen := make(map[string]bool)
+	for _, use := range objectAPIUses(object, kind) {
+		key := use.APIVersion + "/" + use.Kind
+		api := FindDeprecatedAPI(use.APIVersion, use.Kind)
+		if api == nil || seen[key] {
+			continue
+		}
+		deprecated, _ := parseMinorVersion(api.DeprecatedIn)
+		removed, _ := parseMinorVersion(api.RemovedIn)
+		if server < deprecated {
+			continue
+		}
+		seen[key] = true
+
+		fix := fmt.Sprintf("move %s from %s to %s", api.Kind, api.APIVersion, api.Replacement)
+		if api.Replacement == "" {
+			fix = fmt.Sprintf("remove %s, which has no replacement", api.Kind)
+		}
+		if server >= removed {
+			issues = append(issues, fmt.Sprintf("%s %s uses %s %s (%s), which was removed in Kubernetes %s; manifests still using it fail to apply",
+				kind, object.GetName(), api.APIVersion, api.Kind, use.Source, api.RemovedIn))
+			recommendations = append(recommendations, fmt.Sprintf("Update the manifests of %s: %s", use.Object, fix))
+		} else {
+			issues = append(issues, fmt.Sprintf("%s %s uses %s %s (%s), deprecated since Kubernetes %s and removed in %s",
+				kind, object.GetName(), api.APIVersion, api.Kind, use.Source, api.DeprecatedIn, api.RemovedIn))
+			recommendations = append(recommendations, fmt.Sprintf("Before upgrading to Kubernetes %s, update the manifests of %s: %s",
+				api.RemovedIn, use.Object, fix))
+		}
+	}
+	return issues, recommendations
+}
+
+// parseMinorVersion turns a Kubernetes version like "1.22", or "1.27+" as reported by
+// some providers, into a number that orders releases
+func parseMinorVersion(version string) (int, bool) {
+	major, minor, found := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	if !found {
+		return 0, false
+	}
+	minor = strings.TrimRight(minor, "+")
+	majorNumber, err := strconv.Atoi(major)
+	if err != nil {
+		return 0, false
+	}
+	minorNumber, err := strconv.Atoi(minor)
+	if err != nil {
+		return 0, false
+	}
+	return majorNumber*1000 + minorNumber, true
+}
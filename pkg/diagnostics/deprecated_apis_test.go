@@ -0,0 +1,70 @@
+package diagnostics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMinorVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    int
+		wantOK  bool
+	}{
+		{version: "1.22", want: 1022, wantOK: true},
+		{version: "v1.9", want: 1009, wantOK: true},
+		{version: "1.27+", want: 1027, wantOK: true},
+		{version: "2.0", want: 2000, wantOK: true},
+		{version: "1", wantOK: false},
+		{version: "", wantOK: false},
+		{version: "one.two", wantOK: false},
+		{version: "1.x", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, ok := parseMinorVersion(tt.version)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+	older, _ := parseMinorVersion("1.9")
+	newer, _ := parseMinorVersion("1.16")
+	assert.Less(t, older, newer, "1.9 orders before 1.16")
+}
+
+func TestFindDeprecatedAPI(t *testing.T) {
+	tests := []struct {
+		name            string
+		apiVersion      string
+		kind            string
+		wantRemovedIn   string
+		wantReplacement string
+		wantNil         bool
+	}{
+		{name: "removed deployment version", apiVersion: "extensions/v1beta1", kind: "Deployment",
+			wantRemovedIn: "1.16", wantReplacement: "apps/v1"},
+		{name: "same version, other kind", apiVersion: "extensions/v1beta1", kind: "Ingress",
+			wantRemovedIn: "1.22", wantReplacement: "networking.k8s.io/v1"},
+		{name: "no replacement", apiVersion: "policy/v1beta1", kind: "PodSecurityPolicy", wantRemovedIn: "1.25"},
+		{name: "current version", apiVersion: "apps/v1", kind: "Deployment", wantNil: true},
+		{name: "kind must match", apiVersion: "batch/v1beta1", kind: "Job", wantNil: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := FindDeprecatedAPI(tt.apiVersion, tt.kind)
+			if tt.wantNil {
+				assert.Nil(t, api)
+				return
+			}
+			if assert.NotNil(t, api) {
+				assert.Equal(t, tt.wantRemovedIn, api.RemovedIn)
+				assert.Equal(t, tt.wantReplacement, api.Replacement)
+			}
+		})
+	}
+}
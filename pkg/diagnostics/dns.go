@@ -0,0 +1,363 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// clusterDNSService is the service cluster DNS is published under, whichever
+	// implementation backs it
+	clusterDNSService = "kube-dns"
+	// clusterDNSLabel selects the CoreDNS (or kube-dns) pods and deployment
+	clusterDNSLabel = "k8s-app=kube-dns"
+	// dnsLookupPodTimeout bounds how long a test lookup pod may take to complete
+	dnsLookupPodTimeout = 90 * time.Second
+	// dnsRestartThreshold is the number of restarts of a DNS pod worth reporting
+	dnsRestartThreshold = 5
+	// DefaultDNSLookupImage runs nslookup in the test lookup pod
+	DefaultDNSLookupImage = "busybox:1.36"
+)
+
+// DNSAnalyzer checks the health of cluster DNS as seen from a namespace
+type DNSAnalyzer struct {
+	client      kubernetes.Interface
+	namespace   string
+	lookup      string
+	lookupImage string
+}
+
+// NewDNSAnalyzer creates a new DNSAnalyzer
+func NewDNSAnalyzer(client kubernetes.Interface, namespace string) *DNSAnalyzer {
+	return &DNSAnalyzer{
+		client:      client,
+		namespace:   namespace,
+		lookupImage: DefaultDNSLookupImage,
+	}
+}
+
+// SetLookup makes the analysis resolve name from a short-lived pod in the namespace,
+// running nslookup from image. The pod is deleted once the lookup completes.
+func (d *DNSAnalyzer) SetLookup(name, image string) {
+	d.lookup = name
+	if image != "" {
+		d.lookupImage = image
+	}
+}
+
+// DNSReport contains the DNS analysis report
+type DNSReport struct {
+	Namespace string
+	// Deployment is the CoreDNS deployment, empty when none was found
+	Deployment      string
+	DesiredReplicas int32
+	ReadyReplicas   int32
+	// Pods are the cluster DNS pods with their readiness and restarts
+	Pods []DNSPod
+	// ServiceIP is the cluster IP of the kube-dns service, empty when it is missing
+	ServiceIP string
+	// ReadyEndpoints is the number of ready addresses behind the kube-dns service
+	ReadyEndpoints int
+	// CustomDNSPods are pods in the namespace that do not use cluster DNS, by dnsPolicy
+	CustomDNSPods map[string]corev1.DNSPolicy
+	// Lookup is the result of the test lookup, nil when none was requested
+	Lookup   *DNSLookup
+	Analysis DNSAnalysis
+}
+
+// DNSPod is a cluster DNS pod
+type DNSPod struct {
+	Name     string
+	Node     string
+	Ready    bool
+	Restarts int32
+}
+
+// DNSLookup is the result of resolving a name from a pod in the namespace
+type DNSLookup struct {
+	Name      string
+	Pod       string
+	Succeeded bool
+	Output    string
+	// Error is set when the lookup pod could not be run, as opposed to the lookup failing
+	Error string
+}
+
+// DNSAnalysis contains diagnostic results
+type DNSAnalysis struct {
+	Status          string
+	Issues          []string
+	Recommendations []string
+}
+
+// Analyze checks the CoreDNS deployment and pods, the kube-dns service and its
+// endpoints, the DNS policy of the namespace's pods and, when set, runs a test lookup
+func (d *DNSAnalyzer) Analyze(ctx context.Context) (*DNSReport, error) {
+	report := &DNSReport{
+		Namespace:     d.namespace,
+		CustomDNSPods: make(map[string]corev1.DNSPolicy),
+	}
+	critical := false
+
+	// Deployments are looked up by label, since providers rename CoreDNS
+	deployments, err := d.client.AppsV1().Deployments(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{LabelSelector: clusterDNSLabel})
+	if err != nil {
+		if !errors.IsForbidden(err) {
+			return nil, fmt.Errorf("failed to list DNS deployments: %v", err)
+		}
+		deployments = nil
+	}
+	if deployments != nil && len(deployments.Items) > 0 {
+		deployment := deployments.Items[0]
+		report.Deployment = deployment.Name
+		report.DesiredReplicas = 1
+		if deployment.Spec.Replicas != nil {
+			report.DesiredReplicas = *deployment.Spec.Replicas
+		}
+		report.ReadyReplicas = deployment.Status.ReadyReplicas
+	}
+
+	pods, err := d.client.CoreV1().Pods(metav1.NamespaceSystem).List(ctx, metav1.ListOptions{LabelSelector: clusterDNSLabel})
+	if err != nil {
+		if !errors.IsForbidden(err) {
+			return nil, fmt.Errorf("failed to list DNS pods: %v", err)
+		}
+		pods = nil
+	}
+	if pods != nil {
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			dnsPod := DNSPod{Name: pod.Name, Node: pod.Spec.NodeName, Ready: IsPodReady(pod)}
+			for _, status := range pod.Status.ContainerStatuses {
+				dnsPod.Restarts += status.RestartCount
+			}
+			report.Pods = append(report.Pods, dnsPod)
+		}
+	}
+
+	switch {
+	case deployments == nil && pods == nil:
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("The DNS pods in %s are not readable; only the service and lookup are checked", metav1.NamespaceSystem))
+	case report.Deployment == "" && len(report.Pods) == 0:
+		critical = true
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("No CoreDNS deployment or pods labeled %s found in %s", clusterDNSLabel, metav1.NamespaceSystem))
+	case report.Deployment != "" && report.ReadyReplicas == 0:
+		critical = true
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("CoreDNS deployment %s has no ready replicas", report.Deployment))
+	case report.Deployment != "" && report.ReadyReplicas < report.DesiredReplicas:
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("CoreDNS deployment %s has %d/%d ready replicas", report.Deployment, report.ReadyReplicas, report.DesiredReplicas))
+	case report.Deployment != "" && report.DesiredReplicas == 1:
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("CoreDNS deployment %s runs a single replica; DNS fails cluster-wide while it restarts", report.Deployment))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("kubectl scale deployment %s -n %s --replicas=2", report.Deployment, metav1.NamespaceSystem))
+	}
+
+	nodes := make(map[string]bool)
+	for _, pod := range report.Pods {
+		if pod.Node != "" {
+			nodes[pod.Node] = true
+		}
+		if pod.Restarts >= dnsRestartThreshold {
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				fmt.Sprintf("DNS pod %s has restarted %d times, which drops queries while it comes back", pod.Name, pod.Restarts))
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+				fmt.Sprintf("kubectl logs %s -n %s --previous", pod.Name, metav1.NamespaceSystem))
+		}
+	}
+	if len(report.Pods) > 1 && len(nodes) == 1 {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			"All DNS pods run on the same node, so losing that node takes down cluster DNS")
+	}
+
+	if d.analyzeService(ctx, report) {
+		critical = true
+	}
+
+	if err := d.analyzeNamespacePods(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if d.lookup != "" {
+		report.Lookup = d.runLookup(ctx)
+		switch {
+		case report.Lookup.Error != "":
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+				fmt.Sprintf("The test lookup could not run: %s", report.Lookup.Error))
+		case !report.Lookup.Succeeded:
+			critical = true
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				fmt.Sprintf("Lookup of %s from namespace %s failed", d.lookup, d.namespace))
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+				"Check the name exists, and that network policies in the namespace allow egress to the kube-dns service on port 53 (UDP and TCP)")
+		}
+	}
+
+	switch {
+	case critical:
+		report.Analysis.Status = "Unhealthy"
+	case len(report.Analysis.Issues) > 0:
+		report.Analysis.Status = "Needs Attention"
+	default:
+		report.Analysis.Status = "Healthy"
+	}
+	return report, nil
+}
+
+// analyzeService checks the kube-dns service, its DNS ports and its ready endpoints.
+// It reports whether cluster DNS is unreachable.
+func (d *DNSAnalyzer) analyzeService(ctx context.Context, report *DNSReport) bool {
+	service, err := d.client.CoreV1().Services(metav1.NamespaceSystem).Get(ctx, clusterDNSService, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Service %s not found in %s; pods resolve names through it", clusterDNSService, metav1.NamespaceSystem))
+		return true
+	}
+	if err != nil {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("The %s service could not be read: %v", clusterDNSService, err))
+		return false
+	}
+	report.ServiceIP = service.Spec.ClusterIP
+
+	protocols := make(map[corev1.Protocol]bool)
+	for _, port := range service.Spec.Ports {
+		if port.Port == 53 {
+			protocols[port.Protocol] = true
+		}
+	}
+	if !protocols[corev1.ProtocolUDP] || !protocols[corev1.ProtocolTCP] {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Service %s does not expose port 53 over both UDP and TCP; large responses that fall back to TCP fail", clusterDNSService))
+	}
+
+	endpoints := NewEndpointAnalyzer(d.client, metav1.NamespaceSystem)
+	endpointReport, err := endpoints.ValidateEndpoints(ctx, clusterDNSService)
+	if err != nil {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("The %s endpoints could not be read: %v", clusterDNSService, err))
+		return false
+	}
+	report.ReadyEndpoints = readyEndpointAddresses(endpointReport)
+	if report.ReadyEndpoints == 0 {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Service %s has no ready endpoints; every lookup in the cluster times out", clusterDNSService))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("kubectl get pods -n %s -l %s", metav1.NamespaceSystem, clusterDNSLabel))
+		return true
+	}
+	return false
+}
+
+// analyzeNamespacePods finds the pods of the namespace that bypass cluster DNS
+func (d *DNSAnalyzer) analyzeNamespacePods(ctx context.Context, report *DNSReport) error {
+	pods, err := d.client.CoreV1().Pods(d.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods in namespace %s: %v", d.namespace, err)
+	}
+	for _, pod := range pods.Items {
+		switch pod.Spec.DNSPolicy {
+		case corev1.DNSDefault, corev1.DNSNone:
+			report.CustomDNSPods[pod.Name] = pod.Spec.DNSPolicy
+		case corev1.DNSClusterFirst:
+			// Host network pods with ClusterFirst silently fall back to the node's resolver
+			if pod.Spec.HostNetwork {
+				report.CustomDNSPods[pod.Name] = pod.Spec.DNSPolicy
+			}
+		}
+	}
+	if len(report.CustomDNSPods) > 0 {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("%d pod(s) do not resolve through cluster DNS because of their dnsPolicy; service names will not resolve in them",
+				len(report.CustomDNSPods)))
+	}
+	return nil
+}
+
+// runLookup resolves the lookup name with nslookup from a short-lived pod in the
+// namespace and deletes the pod afterwards. The pod satisfies the restricted Pod Security
+// Standard and sets requests and limits, so it is admitted in locked-down namespaces and
+// namespaces whose quota requires them.
+func (d *DNSAnalyzer) runLookup(ctx context.Context) *DNSLookup {
+	lookup := &DNSLookup{Name: d.lookup, Pod: "k8s-lens-dns-" + utilrand.String(5)}
+
+	deadline := int64(dnsLookupPodTimeout / time.Second)
+	// busybox runs as root unless told otherwise; nslookup needs no privileges
+	nobody := int64(65534)
+	nonRoot, noEscalation := true, false
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("10m"),
+		corev1.ResourceMemory: resource.MustParse("16Mi"),
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      lookup.Pod,
+			Namespace: d.namespace,
+			Labels:    map[string]string{"app.kubernetes.io/managed-by": "k8s-lens"},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:         corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds: &deadline,
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   &nonRoot,
+				RunAsUser:      &nobody,
+				RunAsGroup:     &nobody,
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			Containers: []corev1.Container{{
+				Name:    "lookup",
+				Image:   d.lookupImage,
+				Command: []string{"nslookup", d.lookup},
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: &noEscalation,
+					Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				},
+				Resources: corev1.ResourceRequirements{Requests: resources, Limits: resources},
+			}},
+		},
+	}
+	if _, err := d.client.CoreV1().Pods(d.namespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		lookup.Error = fmt.Sprintf("failed to create lookup pod: %v", err)
+		return lookup
+	}
+	defer func() {
+		// The pod is deleted even when the analysis was interrupted
+		grace := int64(0)
+		_ = d.client.CoreV1().Pods(d.namespace).Delete(context.Background(), lookup.Pod, metav1.DeleteOptions{GracePeriodSeconds: &grace})
+	}()
+
+	var phase corev1.PodPhase
+	err := wait.PollUntilContextTimeout(ctx, time.Second, dnsLookupPodTimeout, true, func(ctx context.Context) (bool, error) {
+		current, err := d.client.CoreV1().Pods(d.namespace).Get(ctx, lookup.Pod, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		phase = current.Status.Phase
+		return phase == corev1.PodSucceeded || phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		lookup.Error = fmt.Sprintf("lookup pod did not complete (phase %s): %v", phase, err)
+		return lookup
+	}
+
+	output, err := d.client.CoreV1().Pods(d.namespace).GetLogs(lookup.Pod, &corev1.PodLogOptions{}).DoRaw(ctx)
+	if err == nil {
+		lookup.Output = strings.TrimSpace(string(output))
+	}
+	lookup.Succeeded = phase == corev1.PodSucceeded
+	return lookup
+}
@@ -3,8 +3,12 @@ package diagnostics
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -28,6 +32,7 @@ type EndpointReport struct {
 	ServiceName string
 	Namespace   string
 	Endpoints   *corev1.Endpoints
+	Slices      []discoveryv1.EndpointSlice
 	Pods        []corev1.Pod
 	Analysis    EndpointAnalysis
 }
@@ -39,18 +44,44 @@ type EndpointAnalysis struct {
 	Recommendations []string
 	ReadyPods       int
 	TotalPods       int
+	// SliceAddresses is the number of ready addresses across all EndpointSlices
+	SliceAddresses int
+	// MissingFromEndpoints lists ready addresses present in EndpointSlices but not in Endpoints
+	MissingFromEndpoints []string
+	// MissingFromSlices lists ready addresses present in Endpoints but not in EndpointSlices
+	MissingFromSlices []string
+	// EndpointsTruncated is set when the Endpoints object exceeded its 1000 address capacity
+	EndpointsTruncated bool
+	// Zones breaks the EndpointSlice endpoints down by zone, including zones of the
+	// cluster without endpoints
+	Zones []ZoneEndpoints
+	// TopologyAwareRouting is set when the service asks for in-zone routing
+	TopologyAwareRouting bool
+	// TargetPortMismatches are named target ports some selected pods do not declare
+	TargetPortMismatches []TargetPortMismatch
 }
 
 // ValidateEndpoints analyzes endpoints for a service
-func (e *EndpointAnalyzer) ValidateEndpoints(serviceName string) (*EndpointReport, error) {
-	// Get endpoints
-	endpoints, err := e.client.CoreV1().Endpoints(e.namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
-	if err != nil {
+func (e *EndpointAnalyzer) ValidateEndpoints(ctx context.Context, serviceName string) (*EndpointReport, error) {
+	// Get endpoints; a missing legacy object is reported as an issue rather than
+	// failing, since EndpointSlices may still exist for the service
+	endpoints, err := e.client.CoreV1().Endpoints(e.namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		endpoints = nil
+	} else if err != nil {
 		return nil, fmt.Errorf("failed to get endpoints for service %s: %v", serviceName, err)
 	}
 
+	// Get the EndpointSlices, which are authoritative on newer clusters
+	slices, err := e.client.DiscoveryV1().EndpointSlices(e.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, serviceName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoint slices for service %s: %v", serviceName, err)
+	}
+
 	// Get the service to find selector
-	service, err := e.client.CoreV1().Services(e.namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
+	service, err := e.client.CoreV1().Services(e.namespace).Get(ctx, serviceName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service %s: %v", serviceName, err)
 	}
@@ -62,7 +93,7 @@ func (e *EndpointAnalyzer) ValidateEndpoints(serviceName string) (*EndpointRepor
 			MatchLabels: service.Spec.Selector,
 		})
 
-		podList, err := e.client.CoreV1().Pods(e.namespace).List(context.TODO(), metav1.ListOptions{
+		podList, err := e.client.CoreV1().Pods(e.namespace).List(ctx, metav1.ListOptions{
 			LabelSelector: labelSelector,
 		})
 		if err != nil {
@@ -75,11 +106,15 @@ func (e *EndpointAnalyzer) ValidateEndpoints(serviceName string) (*EndpointRepor
 		ServiceName: serviceName,
 		Namespace:   e.namespace,
 		Endpoints:   endpoints,
+		Slices:      slices.Items,
 		Pods:        pods,
 	}
 
 	e.analyzeEndpoints(report)
+	e.reconcileEndpointSlices(report)
+	e.analyzeTargetPorts(report, service)
 	e.analyzePodReadiness(report)
+	e.analyzeTopology(ctx, report, service)
 
 	return report, nil
 }
@@ -105,16 +140,105 @@ func (e *EndpointAnalyzer) analyzeEndpoints(report *EndpointReport) {
 	}
 }
 
+// reconcileEndpointSlices compares the ready addresses in the legacy Endpoints object
+// with those in the service's EndpointSlices and reports any discrepancy
+func (e *EndpointAnalyzer) reconcileEndpointSlices(report *EndpointReport) {
+	endpointAddresses := make(map[string]bool)
+	if report.Endpoints != nil {
+		for _, subset := range report.Endpoints.Subsets {
+			for _, address := range subset.Addresses {
+				endpointAddresses[address.IP] = true
+			}
+		}
+		report.Analysis.EndpointsTruncated = report.Endpoints.Annotations[corev1.EndpointsOverCapacity] == "truncated"
+	}
+
+	sliceAddresses := make(map[string]bool)
+	for _, slice := range report.Slices {
+		for _, endpoint := range slice.Endpoints {
+			// A nil ready condition is interpreted as ready
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				continue
+			}
+			for _, address := range endpoint.Addresses {
+				sliceAddresses[address] = true
+			}
+		}
+	}
+	report.Analysis.SliceAddresses = len(sliceAddresses)
+
+	if len(report.Slices) == 0 {
+		if report.Endpoints != nil && len(endpointAddresses) > 0 {
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				"No EndpointSlices found for service; kube-proxy on newer clusters will not route traffic to it")
+		}
+		return
+	}
+
+	for address := range sliceAddresses {
+		if !endpointAddresses[address] {
+			report.Analysis.MissingFromEndpoints = append(report.Analysis.MissingFromEndpoints, address)
+		}
+	}
+	for address := range endpointAddresses {
+		if !sliceAddresses[address] {
+			report.Analysis.MissingFromSlices = append(report.Analysis.MissingFromSlices, address)
+		}
+	}
+	sort.Strings(report.Analysis.MissingFromEndpoints)
+	sort.Strings(report.Analysis.MissingFromSlices)
+
+	if report.Analysis.EndpointsTruncated {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Endpoints object is truncated at %d addresses; EndpointSlices list %d ready addresses",
+				len(endpointAddresses), len(sliceAddresses)))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Use EndpointSlices instead of the legacy Endpoints object when inspecting large services")
+	} else if len(report.Analysis.MissingFromEndpoints) > 0 || len(report.Analysis.MissingFromSlices) > 0 {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Endpoints and EndpointSlices disagree: %d address(es) only in slices, %d only in Endpoints",
+				len(report.Analysis.MissingFromEndpoints), len(report.Analysis.MissingFromSlices)))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Check the endpoint and endpointslice-mirroring controllers in kube-controller-manager for errors")
+	}
+}
+
+// analyzeTargetPorts checks the service's named target ports against the container
+// ports of the selected pods
+func (e *EndpointAnalyzer) analyzeTargetPorts(report *EndpointReport, service *corev1.Service) {
+	report.Analysis.TargetPortMismatches = findTargetPortMismatches(service.Spec.Ports, report.Pods)
+	issues, recommendations := targetPortMismatchIssues(report.Analysis.TargetPortMismatches, len(report.Pods))
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+}
+
 func (e *EndpointAnalyzer) analyzePodReadiness(report *EndpointReport) {
 	readyPods := 0
 	totalPods := len(report.Pods)
 
+	gatedPods := 0
 	for _, pod := range report.Pods {
-		if isPodReady(&pod) {
+		if IsPodReady(&pod) {
 			readyPods++
+			continue
+		}
+
+		// Running pods with ready containers can still be held out of the
+		// endpoints by readiness gates, which is easy to miss
+		unmetGates := UnmetReadinessGates(&pod)
+		if pod.Status.Phase == corev1.PodRunning && podConditionTrue(&pod, corev1.ContainersReady) && len(unmetGates) > 0 {
+			gatedPods++
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				fmt.Sprintf("Pod %s is Running with containers ready, but not serving because readiness gate %s is false",
+					pod.Name, strings.Join(unmetGates, ", ")))
 		}
 	}
 
+	if gatedPods > 0 {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Check the controller that owns the readiness gate (e.g. the ingress or load balancer controller) for target registration errors")
+	}
+
 	report.Analysis.ReadyPods = readyPods
 	report.Analysis.TotalPods = totalPods
 
@@ -136,9 +260,27 @@ func (e *EndpointAnalyzer) analyzePodReadiness(report *EndpointReport) {
 	}
 }
 
-func isPodReady(pod *corev1.Pod) bool {
+// IsPodReady reports whether a pod is ready to serve traffic: the PodReady condition
+// must be True and every readiness gate condition in the pod spec must be True
+func IsPodReady(pod *corev1.Pod) bool {
+	return podConditionTrue(pod, corev1.PodReady) && len(UnmetReadinessGates(pod)) == 0
+}
+
+// UnmetReadinessGates returns the readiness gate conditions of a pod that are not True.
+// A gate whose condition has not been reported yet counts as unmet.
+func UnmetReadinessGates(pod *corev1.Pod) []string {
+	var unmet []string
+	for _, gate := range pod.Spec.ReadinessGates {
+		if !podConditionTrue(pod, gate.ConditionType) {
+			unmet = append(unmet, string(gate.ConditionType))
+		}
+	}
+	return unmet
+}
+
+func podConditionTrue(pod *corev1.Pod, conditionType corev1.PodConditionType) bool {
 	for _, condition := range pod.Status.Conditions {
-		if condition.Type == corev1.PodReady {
+		if condition.Type == conditionType {
 			return condition.Status == corev1.ConditionTrue
 		}
 	}
@@ -0,0 +1,150 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// unknownZone groups endpoints and nodes without a zone
+const unknownZone = "unknown"
+
+// ZoneEndpoints counts the endpoints of a service in one zone
+type ZoneEndpoints struct {
+	Zone     string
+	Ready    int
+	NotReady int
+	// Nodes lists the nodes running ready endpoints
+	Nodes []string
+	// Hinted counts the ready endpoints whose topology hints route this zone's
+	// traffic to them; without hints every endpoint serves every zone
+	Hinted int
+}
+
+// topologyAwareRouting reports whether a service asks for traffic to be kept close to
+// the client, with the topology-mode annotation or the trafficDistribution field
+func topologyAwareRouting(service *corev1.Service) bool {
+	if service.Spec.TrafficDistribution != nil {
+		return true
+	}
+	for _, annotation := range []string{corev1.AnnotationTopologyMode, corev1.DeprecatedAnnotationTopologyAwareHints} {
+		if mode := strings.ToLower(service.Annotations[annotation]); mode != "" && mode != "disabled" {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeTopology breaks the endpoints of the service's EndpointSlices down by zone
+// and node, and flags availability risks: every ready endpoint in a single zone of a
+// multi-zone cluster, and topology hints that send no endpoints to a zone. Zones of the
+// cluster come from the node labels; when nodes cannot be listed, only the zones of
+// the endpoints are known. Findings are reported as issues without changing the
+// status, which reflects whether the service can serve traffic at all.
+func (e *EndpointAnalyzer) analyzeTopology(ctx context.Context, report *EndpointReport, service *corev1.Service) {
+	report.Analysis.TopologyAwareRouting = topologyAwareRouting(service)
+
+	zones := make(map[string]*ZoneEndpoints)
+	zone := func(name string) *ZoneEndpoints {
+		if name == "" {
+			name = unknownZone
+		}
+		if zones[name] == nil {
+			zones[name] = &ZoneEndpoints{Zone: name}
+		}
+		return zones[name]
+	}
+
+	// Zones with nodes but no endpoints matter as much as the ones with endpoints
+	clusterZones := make(map[string]bool)
+	if nodes, err := e.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err == nil {
+		for _, node := range nodes.Items {
+			if name := node.Labels[corev1.LabelTopologyZone]; name != "" {
+				clusterZones[name] = true
+				zone(name)
+			}
+		}
+	}
+
+	hints := false
+	ready := 0
+	for _, slice := range report.Slices {
+		for _, endpoint := range slice.Endpoints {
+			entry := zone(stringValue(endpoint.Zone))
+			// A nil ready condition is interpreted as ready
+			if endpoint.Conditions.Ready != nil && !*endpoint.Conditions.Ready {
+				entry.NotReady++
+				continue
+			}
+			entry.Ready++
+			ready++
+			if node := stringValue(endpoint.NodeName); node != "" && !containsString(entry.Nodes, node) {
+				entry.Nodes = append(entry.Nodes, node)
+			}
+			if endpoint.Hints != nil {
+				for _, hint := range endpoint.Hints.ForZones {
+					hints = true
+					zone(hint.Name).Hinted++
+				}
+			}
+		}
+	}
+
+	for _, entry := range zones {
+		sort.Strings(entry.Nodes)
+		report.Analysis.Zones = append(report.Analysis.Zones, *entry)
+	}
+	sort.Slice(report.Analysis.Zones, func(i, j int) bool {
+		a, b := report.Analysis.Zones[i].Zone, report.Analysis.Zones[j].Zone
+		if (a == unknownZone) != (b == unknownZone) {
+			return b == unknownZone
+		}
+		return a < b
+	})
+
+	var readyZones []string
+	for _, entry := range report.Analysis.Zones {
+		if entry.Ready > 0 && entry.Zone != unknownZone {
+			readyZones = append(readyZones, entry.Zone)
+		}
+	}
+	if len(readyZones) == 1 && len(clusterZones) > 1 && zones[unknownZone] == nil {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("All %d ready endpoint(s) are in zone %s of a %d-zone cluster; losing that zone takes the service down",
+				ready, readyZones[0], len(clusterZones)))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Spread the service's pods across zones with a topologySpreadConstraint on topology.kubernetes.io/zone")
+	}
+
+	if !hints {
+		if report.Analysis.TopologyAwareRouting && ready > 0 {
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+				"Topology-aware routing is enabled but the EndpointSlices carry no zone hints, so traffic is not kept in-zone; the controller leaves hints out when endpoints are too few or too unevenly spread")
+		}
+		return
+	}
+	var blackholed []string
+	for _, entry := range report.Analysis.Zones {
+		if entry.Hinted == 0 && entry.Zone != unknownZone {
+			blackholed = append(blackholed, entry.Zone)
+		}
+	}
+	if len(blackholed) > 0 {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Topology hints route no endpoints to zone(s) %s; older kube-proxy versions drop traffic from there and newer ones send it to other zones",
+				strings.Join(blackholed, ", ")))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Run ready endpoints in every zone with clients, or disable topology-aware routing for the service")
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
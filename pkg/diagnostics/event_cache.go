@@ -0,0 +1,60 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EventCache holds every event of a namespace, listed in a single request and indexed
+// by the object they involve. Analyzers given a cache look events up in it instead of
+// listing them per object, which saves one API call per analyzed resource.
+type EventCache struct {
+	events map[string][]corev1.Event
+}
+
+// NewEventCache lists the events of a namespace, or of every namespace when namespace
+// is empty, and indexes them by involved object
+func NewEventCache(ctx context.Context, client kubernetes.Interface, namespace string) (*EventCache, error) {
+	return NewNamespacesEventCache(ctx, client, []string{namespace})
+}
+
+// NewNamespacesEventCache lists the events of each of the namespaces, one request per
+// namespace, and indexes them by involved object
+func NewNamespacesEventCache(ctx context.Context, client kubernetes.Interface, namespaces []string) (*EventCache, error) {
+	cache := &EventCache{events: make(map[string][]corev1.Event)}
+	for _, namespace := range namespaces {
+		events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list events: %v", err)
+		}
+		for _, event := range events.Items {
+			key := event.Namespace + "/" + event.InvolvedObject.Name
+			cache.events[key] = append(cache.events[key], event)
+		}
+	}
+	return cache, nil
+}
+
+// Events returns the events involving the named object in a namespace
+func (c *EventCache) Events(namespace, name string) []corev1.Event {
+	return c.events[namespace+"/"+name]
+}
+
+// objectEvents returns the events involving the named object, from the cache when
+// one is set and otherwise with a List call filtered on the object name
+func objectEvents(ctx context.Context, client kubernetes.Interface, cache *EventCache, namespace, name string) ([]corev1.Event, error) {
+	if cache != nil {
+		return cache.Events(namespace, name), nil
+	}
+	events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "involvedObject.name=" + name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events.Items, nil
+}
@@ -3,6 +3,7 @@ package diagnostics
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -34,9 +35,9 @@ type EventAnalysis struct {
 }
 
 // AnalyzeEvents analyzes events for a specific resource
-func (e *EventsAnalyzer) AnalyzeEvents(resourceName string, resourceType string) (*EventAnalysis, error) {
+func (e *EventsAnalyzer) AnalyzeEvents(ctx context.Context, resourceName string, resourceType string) (*EventAnalysis, error) {
 	// Get events for the resource
-	events, err := e.client.CoreV1().Events(e.namespace).List(context.TODO(), metav1.ListOptions{
+	events, err := e.client.CoreV1().Events(e.namespace).List(ctx, metav1.ListOptions{
 		FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.kind=%s", resourceName, resourceType),
 	})
 	if err != nil {
@@ -75,8 +76,8 @@ func (e *EventsAnalyzer) AnalyzeEvents(resourceName string, resourceType string)
 }
 
 // AnalyzeNamespaceEvents analyzes all events in a namespace
-func (e *EventsAnalyzer) AnalyzeNamespaceEvents() (*EventAnalysis, error) {
-	events, err := e.client.CoreV1().Events(e.namespace).List(context.TODO(), metav1.ListOptions{})
+func (e *EventsAnalyzer) AnalyzeNamespaceEvents(ctx context.Context) (*EventAnalysis, error) {
+	events, err := e.client.CoreV1().Events(e.namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events for namespace %s: %v", e.namespace, err)
 	}
@@ -115,3 +116,132 @@ func (e *EventsAnalyzer) AnalyzeNamespaceEvents() (*EventAnalysis, error) {
 
 	return analysis, nil
 }
+
+// EventSummary groups the recent events of a namespace by reason and involved object
+type EventSummary struct {
+	Namespace     string
+	Since         time.Duration
+	TotalEvents   int
+	WarningEvents int
+	// TopReasons lists each reason with its total occurrences, most frequent first
+	TopReasons []ReasonCount
+	// Groups lists each reason and involved object pair, most recently seen first
+	Groups []EventGroup
+}
+
+// ReasonCount is the number of times events with a reason occurred
+type ReasonCount struct {
+	Reason string
+	Type   string
+	Count  int32
+}
+
+// EventGroup is the events with the same reason about the same object
+type EventGroup struct {
+	Reason    string
+	Type      string
+	Kind      string
+	Name      string
+	Namespace string
+	Count     int32
+	LastSeen  time.Time
+	// Message is the message of the most recent event in the group
+	Message string
+}
+
+// SummarizeEvents groups the events of the namespace seen within since, or all events
+// when since is zero, optionally keeping only warnings
+func (e *EventsAnalyzer) SummarizeEvents(ctx context.Context, since time.Duration, warningsOnly bool) (*EventSummary, error) {
+	events, err := e.client.CoreV1().Events(e.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events for namespace %s: %v", e.namespace, err)
+	}
+
+	summary := &EventSummary{Namespace: e.namespace, Since: since}
+	groups := make(map[string]*EventGroup)
+	reasons := make(map[string]*ReasonCount)
+	cutoff := time.Now().Add(-since)
+
+	for _, event := range events.Items {
+		lastSeen := eventLastSeen(event)
+		if since > 0 && lastSeen.Before(cutoff) {
+			continue
+		}
+		if warningsOnly && event.Type != corev1.EventTypeWarning {
+			continue
+		}
+
+		count := eventCount(event)
+		summary.TotalEvents += int(count)
+		if event.Type == corev1.EventTypeWarning {
+			summary.WarningEvents += int(count)
+		}
+
+		object := event.InvolvedObject
+		key := fmt.Sprintf("%s/%s/%s/%s/%s", event.Reason, event.Type, object.Kind, object.Namespace, object.Name)
+		group, ok := groups[key]
+		if !ok {
+			group = &EventGroup{Reason: event.Reason, Type: event.Type, Kind: object.Kind, Name: object.Name, Namespace: object.Namespace}
+			groups[key] = group
+		}
+		group.Count += count
+		if lastSeen.After(group.LastSeen) {
+			group.LastSeen = lastSeen
+			group.Message = event.Message
+		}
+
+		reasonKey := event.Reason + "/" + event.Type
+		if reasons[reasonKey] == nil {
+			reasons[reasonKey] = &ReasonCount{Reason: event.Reason, Type: event.Type}
+		}
+		reasons[reasonKey].Count += count
+	}
+
+	for _, group := range groups {
+		summary.Groups = append(summary.Groups, *group)
+	}
+	sort.Slice(summary.Groups, func(i, j int) bool {
+		if !summary.Groups[i].LastSeen.Equal(summary.Groups[j].LastSeen) {
+			return summary.Groups[i].LastSeen.After(summary.Groups[j].LastSeen)
+		}
+		return summary.Groups[i].Count > summary.Groups[j].Count
+	})
+
+	for _, reason := range reasons {
+		summary.TopReasons = append(summary.TopReasons, *reason)
+	}
+	sort.Slice(summary.TopReasons, func(i, j int) bool {
+		if summary.TopReasons[i].Count != summary.TopReasons[j].Count {
+			return summary.TopReasons[i].Count > summary.TopReasons[j].Count
+		}
+		return summary.TopReasons[i].Reason < summary.TopReasons[j].Reason
+	})
+
+	return summary, nil
+}
+
+// eventCount returns how many times an event occurred, from its series when the
+// events.k8s.io API recorded one
+func eventCount(event corev1.Event) int32 {
+	if event.Series != nil && event.Series.Count > 0 {
+		return event.Series.Count
+	}
+	if event.Count > 0 {
+		return event.Count
+	}
+	return 1
+}
+
+// eventLastSeen returns when an event was last observed, falling back through the
+// timestamps set by the different event APIs
+func eventLastSeen(event corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
@@ -0,0 +1,277 @@
+package diagnostics
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Explanation expands an issue with its probable cause, its impact and how to fix it
+type Explanation struct {
+	Issue  string
+	Cause  string
+	Impact string
+	// Fix is the remediation, usually the command to run next
+	Fix string
+}
+
+// explanation is a knowledge base entry. Fix may reference the issue's capture groups
+// as $1, $2 and the analyzed resource as {{kind}}, {{name}} and {{namespace}}.
+type explanation struct {
+	pattern *regexp.Regexp
+	cause   string
+	impact  string
+	fix     string
+}
+
+// explanations is the knowledge base of issue types, matched against the issue text
+var explanations = []explanation{
+	// Pods and containers
+	{
+		pattern: regexp.MustCompile(`^Container (\S+) cannot pull image`),
+		cause:   "The image name or tag is wrong, the registry is unreachable from the node, or the pod lacks the imagePullSecrets for a private registry.",
+		impact:  "The container never starts; the pod stays in ImagePullBackOff and the kubelet keeps retrying with increasing delays.",
+		fix:     `kubectl describe pod {{name}} -n {{namespace}}  # check the image of container $1 and the pull error in the events`,
+	},
+	{
+		pattern: regexp.MustCompile(`^Container (\S+) is crashing`),
+		cause:   "The process exits right after starting, typically due to a configuration error, a missing dependency, or a failing liveness probe.",
+		impact:  "The container restarts in CrashLoopBackOff with growing back-off delays and serves no traffic in between.",
+		fix:     `kubectl logs {{name}} -n {{namespace}} -c $1 --previous`,
+	},
+	{
+		pattern: regexp.MustCompile(`^Container (\S+) runs image digest \S+ but its spec pins`),
+		cause:   "The node runs an image other than the pinned digest, usually because the image was retagged or the runtime resolved a different manifest.",
+		impact:  "The pod may run code that was never reviewed or tested under the pinned digest.",
+		fix:     `kubectl delete pod {{name}} -n {{namespace}}  # let the controller recreate it with the pinned image`,
+	},
+	{
+		pattern: regexp.MustCompile(`^Container (\S+) runs \S+ as digest .* the tag was pushed again`),
+		cause:   "A mutable tag was pushed again while pods were running, so pods started at different times pulled different images.",
+		impact:  "Replicas of the same workload run different code, causing inconsistent behavior that is hard to reproduce.",
+		fix:     "Pin images by digest (image@sha256:...) or use immutable tags, then restart the workload so all replicas run the same image",
+	},
+	{
+		pattern: regexp.MustCompile(`^Container (\S+) (\S+) probe timeout \(\d+s\) is longer than its period`),
+		cause:   "timeoutSeconds was raised for a slow endpoint without raising periodSeconds.",
+		impact:  "A new probe starts before the previous one times out, so a slow endpoint piles up concurrent checks and fails them all.",
+		fix:     "Set timeoutSeconds below periodSeconds on the $2 probe of container $1",
+	},
+	{
+		pattern: regexp.MustCompile(`^Container (\S+) (\S+) probe checks port (\S+), which is not declared`),
+		cause:   "The probe port was changed or mistyped, or refers to a named port that does not exist.",
+		impact:  "A probe against a port nothing listens on always fails, keeping the pod unready or restarting it.",
+		fix:     "Point the $2 probe of container $1 at a declared containerPort, or declare port $3 in the container ports",
+	},
+	{
+		pattern: regexp.MustCompile(`^Container (\S+) (\S+) probe only opens a TCP connection to HTTP port`),
+		cause:   "A tcpSocket probe was used where an httpGet probe was intended.",
+		impact:  "The probe passes as long as the port accepts connections, even when the application is hung or returning errors.",
+		fix:     "Replace the tcpSocket $2 probe of container $1 with an httpGet probe on a health endpoint",
+	},
+	{
+		pattern: regexp.MustCompile(`^Container (\S+) liveness probe is flapping`),
+		cause:   "The liveness probe of container $1 is too strict for how long the application takes to respond, or checks dependencies that are briefly unavailable.",
+		impact:  "The kubelet kills a slow but healthy container over and over, which looks like restarts without a crash.",
+		fix:     "kubectl get events -n {{namespace}} --field-selector involvedObject.name={{name}},reason=Unhealthy  # then raise timeoutSeconds/failureThreshold of the $1 liveness probe",
+	},
+	{
+		pattern: regexp.MustCompile(`^Container (\S+) readiness probe starts immediately`),
+		cause:   "The readiness probe has no initialDelaySeconds and no startupProbe, although the application needs time to start.",
+		impact:  "The probe fails until the app is up, filling the events with failures and delaying readiness by the failure back-off.",
+		fix:     "Add a startupProbe to container $1, or set initialDelaySeconds on its readiness probe",
+	},
+	{
+		pattern: regexp.MustCompile(`^Pod (\S+) is Running with containers ready, but not serving because readiness gate (\S+) is false`),
+		cause:   "An external controller, such as a load balancer controller, has not yet set the readiness gate condition.",
+		impact:  "The pod receives no traffic even though all its containers are ready.",
+		fix:     `kubectl get pod $1 -n {{namespace}} -o jsonpath='{.status.conditions}'  # check the controller owning gate $2`,
+	},
+
+	// Scheduling
+	{
+		pattern: regexp.MustCompile(`^Taint (\S+) on \d+ of \d+ node\(s\) is not tolerated`),
+		cause:   "The nodes are tainted for dedicated workloads or maintenance and the pod has no matching toleration.",
+		impact:  "The scheduler cannot place the pod on those nodes; it stays Pending if no other node fits.",
+		fix:     "Add a toleration for $1 to the pod template, or remove the taint with 'kubectl taint nodes <node> $1-'",
+	},
+	{
+		pattern: regexp.MustCompile(`^nodeSelector (\S+) is not satisfied`),
+		cause:   "No schedulable node carries the labels required by the pod's nodeSelector.",
+		impact:  "The pod stays Pending until a node with matching labels becomes available.",
+		fix:     "kubectl get nodes -l $1  # then label a node or fix the nodeSelector",
+	},
+	{
+		pattern: regexp.MustCompile(`^Required node affinity (?:term|expression) (.+) matches 0 of`),
+		cause:   "No node carries labels matching $1 from the pod's requiredDuringSchedulingIgnoredDuringExecution node affinity.",
+		impact:  "The pod stays Pending until a node with matching labels exists.",
+		fix:     "kubectl get nodes -l '$1'  # then label the target nodes or relax the node affinity",
+	},
+	{
+		pattern: regexp.MustCompile(`^Required node affinity is not satisfied`),
+		cause:   "The requiredDuringSchedulingIgnoredDuringExecution node affinity excludes the available nodes.",
+		impact:  "The pod stays Pending until a matching node exists.",
+		fix:     "kubectl get nodes --show-labels  # then relax the node affinity or label the target nodes",
+	},
+	{
+		pattern: regexp.MustCompile(`^No node matches the pod's placement constraints`),
+		cause:   "The combination of taints, nodeSelector and affinity rules excludes every node.",
+		impact:  "The pod cannot be scheduled anywhere and stays Pending.",
+		fix:     "kubectl describe pod {{name}} -n {{namespace}}  # review the FailedScheduling event and loosen the constraints",
+	},
+	{
+		pattern: regexp.MustCompile(`node\(s\) satisfy taints, nodeSelector and affinity`),
+		cause:   "The nodes that accept the pod lack the free CPU or memory it requests, or a volume or port conflict blocks it.",
+		impact:  "The pod stays Pending until capacity frees up or the cluster scales out.",
+		fix:     "kubectl describe nodes | grep -A5 'Allocated resources'  # then lower the requests or add capacity",
+	},
+
+	// Deployments and StatefulSets
+	{
+		pattern: regexp.MustCompile(`^Deployment not available`),
+		cause:   "Fewer pods are available than the deployment's minimum availability requires.",
+		impact:  "The service behind the deployment has reduced or no capacity.",
+		fix:     "kubectl describe deployment {{name}} -n {{namespace}}  # then analyze the unavailable pods",
+	},
+	{
+		pattern: regexp.MustCompile(`^Deployment not progressing`),
+		cause:   "The rollout exceeded its progressDeadlineSeconds, usually because new pods fail to start or become ready.",
+		impact:  "The rollout is stalled; old and new versions may be serving side by side.",
+		fix:     "kubectl rollout status deployment/{{name}} -n {{namespace}} && kubectl rollout undo deployment/{{name}} -n {{namespace}}",
+	},
+	{
+		pattern: regexp.MustCompile(`^(Ready|Current) replicas \(\d+\) does not match desired replicas`),
+		cause:   "Some pods are not running or not passing their readiness probes.",
+		impact:  "The workload runs below its intended capacity and has less headroom for failures.",
+		fix:     "kubectl get pods -n {{namespace}} -o wide  # find the unready pods of {{name}} and analyze them",
+	},
+	{
+		pattern: regexp.MustCompile(`ReplicaSets are running pods simultaneously`),
+		cause:   "A rollout is wedged: the new ReplicaSet cannot become ready, or the rollout was paused halfway.",
+		impact:  "Old and new versions serve traffic at the same time, the classic half-old, half-new incident.",
+		fix:     "kubectl rollout resume deployment/{{name}} -n {{namespace}}  # if paused, otherwise: kubectl rollout undo deployment/{{name}} -n {{namespace}}",
+	},
+	{
+		pattern: regexp.MustCompile(`^Old ReplicaSet (\S+) still has \d+ replicas`),
+		cause:   "A previous rollout did not finish scaling down the old ReplicaSet.",
+		impact:  "Pods of an old revision keep running and consuming resources.",
+		fix:     "kubectl rollout status deployment/{{name}} -n {{namespace}}  # check why $1 is not scaled down",
+	},
+	{
+		pattern: regexp.MustCompile(`^Only \d+ of \d+ pods are ready`),
+		cause:   "Some pods are failing readiness probes or have not started.",
+		impact:  "The workload runs with reduced capacity.",
+		fix:     "kubectl get pods -n {{namespace}} -o wide  # find the unready pods of {{name}} and analyze them",
+	},
+
+	// Services
+	{
+		pattern: regexp.MustCompile(`^No pods found matching service selector`),
+		cause:   "The service selector does not match the labels of any pod, often due to a typo or a relabeled deployment.",
+		impact:  "The service has no endpoints, so connections to it fail.",
+		fix:     "kubectl get pods -n {{namespace}} --show-labels  # compare with 'kubectl get svc {{name}} -n {{namespace}} -o wide'",
+	},
+	{
+		pattern: regexp.MustCompile(`^(No endpoints found for service|Service has no active endpoints|No pods are ready to serve traffic)`),
+		cause:   "The selected pods are not ready, or the selector matches no pods.",
+		impact:  "Requests to the service are refused or time out.",
+		fix:     "kubectl get endpointslices -n {{namespace}} -l kubernetes.io/service-name={{name}}",
+	},
+	{
+		pattern: regexp.MustCompile(`^Service port (\S+) targets port name "([^"]+)", which`),
+		cause:   "Service port $1 refers to container port $2 by name, and the pods name that port differently or not at all.",
+		impact:  "Those pods are ready but have no port for the service to forward to, so connections through it are refused.",
+		fix:     `kubectl get pods -n {{namespace}} -o jsonpath='{range .items[*]}{.metadata.name}{": "}{.spec.containers[*].ports[*].name}{"\n"}{end}'  # compare with targetPort $2`,
+	},
+	{
+		pattern: regexp.MustCompile(`^ExternalName (\S+) is an IP address`),
+		cause:   "ExternalName is published as a DNS CNAME record, which cannot point at an IP address.",
+		impact:  "Lookups of the service name fail, so clients cannot connect.",
+		fix:     "Use a ClusterIP service without a selector and a manual EndpointSlice for $1",
+	},
+
+	// Nodes
+	{
+		pattern: regexp.MustCompile(`^Node is not ready`),
+		cause:   "The kubelet stopped reporting, typically due to a crashed kubelet or container runtime, resource exhaustion, or a network partition.",
+		impact:  "Pods on the node are evicted after the toleration timeout and no new pods are scheduled to it.",
+		fix:     "kubectl describe node {{name}}  # then check 'journalctl -u kubelet' on the node",
+	},
+	{
+		pattern: regexp.MustCompile(`^Node is cordoned`),
+		cause:   "The node was cordoned, usually for maintenance or draining.",
+		impact:  "No new pods are scheduled to the node, reducing cluster capacity.",
+		fix:     "kubectl uncordon {{name}}",
+	},
+	{
+		pattern: regexp.MustCompile(`^Node reports (\w+)`),
+		cause:   "The kubelet detected $1 on the node.",
+		impact:  "The kubelet may evict pods and the node is tainted so new pods are not scheduled to it.",
+		fix:     "kubectl describe node {{name}}  # find the pods consuming the resource under pressure",
+	},
+
+	// GitOps
+	{
+		pattern: regexp.MustCompile(`^\S+ \S+ (?:is managed by|differs from the configuration) (Argo CD Application|Flux Kustomization|Flux HelmRelease) (\S+)`),
+		cause:   "The resource is deployed from Git by $1 $2 and was changed in the cluster by hand.",
+		impact:  "The manual change is reverted on the next sync, or keeps the application out of sync until it is.",
+		fix:     "Commit the change to the Git source of $1 $2 and let it sync, or revert the manual edit",
+	},
+
+	// Rollout strategy
+	{
+		pattern: regexp.MustCompile(`^Rolling update maxUnavailable (\S+) (?:lets all|leaves only)`),
+		cause:   "maxUnavailable $1 allows the rollout to take down too many old pods before new ones are ready.",
+		impact:  "The remaining pods take all the traffic during rollouts and may be overloaded, or the service goes down.",
+		fix:     `kubectl patch deployment {{name}} -n {{namespace}} -p '{"spec":{"strategy":{"rollingUpdate":{"maxUnavailable":0,"maxSurge":1}}}}'`,
+	},
+	{
+		pattern: regexp.MustCompile(`^Deployment uses the Recreate strategy while serving users`),
+		cause:   "The Recreate strategy stops every old pod before creating the new ones.",
+		impact:  "The service has no ready endpoints from the moment the rollout starts until the new pods are ready.",
+		fix:     `kubectl patch deployment {{name}} -n {{namespace}} -p '{"spec":{"strategy":{"type":"RollingUpdate","rollingUpdate":null}}}'`,
+	},
+
+	// Secrets
+	{
+		pattern: regexp.MustCompile(`^Secret (\S+) does not exist but is referenced by`),
+		cause:   "Secret $1 was deleted, never created in this namespace, or its name is mistyped in the references.",
+		impact:  "Pods mounting it or reading it into environment variables without optional: true cannot start.",
+		fix:     "kubectl create secret generic $1 -n {{namespace}} --from-literal=<key>=<value>  # or fix the reference",
+	},
+
+	// Policies
+	{
+		pattern: regexp.MustCompile(`^Missing required label\(s\): (.+)`),
+		cause:   "The resource was created without the labels required by the organization's policy.",
+		impact:  "Ownership, cost allocation and policy tooling cannot attribute the resource.",
+		fix:     "kubectl label {{kind}} {{name}} -n {{namespace}} <key>=<value>  # for each of: $1",
+	},
+	{
+		pattern: regexp.MustCompile(`^Missing required annotation\(s\): (.+)`),
+		cause:   "The resource was created without the annotations required by the organization's policy.",
+		impact:  "Ownership and policy tooling cannot attribute the resource.",
+		fix:     "kubectl annotate {{kind}} {{name}} -n {{namespace}} <key>=<value>  # for each of: $1",
+	},
+}
+
+// Explain returns the explanation for an issue reported on a resource, or nil if the
+// issue type is not in the knowledge base
+func Explain(issue, resourceType, name, namespace string) *Explanation {
+	for _, entry := range explanations {
+		match := entry.pattern.FindStringSubmatchIndex(issue)
+		if match == nil {
+			continue
+		}
+
+		fix := string(entry.pattern.ExpandString(nil, entry.fix, issue, match))
+		cause := string(entry.pattern.ExpandString(nil, entry.cause, issue, match))
+		resource := strings.NewReplacer("{{kind}}", resourceType, "{{name}}", name, "{{namespace}}", namespace)
+		return &Explanation{
+			Issue:  issue,
+			Cause:  cause,
+			Impact: entry.impact,
+			Fix:    resource.Replace(fix),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ExtendedResource is an extended resource, such as nvidia.com/gpu, requested by a
+// container. Extended resources cannot be overcommitted, so a request always equals
+// its limit.
+type ExtendedResource struct {
+	Container string
+	Resource  string
+	Request   string
+	Limit     string
+}
+
+// IsExtendedResource reports whether a resource is an extended resource advertised by
+// a device plugin or an operator, like nvidia.com/gpu, rather than CPU, memory,
+// ephemeral storage or hugepages
+func IsExtendedResource(name corev1.ResourceName) bool {
+	return strings.Contains(string(name), "/") && !strings.HasPrefix(string(name), "kubernetes.io/") &&
+		!strings.HasPrefix(string(name), corev1.DefaultResourceRequestsPrefix)
+}
+
+// IsGPUResource reports whether an extended resource is a GPU, e.g. nvidia.com/gpu,
+// amd.com/gpu or gpu.intel.com/i915
+func IsGPUResource(name corev1.ResourceName) bool {
+	return IsExtendedResource(name) && strings.Contains(strings.ToLower(string(name)), "gpu")
+}
+
+// analyzeExtendedResources lists the extended resources requested by containers and
+// flags the ones requested without a limit, which the API server rejects, and GPU
+// containers without a memory limit, whose leaks can get the pod evicted and lose the
+// work of an expensive GPU
+func analyzeExtendedResources(containers []corev1.Container) (resources []ExtendedResource, issues, recommendations []string) {
+	var missingLimits, unboundedGPU bool
+	for _, container := range containers {
+		names := make(map[corev1.ResourceName]bool)
+		for name := range container.Resources.Requests {
+			names[name] = true
+		}
+		for name := range container.Resources.Limits {
+			names[name] = true
+		}
+
+		gpu := false
+		var sorted []string
+		for name := range names {
+			if IsExtendedResource(name) {
+				sorted = append(sorted, string(name))
+			}
+		}
+		sort.Strings(sorted)
+		for _, name := range sorted {
+			resource := ExtendedResource{Container: container.Name, Resource: name}
+			request, hasRequest := container.Resources.Requests[corev1.ResourceName(name)]
+			limit, hasLimit := container.Resources.Limits[corev1.ResourceName(name)]
+			if hasRequest {
+				resource.Request = request.String()
+			}
+			if hasLimit {
+				resource.Limit = limit.String()
+			} else {
+				missingLimits = true
+				issues = append(issues,
+					fmt.Sprintf("Container %s requests %s %s without a limit; extended resources need a limit equal to the request", container.Name, resource.Request, name))
+			}
+			if IsGPUResource(corev1.ResourceName(name)) {
+				gpu = true
+			}
+			resources = append(resources, resource)
+		}
+
+		if _, ok := container.Resources.Limits[corev1.ResourceMemory]; gpu && !ok {
+			unboundedGPU = true
+			issues = append(issues,
+				fmt.Sprintf("GPU container %s has no memory limit; a leak can get the pod evicted and lose its GPU work", container.Name))
+		}
+	}
+
+	if missingLimits {
+		recommendations = append(recommendations,
+			"Set the limit of every extended resource equal to its request, or only set the limit")
+	}
+	if unboundedGPU {
+		recommendations = append(recommendations,
+			"Set memory limits on GPU containers so a leak cannot get the pod holding the GPU evicted")
+	}
+	return resources, issues, recommendations
+}
@@ -0,0 +1,255 @@
+package diagnostics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Finding categories, grouping findings by the kind of problem they describe
+const (
+	CategoryReliability = "reliability"
+	CategorySecurity    = "security"
+	CategoryRBAC        = "rbac"
+	CategoryCost        = "cost"
+)
+
+// Finding is a single problem found by any analyzer, normalized so the results of
+// diagnostics, security, RBAC and optimization analyses can be listed, compared and
+// baselined together
+type Finding struct {
+	// ID identifies the finding across runs, derived from its source, category, resource
+	// and check, so it survives changes to the counts and pod names in the message
+	ID       string `json:"id"`
+	Category string `json:"category"`
+	// Severity is SeverityWarning or SeverityCritical
+	Severity string `json:"severity"`
+	// Resource is the object the finding is about, e.g. "Pod shop/web-1"
+	Resource    string `json:"resource"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"`
+	// Source names the analyzer that produced the finding, e.g. "pod"
+	Source string `json:"source"`
+	// Check is the message with the resource's own name and every word containing a
+	// digit, such as counts and generated pod names, masked. It names the problem
+	// independently of the resource, so the findings of two resources can be compared.
+	Check string `json:"check"`
+}
+
+// NewFinding creates a finding and derives its check and ID
+func NewFinding(source, category, severity, resource, message, remediation string) Finding {
+	check := findingCheck(resource, message)
+	sum := sha256.Sum256([]byte(source + "\x00" + category + "\x00" + resource + "\x00" + check))
+	return Finding{
+		ID:          hex.EncodeToString(sum[:6]),
+		Category:    category,
+		Severity:    severity,
+		Resource:    resource,
+		Message:     message,
+		Remediation: remediation,
+		Source:      source,
+		Check:       check,
+	}
+}
+
+// findingCheck masks the parts of a message that vary between runs and resources: the
+// name of the resource, e.g. "web" in "Deployment shop/web", becomes <name> and words
+// containing a digit become #
+func findingCheck(resource, message string) string {
+	name := resource[strings.LastIndexAny(resource, " /")+1:]
+	words := strings.Fields(message)
+	for i, word := range words {
+		switch {
+		case strings.ContainsAny(word, "0123456789"):
+			words[i] = "#"
+		case name != "" && strings.Trim(word, ".,:;()'\"") == name:
+			words[i] = strings.Replace(word, name, "<name>", 1)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// SeverityForIssueLevel maps the Low/Medium/High/Critical level of an issue to a
+// finding severity. Low issues are still findings, so they map to a warning.
+func SeverityForIssueLevel(level string) string {
+	return MaxSeverity(SeverityWarning, SeverityForRiskLevel(level))
+}
+
+// FindingCollector gathers the findings of several analyzers. It is safe for
+// concurrent use, so analyses running in parallel can share one collector.
+type FindingCollector struct {
+	mu       sync.Mutex
+	findings []Finding
+	seen     map[string]bool
+}
+
+// NewFindingCollector creates an empty FindingCollector
+func NewFindingCollector() *FindingCollector {
+	return &FindingCollector{seen: make(map[string]bool)}
+}
+
+// Add records findings, ignoring any already collected. A nil collector ignores them,
+// so analyzers can emit findings without checking whether anyone collects them.
+// Either way they are counted toward the run summary.
+func (c *FindingCollector) Add(findings ...Finding) {
+	runTally.add(findings)
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, finding := range findings {
+		if c.seen[finding.ID] {
+			continue
+		}
+		c.seen[finding.ID] = true
+		c.findings = append(c.findings, finding)
+	}
+}
+
+// Findings returns the collected findings, most severe first, then by category,
+// resource and message
+func (c *FindingCollector) Findings() []Finding {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	findings := append([]Finding(nil), c.findings...)
+	SortFindings(findings)
+	return findings
+}
+
+// SortFindings orders findings most severe first, then by category, resource and message
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity != b.Severity {
+			return MaxSeverity(a.Severity, b.Severity) == a.Severity
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.Message < b.Message
+	})
+}
+
+// findingTally counts the findings of a whole run by severity. Findings are told
+// apart by resource and message only, so an issue reported both by an analyzer and by
+// the command printing its report is counted once.
+type findingTally struct {
+	mu       sync.Mutex
+	seen     map[string]bool
+	counts   map[string]int
+	recorded bool
+}
+
+// runTally is the tally behind RunSummary
+var runTally = &findingTally{seen: make(map[string]bool), counts: make(map[string]int)}
+
+func (t *findingTally) add(findings []Finding) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.recorded = true
+	for _, finding := range findings {
+		key := strings.ToLower(finding.Resource) + "\x00" + finding.Message
+		if t.seen[key] {
+			continue
+		}
+		t.seen[key] = true
+		t.counts[finding.Severity]++
+	}
+}
+
+// RecordIssues counts the issues of an analysis toward the run summary, for analyses
+// that do not emit findings themselves. Healthy analyses are recorded too, so the
+// summary reports zero findings rather than nothing.
+func RecordIssues(kind, namespace, name, status string, issues []string) {
+	resource := kind + " " + name
+	if namespace != "" {
+		resource = kind + " " + namespace + "/" + name
+	}
+	RecordFindings(issueFindings(strings.ToLower(kind), resource, status, issues))
+}
+
+// RecordFindings counts findings toward the run summary, for analyses that build
+// their findings without a collector. An empty list still records that the analysis
+// ran, so the summary reports zero findings.
+func RecordFindings(findings []Finding) {
+	runTally.add(findings)
+}
+
+// RunSummary returns a machine-readable tally of the findings counted during the run,
+// e.g. "findings: 2 critical, 5 warning", or an empty string when nothing was analyzed.
+// Critical and warning are always listed. Findings of any other severity, such as info,
+// follow in alphabetical order so none go uncounted.
+func RunSummary() string {
+	return runTally.summary()
+}
+
+func (t *findingTally) summary() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.recorded {
+		return ""
+	}
+	summary := fmt.Sprintf("findings: %d critical, %d warning",
+		t.counts[SeverityCritical], t.counts[SeverityWarning])
+
+	var others []string
+	for severity := range t.counts {
+		if severity != SeverityCritical && severity != SeverityWarning {
+			others = append(others, severity)
+		}
+	}
+	sort.Strings(others)
+	for _, severity := range others {
+		summary += fmt.Sprintf(", %d %s", t.counts[severity], strings.ToLower(severity))
+	}
+	return summary
+}
+
+// FindingsSeverity returns the most severe severity among findings, SeverityHealthy
+// when there are none
+func FindingsSeverity(findings []Finding) string {
+	severity := SeverityHealthy
+	for _, finding := range findings {
+		severity = MaxSeverity(severity, finding.Severity)
+	}
+	return severity
+}
+
+// issueFindings turns the issues of an analysis into findings. Analyses report their
+// issues and recommendations as separate lists, so the recommendations are not tied to
+// any one issue and are left out.
+func issueFindings(source, resource, status string, issues []string) []Finding {
+	severity := MaxSeverity(SeverityWarning, SeverityForStatus(status, len(issues)))
+	findings := make([]Finding, 0, len(issues))
+	for _, issue := range issues {
+		findings = append(findings, NewFinding(source, CategoryReliability, severity, resource, issue, ""))
+	}
+	return findings
+}
+
+// Findings returns the issues of the pod analysis as findings
+func (r *PodReport) Findings() []Finding {
+	return issueFindings("pod", "Pod "+r.Namespace+"/"+r.Name, r.Status, r.Issues)
+}
+
+// Findings returns the issues of the deployment analysis as findings
+func (r *DeploymentReport) Findings() []Finding {
+	return issueFindings("deployment", "Deployment "+r.Namespace+"/"+r.Name, r.Analysis.Status, r.Analysis.Issues)
+}
+
+// Findings returns the issues of the replicaset analysis as findings
+func (r *ReplicaSetReport) Findings() []Finding {
+	return issueFindings("replicaset", "ReplicaSet "+r.Namespace+"/"+r.Name, r.Analysis.Status, r.Analysis.Issues)
+}
+
+// Findings returns the issues of the statefulset analysis as findings
+func (r *StatefulSetReport) Findings() []Finding {
+	return issueFindings("statefulset", "StatefulSet "+r.Namespace+"/"+r.Name, r.Analysis.Status, r.Analysis.Issues)
+}
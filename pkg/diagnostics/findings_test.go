@@ -0,0 +1,152 @@
+package diagnostics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindingTallyAdd(t *testing.T) {
+	tests := []struct {
+		name     string
+		batches  [][]Finding
+		critical int
+		warning  int
+	}{
+		{
+			name: "distinct findings are all counted",
+			batches: [][]Finding{{
+				{Severity: SeverityCritical, Resource: "Pod shop/web-1", Message: "CrashLoopBackOff"},
+				{Severity: SeverityWarning, Resource: "Pod shop/web-1", Message: "No memory limit"},
+				{Severity: SeverityWarning, Resource: "Pod shop/web-2", Message: "No memory limit"},
+			}},
+			critical: 1, warning: 2,
+		},
+		{
+			name: "a finding reported twice counts once",
+			batches: [][]Finding{
+				{{Severity: SeverityCritical, Resource: "Pod shop/web-1", Message: "CrashLoopBackOff", Source: "pod"}},
+				{{Severity: SeverityCritical, Resource: "Pod shop/web-1", Message: "CrashLoopBackOff", Source: "analyze"}},
+			},
+			critical: 1,
+		},
+		{
+			name: "resource case does not tell findings apart",
+			batches: [][]Finding{{
+				{Severity: SeverityWarning, Resource: "pod shop/web-1", Message: "No memory limit"},
+				{Severity: SeverityWarning, Resource: "Pod shop/web-1", Message: "No memory limit"},
+			}},
+			warning: 1,
+		},
+		{
+			name: "message case does",
+			batches: [][]Finding{{
+				{Severity: SeverityWarning, Resource: "Pod shop/web-1", Message: "No memory limit"},
+				{Severity: SeverityWarning, Resource: "Pod shop/web-1", Message: "no memory limit"},
+			}},
+			warning: 2,
+		},
+		{name: "nothing found still records the run", batches: [][]Finding{nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tally := &findingTally{seen: make(map[string]bool), counts: make(map[string]int)}
+			for _, batch := range tt.batches {
+				tally.add(batch)
+			}
+			assert.True(t, tally.recorded)
+			assert.Equal(t, tt.critical, tally.counts[SeverityCritical])
+			assert.Equal(t, tt.warning, tally.counts[SeverityWarning])
+		})
+	}
+}
+
+func TestFindingTallySummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings []Finding
+		record   bool
+		want     string
+	}{
+		{name: "nothing analyzed", want: ""},
+		{name: "nothing found", record: true, want: "findings: 0 critical, 0 warning"},
+		{
+			name: "critical and warning",
+			findings: []Finding{
+				{Severity: SeverityCritical, Resource: "Pod shop/web-1", Message: "CrashLoopBackOff"},
+				{Severity: SeverityWarning, Resource: "Pod shop/web-1", Message: "No memory limit"},
+				{Severity: SeverityWarning, Resource: "Pod shop/web-2", Message: "No memory limit"},
+			},
+			record: true,
+			want:   "findings: 1 critical, 2 warning",
+		},
+		{
+			name: "other severities are listed too",
+			findings: []Finding{
+				{Severity: SeverityWarning, Resource: "Pod shop/web-1", Message: "No memory limit"},
+				{Severity: "Info", Resource: "Pod shop/web-1", Message: "Image uses a mutable tag"},
+				{Severity: "Info", Resource: "Pod shop/web-2", Message: "Image uses a mutable tag"},
+				{Severity: "Low", Resource: "Pod shop/web-2", Message: "No readiness probe"},
+			},
+			record: true,
+			want:   "findings: 0 critical, 1 warning, 2 info, 1 low",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tally := &findingTally{seen: make(map[string]bool), counts: make(map[string]int)}
+			if tt.record {
+				tally.add(tt.findings)
+			}
+			assert.Equal(t, tt.want, tally.summary())
+		})
+	}
+}
+
+func TestNewFindingCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		resourceA string
+		messageA  string
+		resourceB string
+		messageB  string
+		sameCheck bool
+		sameID    bool
+	}{
+		{
+			name:      "changed count",
+			resourceA: "Deployment shop/web", messageA: "Only 1/3 replicas are available",
+			resourceB: "Deployment shop/web", messageB: "Only 2/3 replicas are available",
+			sameCheck: true, sameID: true,
+		},
+		{
+			name:      "new pod hash",
+			resourceA: "Deployment shop/web", messageA: "Pod web-6d4b9-x2k8f is in CrashLoopBackOff",
+			resourceB: "Deployment shop/web", messageB: "Pod web-7f5c8-q9z4t is in CrashLoopBackOff",
+			sameCheck: true, sameID: true,
+		},
+		{
+			name:      "same problem on another resource",
+			resourceA: "Deployment shop/web", messageA: "Deployment web has no readiness probe",
+			resourceB: "Deployment shop/api", messageB: "Deployment api has no readiness probe",
+			sameCheck: true, sameID: false,
+		},
+		{
+			name:      "different problem",
+			resourceA: "Pod shop/web-1", messageA: "Container app has no memory limit",
+			resourceB: "Pod shop/web-1", messageB: "Container app has no CPU limit",
+			sameCheck: false, sameID: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewFinding("deployment", CategoryReliability, SeverityWarning, tt.resourceA, tt.messageA, "")
+			b := NewFinding("deployment", CategoryReliability, SeverityWarning, tt.resourceB, tt.messageB, "")
+			assert.Equal(t, tt.sameCheck, a.Check == b.Check, "checks %q and %q", a.Check, b.Check)
+			assert.Equal(t, tt.sameID, a.ID == b.ID)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GitOps tools recognized from the labels and annotations they put on the resources they manage
+const (
+	GitOpsArgoCD = "Argo CD"
+	GitOpsFlux   = "Flux"
+)
+
+const (
+	argoInstanceLabel       = "argocd.argoproj.io/instance"
+	argoTrackingAnnotation  = "argocd.argoproj.io/tracking-id"
+	fluxKustomizationName   = "kustomize.toolkit.fluxcd.io/name"
+	fluxKustomizationNS     = "kustomize.toolkit.fluxcd.io/namespace"
+	fluxHelmReleaseName     = "helm.toolkit.fluxcd.io/name"
+	fluxHelmReleaseNS       = "helm.toolkit.fluxcd.io/namespace"
+	maxReportedDriftedPaths = 5
+)
+
+// gitOpsManagers are the field managers of the GitOps controllers that apply resources
+var gitOpsManagers = map[string]bool{
+	"argocd-controller":             true,
+	"argocd-application-controller": true,
+	"kustomize-controller":          true,
+	"helm-controller":               true,
+}
+
+// GitOpsSource is the GitOps application managing a resource
+type GitOpsSource struct {
+	Tool string
+	// Kind is the GitOps object the resource belongs to: Application, Kustomization or HelmRelease
+	Kind string
+	Name string
+	// Namespace is the namespace of the Flux object, empty for Argo CD applications
+	Namespace string
+	// ManualEditors are the field managers, such as kubectl-edit, that changed the
+	// resource after the GitOps controller last applied it
+	ManualEditors []string
+	// DriftedPaths are the fields whose live value differs from the last applied configuration
+	DriftedPaths []string
+}
+
+// String names the GitOps object, e.g. "Argo CD Application shop" or "Flux Kustomization flux-system/apps"
+func (g *GitOpsSource) String() string {
+	name := g.Name
+	if g.Namespace != "" {
+		name = g.Namespace + "/" + g.Name
+	}
+	return fmt.Sprintf("%s %s %s", g.Tool, g.Kind, name)
+}
+
+// FindGitOpsSource returns the GitOps application managing a resource, from the Argo CD
+// and Flux labels and annotations on it, or nil when it is not managed by either
+func FindGitOpsSource(object metav1.Object) *GitOpsSource {
+	labels := object.GetLabels()
+	annotations := object.GetAnnotations()
+
+	if tracking := annotations[argoTrackingAnnotation]; tracking != "" {
+		// The tracking id has the form <application>:<group>/<kind>:<namespace>/<name>
+		application, _, _ := strings.Cut(tracking, ":")
+		return &GitOpsSource{Tool: GitOpsArgoCD, Kind: "Application", Name: application}
+	}
+	if application := labels[argoInstanceLabel]; application != "" {
+		return &GitOpsSource{Tool: GitOpsArgoCD, Kind: "Application", Name: application}
+	}
+	if name := labels[fluxKustomizationName]; name != "" {
+		return &GitOpsSource{Tool: GitOpsFlux, Kind: "Kustomization", Name: name, Namespace: labels[fluxKustomizationNS]}
+	}
+	if name := labels[fluxHelmReleaseName]; name != "" {
+		return &GitOpsSource{Tool: GitOpsFlux, Kind: "HelmRelease", Name: name, Namespace: labels[fluxHelmReleaseNS]}
+	}
+	return nil
+}
+
+// analyzeGitOps reports the GitOps application managing a resource and whether it was
+// changed by hand since the GitOps controller applied it: edits by kubectl recorded in
+// the managed fields after the controller's, and spec fields whose live value differs
+// from the last applied configuration. ignoredPaths are spec fields other controllers
+// legitimately change, such as spec.replicas under an autoscaler.
+func analyzeGitOps(object metav1.Object, kind string, ignoredPaths ...string) (*GitOpsSource, []string, []string) {
+	source := FindGitOpsSource(object)
+	if source == nil {
+		return nil, nil, nil
+	}
+	source.ManualEditors = manualEditors(object)
+	source.DriftedPaths = lastAppliedDrift(object, ignoredPaths)
+
+	var issues, recommendations []string
+	name := kind + " " + object.GetName()
+	if len(source.ManualEditors) > 0 {
+		issues = append(issues, fmt.Sprintf("%s is managed by %s but was edited by %s since it was last synced",
+			name, source, strings.Join(source.ManualEditors, ", ")))
+	}
+	if len(source.DriftedPaths) > 0 {
+		paths := source.DriftedPaths
+		more := ""
+		if len(paths) > maxReportedDriftedPaths {
+			more = fmt.Sprintf(" and %d more", len(paths)-maxReportedDriftedPaths)
+			paths = paths[:maxReportedDriftedPaths]
+		}
+		issues = append(issues, fmt.Sprintf("%s differs from the configuration %s last applied at %s%s",
+			name, source, strings.Join(paths, ", "), more))
+	}
+	if len(issues) > 0 {
+		recommendations = append(recommendations,
+			fmt.Sprintf("Make the change in Git instead of by hand: %s reverts manual changes on its next sync", source))
+	}
+	return source, issues, recommendations
+}
+
+// manualEditors returns the kubectl field managers that updated the resource after the
+// last update by a GitOps controller. Nothing is reported when no GitOps controller
+// appears in the managed fields, since the order of the edits is then unknown.
+func manualEditors(object metav1.Object) []string {
+	var synced *metav1.Time
+	for _, field := range object.GetManagedFields() {
+		if gitOpsManagers[field.Manager] && field.Time != nil && (synced == nil || field.Time.After(synced.Time)) {
+			synced = field.Time
+		}
+	}
+	if synced == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var editors []string
+	for _, field := range object.GetManagedFields() {
+		if !strings.HasPrefix(field.Manager, "kubectl") || field.Subresource == "status" {
+			continue
+		}
+		if field.Time != nil && field.Time.After(synced.Time) && !seen[field.Manager] {
+			seen[field.Manager] = true
+			editors = append(editors, field.Manager)
+		}
+	}
+	sort.Strings(editors)
+	return editors
+}
+
+// lastAppliedDrift compares the spec of the last applied configuration with the live
+// object and returns the paths whose value changed. Only fields present in the applied
+// configuration are compared, so defaults filled in by the API server are not drift.
+func lastAppliedDrift(object metav1.Object, ignoredPaths []string) []string {
+	applied := object.GetAnnotations()[lastAppliedAnnotation]
+	if applied == "" {
+		return nil
+	}
+	var appliedObject map[string]interface{}
+	if err := json.Unmarshal([]byte(applied), &appliedObject); err != nil {
+		return nil
+	}
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil
+	}
+	var liveObject map[string]interface{}
+	if err := json.Unmarshal(data, &liveObject); err != nil {
+		return nil
+	}
+
+	ignored := make(map[string]bool, len(ignoredPaths))
+	for _, path := range ignoredPaths {
+		ignored[path] = true
+	}
+	var drifted []string
+	compareApplied("spec", appliedObject["spec"], liveObject["spec"], ignored, &drifted)
+	sort.Strings(drifted)
+	return drifted
+}
+
+func compareApplied(path string, applied, live interface{}, ignored map[string]bool, drifted *[]string) {
+	if applied == nil || ignored[path] {
+		return
+	}
+	switch appliedValue := applied.(type) {
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			*drifted = append(*drifted, path)
+			return
+		}
+		for key, value := range appliedValue {
+			compareApplied(path+"."+key, value, liveValue[key], ignored, drifted)
+		}
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok || len(liveValue) != len(appliedValue) {
+			*drifted = append(*drifted, path)
+			return
+		}
+		for i, value := range appliedValue {
+			compareApplied(fmt.Sprintf("%s[%d]", path, i), value, liveValue[i], ignored, drifted)
+		}
+	default:
+		if !equalAppliedValue(appliedValue, live) {
+			*drifted = append(*drifted, path)
+		}
+	}
+}
+
+// equalAppliedValue compares leaf values, treating quantities written differently, such
+// as 0.5 and 500m, as equal
+func equalAppliedValue(applied, live interface{}) bool {
+	if reflect.DeepEqual(applied, live) {
+		return true
+	}
+	appliedQuantity, err := resource.ParseQuantity(fmt.Sprint(applied))
+	if err != nil {
+		return false
+	}
+	liveQuantity, err := resource.ParseQuantity(fmt.Sprint(live))
+	if err != nil {
+		return false
+	}
+	return appliedQuantity.Cmp(liveQuantity) == 0
+}
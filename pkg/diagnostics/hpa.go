@@ -0,0 +1,140 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Autoscaler is the HorizontalPodAutoscaler that targets a workload
+type Autoscaler struct {
+	Name        string
+	MinReplicas int32
+	MaxReplicas int32
+	// Metrics describes each metric the autoscaler scales on, e.g. "cpu 80% utilization"
+	Metrics []string
+}
+
+// analyzeAutoscaling finds the HPA targeting the deployment and checks that the pod
+// template requests every resource the HPA scales on by utilization. Utilization is
+// usage as a share of the request, so without requests the HPA cannot compute it and
+// never scales, reporting only a FailedGetResourceMetric event.
+func (d *DeploymentAnalyzer) analyzeAutoscaling(ctx context.Context, report *DeploymentReport) {
+	// Not every user may list HPAs; the rest of the analysis stands without them
+	hpas, err := d.client.AutoscalingV2().HorizontalPodAutoscalers(d.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return
+	}
+
+	for i := range hpas.Items {
+		hpa := &hpas.Items[i]
+		target := hpa.Spec.ScaleTargetRef
+		if target.Kind != "Deployment" || target.Name != report.Name {
+			continue
+		}
+
+		report.Autoscaler = describeAutoscaler(hpa)
+		issues, recommendations := checkAutoscalerRequests(hpa, report.PodTemplate.Spec)
+		report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+		return
+	}
+}
+
+func describeAutoscaler(hpa *autoscalingv2.HorizontalPodAutoscaler) *Autoscaler {
+	autoscaler := &Autoscaler{
+		Name:        hpa.Name,
+		MinReplicas: 1,
+		MaxReplicas: hpa.Spec.MaxReplicas,
+	}
+	if hpa.Spec.MinReplicas != nil {
+		autoscaler.MinReplicas = *hpa.Spec.MinReplicas
+	}
+
+	for _, metric := range hpa.Spec.Metrics {
+		switch metric.Type {
+		case autoscalingv2.ResourceMetricSourceType:
+			if metric.Resource != nil {
+				autoscaler.Metrics = append(autoscaler.Metrics,
+					fmt.Sprintf("%s %s", metric.Resource.Name, describeMetricTarget(metric.Resource.Target)))
+			}
+		case autoscalingv2.ContainerResourceMetricSourceType:
+			if metric.ContainerResource != nil {
+				autoscaler.Metrics = append(autoscaler.Metrics,
+					fmt.Sprintf("%s of container %s %s", metric.ContainerResource.Name,
+						metric.ContainerResource.Container, describeMetricTarget(metric.ContainerResource.Target)))
+			}
+		default:
+			autoscaler.Metrics = append(autoscaler.Metrics, strings.ToLower(string(metric.Type))+" metric")
+		}
+	}
+	return autoscaler
+}
+
+func describeMetricTarget(target autoscalingv2.MetricTarget) string {
+	switch {
+	case target.AverageUtilization != nil:
+		return fmt.Sprintf("%d%% utilization", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		return fmt.Sprintf("average %s", target.AverageValue.String())
+	case target.Value != nil:
+		return target.Value.String()
+	}
+	return ""
+}
+
+// checkAutoscalerRequests reports the containers missing a request for a resource the
+// HPA scales on by utilization. A Resource metric averages over all containers of the
+// pod, so a single container without the request breaks it; a ContainerResource metric
+// only needs the request on the named container.
+func checkAutoscalerRequests(hpa *autoscalingv2.HorizontalPodAutoscaler, spec corev1.PodSpec) ([]string, []string) {
+	var issues, recommendations []string
+
+	for _, metric := range hpa.Spec.Metrics {
+		var resource corev1.ResourceName
+		var containers []corev1.Container
+		switch {
+		case metric.Type == autoscalingv2.ResourceMetricSourceType && metric.Resource != nil:
+			if metric.Resource.Target.Type != autoscalingv2.UtilizationMetricType {
+				continue
+			}
+			resource = metric.Resource.Name
+			containers = spec.Containers
+		case metric.Type == autoscalingv2.ContainerResourceMetricSourceType && metric.ContainerResource != nil:
+			if metric.ContainerResource.Target.Type != autoscalingv2.UtilizationMetricType {
+				continue
+			}
+			resource = metric.ContainerResource.Name
+			for _, container := range spec.Containers {
+				if container.Name == metric.ContainerResource.Container {
+					containers = append(containers, container)
+				}
+			}
+		default:
+			continue
+		}
+
+		var missing []string
+		for _, container := range containers {
+			if _, ok := container.Resources.Requests[resource]; !ok {
+				missing = append(missing, container.Name)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		issues = append(issues,
+			fmt.Sprintf("HPA %s scales on %s utilization, but container(s) %s set no %s request; utilization is measured against requests, so the HPA cannot compute it and will never scale",
+				hpa.Name, resource, strings.Join(missing, ", "), resource))
+		recommendations = append(recommendations,
+			fmt.Sprintf("Set %s requests on container(s) %s so HPA %s can scale the deployment, or target an average value instead of utilization",
+				resource, strings.Join(missing, ", "), hpa.Name))
+	}
+
+	return issues, recommendations
+}
@@ -0,0 +1,58 @@
+package diagnostics
+
+import "strings"
+
+// IgnoreAnnotation lists the finding types to suppress for an object, comma separated,
+// e.g. k8s-lens.io/ignore: "WritableRootFilesystem,LoadBalancerService". It records
+// risks a team has accepted so scans stop reporting them on every run.
+const IgnoreAnnotation = "k8s-lens.io/ignore"
+
+// IgnoredFindings returns the finding types suppressed by the ignore annotation of an
+// object, lowercased so they match regardless of case
+func IgnoredFindings(annotations map[string]string) map[string]bool {
+	value := annotations[IgnoreAnnotation]
+	if value == "" {
+		return nil
+	}
+	ignored := make(map[string]bool)
+	for _, findingType := range strings.Split(value, ",") {
+		if findingType = strings.TrimSpace(findingType); findingType != "" {
+			ignored[strings.ToLower(findingType)] = true
+		}
+	}
+	return ignored
+}
+
+// IsIgnored reports whether a finding type is in a set returned by IgnoredFindings
+func IsIgnored(ignored map[string]bool, findingType string) bool {
+	return ignored[strings.ToLower(findingType)]
+}
+
+// suppressIgnored drops the issues and warnings whose type is listed in an object's
+// ignore annotation, counting them in Suppressed
+func (r *SecurityReport) suppressIgnored(annotations map[string]string) {
+	ignored := IgnoredFindings(annotations)
+	if len(ignored) == 0 {
+		return
+	}
+
+	issues := r.Issues[:0]
+	for _, issue := range r.Issues {
+		if IsIgnored(ignored, issue.Type) {
+			r.Suppressed++
+			continue
+		}
+		issues = append(issues, issue)
+	}
+	r.Issues = issues
+
+	warnings := r.Warnings[:0]
+	for _, warning := range r.Warnings {
+		if IsIgnored(ignored, warning.Type) {
+			r.Suppressed++
+			continue
+		}
+		warnings = append(warnings, warning)
+	}
+	r.Warnings = warnings
+}
@@ -0,0 +1,335 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Stages of the request path through an ingress
+const (
+	PathStageIngress   = "Ingress"
+	PathStageService   = "Service"
+	PathStagePort      = "Port"
+	PathStageEndpoints = "Endpoints"
+	PathStagePods      = "Pods"
+)
+
+// brokenStageRecommendations tell how to repair a path broken at each stage
+var brokenStageRecommendations = map[string]string{
+	PathStageIngress:   "Point the ingress rule at a backend service",
+	PathStageService:   "Create the backend service, or fix the service name in the ingress rule",
+	PathStagePort:      "Match the ingress backend port to a service port, and the service targetPort to a container port",
+	PathStageEndpoints: "Fix the service selector so it matches the labels of the application pods",
+	PathStagePods:      "Check why the pods are not ready with 'k8s-lens analyze pod <name>'",
+}
+
+// IngressPathTracer traces requests from an Ingress rule to the pods serving it:
+// Ingress rule, backend Service, its port, its endpoints and the ready pods behind
+// them, reporting the first stage where the chain breaks
+type IngressPathTracer struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// NewIngressPathTracer creates a new IngressPathTracer
+func NewIngressPathTracer(client kubernetes.Interface, namespace string) *IngressPathTracer {
+	return &IngressPathTracer{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+// IngressPathReport contains the traced paths of every backend of an Ingress
+type IngressPathReport struct {
+	Name         string
+	Namespace    string
+	IngressClass string
+	// Address is the address the ingress controller published for the ingress
+	Address  string
+	Paths    []BackendPath
+	Analysis IngressPathAnalysis
+}
+
+// IngressPathAnalysis contains diagnostic results
+type IngressPathAnalysis struct {
+	Status          string
+	Issues          []string
+	Recommendations []string
+}
+
+// BackendPath is the traced path of one Ingress rule to its backend
+type BackendPath struct {
+	// Rule describes the matched requests, e.g. "shop.example.com/api" or "default backend"
+	Rule    string
+	Service string
+	Port    string
+	Steps   []PathStep
+	// Warnings are doubts about a stage that do not stop requests
+	Warnings []string
+	// BrokenAt is the stage where requests stop, empty when they reach ready pods
+	BrokenAt string
+}
+
+// PathStep is one stage of a backend path
+type PathStep struct {
+	Stage  string
+	OK     bool
+	Detail string
+}
+
+// Trace traces every backend of an Ingress down to its ready pods
+func (t *IngressPathTracer) Trace(ctx context.Context, ingressName string) (*IngressPathReport, error) {
+	ingress, err := t.client.NetworkingV1().Ingresses(t.namespace).Get(ctx, ingressName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingress %s: %v", ingressName, err)
+	}
+
+	report := &IngressPathReport{
+		Name:      ingress.Name,
+		Namespace: ingress.Namespace,
+	}
+	if ingress.Spec.IngressClassName != nil {
+		report.IngressClass = *ingress.Spec.IngressClassName
+	}
+	if lbs := ingress.Status.LoadBalancer.Ingress; len(lbs) > 0 {
+		report.Address = lbs[0].IP
+		if report.Address == "" {
+			report.Address = lbs[0].Hostname
+		}
+	}
+
+	t.checkIngressClass(ctx, report)
+	if report.Address == "" {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			"Ingress has no address; the ingress controller has not picked it up, so no traffic reaches it")
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Check that an ingress controller handling this ingress class is running and look at its logs")
+	}
+
+	if backend := ingress.Spec.DefaultBackend; backend != nil {
+		report.Paths = append(report.Paths, t.traceBackend(ctx, "default backend", backend))
+	}
+	for _, rule := range ingress.Spec.Rules {
+		host := rule.Host
+		if host == "" {
+			host = "*"
+		}
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			report.Paths = append(report.Paths, t.traceBackend(ctx, host+path.Path, &path.Backend))
+		}
+	}
+
+	issues, recommendations := analyzeDeprecatedAPIs(ctx, t.client, ingress, "Ingress")
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+
+	if len(report.Paths) == 0 {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			"Ingress has no rules or default backend, so it routes nothing")
+	}
+	broken := 0
+	recommended := make(map[string]bool)
+	for _, path := range report.Paths {
+		if path.BrokenAt != "" {
+			broken++
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				fmt.Sprintf("%s: path breaks at %s: %s", path.Rule, path.BrokenAt, path.Steps[len(path.Steps)-1].Detail))
+			if !recommended[path.BrokenAt] {
+				recommended[path.BrokenAt] = true
+				report.Analysis.Recommendations = append(report.Analysis.Recommendations, brokenStageRecommendations[path.BrokenAt])
+			}
+		}
+		for _, warning := range path.Warnings {
+			report.Analysis.Issues = append(report.Analysis.Issues, fmt.Sprintf("%s: %s", path.Rule, warning))
+		}
+	}
+
+	switch {
+	case broken > 0 && broken == len(report.Paths):
+		report.Analysis.Status = "Unhealthy"
+	case len(report.Analysis.Issues) > 0:
+		report.Analysis.Status = "Degraded"
+	default:
+		report.Analysis.Status = "Healthy"
+	}
+	return report, nil
+}
+
+// checkIngressClass reports an ingress class that does not exist, since no controller
+// will then serve the ingress
+func (t *IngressPathTracer) checkIngressClass(ctx context.Context, report *IngressPathReport) {
+	if report.IngressClass == "" {
+		return
+	}
+	_, err := t.client.NetworkingV1().IngressClasses().Get(ctx, report.IngressClass, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("IngressClass %s does not exist, so no ingress controller serves this ingress", report.IngressClass))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Set spec.ingressClassName to a class listed by 'kubectl get ingressclass'")
+	}
+}
+
+// traceBackend follows one backend through its service, port, endpoints and pods,
+// stopping at the first broken stage
+func (t *IngressPathTracer) traceBackend(ctx context.Context, rule string, backend *networkingv1.IngressBackend) BackendPath {
+	path := BackendPath{Rule: rule}
+	fail := func(stage, detail string) BackendPath {
+		path.Steps = append(path.Steps, PathStep{Stage: stage, Detail: detail})
+		path.BrokenAt = stage
+		return path
+	}
+	pass := func(stage, detail string) {
+		path.Steps = append(path.Steps, PathStep{Stage: stage, OK: true, Detail: detail})
+	}
+
+	if backend.Service == nil {
+		if backend.Resource != nil {
+			pass(PathStageIngress, fmt.Sprintf("routes to %s %s, which is not traced", backend.Resource.Kind, backend.Resource.Name))
+			return path
+		}
+		return fail(PathStageIngress, "rule has no backend service")
+	}
+	path.Service = backend.Service.Name
+	if backend.Service.Port.Name != "" {
+		path.Port = backend.Service.Port.Name
+	} else {
+		path.Port = strconv.Itoa(int(backend.Service.Port.Number))
+	}
+	pass(PathStageIngress, fmt.Sprintf("routes to service %s port %s", path.Service, path.Port))
+
+	service, err := t.client.CoreV1().Services(t.namespace).Get(ctx, path.Service, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return fail(PathStageService, fmt.Sprintf("service %s does not exist", path.Service))
+	} else if err != nil {
+		return fail(PathStageService, fmt.Sprintf("failed to get service %s: %v", path.Service, err))
+	}
+	if service.Spec.Type == corev1.ServiceTypeExternalName {
+		pass(PathStageService, fmt.Sprintf("ExternalName service forwarding to %s, which is not traced", service.Spec.ExternalName))
+		return path
+	}
+	if len(service.Spec.Selector) == 0 {
+		pass(PathStageService, fmt.Sprintf("%s service without a selector; its endpoints are managed manually", service.Spec.Type))
+	} else {
+		pass(PathStageService, fmt.Sprintf("%s service selecting %s", service.Spec.Type,
+			metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: service.Spec.Selector})))
+	}
+
+	servicePort := findServicePort(service, backend.Service.Port)
+	if servicePort == nil {
+		return fail(PathStagePort, fmt.Sprintf("service %s has no port %s", path.Service, path.Port))
+	}
+	targetPort := servicePort.TargetPort
+	if targetPort.Type == intstr.Int && targetPort.IntVal == 0 {
+		targetPort = intstr.FromInt32(servicePort.Port)
+	}
+	pass(PathStagePort, fmt.Sprintf("port %d forwards to target port %s", servicePort.Port, targetPort.String()))
+
+	endpoints := NewEndpointAnalyzer(t.client, t.namespace)
+	endpointReport, err := endpoints.ValidateEndpoints(ctx, path.Service)
+	if err != nil {
+		return fail(PathStageEndpoints, err.Error())
+	}
+
+	if len(service.Spec.Selector) > 0 {
+		if mismatch, broken := targetPortMismatch(endpointReport.Pods, targetPort); broken {
+			return fail(PathStagePort, mismatch)
+		} else if mismatch != "" {
+			path.Warnings = append(path.Warnings, mismatch)
+		}
+	}
+
+	ready := readyEndpointAddresses(endpointReport)
+	if ready == 0 {
+		if len(service.Spec.Selector) > 0 && endpointReport.Analysis.TotalPods == 0 {
+			return fail(PathStageEndpoints, "no pods match the service selector")
+		}
+		if endpointReport.Analysis.TotalPods > 0 {
+			return fail(PathStagePods, fmt.Sprintf("none of the %d pods matching the selector are ready; requests get 503",
+				endpointReport.Analysis.TotalPods))
+		}
+		return fail(PathStageEndpoints, "service has no ready endpoints; requests get 503")
+	}
+	pass(PathStageEndpoints, fmt.Sprintf("%d ready address(es)", ready))
+
+	if total := endpointReport.Analysis.TotalPods; total > 0 {
+		detail := fmt.Sprintf("%d/%d pods ready", endpointReport.Analysis.ReadyPods, total)
+		if endpointReport.Analysis.ReadyPods < total {
+			detail += "; the unready pods receive no traffic"
+		}
+		pass(PathStagePods, detail)
+	}
+	return path
+}
+
+// findServicePort returns the service port an ingress backend refers to by name or number
+func findServicePort(service *corev1.Service, port networkingv1.ServiceBackendPort) *corev1.ServicePort {
+	for i := range service.Spec.Ports {
+		servicePort := &service.Spec.Ports[i]
+		if port.Name != "" && servicePort.Name == port.Name {
+			return servicePort
+		}
+		if port.Name == "" && servicePort.Port == port.Number {
+			return servicePort
+		}
+	}
+	return nil
+}
+
+// targetPortMismatch describes a target port that none of the selected pods expose,
+// and whether that breaks the path. A named target port must match a container port
+// name; a numbered one that no container declares may still be listened on.
+func targetPortMismatch(pods []corev1.Pod, targetPort intstr.IntOrString) (string, bool) {
+	if len(pods) == 0 {
+		return "", false
+	}
+
+	declared := false
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			for _, port := range container.Ports {
+				declared = true
+				if targetPort.Type == intstr.String && port.Name == targetPort.StrVal {
+					return "", false
+				}
+				if targetPort.Type == intstr.Int && port.ContainerPort == targetPort.IntVal {
+					return "", false
+				}
+			}
+		}
+	}
+
+	if targetPort.Type == intstr.String {
+		return fmt.Sprintf("target port %s does not name a container port of the selected pods, so no endpoints are created for it", targetPort.StrVal), true
+	}
+	if declared {
+		return fmt.Sprintf("target port %d is not a container port of the selected pods; check the application listens on it", targetPort.IntVal), false
+	}
+	return "", false
+}
+
+// readyEndpointAddresses returns the ready addresses of a service, preferring
+// EndpointSlices, which are authoritative on newer clusters
+func readyEndpointAddresses(report *EndpointReport) int {
+	if len(report.Slices) > 0 {
+		return report.Analysis.SliceAddresses
+	}
+	ready := 0
+	if report.Endpoints != nil {
+		for _, subset := range report.Endpoints.Subsets {
+			ready += len(subset.Addresses)
+		}
+	}
+	return ready
+}
@@ -0,0 +1,271 @@
+package diagnostics
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// ManifestReport contains the offline analysis of a workload read from a manifest. Only
+// the spec is analyzed, since a manifest has no status, events or pods.
+type ManifestReport struct {
+	Kind              string
+	Name              string
+	Namespace         string
+	Probes            []ProbeInfo
+	ExtendedResources []ExtendedResource
+	Analysis          ManifestAnalysis
+}
+
+// ManifestAnalysis contains diagnostic results
+type ManifestAnalysis struct {
+	Status          string
+	Issues          []string
+	Recommendations []string
+}
+
+// ReadManifests decodes every object of a YAML or JSON manifest stream, including
+// multi-document YAML and the items of List objects, into typed objects. Documents of
+// kinds client-go does not know, like custom resources, are skipped.
+func ReadManifests(input io.Reader) ([]runtime.Object, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bufio.NewReader(input), 4096)
+	deserializer := scheme.Codecs.UniversalDeserializer()
+
+	var objects []runtime.Object
+	for document := 1; ; document++ {
+		var raw runtime.RawExtension
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, fmt.Errorf("failed to parse document %d: %v", document, err)
+		}
+		if len(bytes.TrimSpace(raw.Raw)) == 0 || bytes.Equal(bytes.TrimSpace(raw.Raw), []byte("null")) {
+			continue
+		}
+
+		object, _, err := deserializer.Decode(raw.Raw, nil, nil)
+		if runtime.IsNotRegisteredError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode document %d: %v", document, err)
+		}
+
+		if list, ok := object.(*corev1.List); ok {
+			for _, item := range list.Items {
+				itemObject, _, err := deserializer.Decode(item.Raw, nil, nil)
+				if runtime.IsNotRegisteredError(err) {
+					continue
+				}
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode list item in document %d: %v", document, err)
+				}
+				objects = append(objects, itemObject)
+			}
+			continue
+		}
+		objects = append(objects, object)
+	}
+}
+
+// ManifestPodSpec returns the kind, metadata and pod spec of a workload object, and
+// false for objects that do not run pods
+func ManifestPodSpec(object runtime.Object) (string, metav1.Object, *corev1.PodSpec, bool) {
+	switch o := object.(type) {
+	case *corev1.Pod:
+		return "Pod", o, &o.Spec, true
+	case *appsv1.Deployment:
+		return "Deployment", o, &o.Spec.Template.Spec, true
+	case *appsv1.StatefulSet:
+		return "StatefulSet", o, &o.Spec.Template.Spec, true
+	case *appsv1.DaemonSet:
+		return "DaemonSet", o, &o.Spec.Template.Spec, true
+	case *appsv1.ReplicaSet:
+		return "ReplicaSet", o, &o.Spec.Template.Spec, true
+	case *batchv1.Job:
+		return "Job", o, &o.Spec.Template.Spec, true
+	case *batchv1.CronJob:
+		return "CronJob", o, &o.Spec.JobTemplate.Spec.Template.Spec, true
+	}
+	return "", nil, nil, false
+}
+
+// AnalyzeManifest runs the spec-based checks of the analyzers on a workload from a
+// manifest: resource requests and limits, their ratios, the security context and the
+// probes. It returns false for objects that do not run pods.
+func AnalyzeManifest(object runtime.Object) (*ManifestReport, bool) {
+	kind, meta, spec, ok := ManifestPodSpec(object)
+	if !ok {
+		return nil, false
+	}
+
+	report := &ManifestReport{
+		Kind:      kind,
+		Name:      meta.GetName(),
+		Namespace: meta.GetNamespace(),
+	}
+	analysis := &report.Analysis
+
+	missingResources := false
+	for _, container := range spec.Containers {
+		var missing []string
+		for _, resource := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			if _, ok := container.Resources.Requests[resource]; !ok {
+				missing = append(missing, string(resource)+" request")
+			}
+		}
+		if _, ok := container.Resources.Limits[corev1.ResourceMemory]; !ok {
+			missing = append(missing, "memory limit")
+		}
+		if len(missing) > 0 {
+			missingResources = true
+			analysis.Issues = append(analysis.Issues,
+				fmt.Sprintf("Container %s has no %s", container.Name, strings.Join(missing, ", ")))
+		}
+	}
+	if missingResources {
+		analysis.Recommendations = append(analysis.Recommendations,
+			"Set CPU and memory requests and a memory limit on every container, so pods are scheduled on nodes that can run them and cannot exhaust node memory")
+	}
+
+	issues, recommendations := analyzeResourceRatios(spec.Containers)
+	analysis.Issues = append(analysis.Issues, issues...)
+	analysis.Recommendations = append(analysis.Recommendations, recommendations...)
+
+	probes, issues, recommendations := analyzeProbes(spec.Containers)
+	report.Probes = probes
+	analysis.Issues = append(analysis.Issues, issues...)
+	analysis.Recommendations = append(analysis.Recommendations, recommendations...)
+
+	extended, issues, recommendations := analyzeExtendedResources(spec.Containers)
+	report.ExtendedResources = extended
+	analysis.Issues = append(analysis.Issues, issues...)
+	analysis.Recommendations = append(analysis.Recommendations, recommendations...)
+
+	critical := analyzeManifestSecurity(report, meta, spec)
+
+	switch {
+	case critical:
+		analysis.Status = "Critical"
+	case len(analysis.Issues) > 0:
+		analysis.Status = "Needs Attention"
+	default:
+		analysis.Status = "Healthy"
+	}
+	return report, true
+}
+
+// manifestSecurityIssues runs the security scanner's pod and container checks on a
+// pod spec from a manifest, leaving out the types listed in the workload's ignore
+// annotation, and returns the issues with the number suppressed
+func manifestSecurityIssues(meta metav1.Object, spec *corev1.PodSpec) ([]SecurityIssue, int) {
+	security := &SecurityReport{}
+	pod := &corev1.Pod{Spec: *spec}
+	analyzer := &SecurityAnalyzer{}
+	analyzer.analyzeSecurityContext(security, pod)
+	analyzer.analyzeContainerSecurity(security, pod)
+	security.Warnings = nil
+	security.suppressIgnored(meta.GetAnnotations())
+	return security.Issues, security.Suppressed
+}
+
+// analyzeManifestSecurity adds the security issues of a pod spec, and reports whether
+// any of them is critical
+func analyzeManifestSecurity(report *ManifestReport, meta metav1.Object, spec *corev1.PodSpec) bool {
+	critical := false
+	remediations := make(map[string]bool)
+	issues, _ := manifestSecurityIssues(meta, spec)
+	for _, issue := range issues {
+		if issue.Level == "Critical" {
+			critical = true
+		}
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Security (%s): %s: %s", issue.Level, issue.Title, issue.Description))
+		if !remediations[issue.Remediation] {
+			remediations[issue.Remediation] = true
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations, issue.Remediation)
+		}
+	}
+	return critical
+}
+
+// Policies a manifest is validated against
+const (
+	PolicySecurity         = "security"
+	PolicyRequiredMetadata = "required-metadata"
+)
+
+// ManifestValidation is the policy verdict for one object of a manifest
+type ManifestValidation struct {
+	Kind       string
+	Name       string
+	Namespace  string
+	Violations []PolicyViolation
+	// Severity is the most severe violation, Healthy when the object passes
+	Severity string
+	// Suppressed counts the security findings left out by the object's ignore annotation
+	Suppressed int
+}
+
+// PolicyViolation is one policy an object of a manifest breaks
+type PolicyViolation struct {
+	Policy      string
+	Severity    string
+	Message     string
+	Remediation string
+}
+
+// ValidateManifest checks an object of a manifest against the policies an admission
+// webhook would enforce: the security scanner's checks on the pods it runs, which
+// fail as critical for High and Critical issues, and the required labels and
+// annotations, which fail as warnings
+func ValidateManifest(object runtime.Object, requiredLabels, requiredAnnotations []string) *ManifestValidation {
+	validation := &ManifestValidation{
+		Kind:     object.GetObjectKind().GroupVersionKind().Kind,
+		Severity: SeverityHealthy,
+	}
+	add := func(violation PolicyViolation) {
+		validation.Violations = append(validation.Violations, violation)
+		validation.Severity = MaxSeverity(validation.Severity, violation.Severity)
+	}
+
+	if kind, meta, spec, ok := ManifestPodSpec(object); ok {
+		validation.Kind = kind
+		issues, suppressed := manifestSecurityIssues(meta, spec)
+		validation.Suppressed = suppressed
+		for _, issue := range issues {
+			add(PolicyViolation{
+				Policy:      PolicySecurity,
+				Severity:    SeverityForRiskLevel(issue.Level),
+				Message:     fmt.Sprintf("%s (%s): %s", issue.Title, issue.Level, issue.Description),
+				Remediation: issue.Remediation,
+			})
+		}
+	}
+
+	if meta, ok := object.(metav1.Object); ok {
+		validation.Name, validation.Namespace = meta.GetName(), meta.GetNamespace()
+		issues, recommendations := CheckRequiredMetadata(strings.ToLower(validation.Kind), meta, requiredLabels, requiredAnnotations)
+		for i, issue := range issues {
+			add(PolicyViolation{
+				Policy:      PolicyRequiredMetadata,
+				Severity:    SeverityWarning,
+				Message:     issue,
+				Remediation: recommendations[i],
+			})
+		}
+	}
+	return validation
+}
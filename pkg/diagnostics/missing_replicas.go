@@ -0,0 +1,142 @@
+package diagnostics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// unavailableReasonRecommendations tell how to look into pods not ready for each root
+// reason; %s is replaced with the name of one such pod
+var unavailableReasonRecommendations = map[string]string{
+	"Pending":                    "Pending pods cannot be scheduled; see why with 'k8s-lens analyze pod %s'",
+	"ImagePullBackOff":           "Check the image name, tag and pull secrets; see 'k8s-lens analyze pod %s'",
+	"ErrImagePull":               "Check the image name, tag and pull secrets; see 'k8s-lens analyze pod %s'",
+	"CrashLoopBackOff":           "Containers keep crashing; check the logs with 'kubectl logs %s --previous'",
+	"ContainerCreating":          "Containers are stuck being created, often on volume mounts; check 'kubectl describe pod %s'",
+	"CreateContainerConfigError": "A referenced ConfigMap or Secret key is missing; check 'kubectl describe pod %s'",
+	"NotReady":                   "Containers run but fail their readiness probe; check 'k8s-lens analyze pod %s'",
+}
+
+// leadingCount matches the node count the scheduler puts before each reason
+var leadingCount = regexp.MustCompile(`^[0-9]+ `)
+
+// analyzeMissingReplicas explains a shortfall of ready replicas by grouping the
+// deployment's pods that are not ready by their root reason, e.g.
+// "3 of 5 replicas unavailable: 2 Pending (insufficient memory), 1 CrashLoopBackOff"
+func (d *DeploymentAnalyzer) analyzeMissingReplicas(report *DeploymentReport, replicaSets map[string]*appsv1.ReplicaSet, pods []corev1.Pod) {
+	unavailable := report.DesiredReplicas - report.ReadyReplicas
+	if unavailable <= 0 {
+		return
+	}
+
+	counts := make(map[string]int)
+	examples := make(map[string]string)
+	var order []string
+	running := 0
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == corev1.PodSucceeded {
+			continue
+		}
+		running++
+		if IsPodReady(&pod) {
+			continue
+		}
+
+		reason, detail := notReadyReason(&pod)
+		group := reason
+		if detail != "" {
+			group = fmt.Sprintf("%s (%s)", reason, detail)
+		}
+		if counts[group] == 0 {
+			order = append(order, group)
+			examples[reason] = pod.Name
+		}
+		counts[group]++
+	}
+
+	if missing := int(report.DesiredReplicas) - running; missing > 0 {
+		group := "not created"
+		if message := replicaFailure(replicaSets); message != "" {
+			group = fmt.Sprintf("not created (%s)", message)
+		}
+		order = append(order, group)
+		counts[group] = missing
+	}
+	if len(order) == 0 {
+		return
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	groups := make([]string, 0, len(order))
+	for _, group := range order {
+		groups = append(groups, fmt.Sprintf("%d %s", counts[group], group))
+	}
+	report.Analysis.Issues = append(report.Analysis.Issues,
+		fmt.Sprintf("%d of %d replicas unavailable: %s", unavailable, report.DesiredReplicas, strings.Join(groups, ", ")))
+
+	reasons := make([]string, 0, len(examples))
+	for reason := range examples {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	for _, reason := range reasons {
+		if recommendation, ok := unavailableReasonRecommendations[reason]; ok {
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+				fmt.Sprintf(recommendation, examples[reason]+" -n "+report.Namespace))
+		}
+	}
+}
+
+// notReadyReason returns the root reason a pod is not ready, such as Pending or a
+// container's waiting reason, with a detail like the scheduler's reasons when known
+func notReadyReason(pod *corev1.Pod) (string, string) {
+	if pod.Spec.NodeName == "" {
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionFalse && condition.Message != "" {
+				var details []string
+				for _, reason := range schedulerReasons(condition.Message) {
+					if reason = leadingCount.ReplaceAllString(reason, ""); reason == "" {
+						continue
+					}
+					details = append(details, strings.ToLower(reason[:1])+reason[1:])
+				}
+				return "Pending", strings.Join(details, ", ")
+			}
+		}
+		return "Pending", ""
+	}
+
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" && status.State.Waiting.Reason != "PodInitializing" {
+			return status.State.Waiting.Reason, ""
+		}
+	}
+	for _, status := range statuses {
+		if status.State.Terminated != nil && status.State.Terminated.ExitCode != 0 {
+			return "Terminated", status.State.Terminated.Reason
+		}
+	}
+	if pod.Status.Phase == corev1.PodFailed {
+		return "Failed", pod.Status.Reason
+	}
+	return "NotReady", ""
+}
+
+// replicaFailure returns why the deployment's ReplicaSets could not create pods, e.g.
+// a quota being exceeded, from their ReplicaFailure condition
+func replicaFailure(replicaSets map[string]*appsv1.ReplicaSet) string {
+	for _, rs := range replicaSets {
+		for _, condition := range rs.Status.Conditions {
+			if condition.Type == appsv1.ReplicaSetReplicaFailure && condition.Status == corev1.ConditionTrue {
+				return condition.Message
+			}
+		}
+	}
+	return ""
+}
@@ -43,8 +43,8 @@ type NetworkPolicyAnalysis struct {
 }
 
 // AnalyzeNetworkPolicy analyzes a specific NetworkPolicy
-func (n *NetworkAnalyzer) AnalyzeNetworkPolicy(policyName string) (*NetworkPolicyReport, error) {
-	policy, err := n.client.NetworkingV1().NetworkPolicies(n.namespace).Get(context.TODO(), policyName, metav1.GetOptions{})
+func (n *NetworkAnalyzer) AnalyzeNetworkPolicy(ctx context.Context, policyName string) (*NetworkPolicyReport, error) {
+	policy, err := n.client.NetworkingV1().NetworkPolicies(n.namespace).Get(ctx, policyName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get network policy %s: %v", policyName, err)
 	}
@@ -63,8 +63,8 @@ func (n *NetworkAnalyzer) AnalyzeNetworkPolicy(policyName string) (*NetworkPolic
 }
 
 // AnalyzeNamespaceNetworkPolicies analyzes all NetworkPolicies in a namespace
-func (n *NetworkAnalyzer) AnalyzeNamespaceNetworkPolicies() (*NamespaceNetworkReport, error) {
-	policies, err := n.client.NetworkingV1().NetworkPolicies(n.namespace).List(context.TODO(), metav1.ListOptions{})
+func (n *NetworkAnalyzer) AnalyzeNamespaceNetworkPolicies(ctx context.Context) (*NamespaceNetworkReport, error) {
+	policies, err := n.client.NetworkingV1().NetworkPolicies(n.namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list network policies: %v", err)
 	}
@@ -0,0 +1,253 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// allocationWarningPercent is the share of allocatable requested from which a node is
+// treated as nearly full for scheduling
+const allocationWarningPercent = 90
+
+// NodeAllocation compares the requests and limits of the pods scheduled on a node with
+// its allocatable resources. The scheduler places pods by requests, not usage, so a node
+// whose requests are used up rejects new pods however idle it is.
+type NodeAllocation struct {
+	NodeName string
+	CPU      ResourceAllocation
+	Memory   ResourceAllocation
+	// Pods counts the non-terminated pods, which occupy the node's pod slots
+	Pods            int
+	AllocatablePods int64
+	// ExtendedResources are the GPUs and other extended resources the node advertises,
+	// in units of the resource
+	ExtendedResources []ExtendedAllocation
+	// PodsWithoutLimits counts pods with a container missing a CPU or memory limit,
+	// whose usage the limit totals do not bound
+	PodsWithoutLimits int
+	Issues            []string
+	Recommendations   []string
+}
+
+// ExtendedAllocation is the allocation of an extended resource such as nvidia.com/gpu
+type ExtendedAllocation struct {
+	Resource string
+	ResourceAllocation
+}
+
+// ResourceAllocation is the allocatable amount of a resource and the sum of pod requests
+// and limits for it, in millicores for CPU and bytes for memory
+type ResourceAllocation struct {
+	Allocatable int64
+	Requests    int64
+	Limits      int64
+}
+
+// RequestPercent returns the share of allocatable that is requested
+func (a ResourceAllocation) RequestPercent() float64 {
+	if a.Allocatable == 0 {
+		return 0
+	}
+	return float64(a.Requests) * 100 / float64(a.Allocatable)
+}
+
+// LimitRatio returns the limits as a multiple of allocatable; above 1 the node is
+// overcommitted
+func (a ResourceAllocation) LimitRatio() float64 {
+	if a.Allocatable == 0 {
+		return 0
+	}
+	return float64(a.Limits) / float64(a.Allocatable)
+}
+
+// Free returns the allocatable amount not yet requested
+func (a ResourceAllocation) Free() int64 {
+	if a.Requests > a.Allocatable {
+		return 0
+	}
+	return a.Allocatable - a.Requests
+}
+
+// Allocation sums the requests and limits of the pods scheduled on the node
+func (r *NodeReport) Allocation() *NodeAllocation {
+	allocation := &NodeAllocation{
+		NodeName:        r.Name,
+		CPU:             ResourceAllocation{Allocatable: r.Allocatable.Cpu().MilliValue()},
+		Memory:          ResourceAllocation{Allocatable: r.Allocatable.Memory().Value()},
+		AllocatablePods: r.Allocatable.Pods().Value(),
+	}
+	extended := make(map[corev1.ResourceName]*ResourceAllocation)
+	for name, quantity := range r.Allocatable {
+		if IsExtendedResource(name) {
+			extended[name] = &ResourceAllocation{Allocatable: quantity.Value()}
+		}
+	}
+
+	for i := range r.Pods {
+		pod := &r.Pods[i]
+		// Finished pods no longer hold their requests
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		allocation.Pods++
+
+		requests := podEffectiveResources(pod, false)
+		limits := podEffectiveResources(pod, true)
+		allocation.CPU.Requests += requests.Cpu().MilliValue()
+		allocation.Memory.Requests += requests.Memory().Value()
+		allocation.CPU.Limits += limits.Cpu().MilliValue()
+		allocation.Memory.Limits += limits.Memory().Value()
+		for name, quantity := range requests {
+			if IsExtendedResource(name) {
+				if extended[name] == nil {
+					extended[name] = &ResourceAllocation{}
+				}
+				extended[name].Requests += quantity.Value()
+			}
+		}
+		for name, quantity := range limits {
+			if extended[name] != nil {
+				extended[name].Limits += quantity.Value()
+			}
+		}
+
+		for _, container := range pod.Spec.Containers {
+			if _, ok := container.Resources.Limits[corev1.ResourceCPU]; !ok {
+				allocation.PodsWithoutLimits++
+				break
+			}
+			if _, ok := container.Resources.Limits[corev1.ResourceMemory]; !ok {
+				allocation.PodsWithoutLimits++
+				break
+			}
+		}
+	}
+
+	for name, resource := range extended {
+		allocation.ExtendedResources = append(allocation.ExtendedResources,
+			ExtendedAllocation{Resource: string(name), ResourceAllocation: *resource})
+	}
+	sort.Slice(allocation.ExtendedResources, func(i, j int) bool {
+		return allocation.ExtendedResources[i].Resource < allocation.ExtendedResources[j].Resource
+	})
+
+	allocation.analyze()
+	return allocation
+}
+
+func (a *NodeAllocation) analyze() {
+	if percent := a.CPU.RequestPercent(); percent >= allocationWarningPercent {
+		a.Issues = append(a.Issues,
+			fmt.Sprintf("%.0f%% of allocatable CPU is requested; pods requesting more than %dm CPU cannot be scheduled here",
+				percent, a.CPU.Free()))
+	}
+	if percent := a.Memory.RequestPercent(); percent >= allocationWarningPercent {
+		a.Issues = append(a.Issues,
+			fmt.Sprintf("%.0f%% of allocatable memory is requested; pods requesting more than %s memory cannot be scheduled here",
+				percent, formatBytes(a.Memory.Free())))
+	}
+	if a.AllocatablePods > 0 && int64(a.Pods)*100 >= a.AllocatablePods*allocationWarningPercent {
+		a.Issues = append(a.Issues,
+			fmt.Sprintf("%d of %d pod slots are in use", a.Pods, a.AllocatablePods))
+	}
+	for _, resource := range a.ExtendedResources {
+		switch {
+		case resource.Allocatable == 0 && resource.Requests > 0:
+			a.Issues = append(a.Issues,
+				fmt.Sprintf("Pods on this node request %d %s but the node no longer advertises any; its device plugin may be unhealthy",
+					resource.Requests, resource.Resource))
+		case resource.Allocatable > 0 && resource.Requests >= resource.Allocatable:
+			a.Issues = append(a.Issues,
+				fmt.Sprintf("All %d %s on this node are allocated; pods requesting %s cannot be scheduled here",
+					resource.Allocatable, resource.Resource, resource.Resource))
+		}
+	}
+	if len(a.Issues) > 0 {
+		a.Recommendations = append(a.Recommendations,
+			"Lower over-sized requests of the pods on this node, or add nodes, so new pods have room to schedule")
+	}
+
+	if ratio := a.Memory.LimitRatio(); ratio > 1 {
+		a.Issues = append(a.Issues,
+			fmt.Sprintf("Memory limits are overcommitted %.1fx of allocatable; pods bursting together risk OOM kills and evictions", ratio))
+		a.Recommendations = append(a.Recommendations,
+			"Bring memory limits closer to requests for pods on this node to reduce the risk of evictions")
+	}
+	if ratio := a.CPU.LimitRatio(); ratio > 1 {
+		a.Issues = append(a.Issues,
+			fmt.Sprintf("CPU limits are overcommitted %.1fx of allocatable; pods bursting together will be throttled", ratio))
+	}
+}
+
+// podEffectiveResources returns the requests, or limits, the scheduler accounts for a
+// pod: the sum of its containers and sidecars, or the largest init container if that is
+// larger, plus the pod overhead
+func podEffectiveResources(pod *corev1.Pod, limits bool) corev1.ResourceList {
+	resources := func(container corev1.Container) corev1.ResourceList {
+		if limits {
+			return container.Resources.Limits
+		}
+		return container.Resources.Requests
+	}
+
+	total := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(total, resources(container))
+	}
+
+	// Sidecars run alongside the app containers, so they add to the total; regular init
+	// containers run one at a time alongside only the sidecars declared before them
+	sidecars := corev1.ResourceList{}
+	initPeak := corev1.ResourceList{}
+	for _, container := range pod.Spec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			addResources(total, resources(container))
+			addResources(sidecars, resources(container))
+			continue
+		}
+		initTotal := corev1.ResourceList{}
+		addResources(initTotal, sidecars)
+		addResources(initTotal, resources(container))
+		maxResources(initPeak, initTotal)
+	}
+	maxResources(total, initPeak)
+
+	addResources(total, pod.Spec.Overhead)
+	return total
+}
+
+// addResources adds each quantity of resources to total
+func addResources(total, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		if current, ok := total[name]; ok {
+			current.Add(quantity)
+			total[name] = current
+		} else {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+// maxResources raises each quantity of total to the quantity in resources when larger
+func maxResources(total, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
+
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,332 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NodeAnalyzer provides analysis for Node resources
+type NodeAnalyzer struct {
+	client kubernetes.Interface
+}
+
+// NewNodeAnalyzer creates a new NodeAnalyzer
+func NewNodeAnalyzer(client kubernetes.Interface) *NodeAnalyzer {
+	return &NodeAnalyzer{
+		client: client,
+	}
+}
+
+// NodeReport contains the analysis report for a Node
+type NodeReport struct {
+	Name               string
+	Ready              bool
+	Unschedulable      bool
+	KubeletVersion     string
+	Conditions         []corev1.NodeCondition
+	Capacity           corev1.ResourceList
+	Allocatable        corev1.ResourceList
+	Taints             []corev1.Taint
+	Pods               []corev1.Pod
+	PressureConditions []string
+	Analysis           NodeAnalysis
+	// TaintExplanations explains each taint and lists the pods on the node tolerating it
+	TaintExplanations []TaintExplanation
+	// ContainerRuntime, OSImage, KernelVersion and Architecture come from the node info
+	ContainerRuntime string
+	OSImage          string
+	KernelVersion    string
+	Architecture     string
+	// ControlPlaneVersion is the API server version, empty when it cannot be determined
+	ControlPlaneVersion string
+}
+
+// NodeAnalysis contains diagnostic results
+type NodeAnalysis struct {
+	Status          string
+	Issues          []string
+	Recommendations []string
+}
+
+// NodePressureReport correlates node pressure conditions with the pods causing them
+type NodePressureReport struct {
+	NodeName           string
+	PressureConditions []string
+	RankedBy           string
+	TopConsumers       []PodConsumption
+	EvictedPods        []EvictedPod
+}
+
+// PodConsumption describes how much of a node's resources a pod consumes
+type PodConsumption struct {
+	Name                  string
+	Namespace             string
+	CPUMillis             int64
+	MemoryBytes           int64
+	EphemeralStorageBytes int64
+	FromUsage             bool
+}
+
+// EvictedPod represents a pod that the kubelet has already evicted from a node
+type EvictedPod struct {
+	Name      string
+	Namespace string
+	Message   string
+	Time      time.Time
+}
+
+// PodUsageSource provides live resource usage for pods, e.g. from Prometheus
+type PodUsageSource interface {
+	GetPodUsage(podName, namespace string) (cpuCores float64, memoryBytes float64, err error)
+}
+
+// Analyze performs the analysis of a Node
+func (n *NodeAnalyzer) Analyze(ctx context.Context, nodeName string) (*NodeReport, error) {
+	node, err := n.client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node %s: %v", nodeName, err)
+	}
+
+	pods, err := n.listNodePods(ctx, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &NodeReport{
+		Name:                node.Name,
+		Unschedulable:       node.Spec.Unschedulable,
+		KubeletVersion:      node.Status.NodeInfo.KubeletVersion,
+		ContainerRuntime:    node.Status.NodeInfo.ContainerRuntimeVersion,
+		OSImage:             node.Status.NodeInfo.OSImage,
+		KernelVersion:       node.Status.NodeInfo.KernelVersion,
+		Architecture:        node.Status.NodeInfo.Architecture,
+		ControlPlaneVersion: n.controlPlaneVersion(ctx),
+		Conditions:          node.Status.Conditions,
+		Capacity:            node.Status.Capacity,
+		Allocatable:         node.Status.Allocatable,
+		Taints:              node.Spec.Taints,
+		Pods:                pods,
+	}
+
+	n.analyzeConditions(report)
+	n.analyzeVersionSkew(ctx, report)
+	report.TaintExplanations = explainTaints(node.Spec.Taints, pods)
+
+	return report, nil
+}
+
+// AnalyzePressure lists the pods most likely responsible for node pressure, along with
+// pods that have already been evicted. Pods are ranked by live usage when a usage
+// source is provided and has data for every pod, otherwise all by their resource
+// requests, so a pod's usage is never compared with another pod's requests. Under disk
+// pressure alone pods are ranked by the ephemeral storage the kubelet reports them
+// using, as ephemeral-storage requests are rarely set, or by requests if the kubelet
+// stats are unavailable.
+func (n *NodeAnalyzer) AnalyzePressure(ctx context.Context, nodeName string, limit int, usage PodUsageSource) (*NodePressureReport, error) {
+	report, err := n.Analyze(ctx, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
+	pressure := &NodePressureReport{
+		NodeName:           report.Name,
+		PressureConditions: report.PressureConditions,
+		RankedBy:           "requests",
+	}
+
+	var consumers, used []PodConsumption
+	for _, pod := range report.Pods {
+		if pod.Status.Phase == corev1.PodFailed && pod.Status.Reason == "Evicted" {
+			pressure.EvictedPods = append(pressure.EvictedPods, EvictedPod{
+				Name:      pod.Name,
+				Namespace: pod.Namespace,
+				Message:   pod.Status.Message,
+				Time:      n.evictionTime(ctx, &pod),
+			})
+			continue
+		}
+		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+
+		consumer := podRequests(&pod)
+		consumers = append(consumers, consumer)
+		if usage == nil {
+			continue
+		}
+		cpuCores, memoryBytes, err := usage.GetPodUsage(pod.Name, pod.Namespace)
+		if err != nil {
+			// Without usage for every pod, rank them all by requests
+			usage = nil
+			continue
+		}
+		consumer.CPUMillis = int64(cpuCores * 1000)
+		consumer.MemoryBytes = int64(memoryBytes)
+		consumer.FromUsage = true
+		used = append(used, consumer)
+	}
+	if usage != nil && len(used) == len(consumers) && len(used) > 0 {
+		consumers = used
+		pressure.RankedBy = "usage"
+	}
+
+	// Rank by the resource under pressure; memory is the default since it is
+	// the most common reason for evictions
+	sortKey := func(c PodConsumption) int64 { return c.MemoryBytes }
+	if containsString(report.PressureConditions, string(corev1.NodeDiskPressure)) &&
+		!containsString(report.PressureConditions, string(corev1.NodeMemoryPressure)) {
+		sortKey = func(c PodConsumption) int64 { return c.EphemeralStorageBytes }
+		pressure.RankedBy = "ephemeral-storage requests"
+		if n.applyStorageUsage(ctx, nodeName, consumers) {
+			pressure.RankedBy = "ephemeral-storage usage"
+		}
+	}
+	sort.SliceStable(consumers, func(i, j int) bool {
+		return sortKey(consumers[i]) > sortKey(consumers[j])
+	})
+
+	if limit > 0 && len(consumers) > limit {
+		consumers = consumers[:limit]
+	}
+	pressure.TopConsumers = consumers
+
+	return pressure, nil
+}
+
+// applyStorageUsage replaces the ephemeral storage of the consumers with the usage the
+// kubelet reports. It reports false and leaves them unchanged unless the kubelet has
+// stats for every consumer.
+func (n *NodeAnalyzer) applyStorageUsage(ctx context.Context, nodeName string, consumers []PodConsumption) bool {
+	summary, err := k8s.NodeStatsSummary(ctx, n.client, nodeName)
+	if err != nil {
+		return false
+	}
+	used := make(map[string]int64)
+	for _, pod := range summary.Pods {
+		if pod.EphemeralStorage != nil && pod.EphemeralStorage.UsedBytes != nil {
+			used[pod.PodRef.Namespace+"/"+pod.PodRef.Name] = int64(*pod.EphemeralStorage.UsedBytes)
+		}
+	}
+	for _, consumer := range consumers {
+		if _, ok := used[consumer.Namespace+"/"+consumer.Name]; !ok {
+			return false
+		}
+	}
+	for i := range consumers {
+		consumers[i].EphemeralStorageBytes = used[consumers[i].Namespace+"/"+consumers[i].Name]
+	}
+	return len(consumers) > 0
+}
+
+// evictionTime returns when the kubelet evicted a pod: when its last container stopped,
+// or else the time of its Evicted event. It is zero when neither is known.
+func (n *NodeAnalyzer) evictionTime(ctx context.Context, pod *corev1.Pod) time.Time {
+	var evicted time.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.FinishedAt.After(evicted) {
+			evicted = terminated.FinishedAt.Time
+		}
+	}
+	if !evicted.IsZero() {
+		return evicted
+	}
+
+	events, err := objectEvents(ctx, n.client, nil, pod.Namespace, pod.Name)
+	if err != nil {
+		return evicted
+	}
+	for _, event := range events {
+		if event.Reason == "Evicted" && eventLastSeen(event).After(evicted) {
+			evicted = eventLastSeen(event)
+		}
+	}
+	return evicted
+}
+
+func (n *NodeAnalyzer) listNodePods(ctx context.Context, nodeName string) ([]corev1.Pod, error) {
+	pods, err := n.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %v", nodeName, err)
+	}
+	return pods.Items, nil
+}
+
+func (n *NodeAnalyzer) analyzeConditions(report *NodeReport) {
+	for _, condition := range report.Conditions {
+		switch condition.Type {
+		case corev1.NodeReady:
+			report.Ready = condition.Status == corev1.ConditionTrue
+			if !report.Ready {
+				report.Analysis.Issues = append(report.Analysis.Issues,
+					fmt.Sprintf("Node is not ready: %s", condition.Message))
+			}
+		case corev1.NodeMemoryPressure, corev1.NodeDiskPressure, corev1.NodePIDPressure:
+			if condition.Status == corev1.ConditionTrue {
+				report.PressureConditions = append(report.PressureConditions, string(condition.Type))
+				report.Analysis.Issues = append(report.Analysis.Issues,
+					fmt.Sprintf("Node reports %s: %s", condition.Type, condition.Message))
+			}
+		case corev1.NodeNetworkUnavailable:
+			if condition.Status == corev1.ConditionTrue {
+				report.Analysis.Issues = append(report.Analysis.Issues,
+					fmt.Sprintf("Node network is unavailable: %s", condition.Message))
+			}
+		}
+	}
+
+	if report.Unschedulable {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			"Node is cordoned and will not accept new pods")
+	}
+
+	if len(report.PressureConditions) > 0 {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Identify the top resource-consuming pods on this node and right-size or reschedule them")
+	}
+
+	if len(report.Analysis.Issues) == 0 {
+		report.Analysis.Status = "Healthy"
+	} else {
+		report.Analysis.Status = "Unhealthy"
+	}
+}
+
+func podRequests(pod *corev1.Pod) PodConsumption {
+	consumer := PodConsumption{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+			consumer.CPUMillis += cpu.MilliValue()
+		}
+		if memory, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
+			consumer.MemoryBytes += memory.Value()
+		}
+		if storage, ok := container.Resources.Requests[corev1.ResourceEphemeralStorage]; ok {
+			consumer.EphemeralStorageBytes += storage.Value()
+		}
+	}
+
+	return consumer
+}
+
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
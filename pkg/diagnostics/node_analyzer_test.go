@@ -0,0 +1,88 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestAnalyzePressureRanksDiskPressureByKubeletUsage(t *testing.T) {
+	node := corev1.Node{
+		TypeMeta:   metav1.TypeMeta{Kind: "Node", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue},
+		}},
+	}
+	pod := func(name, storageRequest string) corev1.Pod {
+		container := corev1.Container{Name: "app"}
+		if storageRequest != "" {
+			container.Resources.Requests = corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse(storageRequest)}
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop"},
+			Spec:       corev1.PodSpec{NodeName: "worker-1", Containers: []corev1.Container{container}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	pods := corev1.PodList{
+		TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"},
+		Items:    []corev1.Pod{pod("requests-storage", "1Gi"), pod("fills-disk", "")},
+	}
+	summary := `{"pods": [
+		{"podRef": {"name": "requests-storage", "namespace": "shop"}, "ephemeral-storage": {"usedBytes": 1048576}},
+		{"podRef": {"name": "fills-disk", "namespace": "shop"}, "ephemeral-storage": {"usedBytes": 8589934592}}
+	]}`
+
+	tests := []struct {
+		name      string
+		summary   string
+		rankedBy  string
+		wantFirst string
+	}{
+		{name: "kubelet stats", summary: summary, rankedBy: "ephemeral-storage usage", wantFirst: "fills-disk"},
+		{name: "kubelet unreachable", rankedBy: "ephemeral-storage requests", wantFirst: "requests-storage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/v1/nodes/worker-1":
+					json.NewEncoder(w).Encode(node)
+				case "/api/v1/pods":
+					json.NewEncoder(w).Encode(pods)
+				case "/api/v1/nodes/worker-1/proxy/stats/summary":
+					if tt.summary == "" {
+						http.Error(w, "kubelet unreachable", http.StatusBadGateway)
+						return
+					}
+					w.Write([]byte(tt.summary))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+			assert.NoError(t, err)
+
+			pressure, err := NewNodeAnalyzer(client).AnalyzePressure(context.Background(), "worker-1", 0, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.rankedBy, pressure.RankedBy)
+			if assert.Len(t, pressure.TopConsumers, 2) {
+				assert.Equal(t, tt.wantFirst, pressure.TopConsumers[0].Name)
+			}
+		})
+	}
+}
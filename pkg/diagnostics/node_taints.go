@@ -0,0 +1,76 @@
+package diagnostics
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// wellKnownTaints explains the taints set by Kubernetes components and common tools
+var wellKnownTaints = map[string]string{
+	corev1.TaintNodeNotReady:                         "The node is not ready, so the node controller keeps new pods off it",
+	corev1.TaintNodeUnreachable:                      "The node controller cannot reach the kubelet, so the node is treated as down",
+	corev1.TaintNodeMemoryPressure:                   "The kubelet reports memory pressure and only accepts pods that tolerate it",
+	corev1.TaintNodeDiskPressure:                     "The kubelet reports low disk space and only accepts pods that tolerate it",
+	corev1.TaintNodePIDPressure:                      "The kubelet reports it is running out of process IDs and only accepts pods that tolerate it",
+	corev1.TaintNodeNetworkUnavailable:               "The node's network is not configured yet, so pods would have no connectivity",
+	corev1.TaintNodeUnschedulable:                    "The node is cordoned, e.g. with kubectl cordon or during a drain",
+	corev1.TaintNodeOutOfService:                     "The node is marked out of service, so its pods are force deleted and volumes detached",
+	"node.cloudprovider.kubernetes.io/uninitialized": "The cloud controller manager has not initialized the node yet",
+	"node-role.kubernetes.io/control-plane":          "The node is reserved for control plane components",
+	"node-role.kubernetes.io/master":                 "The node is reserved for control plane components",
+	"ToBeDeletedByClusterAutoscaler":                 "The cluster autoscaler is about to remove this node",
+	"DeletionCandidateOfClusterAutoscaler":           "The cluster autoscaler considers this node unneeded and may remove it",
+}
+
+// TaintExplanation describes what a node taint means for scheduling and which of the
+// pods on the node tolerate it
+type TaintExplanation struct {
+	Taint string
+	// Meaning explains why the taint is set, for taints set by Kubernetes or common tools
+	Meaning string
+	// Effect explains what the taint's effect does to new and running pods
+	Effect string
+	// ToleratingPods lists the pods on the node, as namespace/name, that tolerate the taint
+	ToleratingPods []string
+}
+
+// explainTaints explains each taint of a node and finds the pods on it that tolerate it
+func explainTaints(taints []corev1.Taint, pods []corev1.Pod) []TaintExplanation {
+	var explanations []TaintExplanation
+	for i := range taints {
+		taint := &taints[i]
+		explanation := TaintExplanation{
+			Taint:   formatTaint(*taint),
+			Meaning: wellKnownTaints[taint.Key],
+			Effect:  taintEffectDescription(taint.Effect),
+		}
+		if explanation.Meaning == "" {
+			explanation.Meaning = fmt.Sprintf("Custom taint %s, usually set to dedicate the node to pods that tolerate it", taint.Key)
+		}
+
+		for j := range pods {
+			pod := &pods[j]
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			if toleratesTaint(pod, taint) {
+				explanation.ToleratingPods = append(explanation.ToleratingPods, pod.Namespace+"/"+pod.Name)
+			}
+		}
+		explanations = append(explanations, explanation)
+	}
+	return explanations
+}
+
+func taintEffectDescription(effect corev1.TaintEffect) string {
+	switch effect {
+	case corev1.TaintEffectNoSchedule:
+		return "New pods without a matching toleration are not scheduled here; running pods stay"
+	case corev1.TaintEffectPreferNoSchedule:
+		return "The scheduler avoids this node for pods without a matching toleration, but uses it when nothing else fits"
+	case corev1.TaintEffectNoExecute:
+		return "New pods without a matching toleration are not scheduled here, and running pods without one are evicted"
+	}
+	return fmt.Sprintf("Unknown effect %s", effect)
+}
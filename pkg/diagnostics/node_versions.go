@@ -0,0 +1,188 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxKubeletSkew is how many minor versions a kubelet may run behind the API server
+// before it is reported
+const maxKubeletSkew = 2
+
+// NodeVersion describes the software a node runs, from its status
+type NodeVersion struct {
+	Name             string
+	KubeletVersion   string
+	ContainerRuntime string
+	OSImage          string
+	KernelVersion    string
+	Architecture     string
+}
+
+// NodeFleetReport shows how uniform the software across the nodes of a cluster is
+type NodeFleetReport struct {
+	// ControlPlaneVersion is the API server version, empty when it cannot be determined
+	ControlPlaneVersion string
+	Nodes               []NodeVersion
+	// KubeletVersions, ContainerRuntimes, OSImages and KernelVersions map each
+	// version found to the nodes running it
+	KubeletVersions   map[string][]string
+	ContainerRuntimes map[string][]string
+	OSImages          map[string][]string
+	KernelVersions    map[string][]string
+	Issues            []string
+	Recommendations   []string
+}
+
+// nodeVersion returns the software versions a node reports
+func nodeVersion(node *corev1.Node) NodeVersion {
+	info := node.Status.NodeInfo
+	return NodeVersion{
+		Name:             node.Name,
+		KubeletVersion:   info.KubeletVersion,
+		ContainerRuntime: info.ContainerRuntimeVersion,
+		OSImage:          info.OSImage,
+		KernelVersion:    info.KernelVersion,
+		Architecture:     info.Architecture,
+	}
+}
+
+// AnalyzeFleet reports the kubelet, container runtime, OS and kernel versions of every
+// node, and warns on kubelets of different minor versions or too far behind the
+// control plane
+func (n *NodeAnalyzer) AnalyzeFleet(ctx context.Context) (*NodeFleetReport, error) {
+	nodes, err := n.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+
+	report := &NodeFleetReport{
+		ControlPlaneVersion: n.controlPlaneVersion(ctx),
+		KubeletVersions:     make(map[string][]string),
+		ContainerRuntimes:   make(map[string][]string),
+		OSImages:            make(map[string][]string),
+		KernelVersions:      make(map[string][]string),
+	}
+	kubeletMinors := make(map[string][]string)
+	runtimes := make(map[string]bool)
+	for i := range nodes.Items {
+		version := nodeVersion(&nodes.Items[i])
+		report.Nodes = append(report.Nodes, version)
+		report.KubeletVersions[version.KubeletVersion] = append(report.KubeletVersions[version.KubeletVersion], version.Name)
+		report.ContainerRuntimes[version.ContainerRuntime] = append(report.ContainerRuntimes[version.ContainerRuntime], version.Name)
+		report.OSImages[version.OSImage] = append(report.OSImages[version.OSImage], version.Name)
+		report.KernelVersions[version.KernelVersion] = append(report.KernelVersions[version.KernelVersion], version.Name)
+		if minor := minorVersion(version.KubeletVersion); minor != "" {
+			kubeletMinors[minor] = append(kubeletMinors[minor], version.Name)
+		}
+		runtime, _, _ := strings.Cut(version.ContainerRuntime, "://")
+		runtimes[runtime] = true
+
+		issues, recommendations := kubeletSkew(version.Name, version.KubeletVersion, report.ControlPlaneVersion)
+		report.Issues = append(report.Issues, issues...)
+		report.Recommendations = append(report.Recommendations, recommendations...)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool { return report.Nodes[i].Name < report.Nodes[j].Name })
+
+	if len(kubeletMinors) > 1 {
+		var versions []string
+		for minor, names := range kubeletMinors {
+			versions = append(versions, fmt.Sprintf("%s (%d node(s))", minor, len(names)))
+		}
+		sort.Strings(versions)
+		report.Issues = append(report.Issues, fmt.Sprintf("Nodes run %d kubelet minor versions: %s",
+			len(kubeletMinors), strings.Join(versions, ", ")))
+		report.Recommendations = append(report.Recommendations,
+			"Finish upgrading the node pools so every kubelet runs the same minor version; mixed versions behave differently during and after upgrades")
+	}
+	if len(runtimes) > 1 {
+		report.Issues = append(report.Issues, fmt.Sprintf("Nodes use %d different container runtimes", len(runtimes)))
+		report.Recommendations = append(report.Recommendations,
+			"Standardize the container runtime across node pools, so workloads do not behave differently depending on where they are scheduled")
+	}
+	return report, nil
+}
+
+// analyzeVersionSkew warns when the node's kubelet is too far behind or ahead of the
+// control plane, or on another minor version than the newest kubelets of the cluster.
+// Skew alone makes a healthy node need attention rather than unhealthy.
+func (n *NodeAnalyzer) analyzeVersionSkew(ctx context.Context, report *NodeReport) {
+	issues, recommendations := kubeletSkew(report.Name, report.KubeletVersion, report.ControlPlaneVersion)
+
+	nodes, err := n.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err == nil {
+		newest, newestMinor := "", 0
+		for _, node := range nodes.Items {
+			minor, ok := parseMinorVersion(minorVersion(node.Status.NodeInfo.KubeletVersion))
+			if ok && minor > newestMinor {
+				newest, newestMinor = minorVersion(node.Status.NodeInfo.KubeletVersion), minor
+			}
+		}
+		if minor, ok := parseMinorVersion(minorVersion(report.KubeletVersion)); ok && minor < newestMinor {
+			issues = append(issues, fmt.Sprintf("Kubelet %s is behind the newest nodes of the cluster, which run %s",
+				report.KubeletVersion, newest))
+			recommendations = append(recommendations, fmt.Sprintf("Upgrade this node to kubelet %s like the rest of the cluster", newest))
+		}
+	}
+
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+	if len(issues) > 0 && report.Analysis.Status == "Healthy" {
+		report.Analysis.Status = "Needs Attention"
+	}
+}
+
+// kubeletSkew checks a kubelet version against the control plane version. The kubelet
+// must not be newer than the API server, nor more than maxKubeletSkew minors older.
+func kubeletSkew(nodeName, kubeletVersion, controlPlaneVersion string) ([]string, []string) {
+	kubelet, ok := parseMinorVersion(minorVersion(kubeletVersion))
+	if !ok {
+		return nil, nil
+	}
+	server, ok := parseMinorVersion(minorVersion(controlPlaneVersion))
+	if !ok {
+		return nil, nil
+	}
+
+	switch {
+	case kubelet > server:
+		return []string{fmt.Sprintf("Node %s runs kubelet %s, newer than the control plane %s, which is not supported",
+				nodeName, kubeletVersion, controlPlaneVersion)},
+			[]string{fmt.Sprintf("Upgrade the control plane before the kubelet of node %s", nodeName)}
+	case server-kubelet > maxKubeletSkew:
+		return []string{fmt.Sprintf("Node %s runs kubelet %s, %d minor versions behind the control plane %s (at most %d are allowed)",
+				nodeName, kubeletVersion, server-kubelet, controlPlaneVersion, maxKubeletSkew)},
+			[]string{fmt.Sprintf("Upgrade node %s to a kubelet within %d minor versions of %s", nodeName, maxKubeletSkew, minorVersion(controlPlaneVersion))}
+	}
+	return nil, nil
+}
+
+// controlPlaneVersion returns the API server version, or "" when discovery fails
+func (n *NodeAnalyzer) controlPlaneVersion(ctx context.Context) string {
+	info, err := serverVersion(ctx, n.client)
+	if err != nil {
+		return ""
+	}
+	if info.GitVersion != "" {
+		return info.GitVersion
+	}
+	return info.Major + "." + info.Minor
+}
+
+// minorVersion returns the major.minor part of a Kubernetes version, e.g. "1.29" for
+// "v1.29.3-eks-1234", or "" when version is not one
+func minorVersion(version string) string {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	if _, ok := parseMinorVersion(parts[0] + "." + parts[1]); !ok {
+		return ""
+	}
+	return parts[0] + "." + parts[1]
+}
@@ -0,0 +1,370 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// rootCAConfigMap is published into every namespace by the control plane
+const rootCAConfigMap = "kube-root-ca.crt"
+
+// OrphanAnalyzer finds resources in a namespace that are likely left behind
+type OrphanAnalyzer struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// NewOrphanAnalyzer creates a new OrphanAnalyzer
+func NewOrphanAnalyzer(client kubernetes.Interface, namespace string) *OrphanAnalyzer {
+	return &OrphanAnalyzer{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+// OrphanReport lists the cleanup candidates found in a namespace
+type OrphanReport struct {
+	Namespace string
+	Orphans   []OrphanedResource
+}
+
+// OrphanedResource is a resource that nothing appears to use
+type OrphanedResource struct {
+	Kind   string
+	Name   string
+	Reason string
+}
+
+// orphanInventory holds the namespace resources the orphan checks look at
+type orphanInventory struct {
+	pods         []corev1.Pod
+	replicaSets  []appsv1.ReplicaSet
+	deployments  []appsv1.Deployment
+	statefulSets []appsv1.StatefulSet
+	daemonSets   []appsv1.DaemonSet
+	cronJobs     []batchv1.CronJob
+	ingresses    []networkingv1.Ingress
+	pvcs         []corev1.PersistentVolumeClaim
+	configMaps   []corev1.ConfigMap
+	secrets      []corev1.Secret
+	services     []corev1.Service
+	slices       []discoveryv1.EndpointSlice
+	accounts     []corev1.ServiceAccount
+}
+
+// FindOrphans reports ReplicaSets with no pods and no owning Deployment, PVCs no pod
+// mounts, ConfigMaps and Secrets nothing references, and Services with neither a
+// selector nor endpoints
+func (o *OrphanAnalyzer) FindOrphans(ctx context.Context) (*OrphanReport, error) {
+	inventory, err := o.listInventory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &OrphanReport{Namespace: o.namespace}
+	report.Orphans = append(report.Orphans, orphanedReplicaSets(inventory)...)
+	report.Orphans = append(report.Orphans, orphanedPVCs(inventory)...)
+	report.Orphans = append(report.Orphans, orphanedConfigAndSecrets(inventory)...)
+	report.Orphans = append(report.Orphans, orphanedServices(inventory)...)
+	return report, nil
+}
+
+func (o *OrphanAnalyzer) listInventory(ctx context.Context) (*orphanInventory, error) {
+	inventory := &orphanInventory{}
+	options := metav1.ListOptions{}
+
+	pods, err := o.client.CoreV1().Pods(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+	inventory.pods = pods.Items
+
+	replicaSets, err := o.client.AppsV1().ReplicaSets(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicasets: %v", err)
+	}
+	inventory.replicaSets = replicaSets.Items
+
+	deployments, err := o.client.AppsV1().Deployments(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %v", err)
+	}
+	inventory.deployments = deployments.Items
+
+	statefulSets, err := o.client.AppsV1().StatefulSets(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %v", err)
+	}
+	inventory.statefulSets = statefulSets.Items
+
+	daemonSets, err := o.client.AppsV1().DaemonSets(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets: %v", err)
+	}
+	inventory.daemonSets = daemonSets.Items
+
+	cronJobs, err := o.client.BatchV1().CronJobs(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs: %v", err)
+	}
+	inventory.cronJobs = cronJobs.Items
+
+	ingresses, err := o.client.NetworkingV1().Ingresses(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ingresses: %v", err)
+	}
+	inventory.ingresses = ingresses.Items
+
+	pvcs, err := o.client.CoreV1().PersistentVolumeClaims(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %v", err)
+	}
+	inventory.pvcs = pvcs.Items
+
+	configMaps, err := o.client.CoreV1().ConfigMaps(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list configmaps: %v", err)
+	}
+	inventory.configMaps = configMaps.Items
+
+	secrets, err := o.client.CoreV1().Secrets(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %v", err)
+	}
+	inventory.secrets = secrets.Items
+
+	services, err := o.client.CoreV1().Services(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %v", err)
+	}
+	inventory.services = services.Items
+
+	slices, err := o.client.DiscoveryV1().EndpointSlices(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoint slices: %v", err)
+	}
+	inventory.slices = slices.Items
+
+	accounts, err := o.client.CoreV1().ServiceAccounts(o.namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts: %v", err)
+	}
+	inventory.accounts = accounts.Items
+
+	return inventory, nil
+}
+
+// orphanedReplicaSets finds empty ReplicaSets whose Deployment is gone or that never had one
+func orphanedReplicaSets(inventory *orphanInventory) []OrphanedResource {
+	deployments := make(map[string]bool)
+	for _, deployment := range inventory.deployments {
+		deployments[string(deployment.UID)] = true
+	}
+
+	var orphans []OrphanedResource
+	for _, rs := range inventory.replicaSets {
+		if rs.Status.Replicas > 0 || (rs.Spec.Replicas != nil && *rs.Spec.Replicas > 0) {
+			continue
+		}
+		owner := metav1.GetControllerOf(&rs)
+		switch {
+		case owner == nil:
+			orphans = append(orphans, OrphanedResource{Kind: "ReplicaSet", Name: rs.Name,
+				Reason: "has no pods and is not owned by a Deployment"})
+		case owner.Kind == "Deployment" && !deployments[string(owner.UID)]:
+			orphans = append(orphans, OrphanedResource{Kind: "ReplicaSet", Name: rs.Name,
+				Reason: fmt.Sprintf("has no pods and its Deployment %s no longer exists", owner.Name)})
+		}
+	}
+	return orphans
+}
+
+// orphanedPVCs finds claims that no pod mounts
+func orphanedPVCs(inventory *orphanInventory) []OrphanedResource {
+	mounted := make(map[string]bool)
+	for _, pod := range inventory.pods {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil {
+				mounted[volume.PersistentVolumeClaim.ClaimName] = true
+			}
+			// Generic ephemeral volumes create a claim named after the pod and volume
+			if volume.Ephemeral != nil {
+				mounted[pod.Name+"-"+volume.Name] = true
+			}
+		}
+	}
+
+	var orphans []OrphanedResource
+	for _, pvc := range inventory.pvcs {
+		if mounted[pvc.Name] {
+			continue
+		}
+		reason := "is not mounted by any pod"
+		if statefulSet := claimStatefulSet(pvc.Name, inventory.statefulSets); statefulSet != "" {
+			reason += fmt.Sprintf(" (it belongs to StatefulSet %s, check it was scaled down on purpose)", statefulSet)
+		}
+		orphans = append(orphans, OrphanedResource{Kind: "PersistentVolumeClaim", Name: pvc.Name, Reason: reason})
+	}
+	return orphans
+}
+
+// claimStatefulSet returns the StatefulSet whose volume claim templates produce a claim
+// name, which are named <template>-<statefulset>-<ordinal>
+func claimStatefulSet(claimName string, statefulSets []appsv1.StatefulSet) string {
+	for _, statefulSet := range statefulSets {
+		for _, template := range statefulSet.Spec.VolumeClaimTemplates {
+			if strings.HasPrefix(claimName, template.Name+"-"+statefulSet.Name+"-") {
+				return statefulSet.Name
+			}
+		}
+	}
+	return ""
+}
+
+// orphanedConfigAndSecrets finds ConfigMaps and Secrets that no pod, workload template,
+// ingress or service account references. Objects owned by another resource, and ones managed by
+// the control plane or Helm, are skipped.
+func orphanedConfigAndSecrets(inventory *orphanInventory) []OrphanedResource {
+	configMaps := make(map[string]bool)
+	secrets := make(map[string]bool)
+
+	specs := make([]corev1.PodSpec, 0, len(inventory.pods))
+	for _, pod := range inventory.pods {
+		specs = append(specs, pod.Spec)
+	}
+	// Templates count too, so workloads scaled to zero keep their configuration
+	for _, deployment := range inventory.deployments {
+		specs = append(specs, deployment.Spec.Template.Spec)
+	}
+	for _, statefulSet := range inventory.statefulSets {
+		specs = append(specs, statefulSet.Spec.Template.Spec)
+	}
+	for _, daemonSet := range inventory.daemonSets {
+		specs = append(specs, daemonSet.Spec.Template.Spec)
+	}
+	for _, cronJob := range inventory.cronJobs {
+		specs = append(specs, cronJob.Spec.JobTemplate.Spec.Template.Spec)
+	}
+	for _, spec := range specs {
+		collectPodSpecReferences(spec, configMaps, secrets)
+	}
+
+	for _, ingress := range inventory.ingresses {
+		for _, tls := range ingress.Spec.TLS {
+			secrets[tls.SecretName] = true
+		}
+	}
+	for _, account := range inventory.accounts {
+		for _, secret := range account.Secrets {
+			secrets[secret.Name] = true
+		}
+		for _, secret := range account.ImagePullSecrets {
+			secrets[secret.Name] = true
+		}
+	}
+
+	var orphans []OrphanedResource
+	for _, configMap := range inventory.configMaps {
+		if configMaps[configMap.Name] || configMap.Name == rootCAConfigMap || len(configMap.OwnerReferences) > 0 {
+			continue
+		}
+		orphans = append(orphans, OrphanedResource{Kind: "ConfigMap", Name: configMap.Name,
+			Reason: "is not referenced by any pod, workload template or service account"})
+	}
+	for _, secret := range inventory.secrets {
+		if secrets[secret.Name] || len(secret.OwnerReferences) > 0 {
+			continue
+		}
+		// Token secrets are managed by the control plane, and Helm stores releases as secrets
+		if secret.Type == corev1.SecretTypeServiceAccountToken || secret.Type == "helm.sh/release.v1" {
+			continue
+		}
+		orphans = append(orphans, OrphanedResource{Kind: "Secret", Name: secret.Name,
+			Reason: "is not referenced by any pod, workload template, ingress or service account"})
+	}
+	return orphans
+}
+
+// collectPodSpecReferences records the ConfigMaps and Secrets a pod spec uses through
+// volumes, environment variables and image pull secrets
+func collectPodSpecReferences(spec corev1.PodSpec, configMaps, secrets map[string]bool) {
+	for _, secret := range spec.ImagePullSecrets {
+		secrets[secret.Name] = true
+	}
+
+	for _, volume := range spec.Volumes {
+		if volume.ConfigMap != nil {
+			configMaps[volume.ConfigMap.Name] = true
+		}
+		if volume.Secret != nil {
+			secrets[volume.Secret.SecretName] = true
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					configMaps[source.ConfigMap.Name] = true
+				}
+				if source.Secret != nil {
+					secrets[source.Secret.Name] = true
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, container := range containers {
+		for _, source := range container.EnvFrom {
+			if source.ConfigMapRef != nil {
+				configMaps[source.ConfigMapRef.Name] = true
+			}
+			if source.SecretRef != nil {
+				secrets[source.SecretRef.Name] = true
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				configMaps[env.ValueFrom.ConfigMapKeyRef.Name] = true
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				secrets[env.ValueFrom.SecretKeyRef.Name] = true
+			}
+		}
+	}
+}
+
+// orphanedServices finds selectorless Services without any endpoints, which route
+// traffic nowhere. ExternalName services need neither and are skipped.
+func orphanedServices(inventory *orphanInventory) []OrphanedResource {
+	addresses := make(map[string]int)
+	for _, slice := range inventory.slices {
+		service := slice.Labels[discoveryv1.LabelServiceName]
+		for _, endpoint := range slice.Endpoints {
+			addresses[service] += len(endpoint.Addresses)
+		}
+	}
+
+	var orphans []OrphanedResource
+	for _, service := range inventory.services {
+		if len(service.Spec.Selector) > 0 || service.Spec.Type == corev1.ServiceTypeExternalName {
+			continue
+		}
+		if addresses[service.Name] > 0 {
+			continue
+		}
+		orphans = append(orphans, OrphanedResource{Kind: "Service", Name: service.Name,
+			Reason: "has no selector and no endpoints"})
+	}
+	return orphans
+}
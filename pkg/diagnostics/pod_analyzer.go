@@ -3,6 +3,8 @@ package diagnostics
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -14,6 +16,8 @@ import (
 type PodAnalyzer struct {
 	client    kubernetes.Interface
 	namespace string
+	events    *EventCache
+	findings  *FindingCollector
 }
 
 // NewPodAnalyzer creates a new PodAnalyzer
@@ -24,6 +28,17 @@ func NewPodAnalyzer(client kubernetes.Interface, namespace string) *PodAnalyzer
 	}
 }
 
+// SetEventCache makes the analyzer look events up in a cache shared by the analyses
+// of a namespace instead of listing them for every pod
+func (p *PodAnalyzer) SetEventCache(cache *EventCache) {
+	p.events = cache
+}
+
+// SetFindingCollector makes the analyzer emit the issues of each pod as findings
+func (p *PodAnalyzer) SetFindingCollector(collector *FindingCollector) {
+	p.findings = collector
+}
+
 // PodReport contains the analysis report for a Pod
 type PodReport struct {
 	Name                string
@@ -42,30 +57,66 @@ type PodReport struct {
 	ResourceLimitsSet   bool
 	ResourceRequestsSet bool
 	RestartCount        int32
+	SchedulerReasons    []string
+	SchedulingAnalysis  []string
+	HealthScore         int
+	Probes              []ProbeInfo
+	ExtendedResources   []ExtendedResource
+	// Resources lists the CPU and memory requests and limits of each container from
+	// the pod spec, init containers first
+	Resources []ContainerResources
+	// Owner is the top-level controller managing the pod, nil for bare pods
+	Owner *OwnerReference
+	// OwnerChain lists every controller from the pod upwards, e.g. ReplicaSet then Deployment
+	OwnerChain []OwnerReference
+}
+
+// OwnerReference identifies a controller that manages a resource
+type OwnerReference struct {
+	Kind string
+	Name string
 }
 
 // ContainerStatus represents the status of a container
 type ContainerStatus struct {
-	Name    string
-	Image   string
-	Status  string
-	Ready   bool
-	Reason  string
-	Message string
+	Name  string
+	Image string
+	// SpecImage is the image reference from the pod spec and ImageID the image the
+	// runtime actually started, usually including the resolved digest
+	SpecImage string
+	ImageID   string
+	Status    string
+	Ready     bool
+	Reason    string
+	Message   string
+	// ExitCode and TerminationReason come from the current or last termination, which
+	// ended at TerminatedAt
+	ExitCode          int32
+	TerminationReason string
+	TerminatedAt      time.Time
+}
+
+// ContainerResources holds the CPU and memory requests and limits of a container,
+// empty when not set
+type ContainerResources struct {
+	Name          string
+	Init          bool
+	CPURequest    string
+	CPULimit      string
+	MemoryRequest string
+	MemoryLimit   string
 }
 
 // Analyze performs the analysis of a Pod
-func (p *PodAnalyzer) Analyze(podName string) (*PodReport, error) {
+func (p *PodAnalyzer) Analyze(ctx context.Context, podName string) (*PodReport, error) {
 	// Get the pod
-	pod, err := p.client.CoreV1().Pods(p.namespace).Get(context.TODO(), podName, metav1.GetOptions{})
+	pod, err := p.client.CoreV1().Pods(p.namespace).Get(ctx, podName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pod %s: %v", podName, err)
 	}
 
 	// Get events for the pod
-	events, err := p.client.CoreV1().Events(p.namespace).List(context.TODO(), metav1.ListOptions{
-		FieldSelector: "involvedObject.name=" + podName,
-	})
+	events, err := objectEvents(ctx, p.client, p.events, p.namespace, podName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events for pod %s: %v", podName, err)
 	}
@@ -79,27 +130,79 @@ func (p *PodAnalyzer) Analyze(podName string) (*PodReport, error) {
 		PodIP:          pod.Status.PodIP,
 		ServiceAccount: pod.Spec.ServiceAccountName,
 		Created:        pod.CreationTimestamp.Time,
-		Events:         events.Items,
+		Events:         events,
 	}
 
+	// Resolve the controller that manages the pod
+	p.resolveOwner(ctx, report, pod)
+
 	// Analyze container statuses
 	p.analyzeContainers(report, pod)
 
+	// Compare running image digests with the pinned digest and with sibling pods
+	p.analyzeImageDigests(ctx, report, pod)
+
 	// Analyze resource configuration
 	p.analyzeResources(report, pod)
 
+	// Report probe schemes and flag probe misconfigurations
+	p.analyzeProbes(report, pod)
+	p.analyzeProbeFlapping(report, pod)
+	p.analyzeResourceRatios(report, pod)
+	p.analyzeExtendedResources(report, pod)
+	p.analyzeDeprecatedAPIs(ctx, report, pod)
+
+	// Explain why a pending pod cannot be scheduled
+	p.analyzeScheduling(ctx, report, pod)
+
 	// Generate recommendations
 	p.generateRecommendations(report)
 
+	// Score overall health
+	report.HealthScore = p.calculateHealthScore(report, pod)
+
+	p.findings.Add(report.Findings()...)
 	return report, nil
 }
 
+// resolveOwner follows controller references up from the pod, through ReplicaSets to
+// Deployments and through Jobs to CronJobs, so fixes can target the real owner
+func (p *PodAnalyzer) resolveOwner(ctx context.Context, report *PodReport, pod *corev1.Pod) {
+	ref := metav1.GetControllerOf(pod)
+	for ref != nil {
+		report.OwnerChain = append(report.OwnerChain, OwnerReference{Kind: ref.Kind, Name: ref.Name})
+
+		var next *metav1.OwnerReference
+		switch ref.Kind {
+		case "ReplicaSet":
+			rs, err := p.client.AppsV1().ReplicaSets(p.namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			if err == nil {
+				next = metav1.GetControllerOf(rs)
+			}
+		case "Job":
+			job, err := p.client.BatchV1().Jobs(p.namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+			if err == nil {
+				next = metav1.GetControllerOf(job)
+			}
+		}
+		ref = next
+	}
+
+	if len(report.OwnerChain) > 0 {
+		report.Owner = &report.OwnerChain[len(report.OwnerChain)-1]
+	}
+}
+
 func (p *PodAnalyzer) analyzeContainers(report *PodReport, pod *corev1.Pod) {
 	for _, containerStatus := range pod.Status.ContainerStatuses {
 		container := ContainerStatus{
-			Name:  containerStatus.Name,
-			Image: containerStatus.Image,
-			Ready: containerStatus.Ready,
+			Name:    containerStatus.Name,
+			Image:   containerStatus.Image,
+			ImageID: containerStatus.ImageID,
+			Ready:   containerStatus.Ready,
+		}
+		if spec := podContainer(pod, containerStatus.Name); spec != nil {
+			container.SpecImage = spec.Image
 		}
 
 		// Determine container status
@@ -119,6 +222,12 @@ func (p *PodAnalyzer) analyzeContainers(report *PodReport, pod *corev1.Pod) {
 			container.Message = containerStatus.State.Terminated.Message
 		}
 
+		if terminated := lastTermination(containerStatus); terminated != nil {
+			container.ExitCode = terminated.ExitCode
+			container.TerminationReason = terminated.Reason
+			container.TerminatedAt = terminated.FinishedAt.Time
+		}
+
 		report.Containers = append(report.Containers, container)
 		report.RestartCount += containerStatus.RestartCount
 
@@ -156,10 +265,153 @@ func (p *PodAnalyzer) analyzeContainers(report *PodReport, pod *corev1.Pod) {
 	}
 }
 
+// analyzeImageDigests flags containers running a different image than their reference
+// points to. A digest-pinned reference must match the running digest exactly. For tags,
+// the registry is not queried; instead the running digest is compared with the other
+// pods of the same controller, as differing digests mean the tag was pushed again.
+func (p *PodAnalyzer) analyzeImageDigests(ctx context.Context, report *PodReport, pod *corev1.Pod) {
+	var siblings []corev1.Pod
+	if controller := metav1.GetControllerOf(pod); controller != nil {
+		selector, err := p.controllerSelector(ctx, controller)
+		if err != nil || selector == "" {
+			return
+		}
+		pods, err := p.client.CoreV1().Pods(p.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err == nil {
+			for _, other := range pods.Items {
+				if other.Name == pod.Name {
+					continue
+				}
+				if owner := metav1.GetControllerOf(&other); owner != nil && owner.UID == controller.UID {
+					siblings = append(siblings, other)
+				}
+			}
+		}
+	}
+
+	for _, container := range report.Containers {
+		digest := imageDigest(container.ImageID)
+		if digest == "" || container.SpecImage == "" {
+			continue
+		}
+
+		if pinned := imageDigest(container.SpecImage); pinned != "" {
+			if pinned != digest {
+				report.Issues = append(report.Issues,
+					fmt.Sprintf("Container %s runs image digest %s but its spec pins %s", container.Name, digest, pinned))
+			}
+			continue
+		}
+
+		others := make(map[string][]string)
+		for _, sibling := range siblings {
+			for _, status := range sibling.Status.ContainerStatuses {
+				spec := podContainer(&sibling, status.Name)
+				if status.Name != container.Name || spec == nil || spec.Image != container.SpecImage {
+					continue
+				}
+				if otherDigest := imageDigest(status.ImageID); otherDigest != "" && otherDigest != digest {
+					others[otherDigest] = append(others[otherDigest], sibling.Name)
+				}
+			}
+		}
+		otherDigests := make([]string, 0, len(others))
+		for otherDigest := range others {
+			otherDigests = append(otherDigests, otherDigest)
+		}
+		sort.Strings(otherDigests)
+		for _, otherDigest := range otherDigests {
+			report.Issues = append(report.Issues,
+				fmt.Sprintf("Container %s runs %s as digest %s, but pod(s) %s run it as %s; the tag was pushed again while pods were running",
+					container.Name, container.SpecImage, shortDigest(digest), strings.Join(others[otherDigest], ", "), shortDigest(otherDigest)))
+			report.Recommendations = append(report.Recommendations,
+				fmt.Sprintf("Pin %s by digest or use immutable tags, and restart pods still running the old image", container.SpecImage))
+		}
+	}
+}
+
+// controllerSelector returns the pod selector of a pod's controller, so its siblings are
+// listed without listing the whole namespace, or "" for controllers of other kinds
+func (p *PodAnalyzer) controllerSelector(ctx context.Context, controller *metav1.OwnerReference) (string, error) {
+	var selector *metav1.LabelSelector
+	switch controller.Kind {
+	case "ReplicaSet":
+		rs, err := p.client.AppsV1().ReplicaSets(p.namespace).Get(ctx, controller.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		selector = rs.Spec.Selector
+	case "StatefulSet":
+		statefulSet, err := p.client.AppsV1().StatefulSets(p.namespace).Get(ctx, controller.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		selector = statefulSet.Spec.Selector
+	case "DaemonSet":
+		daemonSet, err := p.client.AppsV1().DaemonSets(p.namespace).Get(ctx, controller.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		selector = daemonSet.Spec.Selector
+	case "Job":
+		job, err := p.client.BatchV1().Jobs(p.namespace).Get(ctx, controller.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		selector = job.Spec.Selector
+	default:
+		return "", nil
+	}
+	if selector == nil {
+		return "", nil
+	}
+	parsed, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil || parsed.Empty() {
+		return "", err
+	}
+	return parsed.String(), nil
+}
+
+// imageDigest returns the repo digest of an image reference or image ID of the form
+// name@sha256:..., e.g. docker-pullable://nginx@sha256:..., or "" if it has none. A bare
+// sha256:... image ID is the config digest of an image side-loaded onto the node (kind,
+// minikube, some containerd setups), which is not comparable with repo digests.
+func imageDigest(image string) string {
+	i := strings.LastIndex(image, "@")
+	if i <= 0 || !strings.HasPrefix(image[i+1:], "sha256:") {
+		return ""
+	}
+	return image[i+1:]
+}
+
+// shortDigest abbreviates a digest for display
+func shortDigest(digest string) string {
+	if len(digest) > len("sha256:")+12 {
+		return digest[:len("sha256:")+12]
+	}
+	return digest
+}
+
+func podContainer(pod *corev1.Pod, name string) *corev1.Container {
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	return nil
+}
+
 func (p *PodAnalyzer) analyzeResources(report *PodReport, pod *corev1.Pod) {
 	report.ResourceLimitsSet = true
 	report.ResourceRequestsSet = true
 
+	for _, container := range pod.Spec.InitContainers {
+		report.Resources = append(report.Resources, containerResources(container, true))
+	}
+	for _, container := range pod.Spec.Containers {
+		report.Resources = append(report.Resources, containerResources(container, false))
+	}
+
 	for _, container := range pod.Spec.Containers {
 		if container.Resources.Limits == nil || len(container.Resources.Limits) == 0 {
 			report.ResourceLimitsSet = false
@@ -170,6 +422,182 @@ func (p *PodAnalyzer) analyzeResources(report *PodReport, pod *corev1.Pod) {
 	}
 }
 
+func containerResources(container corev1.Container, init bool) ContainerResources {
+	resources := ContainerResources{Name: container.Name, Init: init}
+	quantity := func(list corev1.ResourceList, name corev1.ResourceName) string {
+		if value, ok := list[name]; ok {
+			return value.String()
+		}
+		return ""
+	}
+	resources.CPURequest = quantity(container.Resources.Requests, corev1.ResourceCPU)
+	resources.CPULimit = quantity(container.Resources.Limits, corev1.ResourceCPU)
+	resources.MemoryRequest = quantity(container.Resources.Requests, corev1.ResourceMemory)
+	resources.MemoryLimit = quantity(container.Resources.Limits, corev1.ResourceMemory)
+	return resources
+}
+
+func (p *PodAnalyzer) analyzeResourceRatios(report *PodReport, pod *corev1.Pod) {
+	issues, recommendations := analyzeResourceRatios(pod.Spec.Containers)
+	report.Issues = append(report.Issues, issues...)
+	report.Recommendations = append(report.Recommendations, recommendations...)
+}
+
+func (p *PodAnalyzer) analyzeProbes(report *PodReport, pod *corev1.Pod) {
+	probes, issues, recommendations := analyzeProbes(pod.Spec.Containers)
+	report.Probes = probes
+	report.Issues = append(report.Issues, issues...)
+	report.Recommendations = append(report.Recommendations, recommendations...)
+}
+
+func (p *PodAnalyzer) analyzeProbeFlapping(report *PodReport, pod *corev1.Pod) {
+	issues, recommendations := analyzeProbeFlapping(report.Events, pod.Spec.Containers, time.Now())
+	report.Issues = append(report.Issues, issues...)
+	report.Recommendations = append(report.Recommendations, recommendations...)
+}
+
+func (p *PodAnalyzer) analyzeExtendedResources(report *PodReport, pod *corev1.Pod) {
+	resources, issues, recommendations := analyzeExtendedResources(pod.Spec.Containers)
+	report.ExtendedResources = resources
+	report.Issues = append(report.Issues, issues...)
+	report.Recommendations = append(report.Recommendations, recommendations...)
+}
+
+func (p *PodAnalyzer) analyzeDeprecatedAPIs(ctx context.Context, report *PodReport, pod *corev1.Pod) {
+	issues, recommendations := analyzeDeprecatedAPIs(ctx, p.client, pod, "Pod")
+	report.Issues = append(report.Issues, issues...)
+	report.Recommendations = append(report.Recommendations, recommendations...)
+}
+
+func (p *PodAnalyzer) analyzeScheduling(ctx context.Context, report *PodReport, pod *corev1.Pod) {
+	if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" {
+		return
+	}
+
+	failedScheduling := false
+	for _, event := range report.Events {
+		if event.Reason == "FailedScheduling" {
+			failedScheduling = true
+			report.SchedulerReasons = schedulerReasons(event.Message)
+		}
+	}
+	if !failedScheduling {
+		return
+	}
+
+	nodes, err := p.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		report.SchedulingAnalysis = append(report.SchedulingAnalysis,
+			fmt.Sprintf("Unable to list nodes to explain scheduling failure: %v", err))
+		return
+	}
+
+	report.SchedulingAnalysis = explainScheduling(pod, nodes.Items)
+	report.Issues = append(report.Issues, report.SchedulingAnalysis...)
+}
+
+// recentExitWindow is how long a container exit lowers the health score after the
+// container is running and ready again
+const recentExitWindow = time.Hour
+
+// calculateHealthScore rates the pod from 0 to 100, weighting failures by severity so a
+// crash-looping pod scores far lower than one with only a configuration warning
+func (p *PodAnalyzer) calculateHealthScore(report *PodReport, pod *corev1.Pod) int {
+	score := 100
+
+	for _, container := range report.Containers {
+		switch container.Reason {
+		case "CrashLoopBackOff":
+			score -= 40
+		case "ImagePullBackOff", "ErrImagePull":
+			score -= 30
+		}
+
+		// A past exit of a container that has been running and ready since no longer
+		// affects the pod's health
+		if !exitIsCurrent(container, time.Now()) {
+			continue
+		}
+
+		// Exit codes: 137 is SIGKILL (usually the OOM killer), 143 is SIGTERM
+		// from a normal shutdown, anything else non-zero is an application error
+		switch {
+		case container.TerminationReason == "OOMKilled" || container.ExitCode == 137:
+			score -= 30
+		case container.ExitCode == 143:
+			score -= 5
+		case container.ExitCode != 0:
+			score -= 20
+		}
+	}
+
+	if report.RestartCount > 10 {
+		score -= 20
+	} else if report.RestartCount > 3 {
+		score -= 10
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if container.LivenessProbe == nil {
+			score -= 5
+		}
+		if container.ReadinessProbe == nil {
+			score -= 5
+		}
+	}
+
+	if pod.Status.Phase == corev1.PodPending {
+		pendingFor := time.Since(pod.CreationTimestamp.Time)
+		if pendingFor > 5*time.Minute {
+			score -= 30
+		} else if pendingFor > time.Minute {
+			score -= 10
+		}
+	} else if pod.Status.Phase == corev1.PodFailed {
+		score -= 50
+	}
+
+	if !report.ResourceLimitsSet || !report.ResourceRequestsSet {
+		score -= 5
+	}
+
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
+
+// exitIsCurrent reports whether a container's last exit still bears on its health: the
+// container has not recovered from it, being down or not ready, or it exited within
+// recentExitWindow
+func exitIsCurrent(container ContainerStatus, now time.Time) bool {
+	if container.Status != "Running" || !container.Ready {
+		return true
+	}
+	return !container.TerminatedAt.IsZero() && now.Sub(container.TerminatedAt) < recentExitWindow
+}
+
+// HealthRating maps a health score to a severity label
+func HealthRating(score int) string {
+	switch {
+	case score >= 80:
+		return "Healthy"
+	case score >= 50:
+		return "Degraded"
+	default:
+		return "Critical"
+	}
+}
+
+// lastTermination returns the current termination state of a container, falling back
+// to the previous one so that restarted containers still report why they exited
+func lastTermination(status corev1.ContainerStatus) *corev1.ContainerStateTerminated {
+	if status.State.Terminated != nil {
+		return status.State.Terminated
+	}
+	return status.LastTerminationState.Terminated
+}
+
 func (p *PodAnalyzer) generateRecommendations(report *PodReport) {
 	if !report.ResourceLimitsSet {
 		report.Recommendations = append(report.Recommendations,
@@ -186,17 +614,40 @@ func (p *PodAnalyzer) generateRecommendations(report *PodReport) {
 			fmt.Sprintf("Investigate why container has restarted %d times", report.RestartCount))
 	}
 
+	for _, reason := range report.SchedulingAnalysis {
+		if strings.HasPrefix(reason, "Taint ") {
+			report.Recommendations = append(report.Recommendations,
+				"Add a matching toleration to the pod or remove the taint from the target nodes")
+			break
+		}
+	}
+	for _, reason := range report.SchedulingAnalysis {
+		if strings.HasPrefix(reason, "nodeSelector ") || strings.HasPrefix(reason, "Required node affinity") {
+			report.Recommendations = append(report.Recommendations,
+				"Label the target nodes or relax the pod's nodeSelector/node affinity")
+			break
+		}
+	}
+
 	// Check for common issues in events
 	for _, event := range report.Events {
 		if event.Type == "Warning" {
 			switch event.Reason {
 			case "FailedScheduling":
-				report.Recommendations = append(report.Recommendations,
-					"Check node resources and affinity rules")
+				if len(report.SchedulingAnalysis) == 0 {
+					report.Recommendations = append(report.Recommendations,
+						"Check node resources and affinity rules")
+				}
 			case "FailedMount":
 				report.Recommendations = append(report.Recommendations,
 					"Verify volume configurations and storage class availability")
 			}
 		}
 	}
+	// Point remediation at the controller; edits to a managed pod are lost when it is recreated
+	if report.Owner != nil && len(report.Recommendations) > 0 {
+		report.Recommendations = append(report.Recommendations,
+			fmt.Sprintf("Apply fixes to %s %s rather than the pod; direct pod changes are lost when the controller recreates it",
+				report.Owner.Kind, report.Owner.Name))
+	}
 }
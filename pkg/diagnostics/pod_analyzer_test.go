@@ -0,0 +1,30 @@
+package diagnostics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageDigest(t *testing.T) {
+	const digest = "sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac"
+
+	tests := []struct {
+		name  string
+		image string
+		want  string
+	}{
+		{name: "pullable image ID", image: "docker-pullable://nginx@" + digest, want: digest},
+		{name: "containerd image ID", image: "docker.io/library/nginx@" + digest, want: digest},
+		{name: "image pinned by digest", image: "nginx:1.27@" + digest, want: digest},
+		{name: "side-loaded config digest", image: digest, want: ""},
+		{name: "tag only", image: "nginx:1.27", want: ""},
+		{name: "digest without name", image: "@" + digest, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, imageDigest(tt.image))
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package diagnostics
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// probeFlappingWindow is how far back probe failure and restart events are counted
+	probeFlappingWindow = time.Hour
+	// minFlappingFailures and minFlappingRestarts are the liveness failures and the
+	// restarts they caused within the window from which a probe is flapping
+	minFlappingFailures = 3
+	minFlappingRestarts = 2
+)
+
+// containerFieldPath extracts the container name from an event's involved object
+// field path, e.g. spec.containers{app}
+var containerFieldPath = regexp.MustCompile(`^spec\.(?:initContainers|containers)\{(.+)\}$`)
+
+// probeFlapping tallies the liveness probe events of one container
+type probeFlapping struct {
+	failures int32
+	restarts int32
+	timeouts bool
+	lastSeen time.Time
+}
+
+// analyzeProbeFlapping flags containers whose liveness probe repeatedly fails and
+// restarts them within the last hour: the Unhealthy events the kubelet records for
+// each failure, followed by the Killing events of the restarts. A probe that is too
+// strict for a slow but healthy container shows this pattern.
+func analyzeProbeFlapping(events []corev1.Event, containers []corev1.Container, now time.Time) (issues, recommendations []string) {
+	tallies := make(map[string]*probeFlapping)
+	tally := func(event corev1.Event) *probeFlapping {
+		match := containerFieldPath.FindStringSubmatch(event.InvolvedObject.FieldPath)
+		if match == nil {
+			return nil
+		}
+		if tallies[match[1]] == nil {
+			tallies[match[1]] = &probeFlapping{}
+		}
+		return tallies[match[1]]
+	}
+
+	for _, event := range events {
+		lastSeen := eventLastSeen(event)
+		if lastSeen.Before(now.Add(-probeFlappingWindow)) {
+			continue
+		}
+		switch {
+		case event.Reason == "Unhealthy" && strings.HasPrefix(event.Message, "Liveness probe failed"):
+			if t := tally(event); t != nil {
+				t.failures += eventCount(event)
+				t.timeouts = t.timeouts || probeTimedOut(event.Message)
+				if lastSeen.After(t.lastSeen) {
+					t.lastSeen = lastSeen
+				}
+			}
+		case event.Reason == "Killing" && strings.Contains(event.Message, "failed liveness probe"):
+			if t := tally(event); t != nil {
+				t.restarts += eventCount(event)
+			}
+		}
+	}
+
+	for _, container := range containers {
+		t := tallies[container.Name]
+		if t == nil || t.failures < minFlappingFailures || t.restarts < minFlappingRestarts {
+			continue
+		}
+		issues = append(issues,
+			fmt.Sprintf("Container %s liveness probe is flapping: it failed %d times and restarted the container %d times in the last hour, last %s ago",
+				container.Name, t.failures, t.restarts, now.Sub(t.lastSeen).Round(time.Second)))
+
+		settings := ""
+		if probe := container.LivenessProbe; probe != nil {
+			settings = fmt.Sprintf(" (timeout %ds, period %ds, failure threshold %d)",
+				defaultInt32(probe.TimeoutSeconds, 1), defaultInt32(probe.PeriodSeconds, 10), defaultInt32(probe.FailureThreshold, 3))
+		}
+		if t.timeouts {
+			recommendations = append(recommendations,
+				fmt.Sprintf("Raise timeoutSeconds and failureThreshold of the liveness probe of container %s%s; the probe times out, so slow but healthy responses restart it",
+					container.Name, settings))
+		} else {
+			recommendations = append(recommendations,
+				fmt.Sprintf("Tune the liveness probe of container %s%s: raise failureThreshold, add a startupProbe for slow starts, and make the endpoint check only the process itself, not its dependencies",
+					container.Name, settings))
+		}
+	}
+	return issues, recommendations
+}
+
+// probeTimedOut reports whether a probe failure message is a timeout rather than an
+// error response or a refused connection
+func probeTimedOut(message string) bool {
+	return strings.Contains(message, "context deadline exceeded") ||
+		strings.Contains(message, "Client.Timeout") ||
+		strings.Contains(message, "i/o timeout") ||
+		strings.Contains(message, "timed out")
+}
+
+// defaultInt32 returns value, or def when the API left the field unset
+func defaultInt32(value, def int32) int32 {
+	if value == 0 {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,176 @@
+package diagnostics
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// slowStartDelaySeconds is the liveness initialDelaySeconds from which a container is
+// treated as slow to start
+const slowStartDelaySeconds = 10
+
+// httpPortNames are port names that indicate the port serves HTTP
+var httpPortNames = map[string]bool{"http": true, "https": true, "web": true, "http-metrics": true}
+
+// httpPorts are port numbers that conventionally serve HTTP
+var httpPorts = map[int32]bool{80: true, 443: true, 8000: true, 8080: true, 8443: true}
+
+// ProbeInfo describes a probe configured on a container
+type ProbeInfo struct {
+	Container string
+	// Type is liveness, readiness or startup
+	Type string
+	// Scheme is HTTP, HTTPS, TCP, gRPC or Exec
+	Scheme string
+	// Target is the path and port, port or command the probe checks
+	Target              string
+	InitialDelaySeconds int32
+	PeriodSeconds       int32
+	TimeoutSeconds      int32
+}
+
+// analyzeProbes reports the probes of the given containers and flags common probe
+// misconfigurations
+func analyzeProbes(containers []corev1.Container) (probes []ProbeInfo, issues, recommendations []string) {
+	var immediateReadiness, timeoutOverPeriod bool
+	for _, container := range containers {
+		for _, probe := range []struct {
+			kind  string
+			probe *corev1.Probe
+		}{
+			{"liveness", container.LivenessProbe},
+			{"readiness", container.ReadinessProbe},
+			{"startup", container.StartupProbe},
+		} {
+			if probe.probe == nil {
+				continue
+			}
+			info := describeProbe(container.Name, probe.kind, probe.probe)
+			probes = append(probes, info)
+
+			if info.TimeoutSeconds > info.PeriodSeconds {
+				issues = append(issues,
+					fmt.Sprintf("Container %s %s probe timeout (%ds) is longer than its period (%ds)",
+						container.Name, probe.kind, info.TimeoutSeconds, info.PeriodSeconds))
+				timeoutOverPeriod = true
+			}
+
+			if handler := probe.probe.HTTPGet; handler != nil && !containerDeclaresPort(container, handler.Port) {
+				issues = append(issues,
+					fmt.Sprintf("Container %s %s probe checks port %s, which is not declared in the container ports",
+						container.Name, probe.kind, handler.Port.String()))
+				recommendations = append(recommendations,
+					fmt.Sprintf("Point the %s probe of container %s at a port the container declares", probe.kind, container.Name))
+			}
+
+			if handler := probe.probe.TCPSocket; handler != nil {
+				if probesHTTPPort(container, handler.Port) {
+					issues = append(issues,
+						fmt.Sprintf("Container %s %s probe only opens a TCP connection to HTTP port %s",
+							container.Name, probe.kind, handler.Port.String()))
+					recommendations = append(recommendations,
+						fmt.Sprintf("Use an httpGet %s probe on container %s so a hung application fails the check, not only a closed port",
+							probe.kind, container.Name))
+				}
+			}
+		}
+
+		// A readiness probe that starts immediately on an app that needs time to start
+		// fails until it is up, filling the events with probe failures
+		if readiness := container.ReadinessProbe; readiness != nil && readiness.InitialDelaySeconds == 0 &&
+			container.StartupProbe == nil && container.LivenessProbe != nil &&
+			container.LivenessProbe.InitialDelaySeconds >= slowStartDelaySeconds {
+			issues = append(issues,
+				fmt.Sprintf("Container %s readiness probe starts immediately although its liveness probe waits %ds for startup",
+					container.Name, container.LivenessProbe.InitialDelaySeconds))
+			immediateReadiness = true
+		}
+	}
+
+	if timeoutOverPeriod {
+		recommendations = append(recommendations,
+			"Set probe timeoutSeconds below periodSeconds so a slow check finishes before the next one starts")
+	}
+	if immediateReadiness {
+		recommendations = append(recommendations,
+			"Add a startupProbe or set initialDelaySeconds on readiness probes of slow-starting containers")
+	}
+	return probes, issues, recommendations
+}
+
+// describeProbe returns the scheme and target of a probe, applying the API defaults for
+// period and timeout
+func describeProbe(container, kind string, probe *corev1.Probe) ProbeInfo {
+	info := ProbeInfo{
+		Container:           container,
+		Type:                kind,
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		TimeoutSeconds:      probe.TimeoutSeconds,
+	}
+	if info.PeriodSeconds == 0 {
+		info.PeriodSeconds = 10
+	}
+	if info.TimeoutSeconds == 0 {
+		info.TimeoutSeconds = 1
+	}
+
+	switch {
+	case probe.HTTPGet != nil:
+		info.Scheme = "HTTP"
+		if probe.HTTPGet.Scheme == corev1.URISchemeHTTPS {
+			info.Scheme = "HTTPS"
+		}
+		path := probe.HTTPGet.Path
+		if path == "" {
+			path = "/"
+		}
+		info.Target = fmt.Sprintf("%s on port %s", path, probe.HTTPGet.Port.String())
+	case probe.TCPSocket != nil:
+		info.Scheme = "TCP"
+		info.Target = "port " + probe.TCPSocket.Port.String()
+	case probe.GRPC != nil:
+		info.Scheme = "gRPC"
+		info.Target = fmt.Sprintf("port %d", probe.GRPC.Port)
+		if probe.GRPC.Service != nil && *probe.GRPC.Service != "" {
+			info.Target += " service " + *probe.GRPC.Service
+		}
+	case probe.Exec != nil:
+		info.Scheme = "Exec"
+		info.Target = strings.Join(probe.Exec.Command, " ")
+	}
+	return info
+}
+
+// containerPort returns the declared container port matching a probe port, by name or number
+func containerPort(container corev1.Container, port intstr.IntOrString) *corev1.ContainerPort {
+	for i, declared := range container.Ports {
+		if port.Type == intstr.String && declared.Name == port.StrVal ||
+			port.Type == intstr.Int && declared.ContainerPort == port.IntVal {
+			return &container.Ports[i]
+		}
+	}
+	return nil
+}
+
+// containerDeclaresPort reports whether a probe port is declared by the container. A
+// numeric port is not flagged when the container declares no ports at all, since
+// declaring ports is optional and many images omit them.
+func containerDeclaresPort(container corev1.Container, port intstr.IntOrString) bool {
+	if containerPort(container, port) != nil {
+		return true
+	}
+	return port.Type == intstr.Int && len(container.Ports) == 0
+}
+
+// probesHTTPPort reports whether a probe port looks like it serves HTTP, by the name of
+// the declared port or by a conventional HTTP port number
+func probesHTTPPort(container corev1.Container, port intstr.IntOrString) bool {
+	if declared := containerPort(container, port); declared != nil {
+		return httpPortNames[strings.ToLower(declared.Name)] || httpPorts[declared.ContainerPort]
+	}
+	return port.Type == intstr.Int && httpPorts[port.IntVal]
+}
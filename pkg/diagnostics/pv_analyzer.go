@@ -0,0 +1,218 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// overProvisionedRatio is how many times larger than its claim's request a volume can be
+// before it is reported as over-provisioned
+const overProvisionedRatio = 2
+
+// PVAnalyzer provides analysis for PersistentVolume resources
+type PVAnalyzer struct {
+	client kubernetes.Interface
+}
+
+// NewPVAnalyzer creates a new PVAnalyzer
+func NewPVAnalyzer(client kubernetes.Interface) *PVAnalyzer {
+	return &PVAnalyzer{
+		client: client,
+	}
+}
+
+// PVReport contains the analysis report for a PersistentVolume
+type PVReport struct {
+	Name          string
+	Phase         corev1.PersistentVolumePhase
+	ReclaimPolicy corev1.PersistentVolumeReclaimPolicy
+	StorageClass  string
+	Capacity      resource.Quantity
+	AccessModes   []corev1.PersistentVolumeAccessMode
+	// Claim is the namespace/name of the claim the volume is bound or was last bound to
+	Claim    string
+	Analysis PVAnalysis
+}
+
+// PVAnalysis contains diagnostic results
+type PVAnalysis struct {
+	Status          string
+	Issues          []string
+	Recommendations []string
+}
+
+// PVSummary contains the analysis of every PersistentVolume in the cluster
+type PVSummary struct {
+	Volumes []*PVReport
+	// Phases counts the volumes in each phase
+	Phases map[corev1.PersistentVolumePhase]int
+	// StrandedBytes is the capacity of Released volumes, still provisioned but unusable
+	StrandedBytes int64
+}
+
+// Analyze performs the analysis of a PersistentVolume
+func (p *PVAnalyzer) Analyze(ctx context.Context, name string) (*PVReport, error) {
+	pv, err := p.client.CoreV1().PersistentVolumes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get persistent volume %s: %v", name, err)
+	}
+
+	var claim *corev1.PersistentVolumeClaim
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		claim, err = p.client.CoreV1().PersistentVolumeClaims(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			claim = nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get persistent volume claim %s/%s: %v", ref.Namespace, ref.Name, err)
+		}
+	}
+
+	return analyzePV(pv, claim, p.storageClasses(ctx)), nil
+}
+
+// AnalyzeAll analyzes every PersistentVolume in the cluster
+func (p *PVAnalyzer) AnalyzeAll(ctx context.Context) (*PVSummary, error) {
+	pvs, err := p.client.CoreV1().PersistentVolumes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volumes: %v", err)
+	}
+
+	pvcs, err := p.client.CoreV1().PersistentVolumeClaims(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persistent volume claims: %v", err)
+	}
+	claims := make(map[string]*corev1.PersistentVolumeClaim, len(pvcs.Items))
+	for i := range pvcs.Items {
+		claims[pvcs.Items[i].Namespace+"/"+pvcs.Items[i].Name] = &pvcs.Items[i]
+	}
+
+	storageClasses := p.storageClasses(ctx)
+	summary := &PVSummary{Phases: make(map[corev1.PersistentVolumePhase]int)}
+	for i := range pvs.Items {
+		pv := &pvs.Items[i]
+		var claim *corev1.PersistentVolumeClaim
+		if ref := pv.Spec.ClaimRef; ref != nil {
+			claim = claims[ref.Namespace+"/"+ref.Name]
+		}
+
+		report := analyzePV(pv, claim, storageClasses)
+		summary.Volumes = append(summary.Volumes, report)
+		summary.Phases[report.Phase]++
+		if report.Phase == corev1.VolumeReleased {
+			summary.StrandedBytes += report.Capacity.Value()
+		}
+	}
+
+	sort.Slice(summary.Volumes, func(i, j int) bool {
+		return summary.Volumes[i].Name < summary.Volumes[j].Name
+	})
+	return summary, nil
+}
+
+// storageClasses returns the names of the cluster's storage classes, or nil when they
+// cannot be listed, in which case storage classes are not checked
+func (p *PVAnalyzer) storageClasses(ctx context.Context) map[string]bool {
+	list, err := p.client.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil
+	}
+	names := make(map[string]bool, len(list.Items))
+	for _, storageClass := range list.Items {
+		names[storageClass.Name] = true
+	}
+	return names
+}
+
+// analyzePV checks a volume against the claim it references, which is nil when the
+// claim no longer exists
+func analyzePV(pv *corev1.PersistentVolume, claim *corev1.PersistentVolumeClaim, storageClasses map[string]bool) *PVReport {
+	report := &PVReport{
+		Name:          pv.Name,
+		Phase:         pv.Status.Phase,
+		ReclaimPolicy: pv.Spec.PersistentVolumeReclaimPolicy,
+		StorageClass:  pv.Spec.StorageClassName,
+		Capacity:      pv.Spec.Capacity[corev1.ResourceStorage],
+		AccessModes:   pv.Spec.AccessModes,
+	}
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		report.Claim = ref.Namespace + "/" + ref.Name
+	}
+	analysis := &report.Analysis
+
+	switch pv.Status.Phase {
+	case corev1.VolumeReleased:
+		if pv.Spec.PersistentVolumeReclaimPolicy == corev1.PersistentVolumeReclaimRetain {
+			analysis.Issues = append(analysis.Issues,
+				fmt.Sprintf("Volume is Released: claim %s was deleted, but the Retain policy keeps the volume and its storage, which is still billed and cannot be bound by a new claim", report.Claim))
+			analysis.Recommendations = append(analysis.Recommendations,
+				fmt.Sprintf("Back up any data still needed, then delete the volume and its backing storage, or clear spec.claimRef to let a new claim bind it: kubectl patch pv %s -p '{\"spec\":{\"claimRef\":null}}'", pv.Name))
+		} else {
+			analysis.Issues = append(analysis.Issues,
+				fmt.Sprintf("Volume is Released: claim %s was deleted, but the %s reclaim policy has not removed the volume", report.Claim, pv.Spec.PersistentVolumeReclaimPolicy))
+			analysis.Recommendations = append(analysis.Recommendations,
+				fmt.Sprintf("Check the events of the volume and the logs of its provisioner for why reclaiming is stuck: kubectl describe pv %s", pv.Name))
+		}
+	case corev1.VolumeFailed:
+		analysis.Issues = append(analysis.Issues,
+			fmt.Sprintf("Volume reclamation failed: %s", pv.Status.Message))
+		analysis.Recommendations = append(analysis.Recommendations,
+			"Fix the provisioner error and delete or reclaim the volume manually")
+	case corev1.VolumeAvailable:
+		if pv.Spec.PersistentVolumeReclaimPolicy == corev1.PersistentVolumeReclaimRetain {
+			analysis.Issues = append(analysis.Issues,
+				"Volume is Available but unclaimed; with the Retain policy its storage is kept until someone deletes it")
+		}
+	case corev1.VolumeBound:
+		if claim == nil {
+			analysis.Issues = append(analysis.Issues,
+				fmt.Sprintf("Volume is Bound to claim %s, which no longer exists", report.Claim))
+		}
+	}
+
+	if claim != nil && pv.Status.Phase == corev1.VolumeBound {
+		checkClaimCapacity(report, claim)
+	}
+
+	if report.StorageClass != "" && storageClasses != nil && !storageClasses[report.StorageClass] {
+		analysis.Issues = append(analysis.Issues,
+			fmt.Sprintf("StorageClass %s no longer exists, so the volume cannot be expanded and similar claims cannot be provisioned", report.StorageClass))
+	}
+
+	switch {
+	case pv.Status.Phase == corev1.VolumeFailed:
+		analysis.Status = "Unhealthy"
+	case len(analysis.Issues) > 0:
+		analysis.Status = "Needs Attention"
+	default:
+		analysis.Status = "Healthy"
+	}
+	return report
+}
+
+// checkClaimCapacity compares the capacity of a bound volume with what its claim requests
+func checkClaimCapacity(report *PVReport, claim *corev1.PersistentVolumeClaim) {
+	requested, ok := claim.Spec.Resources.Requests[corev1.ResourceStorage]
+	if !ok || report.Capacity.IsZero() {
+		return
+	}
+
+	switch {
+	case requested.Cmp(report.Capacity) > 0:
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Claim %s requests %s but the volume provides %s; a requested expansion has not completed",
+				report.Claim, requested.String(), report.Capacity.String()))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("Check the claim's conditions for a pending or failed resize: kubectl describe pvc %s -n %s", claim.Name, claim.Namespace))
+	case report.Capacity.Value() >= requested.Value()*overProvisionedRatio:
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Volume provides %s but claim %s only requests %s; the unused capacity is still provisioned",
+				report.Capacity.String(), report.Claim, requested.String()))
+	}
+}
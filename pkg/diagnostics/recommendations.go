@@ -0,0 +1,71 @@
+package diagnostics
+
+import "sort"
+
+// RecommendationGroup is one recommendation with every resource it was made for
+type RecommendationGroup struct {
+	Recommendation string   `json:"recommendation"`
+	Resources      []string `json:"resources"`
+}
+
+// RecommendationSummary deduplicates recommendations made for many resources, so a
+// recommendation repeated for every pod of a workload is reported once with the number
+// of resources it affects
+type RecommendationSummary struct {
+	groups    map[string]*RecommendationGroup
+	resources map[string]map[string]bool
+	order     []string
+}
+
+// NewRecommendationSummary creates an empty RecommendationSummary
+func NewRecommendationSummary() *RecommendationSummary {
+	return &RecommendationSummary{
+		groups:    make(map[string]*RecommendationGroup),
+		resources: make(map[string]map[string]bool),
+	}
+}
+
+// Add records recommendations made for a resource. A resource is counted once per
+// recommendation however often it is repeated.
+func (s *RecommendationSummary) Add(resource string, recommendations ...string) {
+	for _, recommendation := range recommendations {
+		if recommendation == "" {
+			continue
+		}
+		group, ok := s.groups[recommendation]
+		if !ok {
+			group = &RecommendationGroup{Recommendation: recommendation}
+			s.groups[recommendation] = group
+			s.resources[recommendation] = make(map[string]bool)
+			s.order = append(s.order, recommendation)
+		}
+		if !s.resources[recommendation][resource] {
+			s.resources[recommendation][resource] = true
+			group.Resources = append(group.Resources, resource)
+		}
+	}
+}
+
+// Groups returns the recommendations, the ones affecting the most resources first and
+// otherwise in the order they were first made
+func (s *RecommendationSummary) Groups() []RecommendationGroup {
+	groups := make([]RecommendationGroup, 0, len(s.order))
+	for _, recommendation := range s.order {
+		group := *s.groups[recommendation]
+		group.Resources = append([]string(nil), group.Resources...)
+		groups = append(groups, group)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].Resources) > len(groups[j].Resources)
+	})
+	return groups
+}
+
+// SummarizeRecommendations groups the remediations of findings by their text
+func SummarizeRecommendations(findings []Finding) []RecommendationGroup {
+	summary := NewRecommendationSummary()
+	for _, finding := range findings {
+		summary.Add(finding.Resource, finding.Remediation)
+	}
+	return summary.Groups()
+}
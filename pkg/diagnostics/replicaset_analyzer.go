@@ -0,0 +1,200 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ReplicaSetAnalyzer provides analysis for ReplicaSet resources
+type ReplicaSetAnalyzer struct {
+	client    kubernetes.Interface
+	namespace string
+	events    *EventCache
+	findings  *FindingCollector
+}
+
+// NewReplicaSetAnalyzer creates a new ReplicaSetAnalyzer
+func NewReplicaSetAnalyzer(client kubernetes.Interface, namespace string) *ReplicaSetAnalyzer {
+	return &ReplicaSetAnalyzer{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+// SetEventCache makes the analyzer look events up in a cache shared by the analyses
+// of a namespace instead of listing them for every replicaset
+func (r *ReplicaSetAnalyzer) SetEventCache(cache *EventCache) {
+	r.events = cache
+}
+
+// SetFindingCollector makes the analyzer emit the issues of each replicaset as findings
+func (r *ReplicaSetAnalyzer) SetFindingCollector(collector *FindingCollector) {
+	r.findings = collector
+}
+
+// ReplicaSetReport contains the analysis report for a ReplicaSet
+type ReplicaSetReport struct {
+	Name              string
+	Namespace         string
+	Revision          string
+	Selector          string
+	DesiredReplicas   int32
+	CurrentReplicas   int32
+	ReadyReplicas     int32
+	AvailableReplicas int32
+	// Owner is the controller of the replicaset, nil for a standalone replicaset
+	Owner *OwnerReference
+	// DeploymentRevision is the current revision of the owning Deployment, empty when
+	// there is none
+	DeploymentRevision string
+	Conditions         []appsv1.ReplicaSetCondition
+	PodTemplate        corev1.PodTemplateSpec
+	Events             []corev1.Event
+	Probes             []ProbeInfo
+	Analysis           ReplicaSetAnalysis
+}
+
+// ReplicaSetAnalysis contains diagnostic results
+type ReplicaSetAnalysis struct {
+	Status          string
+	Issues          []string
+	Recommendations []string
+}
+
+// Analyze performs the analysis of a ReplicaSet
+func (r *ReplicaSetAnalyzer) Analyze(ctx context.Context, replicaSetName string) (*ReplicaSetReport, error) {
+	rs, err := r.client.AppsV1().ReplicaSets(r.namespace).Get(ctx, replicaSetName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replicaset %s: %v", replicaSetName, err)
+	}
+
+	events, err := objectEvents(ctx, r.client, r.events, r.namespace, replicaSetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events for replicaset %s: %v", replicaSetName, err)
+	}
+
+	report := &ReplicaSetReport{
+		Name:              rs.Name,
+		Namespace:         rs.Namespace,
+		Revision:          rs.Annotations[revisionAnnotation],
+		Selector:          metav1.FormatLabelSelector(rs.Spec.Selector),
+		CurrentReplicas:   rs.Status.Replicas,
+		ReadyReplicas:     rs.Status.ReadyReplicas,
+		AvailableReplicas: rs.Status.AvailableReplicas,
+		Conditions:        rs.Status.Conditions,
+		PodTemplate:       rs.Spec.Template,
+		Events:            events,
+	}
+	if rs.Spec.Replicas != nil {
+		report.DesiredReplicas = *rs.Spec.Replicas
+	} else {
+		report.DesiredReplicas = 1
+	}
+
+	critical := r.analyzeReplicas(report)
+	r.analyzeOwner(ctx, report, rs)
+	r.analyzePodTemplate(report)
+
+	switch {
+	case critical:
+		report.Analysis.Status = "Unhealthy"
+	case len(report.Analysis.Issues) > 0:
+		report.Analysis.Status = "Needs Attention"
+	default:
+		report.Analysis.Status = "Healthy"
+	}
+
+	r.findings.Add(report.Findings()...)
+	return report, nil
+}
+
+// analyzeReplicas compares the ready replicas with the desired count and reports
+// ReplicaFailure conditions, such as pods rejected by a quota or admission webhook.
+// It returns whether the replicaset is failing to run its pods.
+func (r *ReplicaSetAnalyzer) analyzeReplicas(report *ReplicaSetReport) bool {
+	critical := false
+	for _, condition := range report.Conditions {
+		if condition.Type == appsv1.ReplicaSetReplicaFailure && condition.Status == corev1.ConditionTrue {
+			critical = true
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				fmt.Sprintf("ReplicaSet cannot create pods (%s): %s", condition.Reason, condition.Message))
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+				fmt.Sprintf("Check the resource quotas and admission policies of namespace %s", report.Namespace))
+		}
+	}
+
+	if report.ReadyReplicas < report.DesiredReplicas {
+		critical = true
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Ready replicas (%d) does not match desired replicas (%d)",
+				report.ReadyReplicas, report.DesiredReplicas))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("kubectl get pods -n %s -l %s  # then analyze the pods that are not ready", report.Namespace, report.Selector))
+	}
+	return critical
+}
+
+// analyzeOwner resolves the Deployment owning the replicaset, flagging standalone
+// replicasets, replicasets whose Deployment is gone, and old revisions that still run pods
+func (r *ReplicaSetAnalyzer) analyzeOwner(ctx context.Context, report *ReplicaSetReport, rs *appsv1.ReplicaSet) {
+	ref := metav1.GetControllerOf(rs)
+	if ref == nil {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("ReplicaSet %s is standalone: no Deployment owns it, so it gets no rolling updates or rollbacks", report.Name))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Manage the pods with a Deployment instead of a bare ReplicaSet, so image and config changes roll out safely")
+		return
+	}
+	report.Owner = &OwnerReference{Kind: ref.Kind, Name: ref.Name}
+	if ref.Kind != "Deployment" {
+		return
+	}
+
+	deployment, err := r.client.AppsV1().Deployments(r.namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("ReplicaSet %s is owned by Deployment %s, which no longer exists", report.Name, ref.Name))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("Delete the leftover replicaset with 'kubectl delete replicaset %s -n %s' once its pods are no longer needed",
+				report.Name, report.Namespace))
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	report.DeploymentRevision = deployment.Annotations[revisionAnnotation]
+	if report.Revision == "" || report.Revision == report.DeploymentRevision || report.CurrentReplicas == 0 {
+		return
+	}
+	report.Analysis.Issues = append(report.Analysis.Issues,
+		fmt.Sprintf("ReplicaSet %s is revision %s of Deployment %s, which is at revision %s, and still runs %d pod(s)",
+			report.Name, report.Revision, ref.Name, report.DeploymentRevision, report.CurrentReplicas))
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+		fmt.Sprintf("The rollout of Deployment %s has not finished; check it with 'kubectl rollout status deployment/%s -n %s'",
+			ref.Name, ref.Name, report.Namespace))
+}
+
+// analyzePodTemplate checks the probes and resources of the pod template
+func (r *ReplicaSetAnalyzer) analyzePodTemplate(report *ReplicaSetReport) {
+	containers := report.PodTemplate.Spec.Containers
+
+	probes, issues, recommendations := analyzeProbes(containers)
+	report.Probes = probes
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+
+	issues, recommendations = analyzeResourceRatios(containers)
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+
+	_, issues, recommendations = analyzeExtendedResources(containers)
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+}
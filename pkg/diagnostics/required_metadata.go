@@ -0,0 +1,38 @@
+package diagnostics
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CheckRequiredMetadata reports the required labels and annotations a resource is
+// missing. A key set to an empty value counts as missing.
+func CheckRequiredMetadata(resourceType string, object metav1.Object, requiredLabels, requiredAnnotations []string) (issues, recommendations []string) {
+	missingLabels := missingKeys(object.GetLabels(), requiredLabels)
+	if len(missingLabels) > 0 {
+		issues = append(issues, fmt.Sprintf("Missing required label(s): %s", strings.Join(missingLabels, ", ")))
+		recommendations = append(recommendations,
+			fmt.Sprintf("Add the labels %s to %s %s", strings.Join(missingLabels, ", "), resourceType, object.GetName()))
+	}
+
+	missingAnnotations := missingKeys(object.GetAnnotations(), requiredAnnotations)
+	if len(missingAnnotations) > 0 {
+		issues = append(issues, fmt.Sprintf("Missing required annotation(s): %s", strings.Join(missingAnnotations, ", ")))
+		recommendations = append(recommendations,
+			fmt.Sprintf("Add the annotations %s to %s %s", strings.Join(missingAnnotations, ", "), resourceType, object.GetName()))
+	}
+	return issues, recommendations
+}
+
+func missingKeys(values map[string]string, required []string) []string {
+	var missing []string
+	for _, key := range required {
+		key = strings.TrimSpace(key)
+		if key != "" && values[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,53 @@
+package diagnostics
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxMemoryLimitRatio is how many times its request a memory limit may be before the
+// gap is reported. The scheduler places pods by request, so a container using far more
+// than it requested takes memory the node promised to others.
+const maxMemoryLimitRatio = 10
+
+// analyzeResourceRatios flags containers whose limits are far from their requests:
+// memory limits many times the request, which overcommit the node and get neighbors
+// OOM killed, and CPU limits equal to the request, which throttle the container as
+// soon as it needs more than its share even on an idle node
+func analyzeResourceRatios(containers []corev1.Container) (issues, recommendations []string) {
+	var memoryRatios, cpuThrottled bool
+	for _, container := range containers {
+		if limit, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+			// A container without a request is given its limit as request
+			if request, ok := container.Resources.Requests[corev1.ResourceMemory]; ok && !request.IsZero() {
+				if ratio := float64(limit.Value()) / float64(request.Value()); ratio > maxMemoryLimitRatio {
+					memoryRatios = true
+					issues = append(issues,
+						fmt.Sprintf("Container %s memory limit (%s) is %.1fx its request (%s); bursting toward the limit takes memory other pods on the node were promised and risks OOM kills",
+							container.Name, limit.String(), ratio, request.String()))
+				}
+			}
+		}
+
+		if limit, ok := container.Resources.Limits[corev1.ResourceCPU]; ok && !limit.IsZero() {
+			request, ok := container.Resources.Requests[corev1.ResourceCPU]
+			if !ok || request.Cmp(limit) == 0 {
+				cpuThrottled = true
+				issues = append(issues,
+					fmt.Sprintf("Container %s CPU limit equals its request (%s, ratio 1.0x); it is throttled whenever it needs more, even when the node has idle CPU",
+						container.Name, limit.String()))
+			}
+		}
+	}
+
+	if memoryRatios {
+		recommendations = append(recommendations,
+			"Raise memory requests to the memory the containers actually use, or lower their limits, so the node is not overcommitted")
+	}
+	if cpuThrottled {
+		recommendations = append(recommendations,
+			"Raise CPU limits above the requests, or remove them, unless the Guaranteed QoS class is required")
+	}
+	return issues, recommendations
+}
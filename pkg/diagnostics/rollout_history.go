@@ -0,0 +1,73 @@
+package diagnostics
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+const (
+	revisionAnnotation    = "deployment.kubernetes.io/revision"
+	changeCauseAnnotation = "kubernetes.io/change-cause"
+)
+
+// RolloutRevision is one revision of a deployment, backed by a ReplicaSet
+type RolloutRevision struct {
+	Revision      int64
+	ReplicaSet    string
+	Images        []string
+	Replicas      int32
+	ReadyReplicas int32
+	Created       time.Time
+	ChangeCause   string
+	// Current is set for the revision the deployment is rolled out to
+	Current bool
+	// Idle is set for old revisions that still run pods
+	Idle bool
+}
+
+// RolloutHistory reconstructs the rollout history of the deployment from the
+// ReplicaSets it owns, oldest revision first
+func (r *DeploymentReport) RolloutHistory() []RolloutRevision {
+	var history []RolloutRevision
+	for _, rs := range r.ReplicaSets {
+		if !ownedByDeployment(rs, r.UID) {
+			continue
+		}
+
+		revision, _ := strconv.ParseInt(rs.Annotations[revisionAnnotation], 10, 64)
+		entry := RolloutRevision{
+			Revision:      revision,
+			ReplicaSet:    rs.Name,
+			Replicas:      rs.Status.Replicas,
+			ReadyReplicas: rs.Status.ReadyReplicas,
+			Created:       rs.CreationTimestamp.Time,
+			ChangeCause:   rs.Annotations[changeCauseAnnotation],
+			Current:       r.Revision != "" && rs.Annotations[revisionAnnotation] == r.Revision,
+		}
+		for _, container := range rs.Spec.Template.Spec.Containers {
+			entry.Images = append(entry.Images, container.Image)
+		}
+		entry.Idle = !entry.Current && rs.Status.Replicas > 0
+		history = append(history, entry)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		if history[i].Revision != history[j].Revision {
+			return history[i].Revision < history[j].Revision
+		}
+		return history[i].Created.Before(history[j].Created)
+	})
+	return history
+}
+
+func ownedByDeployment(rs appsv1.ReplicaSet, uid string) bool {
+	for _, ref := range rs.OwnerReferences {
+		if ref.Controller != nil && *ref.Controller && string(ref.UID) == uid {
+			return true
+		}
+	}
+	return false
+}
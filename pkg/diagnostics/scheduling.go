@@ -0,0 +1,351 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// explainScheduling checks a pending pod's tolerations, nodeSelector and required node
+// affinity against every node and explains which constraints rule the nodes out
+func explainScheduling(pod *corev1.Pod, nodes []corev1.Node) []string {
+	var reasons []string
+
+	untoleratedTaints := make(map[string]int)
+	unmatchedSelectors := make(map[string]int)
+	failingAffinity := 0
+	feasibleNodes := 0
+
+	for i := range nodes {
+		node := &nodes[i]
+		blocked := false
+
+		for _, taint := range untoleratedNodeTaints(pod, node) {
+			untoleratedTaints[formatTaint(taint)]++
+			blocked = true
+		}
+
+		for key, value := range pod.Spec.NodeSelector {
+			if nodeValue, ok := node.Labels[key]; !ok || nodeValue != value {
+				unmatchedSelectors[fmt.Sprintf("%s=%s", key, value)]++
+				blocked = true
+			}
+		}
+
+		if !matchesRequiredNodeAffinity(pod, node) {
+			failingAffinity++
+			blocked = true
+		}
+
+		if !blocked {
+			feasibleNodes++
+		}
+	}
+
+	totalNodes := len(nodes)
+	for _, taint := range sortedKeys(untoleratedTaints) {
+		reasons = append(reasons, fmt.Sprintf("Taint %s on %d of %d node(s) is not tolerated by the pod",
+			taint, untoleratedTaints[taint], totalNodes))
+	}
+	for _, selector := range sortedKeys(unmatchedSelectors) {
+		reasons = append(reasons, fmt.Sprintf("nodeSelector %s is not satisfied by %d of %d node(s)",
+			selector, unmatchedSelectors[selector], totalNodes))
+	}
+	if len(pod.Spec.NodeSelector) > 1 && len(unmatchedSelectors) > 0 {
+		reasons = append(reasons, explainCombinedNodeSelector(pod, nodes, unmatchedSelectors)...)
+	}
+	if failingAffinity > 0 {
+		reasons = append(reasons, fmt.Sprintf("Required node affinity is not satisfied by %d of %d node(s)",
+			failingAffinity, totalNodes))
+	}
+	if totalNodes > 0 {
+		reasons = append(reasons, explainNodeAffinity(pod, nodes, failingAffinity > 0)...)
+	}
+
+	if totalNodes == 0 {
+		reasons = append(reasons, "No nodes are registered in the cluster")
+	} else if feasibleNodes == 0 {
+		reasons = append([]string{fmt.Sprintf("No node matches the pod's placement constraints (%d node(s) checked)", totalNodes)}, reasons...)
+	} else {
+		reasons = append(reasons, fmt.Sprintf("%d of %d node(s) satisfy taints, nodeSelector and affinity - scheduling is likely blocked by resources or other constraints",
+			feasibleNodes, totalNodes))
+	}
+
+	return reasons
+}
+
+// explainCombinedNodeSelector reports a nodeSelector whose labels each match some node
+// but that no node carries all together
+func explainCombinedNodeSelector(pod *corev1.Pod, nodes []corev1.Node, unmatchedSelectors map[string]int) []string {
+	for _, count := range unmatchedSelectors {
+		if count == len(nodes) {
+			// A single label already excludes every node and is reported on its own
+			return nil
+		}
+	}
+	selector := labels.SelectorFromSet(pod.Spec.NodeSelector)
+	for i := range nodes {
+		if selector.Matches(labels.Set(nodes[i].Labels)) {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("nodeSelector %s matches 0 of %d node(s): each label is on some node, but no node has all of them",
+		selector.String(), len(nodes))}
+}
+
+// explainNodeAffinity counts the nodes matching each required node affinity term when
+// the required affinity rules nodes out and, for a term no node matches, pinpoints the
+// expressions that exclude every node. Preferred terms are reported along with them,
+// or on their own when they match no node and so have no effect.
+func explainNodeAffinity(pod *corev1.Pod, nodes []corev1.Node, requiredFailing bool) []string {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
+		return nil
+	}
+	affinity := pod.Spec.Affinity.NodeAffinity
+	totalNodes := len(nodes)
+
+	var reasons []string
+	if requiredFailing && affinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		for _, term := range affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			matching := countNodesMatchingTerm(term, nodes)
+			reasons = append(reasons, fmt.Sprintf("Required node affinity term %s matches %d of %d node(s)",
+				formatNodeSelectorTerm(term), matching, totalNodes))
+			if matching > 0 || len(term.MatchExpressions)+len(term.MatchFields) < 2 {
+				continue
+			}
+
+			excluding := 0
+			for _, expr := range term.MatchExpressions {
+				single := corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{expr}}
+				if countNodesMatchingTerm(single, nodes) == 0 {
+					excluding++
+					reasons = append(reasons, fmt.Sprintf("Required node affinity expression %s matches 0 of %d node(s) and excludes every node",
+						formatNodeSelectorRequirement(expr), totalNodes))
+				}
+			}
+			for _, expr := range term.MatchFields {
+				single := corev1.NodeSelectorTerm{MatchFields: []corev1.NodeSelectorRequirement{expr}}
+				if countNodesMatchingTerm(single, nodes) == 0 {
+					excluding++
+					reasons = append(reasons, fmt.Sprintf("Required node affinity field %s matches 0 of %d node(s) and excludes every node",
+						formatNodeSelectorRequirement(expr), totalNodes))
+				}
+			}
+			if excluding == 0 {
+				reasons = append(reasons, fmt.Sprintf("Required node affinity term %s: each expression matches some node, but no node matches all of them",
+					formatNodeSelectorTerm(term)))
+			}
+		}
+	}
+
+	for _, preferred := range affinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		matching := countNodesMatchingTerm(preferred.Preference, nodes)
+		if !requiredFailing && matching > 0 {
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("Preferred node affinity term %s (weight %d) matches %d of %d node(s); preferences never block scheduling",
+			formatNodeSelectorTerm(preferred.Preference), preferred.Weight, matching, totalNodes))
+	}
+	return reasons
+}
+
+func countNodesMatchingTerm(term corev1.NodeSelectorTerm, nodes []corev1.Node) int {
+	matching := 0
+	for i := range nodes {
+		if matchesNodeSelectorTerm(term, labels.Set(nodes[i].Labels), nodes[i].Name) {
+			matching++
+		}
+	}
+	return matching
+}
+
+// formatNodeSelectorTerm writes a node selector term in label selector syntax, e.g.
+// disktype=ssd,zone in (a,b), so it can be passed to kubectl get nodes -l
+func formatNodeSelectorTerm(term corev1.NodeSelectorTerm) string {
+	parts := make([]string, 0, len(term.MatchExpressions)+len(term.MatchFields))
+	for _, expr := range term.MatchExpressions {
+		parts = append(parts, formatNodeSelectorRequirement(expr))
+	}
+	for _, expr := range term.MatchFields {
+		parts = append(parts, formatNodeSelectorRequirement(expr))
+	}
+	if len(parts) == 0 {
+		return "(empty, matches no node)"
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatNodeSelectorRequirement(expr corev1.NodeSelectorRequirement) string {
+	values := strings.Join(expr.Values, ",")
+	switch expr.Operator {
+	case corev1.NodeSelectorOpIn:
+		if len(expr.Values) == 1 {
+			return expr.Key + "=" + values
+		}
+		return fmt.Sprintf("%s in (%s)", expr.Key, values)
+	case corev1.NodeSelectorOpNotIn:
+		if len(expr.Values) == 1 {
+			return expr.Key + "!=" + values
+		}
+		return fmt.Sprintf("%s notin (%s)", expr.Key, values)
+	case corev1.NodeSelectorOpExists:
+		return expr.Key
+	case corev1.NodeSelectorOpDoesNotExist:
+		return "!" + expr.Key
+	case corev1.NodeSelectorOpGt:
+		return expr.Key + ">" + values
+	case corev1.NodeSelectorOpLt:
+		return expr.Key + "<" + values
+	}
+	return fmt.Sprintf("%s %s (%s)", expr.Key, expr.Operator, values)
+}
+
+// untoleratedNodeTaints returns the scheduling-blocking taints on a node that the pod does not tolerate
+func untoleratedNodeTaints(pod *corev1.Pod, node *corev1.Node) []corev1.Taint {
+	var untolerated []corev1.Taint
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect != corev1.TaintEffectNoSchedule && taint.Effect != corev1.TaintEffectNoExecute {
+			continue
+		}
+
+		if !toleratesTaint(pod, taint) {
+			untolerated = append(untolerated, *taint)
+		}
+	}
+	return untolerated
+}
+
+// toleratesTaint reports whether any of the pod's tolerations matches the taint
+func toleratesTaint(pod *corev1.Pod, taint *corev1.Taint) bool {
+	for i := range pod.Spec.Tolerations {
+		if pod.Spec.Tolerations[i].ToleratesTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRequiredNodeAffinity evaluates requiredDuringSchedulingIgnoredDuringExecution;
+// terms are ORed and the expressions within a term are ANDed
+func matchesRequiredNodeAffinity(pod *corev1.Pod, node *corev1.Node) bool {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil ||
+		pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+
+	terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) == 0 {
+		return true
+	}
+
+	nodeLabels := labels.Set(node.Labels)
+	for _, term := range terms {
+		if matchesNodeSelectorTerm(term, nodeLabels, node.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesNodeSelectorTerm(term corev1.NodeSelectorTerm, nodeLabels labels.Set, nodeName string) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+
+	for _, expr := range term.MatchExpressions {
+		if !matchesNodeSelectorRequirement(expr, nodeLabels) {
+			return false
+		}
+	}
+
+	// metadata.name is the only field supported by matchFields
+	for _, expr := range term.MatchFields {
+		if expr.Key != "metadata.name" {
+			return false
+		}
+		if !matchesNodeSelectorRequirement(expr, labels.Set{expr.Key: nodeName}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesNodeSelectorRequirement(expr corev1.NodeSelectorRequirement, nodeLabels labels.Set) bool {
+	value, exists := nodeLabels[expr.Key]
+
+	switch expr.Operator {
+	case corev1.NodeSelectorOpIn:
+		return exists && containsString(expr.Values, value)
+	case corev1.NodeSelectorOpNotIn:
+		return !exists || !containsString(expr.Values, value)
+	case corev1.NodeSelectorOpExists:
+		return exists
+	case corev1.NodeSelectorOpDoesNotExist:
+		return !exists
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !exists || len(expr.Values) != 1 {
+			return false
+		}
+		nodeValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		exprValue, err := strconv.ParseInt(expr.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if expr.Operator == corev1.NodeSelectorOpGt {
+			return nodeValue > exprValue
+		}
+		return nodeValue < exprValue
+	}
+
+	return false
+}
+
+// schedulerReasons splits a FailedScheduling message such as
+// "0/3 nodes are available: 1 Insufficient cpu, 2 node(s) had untolerated taint {...}."
+// into its individual reasons
+func schedulerReasons(message string) []string {
+	idx := strings.Index(message, ": ")
+	if idx == -1 {
+		return []string{message}
+	}
+
+	// Drop the preemption hint the scheduler appends after the reasons
+	details := message[idx+2:]
+	if end := strings.Index(details, ". preemption:"); end != -1 {
+		details = details[:end]
+	}
+	details = strings.TrimSuffix(strings.TrimSpace(details), ".")
+
+	var reasons []string
+	for _, reason := range strings.Split(details, ", ") {
+		if reason = strings.TrimSpace(reason); reason != "" {
+			reasons = append(reasons, reason)
+		}
+	}
+	return reasons
+}
+
+func formatTaint(taint corev1.Taint) string {
+	if taint.Value == "" {
+		return fmt.Sprintf("%s:%s", taint.Key, taint.Effect)
+	}
+	return fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect)
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
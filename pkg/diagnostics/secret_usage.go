@@ -0,0 +1,278 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// SecretUsageAnalyzer cross-references the Secrets of a namespace with everything that
+// uses them, to find secrets nothing uses and references to secrets that do not exist
+type SecretUsageAnalyzer struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// NewSecretUsageAnalyzer creates a new SecretUsageAnalyzer
+func NewSecretUsageAnalyzer(client kubernetes.Interface, namespace string) *SecretUsageAnalyzer {
+	return &SecretUsageAnalyzer{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+// SecretReference is one use of a secret by a resource
+type SecretReference struct {
+	Secret string
+	// User is the referencing resource, e.g. Deployment/web
+	User string
+	// Via tells how the secret is used, e.g. "env DB_PASSWORD in container app"
+	Via string
+	// Optional references do not stop pods from starting when the secret is missing
+	Optional bool
+	// ServiceAccount is set for the token and image pull secrets listed on service accounts
+	ServiceAccount bool
+}
+
+// String describes the reference, e.g. "Deployment/web (volume certs)"
+func (r SecretReference) String() string {
+	return fmt.Sprintf("%s (%s)", r.User, r.Via)
+}
+
+// SecretUsage is a secret of the namespace and the resources using it
+type SecretUsage struct {
+	Name   string
+	Type   string
+	UsedBy []SecretReference
+	// Skipped is set for secrets managed by the control plane, Helm or an owning
+	// resource, which are never reported as unused
+	Skipped bool
+}
+
+// MissingSecret is a secret that is referenced but does not exist
+type MissingSecret struct {
+	Name       string
+	References []SecretReference
+}
+
+// SecretUsageReport contains the secret usage audit of a namespace
+type SecretUsageReport struct {
+	Namespace string
+	Secrets   []SecretUsage
+	Unused    []string
+	Missing   []MissingSecret
+	Analysis  SecretUsageAnalysis
+}
+
+// SecretUsageAnalysis contains diagnostic results
+type SecretUsageAnalysis struct {
+	Status          string
+	Issues          []string
+	Recommendations []string
+}
+
+// Analyze lists the secrets of the namespace and the references to them from pod
+// volumes, envFrom and secretKeyRef variables, image pull secrets, ingress TLS and
+// service accounts. Pods managed by a ReplicaSet, StatefulSet or DaemonSet are covered
+// through their controller's template, so workloads scaled to zero still count.
+func (s *SecretUsageAnalyzer) Analyze(ctx context.Context) (*SecretUsageReport, error) {
+	inventory, err := (&OrphanAnalyzer{client: s.client, namespace: s.namespace}).listInventory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	references := secretReferences(inventory)
+	used := make(map[string][]SecretReference)
+	for _, reference := range references {
+		used[reference.Secret] = append(used[reference.Secret], reference)
+	}
+
+	report := &SecretUsageReport{Namespace: s.namespace}
+	existing := make(map[string]bool)
+	for _, secret := range inventory.secrets {
+		existing[secret.Name] = true
+		usage := SecretUsage{
+			Name:    secret.Name,
+			Type:    string(secret.Type),
+			UsedBy:  used[secret.Name],
+			Skipped: len(secret.OwnerReferences) > 0 || managedSecretTypes[secret.Type],
+		}
+		report.Secrets = append(report.Secrets, usage)
+		if len(usage.UsedBy) == 0 && !usage.Skipped {
+			report.Unused = append(report.Unused, secret.Name)
+		}
+	}
+	sort.Slice(report.Secrets, func(i, j int) bool { return report.Secrets[i].Name < report.Secrets[j].Name })
+	sort.Strings(report.Unused)
+
+	for name, refs := range used {
+		if existing[name] {
+			continue
+		}
+		var required []SecretReference
+		for _, reference := range refs {
+			if !reference.Optional {
+				required = append(required, reference)
+			}
+		}
+		if len(required) > 0 {
+			report.Missing = append(report.Missing, MissingSecret{Name: name, References: required})
+		}
+	}
+	sort.Slice(report.Missing, func(i, j int) bool { return report.Missing[i].Name < report.Missing[j].Name })
+
+	s.analyze(report)
+	return report, nil
+}
+
+// managedSecretTypes are created and cleaned up by the control plane or Helm
+var managedSecretTypes = map[corev1.SecretType]bool{
+	corev1.SecretTypeServiceAccountToken: true,
+	corev1.SecretTypeBootstrapToken:      true,
+	"helm.sh/release.v1":                 true,
+}
+
+// secretReferences collects every reference to a secret in the inventory
+func secretReferences(inventory *orphanInventory) []SecretReference {
+	var references []SecretReference
+	for _, pod := range inventory.pods {
+		if owner := metav1.GetControllerOf(&pod); owner != nil {
+			switch owner.Kind {
+			case "ReplicaSet", "StatefulSet", "DaemonSet":
+				continue
+			}
+		}
+		references = append(references, podSpecSecretReferences(pod.Spec, "Pod/"+pod.Name)...)
+	}
+	for _, rs := range inventory.replicaSets {
+		if metav1.GetControllerOf(&rs) == nil {
+			references = append(references, podSpecSecretReferences(rs.Spec.Template.Spec, "ReplicaSet/"+rs.Name)...)
+		}
+	}
+	for _, deployment := range inventory.deployments {
+		references = append(references, podSpecSecretReferences(deployment.Spec.Template.Spec, "Deployment/"+deployment.Name)...)
+	}
+	for _, statefulSet := range inventory.statefulSets {
+		references = append(references, podSpecSecretReferences(statefulSet.Spec.Template.Spec, "StatefulSet/"+statefulSet.Name)...)
+	}
+	for _, daemonSet := range inventory.daemonSets {
+		references = append(references, podSpecSecretReferences(daemonSet.Spec.Template.Spec, "DaemonSet/"+daemonSet.Name)...)
+	}
+	for _, cronJob := range inventory.cronJobs {
+		references = append(references, podSpecSecretReferences(cronJob.Spec.JobTemplate.Spec.Template.Spec, "CronJob/"+cronJob.Name)...)
+	}
+
+	for _, ingress := range inventory.ingresses {
+		for _, tls := range ingress.Spec.TLS {
+			if tls.SecretName != "" {
+				references = append(references, SecretReference{Secret: tls.SecretName, User: "Ingress/" + ingress.Name,
+					Via: "TLS for " + strings.Join(tls.Hosts, ", ")})
+			}
+		}
+	}
+	for _, account := range inventory.accounts {
+		for _, secret := range account.Secrets {
+			references = append(references, SecretReference{Secret: secret.Name, User: "ServiceAccount/" + account.Name,
+				Via: "token secret", ServiceAccount: true})
+		}
+		for _, secret := range account.ImagePullSecrets {
+			references = append(references, SecretReference{Secret: secret.Name, User: "ServiceAccount/" + account.Name,
+				Via: "imagePullSecrets", ServiceAccount: true})
+		}
+	}
+	return references
+}
+
+// podSpecSecretReferences returns the secrets a pod spec uses through volumes,
+// environment variables and image pull secrets
+func podSpecSecretReferences(spec corev1.PodSpec, user string) []SecretReference {
+	var references []SecretReference
+	add := func(secret, via string, optional *bool) {
+		if secret == "" {
+			return
+		}
+		references = append(references, SecretReference{Secret: secret, User: user, Via: via,
+			Optional: optional != nil && *optional})
+	}
+
+	for _, secret := range spec.ImagePullSecrets {
+		add(secret.Name, "imagePullSecrets", nil)
+	}
+	for _, volume := range spec.Volumes {
+		if volume.Secret != nil {
+			add(volume.Secret.SecretName, "volume "+volume.Name, volume.Secret.Optional)
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.Secret != nil {
+					add(source.Secret.Name, "projected volume "+volume.Name, source.Secret.Optional)
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+	for _, container := range containers {
+		for _, source := range container.EnvFrom {
+			if source.SecretRef != nil {
+				add(source.SecretRef.Name, "envFrom in container "+container.Name, source.SecretRef.Optional)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				add(env.ValueFrom.SecretKeyRef.Name, fmt.Sprintf("env %s in container %s", env.Name, container.Name),
+					env.ValueFrom.SecretKeyRef.Optional)
+			}
+		}
+	}
+	return references
+}
+
+func (s *SecretUsageAnalyzer) analyze(report *SecretUsageReport) {
+	critical := false
+
+	for _, missing := range report.Missing {
+		users := make([]string, 0, len(missing.References))
+		workload := false
+		for _, reference := range missing.References {
+			users = append(users, reference.String())
+			workload = workload || !reference.ServiceAccount
+		}
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Secret %s does not exist but is referenced by %s", missing.Name, strings.Join(users, ", ")))
+		if workload {
+			// Pods referencing a missing secret without optional: true cannot start
+			critical = true
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+				fmt.Sprintf("Create secret %s in namespace %s, or fix the references to it; pods using it stay in ContainerCreating or CreateContainerConfigError",
+					missing.Name, report.Namespace))
+		} else {
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+				fmt.Sprintf("Remove the stale reference to secret %s from the service account, or recreate the secret", missing.Name))
+		}
+	}
+
+	for _, name := range report.Unused {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Secret %s is not used by any pod, workload template, ingress or service account", name))
+	}
+	if len(report.Unused) > 0 {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("Confirm no application reads the unused secrets through the API, then delete them: kubectl delete secret -n %s %s",
+				report.Namespace, strings.Join(report.Unused, " ")))
+	}
+
+	switch {
+	case critical:
+		report.Analysis.Status = "Unhealthy"
+	case len(report.Analysis.Issues) > 0:
+		report.Analysis.Status = "Needs Attention"
+	default:
+		report.Analysis.Status = "Healthy"
+	}
+}
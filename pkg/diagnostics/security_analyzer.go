@@ -31,6 +31,9 @@ type SecurityReport struct {
 	Issues          []SecurityIssue
 	Warnings        []SecurityWarning
 	Recommendations []string
+	// Suppressed counts the issues and warnings left out because the pod's ignore
+	// annotation lists their type
+	Suppressed int
 }
 
 // SecurityAnalysis contains security assessment results
@@ -40,8 +43,10 @@ type SecurityAnalysis struct {
 	Score     int
 }
 
-// SecurityIssue represents a security vulnerability
+// SecurityIssue represents a security vulnerability. Type names the check, as used by
+// the ignore annotation.
 type SecurityIssue struct {
+	Type        string
 	Level       string
 	Title       string
 	Description string
@@ -50,14 +55,15 @@ type SecurityIssue struct {
 
 // SecurityWarning represents a security warning
 type SecurityWarning struct {
+	Type        string
 	Level       string
 	Title       string
 	Description string
 }
 
 // AnalyzePodSecurity performs security analysis of a Pod
-func (s *SecurityAnalyzer) AnalyzePodSecurity(podName string) (*SecurityReport, error) {
-	pod, err := s.client.CoreV1().Pods(s.namespace).Get(context.TODO(), podName, metav1.GetOptions{})
+func (s *SecurityAnalyzer) AnalyzePodSecurity(ctx context.Context, podName string) (*SecurityReport, error) {
+	pod, err := s.client.CoreV1().Pods(s.namespace).Get(ctx, podName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pod %s: %v", podName, err)
 	}
@@ -69,6 +75,7 @@ func (s *SecurityAnalyzer) AnalyzePodSecurity(podName string) (*SecurityReport,
 
 	s.analyzeSecurityContext(report, pod)
 	s.analyzeContainerSecurity(report, pod)
+	report.suppressIgnored(pod.Annotations)
 	s.calculateRiskScore(report)
 
 	return report, nil
@@ -78,6 +85,7 @@ func (s *SecurityAnalyzer) analyzeSecurityContext(report *SecurityReport, pod *c
 	// Analyze pod-level security context
 	if pod.Spec.SecurityContext == nil {
 		report.Issues = append(report.Issues, SecurityIssue{
+			Type:        "MissingPodSecurityContext",
 			Level:       "High",
 			Title:       "No Pod Security Context",
 			Description: "Pod is running without any security context",
@@ -88,6 +96,7 @@ func (s *SecurityAnalyzer) analyzeSecurityContext(report *SecurityReport, pod *c
 
 		if sc.RunAsNonRoot == nil || !*sc.RunAsNonRoot {
 			report.Issues = append(report.Issues, SecurityIssue{
+				Type:        "RunAsRootAllowed",
 				Level:       "High",
 				Title:       "Running as Root",
 				Description: "Pod may be running as root user",
@@ -97,6 +106,7 @@ func (s *SecurityAnalyzer) analyzeSecurityContext(report *SecurityReport, pod *c
 
 		if sc.SeccompProfile == nil || sc.SeccompProfile.Type != corev1.SeccompProfileTypeRuntimeDefault {
 			report.Warnings = append(report.Warnings, SecurityWarning{
+				Type:        "MissingSeccompProfile",
 				Level:       "Medium",
 				Title:       "No Seccomp Profile",
 				Description: "Pod is not using runtime default seccomp profile",
@@ -110,6 +120,7 @@ func (s *SecurityAnalyzer) analyzeContainerSecurity(report *SecurityReport, pod
 		// Check container security context
 		if container.SecurityContext == nil {
 			report.Issues = append(report.Issues, SecurityIssue{
+				Type:        "MissingContainerSecurityContext",
 				Level:       "High",
 				Title:       fmt.Sprintf("Container %d: No Security Context", i),
 				Description: "Container is running without security context",
@@ -123,6 +134,7 @@ func (s *SecurityAnalyzer) analyzeContainerSecurity(report *SecurityReport, pod
 		// Check privilege escalation
 		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
 			report.Issues = append(report.Issues, SecurityIssue{
+				Type:        "PrivilegeEscalationAllowed",
 				Level:       "High",
 				Title:       fmt.Sprintf("Container %d: Privilege Escalation Allowed", i),
 				Description: "Container can escalate privileges",
@@ -133,6 +145,7 @@ func (s *SecurityAnalyzer) analyzeContainerSecurity(report *SecurityReport, pod
 		// Check read-only root filesystem
 		if sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
 			report.Warnings = append(report.Warnings, SecurityWarning{
+				Type:        "WritableRootFilesystem",
 				Level:       "Medium",
 				Title:       fmt.Sprintf("Container %d: Writable Root Filesystem", i),
 				Description: "Container has writable root filesystem",
@@ -142,6 +155,7 @@ func (s *SecurityAnalyzer) analyzeContainerSecurity(report *SecurityReport, pod
 		// Check privileged mode
 		if sc.Privileged != nil && *sc.Privileged {
 			report.Issues = append(report.Issues, SecurityIssue{
+				Type:        "PrivilegedContainer",
 				Level:       "Critical",
 				Title:       fmt.Sprintf("Container %d: Privileged Mode", i),
 				Description: "Container is running in privileged mode",
@@ -154,6 +168,7 @@ func (s *SecurityAnalyzer) analyzeContainerSecurity(report *SecurityReport, pod
 			for _, cap := range sc.Capabilities.Add {
 				if isDangerousCapability(string(cap)) {
 					report.Issues = append(report.Issues, SecurityIssue{
+						Type:        "DangerousCapability",
 						Level:       "High",
 						Title:       fmt.Sprintf("Container %d: Dangerous Capability %s", i, cap),
 						Description: "Container has dangerous capability added",
@@ -0,0 +1,212 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// analyzeSecurityDrift compares the security settings of the deployment's running pods
+// with the template they should run. Admission webhooks and manual
+// edits can change what actually runs; settings weaker than the template declares,
+// such as a privileged container or an added capability, are reported as weakened and
+// other differences as drift.
+func (d *DeploymentAnalyzer) analyzeSecurityDrift(report *DeploymentReport, replicaSets map[string]*appsv1.ReplicaSet, pods []corev1.Pod) {
+	type finding struct {
+		pods    int
+		example string
+	}
+	findings := make(map[string]*finding)
+	weakened := false
+	for _, pod := range pods {
+		ref := metav1.GetControllerOf(&pod)
+		if ref == nil || replicaSets[ref.Name] == nil || pod.DeletionTimestamp != nil {
+			continue
+		}
+		// Pods of the current revision are held to the deployment's template, which also
+		// catches edits to the ReplicaSet; older pods to the template they were created from
+		template := &replicaSets[ref.Name].Spec.Template.Spec
+		if report.Revision != "" && replicaSets[ref.Name].Annotations[revisionAnnotation] == report.Revision {
+			template = &report.PodTemplate.Spec
+		}
+		weaker, drift := securityContextDrift(template, &pod.Spec)
+		for _, difference := range weaker {
+			weakened = true
+			key := "Running pods have a weaker security context than their template: " + difference
+			if findings[key] == nil {
+				findings[key] = &finding{example: pod.Name}
+			}
+			findings[key].pods++
+		}
+		for _, difference := range drift {
+			key := "Running pods' security context drifted from their template: " + difference
+			if findings[key] == nil {
+				findings[key] = &finding{example: pod.Name}
+			}
+			findings[key].pods++
+		}
+	}
+	if len(findings) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(findings))
+	for key := range findings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("%s (%d pod(s), e.g. %s)", key, findings[key].pods, findings[key].example))
+	}
+	if weakened {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Find the mutating webhook or manual edit that weakened the running pods, and restart the deployment so they match the template")
+	} else {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Check which mutating webhook changes the pods' security context, and declare its settings in the template so the manifests show what runs")
+	}
+}
+
+// securityContextDrift compares the security settings of a running pod with its
+// template, returning the settings that are weaker than the template and the ones
+// that only differ
+func securityContextDrift(template, running *corev1.PodSpec) (weaker, drift []string) {
+	for _, field := range []struct {
+		name              string
+		template, running bool
+	}{
+		{"hostNetwork", template.HostNetwork, running.HostNetwork},
+		{"hostPID", template.HostPID, running.HostPID},
+		{"hostIPC", template.HostIPC, running.HostIPC},
+	} {
+		if field.running && !field.template {
+			weaker = append(weaker, field.name+" is enabled")
+		}
+	}
+	if !equality.Semantic.DeepEqual(template.SecurityContext, running.SecurityContext) {
+		drift = append(drift, "pod securityContext differs")
+	}
+
+	templateContainers := make(map[string]*corev1.Container)
+	for i := range template.Containers {
+		templateContainers[template.Containers[i].Name] = &template.Containers[i]
+	}
+	for i := range running.Containers {
+		container := &running.Containers[i]
+		declared, ok := templateContainers[container.Name]
+		if !ok {
+			drift = append(drift, fmt.Sprintf("container %s is not in the template", container.Name))
+			continue
+		}
+		differences := containerSecurityWeakened(template, declared, running, container)
+		for _, difference := range differences {
+			weaker = append(weaker, fmt.Sprintf("container %s %s", container.Name, difference))
+		}
+		if len(differences) == 0 && !equality.Semantic.DeepEqual(declared.SecurityContext, container.SecurityContext) {
+			drift = append(drift, fmt.Sprintf("container %s securityContext differs", container.Name))
+		}
+	}
+	return weaker, drift
+}
+
+// containerSecurityWeakened lists the security settings of a running container that
+// are weaker than its template declares, taking pod-level defaults into account
+func containerSecurityWeakened(templatePod *corev1.PodSpec, template *corev1.Container, runningPod *corev1.PodSpec, running *corev1.Container) []string {
+	declared := effectiveSecurity(templatePod, template)
+	actual := effectiveSecurity(runningPod, running)
+
+	var weaker []string
+	if actual.privileged && !declared.privileged {
+		weaker = append(weaker, "is privileged")
+	}
+	if actual.privilegeEscalation && !declared.privilegeEscalation {
+		weaker = append(weaker, "allows privilege escalation")
+	}
+	if declared.runAsNonRoot && !actual.runAsNonRoot {
+		weaker = append(weaker, "no longer requires a non-root user")
+	}
+	if actual.runsAsRoot && !declared.runsAsRoot {
+		weaker = append(weaker, "runs as root")
+	}
+	if declared.readOnlyRootFilesystem && !actual.readOnlyRootFilesystem {
+		weaker = append(weaker, "has a writable root filesystem")
+	}
+	if added := missingFrom(actual.added, declared.added); len(added) > 0 {
+		weaker = append(weaker, "adds capabilities "+strings.Join(added, ", "))
+	}
+	if kept := missingFrom(declared.dropped, actual.dropped); len(kept) > 0 {
+		weaker = append(weaker, "no longer drops capabilities "+strings.Join(kept, ", "))
+	}
+	return weaker
+}
+
+// containerSecurity is the security posture of a container with the pod-level
+// settings it inherits applied
+type containerSecurity struct {
+	privileged             bool
+	privilegeEscalation    bool
+	runAsNonRoot           bool
+	runsAsRoot             bool
+	readOnlyRootFilesystem bool
+	added                  []string
+	dropped                []string
+}
+
+func effectiveSecurity(pod *corev1.PodSpec, container *corev1.Container) containerSecurity {
+	// Privilege escalation is allowed unless explicitly disabled
+	security := containerSecurity{privilegeEscalation: true}
+	if pod.SecurityContext != nil {
+		if pod.SecurityContext.RunAsNonRoot != nil {
+			security.runAsNonRoot = *pod.SecurityContext.RunAsNonRoot
+		}
+		if pod.SecurityContext.RunAsUser != nil {
+			security.runsAsRoot = *pod.SecurityContext.RunAsUser == 0
+		}
+	}
+
+	securityContext := container.SecurityContext
+	if securityContext == nil {
+		return security
+	}
+	if securityContext.Privileged != nil {
+		security.privileged = *securityContext.Privileged
+	}
+	if securityContext.AllowPrivilegeEscalation != nil {
+		security.privilegeEscalation = *securityContext.AllowPrivilegeEscalation
+	}
+	if securityContext.RunAsNonRoot != nil {
+		security.runAsNonRoot = *securityContext.RunAsNonRoot
+	}
+	if securityContext.RunAsUser != nil {
+		security.runsAsRoot = *securityContext.RunAsUser == 0
+	}
+	if securityContext.ReadOnlyRootFilesystem != nil {
+		security.readOnlyRootFilesystem = *securityContext.ReadOnlyRootFilesystem
+	}
+	if securityContext.Capabilities != nil {
+		for _, capability := range securityContext.Capabilities.Add {
+			security.added = append(security.added, string(capability))
+		}
+		for _, capability := range securityContext.Capabilities.Drop {
+			security.dropped = append(security.dropped, string(capability))
+		}
+	}
+	return security
+}
+
+// missingFrom returns the values of values that are not in other
+func missingFrom(values, other []string) []string {
+	var missing []string
+	for _, value := range values {
+		if !containsString(other, value) {
+			missing = append(missing, value)
+		}
+	}
+	return missing
+}
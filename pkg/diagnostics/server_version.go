@@ -0,0 +1,50 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes"
+)
+
+// serverVersions caches the API server version of each client, so bulk runs analyzing
+// many resources with one client ask for it once
+var serverVersions sync.Map // kubernetes.Interface -> *cachedServerVersion
+
+type cachedServerVersion struct {
+	once sync.Once
+	info *version.Info
+	err  error
+}
+
+// serverVersion returns the version of the client's API server, fetched on the first
+// call for the client and reused afterwards
+func serverVersion(ctx context.Context, client kubernetes.Interface) (*version.Info, error) {
+	value, _ := serverVersions.LoadOrStore(client, &cachedServerVersion{})
+	cached := value.(*cachedServerVersion)
+	cached.once.Do(func() {
+		cached.info, cached.err = fetchServerVersion(ctx, client)
+	})
+	return cached.info, cached.err
+}
+
+// fetchServerVersion gets /version through the discovery REST client so the request
+// honors ctx, falling back to ServerVersion for clients without one, such as fakes
+func fetchServerVersion(ctx context.Context, client kubernetes.Interface) (*version.Info, error) {
+	discovery := client.Discovery()
+	rest := discovery.RESTClient()
+	if rest == nil {
+		return discovery.ServerVersion()
+	}
+	body, err := rest.Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return nil, err
+	}
+	var info version.Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
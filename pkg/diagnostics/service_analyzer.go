@@ -3,16 +3,25 @@ package diagnostics
 import (
 	"context"
 	"fmt"
+	"net"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 )
 
+// dnsLookupTimeout bounds the lookup of an ExternalName service's target
+const dnsLookupTimeout = 5 * time.Second
+
 // ServiceAnalyzer provides analysis for Service resources
 type ServiceAnalyzer struct {
 	client    kubernetes.Interface
 	namespace string
+	dnsLookup bool
+	events    *EventCache
 }
 
 // NewServiceAnalyzer creates a new ServiceAnalyzer
@@ -23,6 +32,18 @@ func NewServiceAnalyzer(client kubernetes.Interface, namespace string) *ServiceA
 	}
 }
 
+// SetEventCache makes the analyzer look events up in a cache shared by the analyses
+// of a namespace instead of listing them for every service
+func (s *ServiceAnalyzer) SetEventCache(cache *EventCache) {
+	s.events = cache
+}
+
+// SetDNSLookup enables resolving the target of ExternalName services from where
+// k8s-lens runs, which may differ from what pods in the cluster can resolve
+func (s *ServiceAnalyzer) SetDNSLookup(enabled bool) {
+	s.dnsLookup = enabled
+}
+
 // ServiceReport contains the analysis report for a Service
 type ServiceReport struct {
 	Name       string
@@ -30,11 +51,23 @@ type ServiceReport struct {
 	Type       corev1.ServiceType
 	ClusterIP  string
 	ExternalIP string
-	Ports      []corev1.ServicePort
-	Selector   map[string]string
-	Endpoints  *corev1.Endpoints
-	Events     []corev1.Event
-	Analysis   ServiceAnalysis
+	// ExternalName is the DNS name an ExternalName service aliases
+	ExternalName string
+	// Headless is set for services with ClusterIP None, which resolve to pod IPs directly
+	Headless bool
+	// StatefulSets are the StatefulSets using a headless service as their governing service
+	StatefulSets []string
+	// SessionAffinity and ExternalTrafficPolicy are reported for all services; the
+	// traffic policy only applies to LoadBalancer and NodePort services
+	SessionAffinity       corev1.ServiceAffinity
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy
+	// TrafficAdvisories are traffic behaviors worth knowing about that are not issues
+	TrafficAdvisories []string
+	Ports             []corev1.ServicePort
+	Selector          map[string]string
+	Endpoints         *corev1.Endpoints
+	Events            []corev1.Event
+	Analysis          ServiceAnalysis
 }
 
 // ServiceAnalysis contains diagnostic results
@@ -42,44 +75,72 @@ type ServiceAnalysis struct {
 	Status          string
 	Issues          []string
 	Recommendations []string
+	// TargetPortMismatches are named target ports some selected pods do not declare
+	TargetPortMismatches []TargetPortMismatch
 }
 
 // Analyze performs the analysis of a Service
-func (s *ServiceAnalyzer) Analyze(serviceName string) (*ServiceReport, error) {
+func (s *ServiceAnalyzer) Analyze(ctx context.Context, serviceName string) (*ServiceReport, error) {
 	// Get the service
-	service, err := s.client.CoreV1().Services(s.namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
+	service, err := s.client.CoreV1().Services(s.namespace).Get(ctx, serviceName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get service %s: %v", serviceName, err)
 	}
 
-	// Get endpoints
-	endpoints, err := s.client.CoreV1().Endpoints(s.namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to get endpoints for service %s: %v", serviceName, err)
+	// Get endpoints; ExternalName services are DNS aliases and have none
+	var endpoints *corev1.Endpoints
+	if service.Spec.Type != corev1.ServiceTypeExternalName {
+		endpoints, err = s.client.CoreV1().Endpoints(s.namespace).Get(ctx, serviceName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get endpoints for service %s: %v", serviceName, err)
+		}
 	}
 
 	// Get events
-	events, err := s.client.CoreV1().Events(s.namespace).List(context.TODO(), metav1.ListOptions{
-		FieldSelector: "involvedObject.name=" + serviceName,
-	})
+	events, err := objectEvents(ctx, s.client, s.events, s.namespace, serviceName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events for service %s: %v", serviceName, err)
 	}
 
 	report := &ServiceReport{
-		Name:       service.Name,
-		Namespace:  service.Namespace,
-		Type:       service.Spec.Type,
-		ClusterIP:  service.Spec.ClusterIP,
-		ExternalIP: s.getExternalIP(service),
-		Ports:      service.Spec.Ports,
-		Selector:   service.Spec.Selector,
-		Endpoints:  endpoints,
-		Events:     events.Items,
+		Name:                  service.Name,
+		Namespace:             service.Namespace,
+		Type:                  service.Spec.Type,
+		ClusterIP:             service.Spec.ClusterIP,
+		ExternalIP:            s.getExternalIP(service),
+		ExternalName:          service.Spec.ExternalName,
+		Headless:              service.Spec.ClusterIP == corev1.ClusterIPNone,
+		SessionAffinity:       service.Spec.SessionAffinity,
+		ExternalTrafficPolicy: service.Spec.ExternalTrafficPolicy,
+		Ports:                 service.Spec.Ports,
+		Selector:              service.Spec.Selector,
+		Endpoints:             endpoints,
+		Events:                events,
 	}
 
+	if report.Headless {
+		statefulSets, err := s.client.AppsV1().StatefulSets(s.namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list statefulsets in namespace %s: %v", s.namespace, err)
+		}
+		for _, statefulSet := range statefulSets.Items {
+			if statefulSet.Spec.ServiceName == service.Name {
+				report.StatefulSets = append(report.StatefulSets, statefulSet.Name)
+			}
+		}
+	}
+
+	if report.Type == corev1.ServiceTypeExternalName {
+		s.analyzeExternalNameService(ctx, report)
+		return report, nil
+	}
+
+	if err := s.analyzeTargetPorts(ctx, report); err != nil {
+		return nil, err
+	}
 	s.analyzeService(report)
 	s.analyzeEndpoints(report)
+	s.analyzeTrafficPolicy(ctx, report)
 
 	return report, nil
 }
@@ -94,7 +155,32 @@ func (s *ServiceAnalyzer) getExternalIP(service *corev1.Service) string {
 	return ""
 }
 
+// analyzeTargetPorts checks the service's named target ports against the container
+// ports of the pods its selector matches
+func (s *ServiceAnalyzer) analyzeTargetPorts(ctx context.Context, report *ServiceReport) error {
+	if len(report.Selector) == 0 {
+		return nil
+	}
+	pods, err := s.client.CoreV1().Pods(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: metav1.FormatLabelSelector(&metav1.LabelSelector{MatchLabels: report.Selector}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get pods for service %s: %v", report.Name, err)
+	}
+
+	report.Analysis.TargetPortMismatches = findTargetPortMismatches(report.Ports, pods.Items)
+	issues, recommendations := targetPortMismatchIssues(report.Analysis.TargetPortMismatches, len(pods.Items))
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+	return nil
+}
+
 func (s *ServiceAnalyzer) analyzeService(report *ServiceReport) {
+	// Headless services have no cluster IP by design
+	if report.Headless {
+		s.analyzeHeadlessService(report)
+	}
+
 	// Check service type specific issues
 	switch report.Type {
 	case corev1.ServiceTypeLoadBalancer:
@@ -114,8 +200,8 @@ func (s *ServiceAnalyzer) analyzeService(report *ServiceReport) {
 		}
 	}
 
-	// Check selector
-	if len(report.Selector) == 0 {
+	// Check selector; headless services are validated separately
+	if len(report.Selector) == 0 && !report.Headless {
 		report.Analysis.Issues = append(report.Analysis.Issues,
 			"Service has no selector configured")
 	}
@@ -133,6 +219,125 @@ func (s *ServiceAnalyzer) analyzeService(report *ServiceReport) {
 	}
 }
 
+// analyzeHeadlessService checks a headless service has something to resolve to: pods
+// matched by its selector, or the pods of a StatefulSet it governs
+func (s *ServiceAnalyzer) analyzeHeadlessService(report *ServiceReport) {
+	if len(report.StatefulSets) > 0 {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			fmt.Sprintf("Headless service governs StatefulSet(s) %s", strings.Join(report.StatefulSets, ", ")))
+		if len(report.Selector) == 0 {
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				"Headless service has no selector, so no DNS records are created for the StatefulSet pods")
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+				"Set the service selector to the StatefulSet's pod labels")
+		}
+		return
+	}
+
+	if len(report.Selector) == 0 {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			"Headless service has no selector and is not used by any StatefulSet")
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Add a selector, or reference the service in a StatefulSet's serviceName")
+	}
+}
+
+// analyzeExternalNameService validates the DNS name an ExternalName service aliases
+// and flags fields that Kubernetes ignores for this service type
+func (s *ServiceAnalyzer) analyzeExternalNameService(ctx context.Context, report *ServiceReport) {
+	name := strings.TrimSuffix(report.ExternalName, ".")
+	switch {
+	case name == "":
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			"ExternalName service has no externalName configured")
+	case net.ParseIP(name) != nil:
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("ExternalName %s is an IP address; it is published as a CNAME and will not resolve", name))
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Use a ClusterIP service without a selector and a manually managed Endpoints object for IP targets")
+	case len(validation.IsDNS1123Subdomain(name)) > 0:
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("ExternalName %s is not a valid DNS name", name))
+	case s.dnsLookup:
+		ctx, cancel := context.WithTimeout(ctx, dnsLookupTimeout)
+		defer cancel()
+		if _, err := net.DefaultResolver.LookupHost(ctx, name); err != nil {
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				fmt.Sprintf("ExternalName %s does not resolve: %v", name, err))
+			report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+				"Check the external DNS name is correct and resolvable from the cluster")
+		}
+	}
+	// Problems with the name itself break the service; the fields below are only misleading
+	nameIssues := len(report.Analysis.Issues)
+
+	if len(report.Selector) > 0 {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			"ExternalName service has a selector, which is ignored for this service type")
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Remove the selector, or change the service type if it should route to pods")
+	}
+	if len(report.Ports) > 0 {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			"ExternalName service has ports configured, which are not used for routing")
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Clients connect to the external name on its own ports; remove the ports to avoid confusion")
+	}
+
+	switch {
+	case nameIssues > 0:
+		report.Analysis.Status = "Unhealthy"
+	case len(report.Analysis.Issues) > 0:
+		report.Analysis.Status = "Needs Attention"
+	default:
+		report.Analysis.Status = "Healthy"
+	}
+}
+
+// analyzeTrafficPolicy explains how externalTrafficPolicy and sessionAffinity affect
+// the traffic of externally exposed services
+func (s *ServiceAnalyzer) analyzeTrafficPolicy(ctx context.Context, report *ServiceReport) {
+	if report.Type != corev1.ServiceTypeLoadBalancer && report.Type != corev1.ServiceTypeNodePort {
+		return
+	}
+
+	switch report.ExternalTrafficPolicy {
+	case corev1.ServiceExternalTrafficPolicyLocal:
+		endpointNodes := make(map[string]bool)
+		if report.Endpoints != nil {
+			for _, subset := range report.Endpoints.Subsets {
+				for _, address := range subset.Addresses {
+					if address.NodeName != nil {
+						endpointNodes[*address.NodeName] = true
+					}
+				}
+			}
+		}
+
+		// Nodes are cluster-scoped and may not be readable, in which case the spread is unknown
+		nodes, err := s.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			report.TrafficAdvisories = append(report.TrafficAdvisories,
+				"externalTrafficPolicy Local only serves traffic on nodes running a ready pod of this service")
+		} else if len(endpointNodes) < len(nodes.Items) {
+			advisory := fmt.Sprintf("externalTrafficPolicy Local: only %d of %d nodes run a ready pod of this service", len(endpointNodes), len(nodes.Items))
+			if report.Type == corev1.ServiceTypeNodePort {
+				advisory += "; traffic sent to the NodePort on other nodes is dropped"
+			} else {
+				advisory += "; the load balancer must health-check nodes to avoid sending traffic to the others"
+			}
+			report.TrafficAdvisories = append(report.TrafficAdvisories, advisory)
+		}
+	default:
+		report.TrafficAdvisories = append(report.TrafficAdvisories,
+			"externalTrafficPolicy Cluster masks client source IPs behind node IPs, which breaks IP-based rate limiting and allow-lists; use Local if the application needs them")
+		if report.SessionAffinity == corev1.ServiceAffinityClientIP {
+			report.TrafficAdvisories = append(report.TrafficAdvisories,
+				"sessionAffinity ClientIP with externalTrafficPolicy Cluster is keyed on the address seen by the node, which may be a proxy or load balancer rather than the client")
+		}
+	}
+}
+
 func (s *ServiceAnalyzer) analyzeEndpoints(report *ServiceReport) {
 	if report.Endpoints == nil {
 		report.Analysis.Issues = append(report.Analysis.Issues,
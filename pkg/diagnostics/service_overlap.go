@@ -0,0 +1,245 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// appLabels are the pod labels that identify an application, in order of preference
+var appLabels = []string{"app.kubernetes.io/name", "app", "k8s-app"}
+
+// ServiceOverlapReport contains the services of a namespace whose selectors overlap
+type ServiceOverlapReport struct {
+	Namespace     string
+	TotalServices int
+	// SameSelection are groups of services that select exactly the same pods
+	SameSelection []ServiceOverlap
+	// PartialOverlaps are pairs of services that share some, but not all, of their pods
+	PartialOverlaps []ServiceOverlap
+	MixedApps       []MixedAppService
+	Analysis        ServiceAnalysis
+}
+
+// ServiceOverlap is a set of services selecting the same pods
+type ServiceOverlap struct {
+	Services []string
+	Pods     []string
+	// IdenticalSelectors is set when all the services have the same selector
+	IdenticalSelectors bool
+}
+
+// MixedAppService is a service whose selector matches pods of different applications
+type MixedAppService struct {
+	Service string
+	Apps    []string
+}
+
+// serviceSelection is the set of pods a service selects
+type serviceSelection struct {
+	name     string
+	selector string
+	headless bool
+	pods     map[string]bool
+	apps     map[string]bool
+}
+
+// AnalyzeNamespaceServices finds services in the namespace whose selectors route to the
+// same pods, and services whose selectors match pods of different applications.
+// Services without a selector are skipped, as their endpoints are managed manually.
+func (s *ServiceAnalyzer) AnalyzeNamespaceServices(ctx context.Context) (*ServiceOverlapReport, error) {
+	services, err := s.client.CoreV1().Services(s.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services in namespace %s: %v", s.namespace, err)
+	}
+
+	pods, err := s.client.CoreV1().Pods(s.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace %s: %v", s.namespace, err)
+	}
+
+	report := &ServiceOverlapReport{
+		Namespace:     s.namespace,
+		TotalServices: len(services.Items),
+	}
+
+	var selections []*serviceSelection
+	for _, service := range services.Items {
+		if len(service.Spec.Selector) == 0 {
+			continue
+		}
+
+		selector := labels.SelectorFromSet(service.Spec.Selector)
+		selection := &serviceSelection{
+			name:     service.Name,
+			selector: selector.String(),
+			headless: service.Spec.ClusterIP == corev1.ClusterIPNone,
+			pods:     make(map[string]bool),
+			apps:     make(map[string]bool),
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			if !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			selection.pods[pod.Name] = true
+			if app := podApp(pod.Labels); app != "" {
+				selection.apps[app] = true
+			}
+		}
+		if len(selection.pods) > 0 {
+			selections = append(selections, selection)
+		}
+	}
+	sort.Slice(selections, func(i, j int) bool {
+		return selections[i].name < selections[j].name
+	})
+
+	s.findOverlaps(report, selections)
+
+	for _, selection := range selections {
+		if len(selection.apps) > 1 {
+			report.MixedApps = append(report.MixedApps, MixedAppService{
+				Service: selection.name,
+				Apps:    sortedSet(selection.apps),
+			})
+		}
+	}
+
+	s.analyzeOverlaps(report)
+	return report, nil
+}
+
+// findOverlaps groups services selecting the same pods and pairs services sharing only
+// some pods. A headless service and a regular service over the same pods is the usual
+// StatefulSet setup, so overlaps are only compared between services of the same kind.
+func (s *ServiceAnalyzer) findOverlaps(report *ServiceOverlapReport, selections []*serviceSelection) {
+	groups := make(map[string][]*serviceSelection)
+	var groupKeys []string
+	for _, selection := range selections {
+		key := fmt.Sprintf("%t/%s", selection.headless, strings.Join(sortedSet(selection.pods), ","))
+		if groups[key] == nil {
+			groupKeys = append(groupKeys, key)
+		}
+		groups[key] = append(groups[key], selection)
+	}
+
+	for _, key := range groupKeys {
+		group := groups[key]
+		if len(group) < 2 {
+			continue
+		}
+		overlap := ServiceOverlap{
+			Pods:               sortedSet(group[0].pods),
+			IdenticalSelectors: true,
+		}
+		for _, selection := range group {
+			overlap.Services = append(overlap.Services, selection.name)
+			if selection.selector != group[0].selector {
+				overlap.IdenticalSelectors = false
+			}
+		}
+		report.SameSelection = append(report.SameSelection, overlap)
+	}
+
+	for i, a := range selections {
+		for _, b := range selections[i+1:] {
+			if a.headless != b.headless || sameSet(a.pods, b.pods) {
+				continue
+			}
+			var shared []string
+			for pod := range a.pods {
+				if b.pods[pod] {
+					shared = append(shared, pod)
+				}
+			}
+			if len(shared) == 0 {
+				continue
+			}
+			sort.Strings(shared)
+			report.PartialOverlaps = append(report.PartialOverlaps, ServiceOverlap{
+				Services: []string{a.name, b.name},
+				Pods:     shared,
+			})
+		}
+	}
+}
+
+func (s *ServiceAnalyzer) analyzeOverlaps(report *ServiceOverlapReport) {
+	for _, overlap := range report.SameSelection {
+		if overlap.IdenticalSelectors {
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				fmt.Sprintf("Services %s have identical selectors and route to the same %d pod(s)",
+					strings.Join(overlap.Services, ", "), len(overlap.Pods)))
+		} else {
+			report.Analysis.Issues = append(report.Analysis.Issues,
+				fmt.Sprintf("Services %s select exactly the same %d pod(s)",
+					strings.Join(overlap.Services, ", "), len(overlap.Pods)))
+		}
+	}
+
+	for _, overlap := range report.PartialOverlaps {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Services %s and %s both select pod(s) %s",
+				overlap.Services[0], overlap.Services[1], strings.Join(overlap.Pods, ", ")))
+	}
+
+	for _, mixed := range report.MixedApps {
+		report.Analysis.Issues = append(report.Analysis.Issues,
+			fmt.Sprintf("Service %s selects pods of different apps: %s",
+				mixed.Service, strings.Join(mixed.Apps, ", ")))
+	}
+
+	if len(report.SameSelection) > 0 || len(report.PartialOverlaps) > 0 {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Check overlapping services for copy-pasted selectors; remove duplicates or make each selector specific to one workload")
+	}
+	if len(report.MixedApps) > 0 {
+		report.Analysis.Recommendations = append(report.Analysis.Recommendations,
+			"Add an app-specific label to service selectors so traffic is not routed to unrelated pods")
+	}
+
+	if len(report.Analysis.Issues) == 0 {
+		report.Analysis.Status = "Healthy"
+	} else {
+		report.Analysis.Status = "Needs Review"
+	}
+}
+
+// podApp returns the application a pod belongs to from its well-known app labels
+func podApp(podLabels map[string]string) string {
+	for _, key := range appLabels {
+		if value := podLabels[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func sortedSet(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sameSet(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key := range a {
+		if !b[key] {
+			return false
+		}
+	}
+	return true
+}
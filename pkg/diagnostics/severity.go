@@ -0,0 +1,62 @@
+package diagnostics
+
+// Finding severities, from least to most severe
+const (
+	SeverityHealthy  = "Healthy"
+	SeverityWarning  = "Warning"
+	SeverityCritical = "Critical"
+)
+
+var (
+	// criticalStatuses are analyzer statuses meaning the resource is broken
+	criticalStatuses = map[string]bool{
+		"Unhealthy":         true,
+		"Degraded":          true,
+		"Vulnerable":        true,
+		"Highly Vulnerable": true,
+		"Critical":          true,
+		"Error":             true,
+	}
+
+	// warningStatuses are analyzer statuses meaning the resource needs attention
+	warningStatuses = map[string]bool{
+		"Progressing":                          true,
+		"Needs Attention":                      true,
+		"Needs Review":                         true,
+		"Needs Improvement":                    true,
+		"Running but not all containers ready": true,
+		"No network policies":                  true,
+	}
+)
+
+// SeverityForStatus maps an analyzer status and its issue count to a severity.
+// Unknown statuses are treated as healthy unless issues were found.
+func SeverityForStatus(status string, issueCount int) string {
+	switch {
+	case criticalStatuses[status]:
+		return SeverityCritical
+	case warningStatuses[status] || issueCount > 0:
+		return SeverityWarning
+	}
+	return SeverityHealthy
+}
+
+// SeverityForRiskLevel maps a Low/Medium/High/Critical risk level to a severity
+func SeverityForRiskLevel(riskLevel string) string {
+	switch riskLevel {
+	case "Critical", "High":
+		return SeverityCritical
+	case "Medium":
+		return SeverityWarning
+	}
+	return SeverityHealthy
+}
+
+// MaxSeverity returns the more severe of two severities
+func MaxSeverity(a, b string) string {
+	rank := map[string]int{SeverityHealthy: 0, SeverityWarning: 1, SeverityCritical: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
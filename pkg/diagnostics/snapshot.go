@@ -0,0 +1,309 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// NamespaceSnapshotter records the analysis of every workload in a namespace as a
+// snapshot, which can be saved as a golden report and compared against later
+type NamespaceSnapshotter struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// NewNamespaceSnapshotter creates a new NamespaceSnapshotter
+func NewNamespaceSnapshotter(client kubernetes.Interface, namespace string) *NamespaceSnapshotter {
+	return &NamespaceSnapshotter{
+		client:    client,
+		namespace: namespace,
+	}
+}
+
+// NamespaceSnapshot is the normalized analysis of a namespace. Workloads are keyed by
+// "kind/name" and their issues and findings are sorted, so two snapshots of an
+// unchanged namespace encode to the same JSON apart from CreatedAt.
+type NamespaceSnapshot struct {
+	Namespace string                       `json:"namespace"`
+	CreatedAt time.Time                    `json:"createdAt"`
+	Workloads map[string]*WorkloadSnapshot `json:"workloads"`
+}
+
+// WorkloadSnapshot is the analysis of one workload or service in a snapshot
+type WorkloadSnapshot struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// DesiredReplicas and ReadyReplicas are set for deployments and statefulsets
+	DesiredReplicas *int32   `json:"desiredReplicas,omitempty"`
+	ReadyReplicas   *int32   `json:"readyReplicas,omitempty"`
+	Issues          []string `json:"issues,omitempty"`
+	// SecurityFindings are the security issues of the workload's pods, as "Level: Title"
+	SecurityFindings []string `json:"securityFindings,omitempty"`
+	Error            string   `json:"error,omitempty"`
+}
+
+// SnapshotComparison lists what changed between a saved snapshot and the current state
+type SnapshotComparison struct {
+	Regressions  []SnapshotChange
+	Improvements []SnapshotChange
+}
+
+// SnapshotChange is one difference between two snapshots
+type SnapshotChange struct {
+	// Resource is the "kind/name" key of the workload
+	Resource string
+	Detail   string
+}
+
+// Snapshot analyzes the deployments, statefulsets, services and bare pods of the
+// namespace, and the security of every pod, attributing pod findings to their owner
+func (n *NamespaceSnapshotter) Snapshot(ctx context.Context) (*NamespaceSnapshot, error) {
+	snapshot := &NamespaceSnapshot{
+		Namespace: n.namespace,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Workloads: make(map[string]*WorkloadSnapshot),
+	}
+
+	// Events of the whole namespace are listed once and shared by the analyzers
+	events, err := NewEventCache(ctx, n.client, n.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	deployments, err := n.client.AppsV1().Deployments(n.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments: %v", err)
+	}
+	deploymentAnalyzer := NewDeploymentAnalyzer(n.client, n.namespace)
+	deploymentAnalyzer.SetEventCache(events)
+	for _, deployment := range deployments.Items {
+		workload := &WorkloadSnapshot{Kind: "deployment", Name: deployment.Name}
+		if report, err := deploymentAnalyzer.Analyze(ctx, deployment.Name); err != nil {
+			workload.Status, workload.Error = "Error", err.Error()
+		} else {
+			workload.Status, workload.Issues = report.Analysis.Status, report.Analysis.Issues
+			workload.DesiredReplicas, workload.ReadyReplicas = &report.DesiredReplicas, &report.ReadyReplicas
+		}
+		snapshot.add(workload)
+	}
+
+	statefulSets, err := n.client.AppsV1().StatefulSets(n.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets: %v", err)
+	}
+	statefulSetAnalyzer := NewStatefulSetAnalyzer(n.client, n.namespace)
+	statefulSetAnalyzer.SetEventCache(events)
+	for _, statefulSet := range statefulSets.Items {
+		workload := &WorkloadSnapshot{Kind: "statefulset", Name: statefulSet.Name}
+		if report, err := statefulSetAnalyzer.Analyze(ctx, statefulSet.Name); err != nil {
+			workload.Status, workload.Error = "Error", err.Error()
+		} else {
+			workload.Status, workload.Issues = report.Analysis.Status, report.Analysis.Issues
+			workload.DesiredReplicas, workload.ReadyReplicas = &report.DesiredReplicas, &report.ReadyReplicas
+		}
+		snapshot.add(workload)
+	}
+
+	services, err := n.client.CoreV1().Services(n.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %v", err)
+	}
+	serviceAnalyzer := NewServiceAnalyzer(n.client, n.namespace)
+	serviceAnalyzer.SetEventCache(events)
+	for _, service := range services.Items {
+		workload := &WorkloadSnapshot{Kind: "service", Name: service.Name}
+		if report, err := serviceAnalyzer.Analyze(ctx, service.Name); err != nil {
+			workload.Status, workload.Error = "Error", err.Error()
+		} else {
+			workload.Status, workload.Issues = report.Analysis.Status, report.Analysis.Issues
+		}
+		snapshot.add(workload)
+	}
+
+	if err := n.snapshotPods(ctx, snapshot, events); err != nil {
+		return nil, err
+	}
+
+	for _, workload := range snapshot.Workloads {
+		sort.Strings(workload.Issues)
+		sort.Strings(workload.SecurityFindings)
+	}
+	return snapshot, nil
+}
+
+func (s *NamespaceSnapshot) add(workload *WorkloadSnapshot) {
+	s.Workloads[workload.Kind+"/"+workload.Name] = workload
+}
+
+// snapshotPods adds the security findings of every pod to its owning workload. Pods
+// come and go under their controllers, so only bare pods are recorded on their own.
+func (n *NamespaceSnapshotter) snapshotPods(ctx context.Context, snapshot *NamespaceSnapshot, events *EventCache) error {
+	pods, err := n.client.CoreV1().Pods(n.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %v", err)
+	}
+	replicaSets, err := n.client.AppsV1().ReplicaSets(n.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list replicasets: %v", err)
+	}
+	replicaSetOwners := make(map[string]string)
+	for i := range replicaSets.Items {
+		if ref := metav1.GetControllerOf(&replicaSets.Items[i]); ref != nil && ref.Kind == "Deployment" {
+			replicaSetOwners[replicaSets.Items[i].Name] = ref.Name
+		}
+	}
+
+	podAnalyzer := NewPodAnalyzer(n.client, n.namespace)
+	podAnalyzer.SetEventCache(events)
+	security := NewSecurityAnalyzer(n.client, n.namespace)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded {
+			continue
+		}
+
+		key := podOwnerKey(pod, replicaSetOwners)
+		workload, ok := snapshot.Workloads[key]
+		if !ok {
+			// Pods of jobs, daemonsets and other controllers are grouped under their owner
+			kind, name, _ := strings.Cut(key, "/")
+			workload = &WorkloadSnapshot{Kind: kind, Name: name, Status: "Healthy"}
+			if kind == "pod" {
+				if report, err := podAnalyzer.Analyze(ctx, pod.Name); err != nil {
+					workload.Status, workload.Error = "Error", err.Error()
+				} else {
+					workload.Issues = report.Issues
+					if len(report.Issues) > 0 {
+						workload.Status = "Needs Attention"
+					}
+				}
+			}
+			snapshot.add(workload)
+		}
+
+		report := &SecurityReport{PodName: pod.Name, Namespace: pod.Namespace}
+		security.analyzeSecurityContext(report, pod)
+		security.analyzeContainerSecurity(report, pod)
+		report.suppressIgnored(pod.Annotations)
+		for _, issue := range report.Issues {
+			finding := fmt.Sprintf("%s: %s", issue.Level, issue.Title)
+			if !containsString(workload.SecurityFindings, finding) {
+				workload.SecurityFindings = append(workload.SecurityFindings, finding)
+			}
+		}
+	}
+	return nil
+}
+
+// podOwnerKey returns the snapshot key of the workload managing a pod, following
+// ReplicaSets up to their Deployment
+func podOwnerKey(pod *corev1.Pod, replicaSetOwners map[string]string) string {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "pod/" + pod.Name
+	}
+	if ref.Kind == "ReplicaSet" {
+		if deployment, ok := replicaSetOwners[ref.Name]; ok {
+			return "deployment/" + deployment
+		}
+	}
+	return strings.ToLower(ref.Kind) + "/" + ref.Name
+}
+
+// numberPattern matches the counts, durations and sizes in issue texts
+var numberPattern = regexp.MustCompile(`[0-9]+(\.[0-9]+)?`)
+
+// normalizeIssue strips the numbers from an issue, so a restart count going from 3 to
+// 4 is not reported as a new issue; replica changes are compared separately
+func normalizeIssue(issue string) string {
+	return numberPattern.ReplaceAllString(issue, "#")
+}
+
+// CompareSnapshots reports the regressions of current against a saved baseline: new
+// issues, fewer ready replicas, new security findings, worse statuses and workloads
+// that disappeared. Issues and findings that went away are reported as improvements.
+func CompareSnapshots(baseline, current *NamespaceSnapshot) *SnapshotComparison {
+	comparison := &SnapshotComparison{}
+	regress := func(resource, format string, args ...interface{}) {
+		comparison.Regressions = append(comparison.Regressions, SnapshotChange{Resource: resource, Detail: fmt.Sprintf(format, args...)})
+	}
+	improve := func(resource, format string, args ...interface{}) {
+		comparison.Improvements = append(comparison.Improvements, SnapshotChange{Resource: resource, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	keys := make(map[string]bool)
+	for key := range baseline.Workloads {
+		keys[key] = true
+	}
+	for key := range current.Workloads {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	empty := &WorkloadSnapshot{}
+	for _, key := range sorted {
+		before, existed := baseline.Workloads[key]
+		after, exists := current.Workloads[key]
+		switch {
+		case !exists:
+			regress(key, "no longer exists")
+			continue
+		case !existed:
+			before = empty
+		}
+
+		wasSeverity, isSeverity := SeverityForStatus(before.Status, 0), SeverityForStatus(after.Status, 0)
+		if existed && isSeverity != wasSeverity && MaxSeverity(wasSeverity, isSeverity) == isSeverity {
+			regress(key, "status went from %s to %s", before.Status, after.Status)
+		}
+		if before.ReadyReplicas != nil && after.ReadyReplicas != nil {
+			if *after.ReadyReplicas < *before.ReadyReplicas {
+				regress(key, "ready replicas dropped from %d to %d", *before.ReadyReplicas, *after.ReadyReplicas)
+			} else if *after.ReadyReplicas > *before.ReadyReplicas {
+				improve(key, "ready replicas rose from %d to %d", *before.ReadyReplicas, *after.ReadyReplicas)
+			}
+		}
+
+		for _, issue := range missingIssues(after.Issues, before.Issues) {
+			regress(key, "new issue: %s", issue)
+		}
+		for _, issue := range missingIssues(before.Issues, after.Issues) {
+			improve(key, "resolved issue: %s", issue)
+		}
+		for _, finding := range missingIssues(after.SecurityFindings, before.SecurityFindings) {
+			regress(key, "new security finding: %s", finding)
+		}
+		for _, finding := range missingIssues(before.SecurityFindings, after.SecurityFindings) {
+			improve(key, "resolved security finding: %s", finding)
+		}
+	}
+	return comparison
+}
+
+// missingIssues returns the issues of from that are not in other once normalized
+func missingIssues(from, other []string) []string {
+	known := make(map[string]bool, len(other))
+	for _, issue := range other {
+		known[normalizeIssue(issue)] = true
+	}
+	var missing []string
+	for _, issue := range from {
+		if !known[normalizeIssue(issue)] {
+			missing = append(missing, issue)
+		}
+	}
+	return missing
+}
@@ -14,6 +14,8 @@ import (
 type StatefulSetAnalyzer struct {
 	client    kubernetes.Interface
 	namespace string
+	events    *EventCache
+	findings  *FindingCollector
 }
 
 // NewStatefulSetAnalyzer creates a new StatefulSetAnalyzer
@@ -24,6 +26,17 @@ func NewStatefulSetAnalyzer(client kubernetes.Interface, namespace string) *Stat
 	}
 }
 
+// SetEventCache makes the analyzer look events up in a cache shared by the analyses
+// of a namespace instead of listing them for every statefulset
+func (s *StatefulSetAnalyzer) SetEventCache(cache *EventCache) {
+	s.events = cache
+}
+
+// SetFindingCollector makes the analyzer emit the issues of each statefulset as findings
+func (s *StatefulSetAnalyzer) SetFindingCollector(collector *FindingCollector) {
+	s.findings = collector
+}
+
 // StatefulSetReport contains the analysis report
 type StatefulSetReport struct {
 	Name                 string
@@ -37,6 +50,8 @@ type StatefulSetReport struct {
 	VolumeClaimTemplates []corev1.PersistentVolumeClaim
 	Events               []corev1.Event
 	Analysis             StatefulSetAnalysis
+	// GitOps is the GitOps application managing the statefulset, nil when there is none
+	GitOps *GitOpsSource
 }
 
 // StatefulSetAnalysis contains diagnostic results
@@ -48,15 +63,13 @@ type StatefulSetAnalysis struct {
 }
 
 // Analyze performs the analysis of a StatefulSet
-func (s *StatefulSetAnalyzer) Analyze(statefulSetName string) (*StatefulSetReport, error) {
-	statefulSet, err := s.client.AppsV1().StatefulSets(s.namespace).Get(context.TODO(), statefulSetName, metav1.GetOptions{})
+func (s *StatefulSetAnalyzer) Analyze(ctx context.Context, statefulSetName string) (*StatefulSetReport, error) {
+	statefulSet, err := s.client.AppsV1().StatefulSets(s.namespace).Get(ctx, statefulSetName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get statefulset %s: %v", statefulSetName, err)
 	}
 
-	events, err := s.client.CoreV1().Events(s.namespace).List(context.TODO(), metav1.ListOptions{
-		FieldSelector: "involvedObject.name=" + statefulSetName,
-	})
+	events, err := objectEvents(ctx, s.client, s.events, s.namespace, statefulSetName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events for statefulset %s: %v", statefulSetName, err)
 	}
@@ -71,16 +84,32 @@ func (s *StatefulSetAnalyzer) Analyze(statefulSetName string) (*StatefulSetRepor
 		Conditions:           statefulSet.Status.Conditions,
 		PodTemplate:          statefulSet.Spec.Template,
 		VolumeClaimTemplates: statefulSet.Spec.VolumeClaimTemplates,
-		Events:               events.Items,
+		Events:               events,
 	}
 
 	s.analyzeConditions(report)
 	s.analyzeUpdateStrategy(report, statefulSet)
 	s.analyzeReplicaStatus(report)
+	s.analyzeDeprecatedAPIs(ctx, report, statefulSet)
+	s.analyzeGitOps(report, statefulSet)
 
+	s.findings.Add(report.Findings()...)
 	return report, nil
 }
 
+func (s *StatefulSetAnalyzer) analyzeDeprecatedAPIs(ctx context.Context, report *StatefulSetReport, statefulSet *appsv1.StatefulSet) {
+	issues, recommendations := analyzeDeprecatedAPIs(ctx, s.client, statefulSet, "StatefulSet")
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+}
+
+func (s *StatefulSetAnalyzer) analyzeGitOps(report *StatefulSetReport, statefulSet *appsv1.StatefulSet) {
+	source, issues, recommendations := analyzeGitOps(statefulSet, "StatefulSet")
+	report.GitOps = source
+	report.Analysis.Issues = append(report.Analysis.Issues, issues...)
+	report.Analysis.Recommendations = append(report.Analysis.Recommendations, recommendations...)
+}
+
 func (s *StatefulSetAnalyzer) analyzeConditions(report *StatefulSetReport) {
 	for _, condition := range report.Conditions {
 		// Check for any condition that indicates a problem
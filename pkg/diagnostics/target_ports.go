@@ -0,0 +1,93 @@
+package diagnostics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// TargetPortMismatch is a service port whose named targetPort is not declared as a
+// container port by some of the pods the service selects
+type TargetPortMismatch struct {
+	// ServicePort is the service port's name, or its number when unnamed
+	ServicePort string
+	TargetPort  string
+	// Pods are the selected pods without a container port of that name
+	Pods []string
+}
+
+// findTargetPortMismatches checks every named targetPort of a service against the
+// container ports of the selected pods. Traffic to a pod that does not declare the
+// name has no port to go to, so its endpoints refuse connections or are left out.
+// Numbered target ports are not checked, since applications may listen on ports
+// they do not declare.
+func findTargetPortMismatches(ports []corev1.ServicePort, pods []corev1.Pod) []TargetPortMismatch {
+	var mismatches []TargetPortMismatch
+	for _, servicePort := range ports {
+		if servicePort.TargetPort.Type != intstr.String {
+			continue
+		}
+
+		var missing []string
+		for i := range pods {
+			if !declaresContainerPort(&pods[i], servicePort.TargetPort.StrVal) {
+				missing = append(missing, pods[i].Name)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		sort.Strings(missing)
+
+		name := servicePort.Name
+		if name == "" {
+			name = fmt.Sprintf("%d", servicePort.Port)
+		}
+		mismatches = append(mismatches, TargetPortMismatch{
+			ServicePort: name,
+			TargetPort:  servicePort.TargetPort.StrVal,
+			Pods:        missing,
+		})
+	}
+	return mismatches
+}
+
+// declaresContainerPort reports whether a container of the pod, including restartable
+// init containers, which run as sidecars, declares a port with the given name
+func declaresContainerPort(pod *corev1.Pod, name string) bool {
+	containers := pod.Spec.Containers
+	for _, container := range pod.Spec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			containers = append(containers[:len(containers):len(containers)], container)
+		}
+	}
+	for _, container := range containers {
+		for _, port := range container.Ports {
+			if port.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// targetPortMismatchIssues describes the mismatches as issues, with one recommendation
+// when there are any
+func targetPortMismatchIssues(mismatches []TargetPortMismatch, totalPods int) (issues []string, recommendations []string) {
+	for _, mismatch := range mismatches {
+		pods := strings.Join(mismatch.Pods, ", ")
+		if len(mismatch.Pods) > 3 {
+			pods = strings.Join(mismatch.Pods[:3], ", ") + fmt.Sprintf(" and %d more", len(mismatch.Pods)-3)
+		}
+		issues = append(issues, fmt.Sprintf("Service port %s targets port name %q, which %d of %d selected pod(s) do not declare (%s); connections to them on this port are refused",
+			mismatch.ServicePort, mismatch.TargetPort, len(mismatch.Pods), totalPods, pods))
+	}
+	if len(mismatches) > 0 {
+		recommendations = append(recommendations,
+			"Name the container port to match the service targetPort, or set targetPort to the container port number")
+	}
+	return issues, recommendations
+}
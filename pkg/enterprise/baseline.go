@@ -0,0 +1,120 @@
+package enterprise
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScanBaseline is a saved set of security scan findings, the issues a team has
+// accepted when starting to scan an existing cluster. Later scans compared with it
+// only report the issues that are not in it.
+type ScanBaseline struct {
+	CreatedAt time.Time         `json:"createdAt"`
+	Findings  []BaselineFinding `json:"findings"`
+}
+
+// BaselineFinding identifies a security issue by its type and the resource it was
+// found on, with pods replaced by the workload owning them so the identity survives
+// restarts and rollouts
+type BaselineFinding struct {
+	Namespace   string `json:"namespace"`
+	Type        string `json:"type"`
+	Resource    string `json:"resource"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+}
+
+// key is the stable identity of a finding
+func (f BaselineFinding) key() string {
+	return f.Namespace + "|" + f.Type + "|" + f.Resource
+}
+
+// BaselineComparison holds the findings of a scan that are not in the baseline, and
+// the baseline findings of the scanned namespaces that are gone
+type BaselineComparison struct {
+	New      []BaselineFinding
+	Resolved []BaselineFinding
+}
+
+// NewScanBaseline records the findings of scan reports, each distinct finding once
+func NewScanBaseline(reports []*SecurityScanReport) *ScanBaseline {
+	return &ScanBaseline{CreatedAt: time.Now(), Findings: baselineFindings(reports)}
+}
+
+// CompareBaseline compares the findings of scan reports with a baseline. Only baseline
+// findings of the namespaces that were scanned can be resolved.
+func CompareBaseline(baseline *ScanBaseline, reports []*SecurityScanReport) *BaselineComparison {
+	comparison := &BaselineComparison{}
+	known := make(map[string]bool)
+	for _, finding := range baseline.Findings {
+		known[finding.key()] = true
+	}
+
+	scanned := make(map[string]bool)
+	for _, report := range reports {
+		scanned[report.Namespace] = true
+	}
+	current := make(map[string]bool)
+	for _, finding := range baselineFindings(reports) {
+		current[finding.key()] = true
+		if !known[finding.key()] {
+			comparison.New = append(comparison.New, finding)
+		}
+	}
+	for _, finding := range baseline.Findings {
+		if scanned[finding.Namespace] && !current[finding.key()] {
+			comparison.Resolved = append(comparison.Resolved, finding)
+		}
+	}
+	return comparison
+}
+
+// baselineFindings returns the distinct findings of scan reports, sorted
+func baselineFindings(reports []*SecurityScanReport) []BaselineFinding {
+	seen := make(map[string]bool)
+	var findings []BaselineFinding
+	for _, report := range reports {
+		for _, issue := range report.SecurityIssues {
+			finding := BaselineFinding{
+				Namespace:   report.Namespace,
+				Type:        issue.Type,
+				Resource:    issue.Resource,
+				Severity:    issue.Severity,
+				Description: issue.Description,
+			}
+			// Pod names change on every restart, so pod findings are keyed by workload,
+			// keeping the container part of pod/container resources
+			if issue.Workload != "" {
+				finding.Resource = issue.Workload
+				if i := strings.Index(issue.Resource, "/"); i >= 0 {
+					finding.Resource += issue.Resource[i:]
+				}
+			}
+			if seen[finding.key()] {
+				continue
+			}
+			seen[finding.key()] = true
+			findings = append(findings, finding)
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool { return findings[i].key() < findings[j].key() })
+	return findings
+}
+
+// podWorkload names the controller owning a pod as kind/name, resolving ReplicaSets
+// to their Deployment through the pod-template-hash suffix. Bare pods are their own
+// workload.
+func podWorkload(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "Pod/" + pod.Name
+	}
+	if hash := pod.Labels["pod-template-hash"]; owner.Kind == "ReplicaSet" && hash != "" && strings.HasSuffix(owner.Name, "-"+hash) {
+		return "Deployment/" + strings.TrimSuffix(owner.Name, "-"+hash)
+	}
+	return owner.Kind + "/" + owner.Name
+}
@@ -0,0 +1,36 @@
+package enterprise
+
+import "github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+
+// securityFindings turns security issues into findings. resource maps the resource of
+// an issue to the resource of its finding.
+func securityFindings(source, category string, issues []SecurityIssue, resource func(string) string) []diagnostics.Finding {
+	findings := make([]diagnostics.Finding, 0, len(issues))
+	for _, issue := range issues {
+		findings = append(findings, diagnostics.NewFinding(source, category, diagnostics.SeverityForIssueLevel(issue.Severity),
+			resource(issue.Resource), issue.Description, issue.Recommendation))
+	}
+	return findings
+}
+
+// Findings returns the security issues of the scan as findings. Suppressed issues are
+// left out.
+func (r *SecurityScanReport) Findings() []diagnostics.Finding {
+	return securityFindings("security-scan", diagnostics.CategorySecurity, r.SecurityIssues, func(resource string) string {
+		if resource == "" || resource == r.Namespace {
+			return "Namespace " + r.Namespace
+		}
+		return r.Namespace + "/" + resource
+	})
+}
+
+// Findings returns the security issues of the RBAC analysis as findings. Issues about
+// cluster-scoped roles and bindings keep their unqualified names.
+func (r *RBACReport) Findings() []diagnostics.Finding {
+	return securityFindings("rbac", diagnostics.CategoryRBAC, r.SecurityIssues, func(resource string) string {
+		if resource == "" {
+			return "Namespace " + r.Namespace
+		}
+		return resource
+	})
+}
@@ -0,0 +1,315 @@
+package enterprise
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Pod Security Standards levels, from least to most restrictive
+const (
+	PSSPrivileged = "privileged"
+	PSSBaseline   = "baseline"
+	PSSRestricted = "restricted"
+)
+
+// pssEnforceLabel is the namespace label used by Pod Security Admission to enforce a level
+const pssEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+// PSSReport contains the Pod Security Standards evaluation of a namespace
+type PSSReport struct {
+	Namespace string
+	// EnforcedLevel is the level enforced on the namespace by Pod Security Admission, if any
+	EnforcedLevel string
+	Pods          []PodSecurityEvaluation
+	// LevelCounts is the number of pods whose highest satisfied level is each level
+	LevelCounts map[string]int
+}
+
+// PodSecurityEvaluation is the highest Pod Security Standard a pod satisfies
+type PodSecurityEvaluation struct {
+	Pod   string
+	Level string
+	// Violations lists the fields preventing the pod from meeting the next level up
+	Violations []PSSViolation
+}
+
+// PSSViolation is a single field that violates a Pod Security Standards level
+type PSSViolation struct {
+	Level  string
+	Field  string
+	Reason string
+}
+
+var (
+	// baselineCapabilities are the capabilities the baseline level allows containers to add
+	baselineCapabilities = map[corev1.Capability]bool{
+		"AUDIT_WRITE": true, "CHOWN": true, "DAC_OVERRIDE": true, "FOWNER": true, "FSETID": true,
+		"KILL": true, "MKNOD": true, "NET_BIND_SERVICE": true, "SETFCAP": true, "SETGID": true,
+		"SETPCAP": true, "SETUID": true, "SYS_CHROOT": true,
+	}
+
+	// baselineSysctls are the sysctls the baseline level considers safe
+	baselineSysctls = map[string]bool{
+		"kernel.shm_rmid_forced": true, "net.ipv4.ip_local_port_range": true,
+		"net.ipv4.ip_unprivileged_port_start": true, "net.ipv4.tcp_syncookies": true,
+		"net.ipv4.ping_group_range": true, "net.ipv4.ip_local_reserved_ports": true,
+		"net.ipv4.tcp_keepalive_time": true, "net.ipv4.tcp_fin_timeout": true,
+		"net.ipv4.tcp_keepalive_intvl": true, "net.ipv4.tcp_keepalive_probes": true,
+	}
+
+	// baselineSELinuxTypes are the SELinux types the baseline level allows
+	baselineSELinuxTypes = map[string]bool{
+		"": true, "container_t": true, "container_init_t": true, "container_kvm_t": true, "container_engine_t": true,
+	}
+)
+
+// EvaluatePodSecurityStandards evaluates every pod in the namespace against the Pod
+// Security Standards and reports the highest level each pod satisfies
+func (s *SecurityScanner) EvaluatePodSecurityStandards(ctx context.Context, namespace string) (*PSSReport, error) {
+	report := &PSSReport{
+		Namespace:   namespace,
+		LevelCounts: map[string]int{PSSPrivileged: 0, PSSBaseline: 0, PSSRestricted: 0},
+	}
+
+	ns, err := s.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace: %v", err)
+	}
+	report.EnforcedLevel = ns.Labels[pssEnforceLabel]
+
+	pods, err := s.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	for i := range pods.Items {
+		evaluation := EvaluatePodSecurity(&pods.Items[i])
+		report.Pods = append(report.Pods, evaluation)
+		report.LevelCounts[evaluation.Level]++
+	}
+
+	return report, nil
+}
+
+// EvaluatePodSecurity returns the highest Pod Security Standards level the pod satisfies,
+// with the violations that block it from the next level up
+func EvaluatePodSecurity(pod *corev1.Pod) PodSecurityEvaluation {
+	evaluation := PodSecurityEvaluation{Pod: pod.Name}
+
+	if violations := baselineViolations(pod); len(violations) > 0 {
+		evaluation.Level = PSSPrivileged
+		evaluation.Violations = violations
+		return evaluation
+	}
+
+	if violations := restrictedViolations(pod); len(violations) > 0 {
+		evaluation.Level = PSSBaseline
+		evaluation.Violations = violations
+		return evaluation
+	}
+
+	evaluation.Level = PSSRestricted
+	return evaluation
+}
+
+// podContainer is a container of any kind with the field path prefix used in violations
+type podContainer struct {
+	path            string
+	name            string
+	securityContext *corev1.SecurityContext
+	ports           []corev1.ContainerPort
+}
+
+func allContainers(pod *corev1.Pod) []podContainer {
+	var containers []podContainer
+	for _, c := range pod.Spec.InitContainers {
+		containers = append(containers, podContainer{fmt.Sprintf("spec.initContainers[%s]", c.Name), c.Name, c.SecurityContext, c.Ports})
+	}
+	for _, c := range pod.Spec.Containers {
+		containers = append(containers, podContainer{fmt.Sprintf("spec.containers[%s]", c.Name), c.Name, c.SecurityContext, c.Ports})
+	}
+	for _, c := range pod.Spec.EphemeralContainers {
+		containers = append(containers, podContainer{fmt.Sprintf("spec.ephemeralContainers[%s]", c.Name), c.Name, c.SecurityContext, c.Ports})
+	}
+	return containers
+}
+
+func baselineViolations(pod *corev1.Pod) []PSSViolation {
+	var violations []PSSViolation
+	add := func(field, reason string) {
+		violations = append(violations, PSSViolation{Level: PSSBaseline, Field: field, Reason: reason})
+	}
+
+	spec := pod.Spec
+	if spec.HostNetwork {
+		add("spec.hostNetwork", "host network namespace must not be shared")
+	}
+	if spec.HostPID {
+		add("spec.hostPID", "host PID namespace must not be shared")
+	}
+	if spec.HostIPC {
+		add("spec.hostIPC", "host IPC namespace must not be shared")
+	}
+
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			add(fmt.Sprintf("spec.volumes[%s].hostPath", volume.Name), "hostPath volumes are not allowed")
+		}
+	}
+
+	if sc := spec.SecurityContext; sc != nil {
+		if sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			add("spec.securityContext.seccompProfile.type", "seccomp profile must not be Unconfined")
+		}
+		if sc.AppArmorProfile != nil && sc.AppArmorProfile.Type == corev1.AppArmorProfileTypeUnconfined {
+			add("spec.securityContext.appArmorProfile.type", "AppArmor profile must not be Unconfined")
+		}
+		if reason := seLinuxViolation(sc.SELinuxOptions); reason != "" {
+			add("spec.securityContext.seLinuxOptions", reason)
+		}
+		for _, sysctl := range sc.Sysctls {
+			if !baselineSysctls[sysctl.Name] {
+				add(fmt.Sprintf("spec.securityContext.sysctls[%s]", sysctl.Name), "sysctl is not in the safe set")
+			}
+		}
+		if sc.WindowsOptions != nil && sc.WindowsOptions.HostProcess != nil && *sc.WindowsOptions.HostProcess {
+			add("spec.securityContext.windowsOptions.hostProcess", "Windows host process pods are not allowed")
+		}
+	}
+
+	for key, value := range pod.Annotations {
+		if strings.HasPrefix(key, "container.apparmor.security.beta.kubernetes.io/") && value == "unconfined" {
+			add(fmt.Sprintf("metadata.annotations[%s]", key), "AppArmor profile must not be unconfined")
+		}
+	}
+
+	for _, c := range allContainers(pod) {
+		for _, port := range c.ports {
+			if port.HostPort != 0 {
+				add(fmt.Sprintf("%s.ports[%d].hostPort", c.path, port.ContainerPort), "host ports are not allowed")
+			}
+		}
+
+		sc := c.securityContext
+		if sc == nil {
+			continue
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			add(c.path+".securityContext.privileged", "privileged containers are not allowed")
+		}
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if !baselineCapabilities[capability] {
+					add(c.path+".securityContext.capabilities.add", fmt.Sprintf("capability %s is not allowed", capability))
+				}
+			}
+		}
+		if sc.ProcMount != nil && *sc.ProcMount != corev1.DefaultProcMount {
+			add(c.path+".securityContext.procMount", "proc mount must be Default")
+		}
+		if sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			add(c.path+".securityContext.seccompProfile.type", "seccomp profile must not be Unconfined")
+		}
+		if sc.AppArmorProfile != nil && sc.AppArmorProfile.Type == corev1.AppArmorProfileTypeUnconfined {
+			add(c.path+".securityContext.appArmorProfile.type", "AppArmor profile must not be Unconfined")
+		}
+		if reason := seLinuxViolation(sc.SELinuxOptions); reason != "" {
+			add(c.path+".securityContext.seLinuxOptions", reason)
+		}
+		if sc.WindowsOptions != nil && sc.WindowsOptions.HostProcess != nil && *sc.WindowsOptions.HostProcess {
+			add(c.path+".securityContext.windowsOptions.hostProcess", "Windows host process containers are not allowed")
+		}
+	}
+
+	return violations
+}
+
+func seLinuxViolation(options *corev1.SELinuxOptions) string {
+	if options == nil {
+		return ""
+	}
+	if !baselineSELinuxTypes[options.Type] {
+		return fmt.Sprintf("SELinux type %s is not allowed", options.Type)
+	}
+	if options.User != "" || options.Role != "" {
+		return "custom SELinux user or role is not allowed"
+	}
+	return ""
+}
+
+func restrictedViolations(pod *corev1.Pod) []PSSViolation {
+	var violations []PSSViolation
+	add := func(field, reason string) {
+		violations = append(violations, PSSViolation{Level: PSSRestricted, Field: field, Reason: reason})
+	}
+
+	for _, volume := range pod.Spec.Volumes {
+		if !restrictedVolumeSource(volume.VolumeSource) {
+			add(fmt.Sprintf("spec.volumes[%s]", volume.Name), "only configMap, csi, downwardAPI, emptyDir, ephemeral, persistentVolumeClaim, projected and secret volumes are allowed")
+		}
+	}
+
+	podRunAsNonRoot := false
+	podSeccompSet := false
+	if sc := pod.Spec.SecurityContext; sc != nil {
+		podRunAsNonRoot = sc.RunAsNonRoot != nil && *sc.RunAsNonRoot
+		podSeccompSet = sc.SeccompProfile != nil
+		if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			add("spec.securityContext.runAsUser", "must not run as UID 0")
+		}
+	}
+
+	for _, c := range allContainers(pod) {
+		sc := c.securityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			add(c.path+".securityContext.allowPrivilegeEscalation", "must be set to false")
+		}
+
+		if sc.RunAsNonRoot != nil && !*sc.RunAsNonRoot {
+			add(c.path+".securityContext.runAsNonRoot", "must not be set to false")
+		} else if sc.RunAsNonRoot == nil && !podRunAsNonRoot {
+			add(c.path+".securityContext.runAsNonRoot", "must be set to true on the pod or container")
+		}
+
+		if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			add(c.path+".securityContext.runAsUser", "must not run as UID 0")
+		}
+
+		if sc.SeccompProfile == nil && !podSeccompSet {
+			add(c.path+".securityContext.seccompProfile", "must be set to RuntimeDefault or Localhost on the pod or container")
+		}
+
+		dropsAll := false
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Drop {
+				if capability == "ALL" {
+					dropsAll = true
+				}
+			}
+			for _, capability := range sc.Capabilities.Add {
+				if capability != "NET_BIND_SERVICE" {
+					add(c.path+".securityContext.capabilities.add", fmt.Sprintf("capability %s is not allowed, only NET_BIND_SERVICE", capability))
+				}
+			}
+		}
+		if !dropsAll {
+			add(c.path+".securityContext.capabilities.drop", "must drop ALL capabilities")
+		}
+	}
+
+	return violations
+}
+
+func restrictedVolumeSource(source corev1.VolumeSource) bool {
+	return source.ConfigMap != nil || source.CSI != nil || source.DownwardAPI != nil ||
+		source.EmptyDir != nil || source.Ephemeral != nil || source.PersistentVolumeClaim != nil ||
+		source.Projected != nil || source.Secret != nil
+}
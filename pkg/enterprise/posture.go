@@ -0,0 +1,127 @@
+package enterprise
+
+import (
+	"sort"
+	"time"
+)
+
+// PostureReport rolls the security scans of every namespace up into a single cluster
+// posture score, with the namespaces and issue types that weigh on it most
+type PostureReport struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	// Score is the compliance score of the namespaces weighted by their pods, 0-100
+	Score      int                `json:"score"`
+	RiskLevel  string             `json:"riskLevel"`
+	Namespaces int                `json:"namespaces"`
+	TotalPods  int                `json:"totalPods"`
+	Issues     int                `json:"issues"`
+	Suppressed int                `json:"suppressed"`
+	Worst      []NamespacePosture `json:"worstNamespaces"`
+	TopIssues  []IssueTypePosture `json:"topIssueTypes"`
+	Severities map[string]int     `json:"severities"`
+}
+
+// NamespacePosture is the scan outcome of one namespace
+type NamespacePosture struct {
+	Namespace string `json:"namespace"`
+	Score     int    `json:"score"`
+	RiskLevel string `json:"riskLevel"`
+	Pods      int    `json:"pods"`
+	Issues    int    `json:"issues"`
+}
+
+// IssueTypePosture counts the occurrences of an issue type across the cluster
+type IssueTypePosture struct {
+	Type       string `json:"type"`
+	Count      int    `json:"count"`
+	Namespaces int    `json:"namespaces"`
+}
+
+// WeightedComplianceScore combines the compliance scores of several namespaces,
+// weighting each by its number of pods. Namespaces without pods count as one pod, so
+// they are not ignored entirely.
+func WeightedComplianceScore(reports []*SecurityScanReport) int {
+	weightedScore, weight := 0, 0
+	for _, report := range reports {
+		pods := max(report.TotalPods, 1)
+		weightedScore += report.ComplianceScore * pods
+		weight += pods
+	}
+	if weight == 0 {
+		return 100
+	}
+	return weightedScore / weight
+}
+
+// NewPostureReport computes the cluster posture from the scans of its namespaces,
+// keeping the top lowest-scoring namespaces and most frequent issue types
+func NewPostureReport(reports []*SecurityScanReport, top int) *PostureReport {
+	scanner := &SecurityScanner{}
+	posture := &PostureReport{
+		GeneratedAt: time.Now(),
+		Score:       WeightedComplianceScore(reports),
+		Namespaces:  len(reports),
+		Severities:  make(map[string]int),
+	}
+	posture.RiskLevel = scanner.calculateRiskLevelFromScore(posture.Score)
+
+	counts := make(map[string]*IssueTypePosture)
+	for _, report := range reports {
+		posture.TotalPods += report.TotalPods
+		posture.Issues += len(report.SecurityIssues)
+		posture.Suppressed += len(report.SuppressedIssues)
+		posture.Worst = append(posture.Worst, NamespacePosture{
+			Namespace: report.Namespace,
+			Score:     report.ComplianceScore,
+			RiskLevel: report.RiskLevel,
+			Pods:      report.TotalPods,
+			Issues:    len(report.SecurityIssues),
+		})
+
+		seen := make(map[string]bool)
+		for _, issue := range report.SecurityIssues {
+			posture.Severities[issue.Severity]++
+			count, ok := counts[issue.Type]
+			if !ok {
+				count = &IssueTypePosture{Type: issue.Type}
+				counts[issue.Type] = count
+			}
+			count.Count++
+			if !seen[issue.Type] {
+				seen[issue.Type] = true
+				count.Namespaces++
+			}
+		}
+	}
+
+	sort.SliceStable(posture.Worst, func(i, j int) bool {
+		if posture.Worst[i].Score != posture.Worst[j].Score {
+			return posture.Worst[i].Score < posture.Worst[j].Score
+		}
+		return posture.Worst[i].Issues > posture.Worst[j].Issues
+	})
+	// Namespaces without issues are not offenders
+	for i, namespace := range posture.Worst {
+		if namespace.Issues == 0 {
+			posture.Worst = posture.Worst[:i]
+			break
+		}
+	}
+	if len(posture.Worst) > top {
+		posture.Worst = posture.Worst[:top]
+	}
+
+	for _, count := range counts {
+		posture.TopIssues = append(posture.TopIssues, *count)
+	}
+	sort.Slice(posture.TopIssues, func(i, j int) bool {
+		if posture.TopIssues[i].Count != posture.TopIssues[j].Count {
+			return posture.TopIssues[i].Count > posture.TopIssues[j].Count
+		}
+		return posture.TopIssues[i].Type < posture.TopIssues[j].Type
+	})
+	if len(posture.TopIssues) > top {
+		posture.TopIssues = posture.TopIssues[:top]
+	}
+	return posture
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,7 +14,8 @@ import (
 
 // RBACAnalyzer analyzes Kubernetes RBAC configurations
 type RBACAnalyzer struct {
-	client kubernetes.Interface
+	client   kubernetes.Interface
+	findings *diagnostics.FindingCollector
 }
 
 // NewRBACAnalyzer creates a new RBAC analyzer
@@ -23,6 +25,12 @@ func NewRBACAnalyzer(client kubernetes.Interface) *RBACAnalyzer {
 	}
 }
 
+// SetFindingCollector makes the analyzer emit RBAC issues as findings into a collector
+// shared with the other analyzers of a run
+func (r *RBACAnalyzer) SetFindingCollector(collector *diagnostics.FindingCollector) {
+	r.findings = collector
+}
+
 // RBACReport contains RBAC analysis results
 type RBACReport struct {
 	Namespace           string
@@ -34,6 +42,25 @@ type RBACReport struct {
 	SecurityIssues      []SecurityIssue
 	Recommendations     []string
 	RiskLevel           string
+	// ServiceAccountUsage describes how each service account in the namespace is bound and used
+	ServiceAccountUsage []ServiceAccountUsage
+}
+
+// ServiceAccountUsage describes the role bindings, pods and token secrets of a service account
+type ServiceAccountUsage struct {
+	Name string
+	// Bindings lists each binding granting the service account a role, e.g. "RoleBinding/x -> Role/y"
+	Bindings []string
+	// HighPrivilegeRoles lists bound roles that grant admin-level or wildcard access
+	HighPrivilegeRoles []string
+	Pods               int
+	// LegacyTokenSecrets lists long-lived token secrets that still exist for the service account
+	LegacyTokenSecrets []string
+}
+
+// Unused reports whether the service account has no role bindings and no pods using it
+func (u ServiceAccountUsage) Unused() bool {
+	return len(u.Bindings) == 0 && u.Pods == 0
 }
 
 // SecurityIssue represents a security concern in RBAC configuration
@@ -43,60 +70,83 @@ type SecurityIssue struct {
 	Resource       string
 	Description    string
 	Recommendation string
+	// Workload is the controller owning the pod of a pod issue, e.g. Deployment/web,
+	// which unlike the pod name stays the same across restarts
+	Workload string
 }
 
 // AnalyzeNamespaceRBAC analyzes RBAC configuration in a namespace
-func (r *RBACAnalyzer) AnalyzeNamespaceRBAC(namespace string) (*RBACReport, error) {
+func (r *RBACAnalyzer) AnalyzeNamespaceRBAC(ctx context.Context, namespace string) (*RBACReport, error) {
 	report := &RBACReport{
 		Namespace: namespace,
 	}
 
 	// Get cluster roles
-	clusterRoles, err := r.client.RbacV1().ClusterRoles().List(context.TODO(), metav1.ListOptions{})
+	clusterRoles, err := r.client.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list cluster roles: %v", err)
 	}
 	report.ClusterRoles = len(clusterRoles.Items)
 
 	// Get roles in namespace
-	roles, err := r.client.RbacV1().Roles(namespace).List(context.TODO(), metav1.ListOptions{})
+	roles, err := r.client.RbacV1().Roles(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list roles: %v", err)
 	}
 	report.Roles = len(roles.Items)
 
 	// Get cluster role bindings
-	clusterRoleBindings, err := r.client.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+	clusterRoleBindings, err := r.client.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list cluster role bindings: %v", err)
 	}
 	report.ClusterRoleBindings = len(clusterRoleBindings.Items)
 
 	// Get role bindings in namespace
-	roleBindings, err := r.client.RbacV1().RoleBindings(namespace).List(context.TODO(), metav1.ListOptions{})
+	roleBindings, err := r.client.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list role bindings: %v", err)
 	}
 	report.RoleBindings = len(roleBindings.Items)
 
 	// Get service accounts
-	serviceAccounts, err := r.client.CoreV1().ServiceAccounts(namespace).List(context.TODO(), metav1.ListOptions{})
+	serviceAccounts, err := r.client.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list service accounts: %v", err)
 	}
 	report.ServiceAccounts = len(serviceAccounts.Items)
 
+	pods, err := r.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %v", err)
+	}
+
+	tokenSecrets, err := r.client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "type=" + string(corev1.SecretTypeServiceAccountToken),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service account token secrets: %v", err)
+	}
+
 	// Analyze security issues
 	r.analyzeClusterRoles(report, clusterRoles.Items)
 	r.analyzeRoles(report, roles.Items)
 	r.analyzeClusterRoleBindings(report, clusterRoleBindings.Items)
 	r.analyzeRoleBindings(report, roleBindings.Items)
-	r.analyzeServiceAccounts(report, serviceAccounts.Items)
+	r.analyzeServiceAccounts(report, serviceAccounts.Items, rbacInventory{
+		clusterRoles:        clusterRoles.Items,
+		roles:               roles.Items,
+		clusterRoleBindings: clusterRoleBindings.Items,
+		roleBindings:        roleBindings.Items,
+		pods:                pods.Items,
+		tokenSecrets:        tokenSecrets.Items,
+	})
 
 	// Determine overall risk level
 	report.RiskLevel = r.calculateRiskLevel(report.SecurityIssues)
 	report.Recommendations = r.generateRecommendations(report.SecurityIssues)
 
+	r.findings.Add(report.Findings()...)
 	return report, nil
 }
 
@@ -216,19 +266,144 @@ func (r *RBACAnalyzer) analyzeRoleBindings(report *RBACReport, bindings []rbacv1
 	}
 }
 
-func (r *RBACAnalyzer) analyzeServiceAccounts(report *RBACReport, serviceAccounts []corev1.ServiceAccount) {
-	// Check for service accounts without explicit secrets
+// rbacInventory holds the objects service account analysis cross-references
+type rbacInventory struct {
+	clusterRoles        []rbacv1.ClusterRole
+	roles               []rbacv1.Role
+	clusterRoleBindings []rbacv1.ClusterRoleBinding
+	roleBindings        []rbacv1.RoleBinding
+	pods                []corev1.Pod
+	tokenSecrets        []corev1.Secret
+}
+
+// highPrivilegeRoles are built-in roles that grant broad write access
+var highPrivilegeRoles = map[string]bool{
+	"cluster-admin": true,
+	"admin":         true,
+	"edit":          true,
+}
+
+func (r *RBACAnalyzer) analyzeServiceAccounts(report *RBACReport, serviceAccounts []corev1.ServiceAccount, inventory rbacInventory) {
+	clusterRoleRules := make(map[string][]rbacv1.PolicyRule)
+	for _, clusterRole := range inventory.clusterRoles {
+		clusterRoleRules[clusterRole.Name] = clusterRole.Rules
+	}
+	roleRules := make(map[string][]rbacv1.PolicyRule)
+	for _, role := range inventory.roles {
+		roleRules[role.Name] = role.Rules
+	}
+
+	usage := make(map[string]*ServiceAccountUsage)
 	for _, sa := range serviceAccounts {
-		if len(sa.Secrets) == 0 {
+		usage[sa.Name] = &ServiceAccountUsage{Name: sa.Name}
+	}
+
+	addBinding := func(subjects []rbacv1.Subject, bindingKind, bindingName string, roleRef rbacv1.RoleRef) {
+		for _, subject := range subjects {
+			if subject.Kind != rbacv1.ServiceAccountKind || subject.Namespace != report.Namespace {
+				continue
+			}
+			sa, ok := usage[subject.Name]
+			if !ok {
+				continue
+			}
+
+			role := fmt.Sprintf("%s/%s", roleRef.Kind, roleRef.Name)
+			sa.Bindings = append(sa.Bindings, fmt.Sprintf("%s/%s -> %s", bindingKind, bindingName, role))
+
+			rules := clusterRoleRules[roleRef.Name]
+			if roleRef.Kind == "Role" {
+				rules = roleRules[roleRef.Name]
+			}
+			if highPrivilegeRoles[roleRef.Name] || isHighPrivilege(rules) {
+				sa.HighPrivilegeRoles = append(sa.HighPrivilegeRoles, role)
+			}
+		}
+	}
+
+	for _, binding := range inventory.clusterRoleBindings {
+		addBinding(binding.Subjects, "ClusterRoleBinding", binding.Name, binding.RoleRef)
+	}
+	for _, binding := range inventory.roleBindings {
+		// RoleBinding subjects may omit the namespace, which defaults to the binding's own
+		subjects := make([]rbacv1.Subject, len(binding.Subjects))
+		for i, subject := range binding.Subjects {
+			if subject.Kind == rbacv1.ServiceAccountKind && subject.Namespace == "" {
+				subject.Namespace = binding.Namespace
+			}
+			subjects[i] = subject
+		}
+		addBinding(subjects, "RoleBinding", binding.Name, binding.RoleRef)
+	}
+
+	for _, pod := range inventory.pods {
+		name := pod.Spec.ServiceAccountName
+		if name == "" {
+			name = "default"
+		}
+		if sa, ok := usage[name]; ok {
+			sa.Pods++
+		}
+	}
+
+	for _, secret := range inventory.tokenSecrets {
+		if secret.Type != corev1.SecretTypeServiceAccountToken {
+			continue
+		}
+		if sa, ok := usage[secret.Annotations[corev1.ServiceAccountNameKey]]; ok {
+			sa.LegacyTokenSecrets = append(sa.LegacyTokenSecrets, secret.Name)
+		}
+	}
+
+	for _, sa := range serviceAccounts {
+		u := usage[sa.Name]
+		report.ServiceAccountUsage = append(report.ServiceAccountUsage, *u)
+		resource := fmt.Sprintf("%s/%s", report.Namespace, sa.Name)
+
+		// The default service account always exists, so it's only worth flagging when it has privileges
+		if u.Unused() && sa.Name != "default" {
 			report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
-				Type:           "ServiceAccountWithoutSecrets",
+				Type:           "UnusedServiceAccount",
 				Severity:       "Low",
-				Resource:       sa.Name,
-				Description:    fmt.Sprintf("ServiceAccount '%s' has no explicitly defined secrets", sa.Name),
-				Recommendation: "Consider defining explicit secrets for service accounts",
+				Resource:       resource,
+				Description:    fmt.Sprintf("ServiceAccount '%s' has no role bindings and is not used by any pod", sa.Name),
+				Recommendation: "Delete unused service accounts to reduce the attack surface",
+			})
+		}
+
+		if len(u.HighPrivilegeRoles) > 0 {
+			report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+				Type:           "HighPrivilegeServiceAccount",
+				Severity:       "High",
+				Resource:       resource,
+				Description:    fmt.Sprintf("ServiceAccount '%s' is bound to high-privilege roles: %s", sa.Name, strings.Join(u.HighPrivilegeRoles, ", ")),
+				Recommendation: "Bind the service account to a role granting only the permissions its workloads need",
 			})
 		}
+
+		if len(u.LegacyTokenSecrets) > 0 {
+			report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+				Type:           "LongLivedServiceAccountToken",
+				Severity:       "Medium",
+				Resource:       resource,
+				Description:    fmt.Sprintf("ServiceAccount '%s' has long-lived token secrets: %s", sa.Name, strings.Join(u.LegacyTokenSecrets, ", ")),
+				Recommendation: "Delete long-lived token secrets and use projected, auto-rotated tokens instead",
+			})
+		}
+	}
+}
+
+// isHighPrivilege reports whether rules grant wildcard access or write access to secrets
+func isHighPrivilege(rules []rbacv1.PolicyRule) bool {
+	for _, rule := range rules {
+		if contains(rule.Verbs, "*") || contains(rule.Resources, "*") {
+			return true
+		}
+		if contains(rule.Resources, "secrets") && containsAny(rule.Verbs, "create", "update", "patch", "delete") {
+			return true
+		}
 	}
+	return false
 }
 
 func (r *RBACAnalyzer) calculateRiskLevel(issues []SecurityIssue) string {
@@ -264,6 +439,7 @@ func (r *RBACAnalyzer) generateRecommendations(issues []SecurityIssue) []string
 	hasWildcard := false
 	hasClusterAdmin := false
 	hasDangerousPermissions := false
+	hasServiceAccountIssues := false
 
 	for _, issue := range issues {
 		switch issue.Type {
@@ -273,6 +449,8 @@ func (r *RBACAnalyzer) generateRecommendations(issues []SecurityIssue) []string
 			hasClusterAdmin = true
 		case "DangerousSecretPermission", "PodExecPermission":
 			hasDangerousPermissions = true
+		case "HighPrivilegeServiceAccount", "LongLivedServiceAccountToken":
+			hasServiceAccountIssues = true
 		}
 	}
 
@@ -291,6 +469,11 @@ func (r *RBACAnalyzer) generateRecommendations(issues []SecurityIssue) []string
 			"Restrict dangerous permissions (secrets, pod exec) to trusted principals only")
 	}
 
+	if hasServiceAccountIssues {
+		recommendations = append(recommendations,
+			"Give each workload its own least-privilege service account with short-lived projected tokens")
+	}
+
 	if len(recommendations) == 0 {
 		recommendations = append(recommendations,
 			"RBAC configuration appears secure - maintain current security practices")
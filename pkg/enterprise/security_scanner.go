@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -11,7 +12,8 @@ import (
 
 // SecurityScanner provides comprehensive security scanning
 type SecurityScanner struct {
-	client kubernetes.Interface
+	client   kubernetes.Interface
+	findings *diagnostics.FindingCollector
 }
 
 // NewSecurityScanner creates a new security scanner
@@ -21,32 +23,41 @@ func NewSecurityScanner(client kubernetes.Interface) *SecurityScanner {
 	}
 }
 
+// SetFindingCollector makes the scanner emit the issues of each namespace it scans as
+// findings, skipping suppressed ones
+func (s *SecurityScanner) SetFindingCollector(collector *diagnostics.FindingCollector) {
+	s.findings = collector
+}
+
 // SecurityScanReport contains security scan results
 type SecurityScanReport struct {
-	Namespace       string
-	TotalPods       int
-	TotalServices   int
-	SecurityIssues  []SecurityIssue
-	ComplianceScore int
-	RiskLevel       string
-	Recommendations []string
+	Namespace      string
+	TotalPods      int
+	TotalServices  int
+	SecurityIssues []SecurityIssue
+	// SuppressedIssues are the issues whose type the object's k8s-lens.io/ignore
+	// annotation lists; they do not count toward the score
+	SuppressedIssues []SecurityIssue
+	ComplianceScore  int
+	RiskLevel        string
+	Recommendations  []string
 }
 
 // ScanNamespace performs a comprehensive security scan of a namespace
-func (s *SecurityScanner) ScanNamespace(namespace string) (*SecurityScanReport, error) {
+func (s *SecurityScanner) ScanNamespace(ctx context.Context, namespace string) (*SecurityScanReport, error) {
 	report := &SecurityScanReport{
 		Namespace: namespace,
 	}
 
 	// Get all pods in the namespace
-	pods, err := s.client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	pods, err := s.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods: %v", err)
 	}
 	report.TotalPods = len(pods.Items)
 
 	// Get all services in the namespace
-	services, err := s.client.CoreV1().Services(namespace).List(context.TODO(), metav1.ListOptions{})
+	services, err := s.client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list services: %v", err)
 	}
@@ -55,21 +66,29 @@ func (s *SecurityScanner) ScanNamespace(namespace string) (*SecurityScanReport,
 	// Perform security scans
 	s.scanPodSecurity(report, pods.Items)
 	s.scanServiceSecurity(report, services.Items)
-	s.scanNetworkPolicies(report, namespace)
+	s.scanNetworkPolicies(ctx, report, namespace)
 
 	// Calculate compliance score and risk level
 	report.ComplianceScore = s.calculateComplianceScore(report.SecurityIssues)
 	report.RiskLevel = s.calculateRiskLevelFromScore(report.ComplianceScore)
 	report.Recommendations = s.generateSecurityRecommendations(report.SecurityIssues)
 
+	s.findings.Add(report.Findings()...)
 	return report, nil
 }
 
 func (s *SecurityScanner) scanPodSecurity(report *SecurityScanReport, pods []corev1.Pod) {
 	for _, pod := range pods {
+		workload := podWorkload(&pod)
+		record := issueAdder(report, pod.Annotations)
+		add := func(issue SecurityIssue) {
+			issue.Workload = workload
+			record(issue)
+		}
+
 		// Check pod security context
 		if pod.Spec.SecurityContext == nil {
-			report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+			add(SecurityIssue{
 				Type:           "MissingPodSecurityContext",
 				Severity:       "Medium",
 				Resource:       pod.Name,
@@ -79,7 +98,7 @@ func (s *SecurityScanner) scanPodSecurity(report *SecurityScanReport, pods []cor
 		} else {
 			// Check specific security context settings
 			if pod.Spec.SecurityContext.RunAsNonRoot == nil || !*pod.Spec.SecurityContext.RunAsNonRoot {
-				report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+				add(SecurityIssue{
 					Type:           "RunAsRootAllowed",
 					Severity:       "Medium",
 					Resource:       pod.Name,
@@ -89,7 +108,7 @@ func (s *SecurityScanner) scanPodSecurity(report *SecurityScanReport, pods []cor
 			}
 
 			if pod.Spec.SecurityContext.SeccompProfile == nil {
-				report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+				add(SecurityIssue{
 					Type:           "MissingSeccompProfile",
 					Severity:       "Low",
 					Resource:       pod.Name,
@@ -102,7 +121,7 @@ func (s *SecurityScanner) scanPodSecurity(report *SecurityScanReport, pods []cor
 		// Check container security
 		for _, container := range pod.Spec.Containers {
 			if container.SecurityContext == nil {
-				report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+				add(SecurityIssue{
 					Type:           "MissingContainerSecurityContext",
 					Severity:       "Medium",
 					Resource:       fmt.Sprintf("%s/%s", pod.Name, container.Name),
@@ -114,7 +133,7 @@ func (s *SecurityScanner) scanPodSecurity(report *SecurityScanReport, pods []cor
 
 			// Check for privileged mode
 			if container.SecurityContext.Privileged != nil && *container.SecurityContext.Privileged {
-				report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+				add(SecurityIssue{
 					Type:           "PrivilegedContainer",
 					Severity:       "High",
 					Resource:       fmt.Sprintf("%s/%s", pod.Name, container.Name),
@@ -125,7 +144,7 @@ func (s *SecurityScanner) scanPodSecurity(report *SecurityScanReport, pods []cor
 
 			// Check for root user
 			if container.SecurityContext.RunAsUser != nil && *container.SecurityContext.RunAsUser == 0 {
-				report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+				add(SecurityIssue{
 					Type:           "RunAsRoot",
 					Severity:       "Medium",
 					Resource:       fmt.Sprintf("%s/%s", pod.Name, container.Name),
@@ -136,7 +155,7 @@ func (s *SecurityScanner) scanPodSecurity(report *SecurityScanReport, pods []cor
 
 			// Check for read-only root filesystem
 			if container.SecurityContext.ReadOnlyRootFilesystem == nil || !*container.SecurityContext.ReadOnlyRootFilesystem {
-				report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+				add(SecurityIssue{
 					Type:           "WritableRootFilesystem",
 					Severity:       "Low",
 					Resource:       fmt.Sprintf("%s/%s", pod.Name, container.Name),
@@ -149,7 +168,7 @@ func (s *SecurityScanner) scanPodSecurity(report *SecurityScanReport, pods []cor
 			if container.SecurityContext.Capabilities != nil {
 				for _, cap := range container.SecurityContext.Capabilities.Add {
 					if isDangerousCapability(string(cap)) {
-						report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+						add(SecurityIssue{
 							Type:           "DangerousCapability",
 							Severity:       "High",
 							Resource:       fmt.Sprintf("%s/%s", pod.Name, container.Name),
@@ -165,9 +184,11 @@ func (s *SecurityScanner) scanPodSecurity(report *SecurityScanReport, pods []cor
 
 func (s *SecurityScanner) scanServiceSecurity(report *SecurityScanReport, services []corev1.Service) {
 	for _, service := range services {
+		add := issueAdder(report, service.Annotations)
+
 		// Check for services with external IPs
 		if len(service.Spec.ExternalIPs) > 0 {
-			report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+			add(SecurityIssue{
 				Type:           "ServiceWithExternalIP",
 				Severity:       "Medium",
 				Resource:       service.Name,
@@ -178,7 +199,7 @@ func (s *SecurityScanner) scanServiceSecurity(report *SecurityScanReport, servic
 
 		// Check for LoadBalancer services
 		if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
-			report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+			add(SecurityIssue{
 				Type:           "LoadBalancerService",
 				Severity:       "Low",
 				Resource:       service.Name,
@@ -189,16 +210,21 @@ func (s *SecurityScanner) scanServiceSecurity(report *SecurityScanReport, servic
 	}
 }
 
-func (s *SecurityScanner) scanNetworkPolicies(report *SecurityScanReport, namespace string) {
+func (s *SecurityScanner) scanNetworkPolicies(ctx context.Context, report *SecurityScanReport, namespace string) {
 	// Check if namespace has network policies
-	networkPolicies, err := s.client.NetworkingV1().NetworkPolicies(namespace).List(context.TODO(), metav1.ListOptions{})
+	networkPolicies, err := s.client.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		// Network policies might not be available in all clusters
 		return
 	}
 
 	if len(networkPolicies.Items) == 0 {
-		report.SecurityIssues = append(report.SecurityIssues, SecurityIssue{
+		// The namespace is the object lacking policies, so its annotations decide
+		var annotations map[string]string
+		if ns, err := s.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err == nil {
+			annotations = ns.Annotations
+		}
+		issueAdder(report, annotations)(SecurityIssue{
 			Type:           "NoNetworkPolicies",
 			Severity:       "Medium",
 			Resource:       namespace,
@@ -208,6 +234,19 @@ func (s *SecurityScanner) scanNetworkPolicies(report *SecurityScanReport, namesp
 	}
 }
 
+// issueAdder returns a function recording the issues of an object, or keeping them
+// apart as suppressed when the object's ignore annotation lists their type
+func issueAdder(report *SecurityScanReport, annotations map[string]string) func(SecurityIssue) {
+	ignored := diagnostics.IgnoredFindings(annotations)
+	return func(issue SecurityIssue) {
+		if diagnostics.IsIgnored(ignored, issue.Type) {
+			report.SuppressedIssues = append(report.SuppressedIssues, issue)
+			return
+		}
+		report.SecurityIssues = append(report.SecurityIssues, issue)
+	}
+}
+
 func (s *SecurityScanner) calculateComplianceScore(issues []SecurityIssue) int {
 	baseScore := 100
 
@@ -0,0 +1,167 @@
+package integrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AlertmanagerClient represents a client to query active alerts from Alertmanager
+type AlertmanagerClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewAlertmanagerClient creates a new Alertmanager client
+func NewAlertmanagerClient(baseURL string) *AlertmanagerClient {
+	return &AlertmanagerClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Alert is an active alert as returned by the Alertmanager v2 API
+type Alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	Status      struct {
+		State string `json:"state"`
+	} `json:"status"`
+}
+
+// Name returns the alert name
+func (a Alert) Name() string {
+	return a.Labels["alertname"]
+}
+
+// Severity returns the alert's severity label, or "none" if it has none
+func (a Alert) Severity() string {
+	if severity := a.Labels["severity"]; severity != "" {
+		return severity
+	}
+	return "none"
+}
+
+// Summary returns the most descriptive annotation available
+func (a Alert) Summary() string {
+	for _, key := range []string{"summary", "message", "description"} {
+		if text := a.Annotations[key]; text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// GetActiveAlerts returns the firing alerts that are not silenced or inhibited and carry
+// all of the given labels. Empty label values are ignored.
+func (c *AlertmanagerClient) GetActiveAlerts(labels map[string]string) ([]Alert, error) {
+	return c.GetActiveAlertsMatchingAny([]map[string]string{labels})
+}
+
+// GetActiveAlertsMatchingAny returns the firing alerts that are not silenced or inhibited
+// and carry all the labels of at least one of the label sets, e.g. the alerts of a
+// deployment or of any of its pods. Only the labels every set shares are sent to
+// Alertmanager as filters; the rest are matched here.
+func (c *AlertmanagerClient) GetActiveAlertsMatchingAny(labelSets []map[string]string) ([]Alert, error) {
+	if len(labelSets) == 0 {
+		return nil, nil
+	}
+	shared := sharedLabels(labelSets)
+
+	u, err := url.Parse(c.baseURL + "/api/v2/alerts")
+	if err != nil {
+		return nil, fmt.Errorf("invalid Alertmanager URL: %v", err)
+	}
+
+	q := u.Query()
+	q.Set("active", "true")
+	q.Set("silenced", "false")
+	q.Set("inhibited", "false")
+	for _, name := range sortedLabelNames(shared) {
+		q.Add("filter", fmt.Sprintf("%s=%q", name, shared[name]))
+	}
+	u.RawQuery = q.Encode()
+
+	resp, err := c.client.Get(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Alertmanager returned status %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []Alert
+	if err := json.Unmarshal(body, &alerts); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	// Filter again in case the server ignored the matchers
+	var matching []Alert
+	for _, alert := range alerts {
+		if alert.Status.State != "" && alert.Status.State != "active" {
+			continue
+		}
+		for _, labels := range labelSets {
+			if matchesLabels(alert.Labels, labels) {
+				matching = append(matching, alert)
+				break
+			}
+		}
+	}
+
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].StartsAt.Before(matching[j].StartsAt)
+	})
+	return matching, nil
+}
+
+func matchesLabels(alertLabels, labels map[string]string) bool {
+	for name, value := range labels {
+		if value != "" && alertLabels[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// sharedLabels returns the non-empty labels that have the same value in every set
+func sharedLabels(labelSets []map[string]string) map[string]string {
+	shared := make(map[string]string)
+	for name, value := range labelSets[0] {
+		if value != "" {
+			shared[name] = value
+		}
+	}
+	for _, labels := range labelSets[1:] {
+		for name, value := range shared {
+			if labels[name] != value {
+				delete(shared, name)
+			}
+		}
+	}
+	return shared
+}
+
+func sortedLabelNames(labels map[string]string) []string {
+	var names []string
+	for name, value := range labels {
+		if value != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
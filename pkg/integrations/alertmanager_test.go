@@ -0,0 +1,69 @@
+package integrations
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetActiveAlertsMatchingAny(t *testing.T) {
+	firing := []map[string]string{
+		{"alertname": "KubeDeploymentReplicasMismatch", "namespace": "shop", "deployment": "web"},
+		{"alertname": "KubePodCrashLooping", "namespace": "shop", "pod": "web-6d4b9-x2k8f"},
+		{"alertname": "KubePodCrashLooping", "namespace": "shop", "pod": "api-7f5c8-q9z4t"},
+		{"alertname": "KubePodCrashLooping", "namespace": "staging", "pod": "web-6d4b9-x2k8f"},
+	}
+
+	tests := []struct {
+		name       string
+		labelSets  []map[string]string
+		wantFilter []string
+		want       []string
+	}{
+		{
+			name:       "workload labels only",
+			labelSets:  []map[string]string{{"namespace": "shop", "deployment": "web"}},
+			wantFilter: []string{`deployment="web"`, `namespace="shop"`},
+			want:       []string{"KubeDeploymentReplicasMismatch"},
+		},
+		{
+			name: "workload and its pods",
+			labelSets: []map[string]string{
+				{"namespace": "shop", "deployment": "web"},
+				{"namespace": "shop", "pod": "web-6d4b9-x2k8f"},
+			},
+			wantFilter: []string{`namespace="shop"`},
+			want:       []string{"KubeDeploymentReplicasMismatch", "KubePodCrashLooping"},
+		},
+		{name: "no label sets"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filters []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				filters = r.URL.Query()["filter"]
+				var alerts []Alert
+				started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+				for i, labels := range firing {
+					alerts = append(alerts, Alert{Labels: labels, StartsAt: started.Add(time.Duration(i) * time.Minute)})
+				}
+				json.NewEncoder(w).Encode(alerts)
+			}))
+			defer server.Close()
+
+			alerts, err := NewAlertmanagerClient(server.URL).GetActiveAlertsMatchingAny(tt.labelSets)
+			assert.NoError(t, err)
+			var names []string
+			for _, alert := range alerts {
+				names = append(names, alert.Name())
+			}
+			assert.Equal(t, tt.want, names)
+			assert.Equal(t, tt.wantFilter, filters)
+		})
+	}
+}
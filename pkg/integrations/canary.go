@@ -0,0 +1,314 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Canary verdicts
+const (
+	CanaryPass         = "Pass"
+	CanaryFail         = "Fail"
+	CanaryInconclusive = "Inconclusive"
+)
+
+const (
+	// canarySignificance is the p-value below which the canary counts as worse
+	canarySignificance = 0.05
+	// canaryMinSamples is the number of samples each deployment needs for a metric to be compared
+	canaryMinSamples = 5
+	// canaryTolerancePercent is how much higher the canary mean may be before a
+	// significant difference fails it, so negligible regressions are ignored
+	canaryTolerancePercent = 10
+)
+
+// DefaultErrorRateQuery is the share of HTTP requests answered with a 5xx status.
+// $namespace and $pods are replaced with the namespace and a regular expression
+// matching the pods of a deployment.
+const DefaultErrorRateQuery = `sum(rate(http_requests_total{namespace="$namespace", pod=~"$pods", code=~"5.."}[5m])) / sum(rate(http_requests_total{namespace="$namespace", pod=~"$pods"}[5m]))`
+
+// CanaryAnalyzer compares the metrics of a canary deployment with those of the stable
+// deployment it is meant to replace
+type CanaryAnalyzer struct {
+	k8sClient      kubernetes.Interface
+	promClient     MetricsBackend
+	namespace      string
+	errorRateQuery string
+}
+
+// NewCanaryAnalyzer creates a new CanaryAnalyzer
+func NewCanaryAnalyzer(k8sClient kubernetes.Interface, promClient MetricsBackend, namespace string) *CanaryAnalyzer {
+	return &CanaryAnalyzer{
+		k8sClient:      k8sClient,
+		promClient:     promClient,
+		namespace:      namespace,
+		errorRateQuery: DefaultErrorRateQuery,
+	}
+}
+
+// SetErrorRateQuery replaces DefaultErrorRateQuery, for applications that export
+// their errors under other metric names
+func (c *CanaryAnalyzer) SetErrorRateQuery(query string) {
+	c.errorRateQuery = query
+}
+
+// CanaryReport is the comparison of a canary deployment with the stable deployment
+type CanaryReport struct {
+	Namespace string
+	Stable    string
+	Canary    string
+	Window    time.Duration
+	// StableStatus and CanaryStatus are the statuses of the deployment analyses
+	StableStatus string
+	CanaryStatus string
+	CanaryIssues []string
+	Metrics      []CanaryMetric
+	Verdict      string
+	// Reasons explain the verdict
+	Reasons []string
+}
+
+// CanaryMetric compares one metric of the stable and canary pods over the window
+type CanaryMetric struct {
+	Name string
+	Unit string
+	// StableMean and CanaryMean average the per-pod values sampled over the window
+	StableMean    float64
+	CanaryMean    float64
+	StableSamples int
+	CanarySamples int
+	// ChangePercent is how much higher the canary mean is than the stable mean
+	ChangePercent float64
+	// FromZero is set when only the canary has a non-zero mean, so there is no
+	// relative change, e.g. when only the canary returns errors
+	FromZero bool
+	// PValue is the probability of the canary values being this much higher by chance,
+	// from a one-sided Mann-Whitney U test
+	PValue float64
+	// Available is false when either deployment had too few samples to compare
+	Available bool
+	Worse     bool
+}
+
+// canaryQuery is a metric compared between the deployments. The query is a template
+// with $namespace and $pods placeholders and must aggregate to a single series.
+type canaryQuery struct {
+	name  string
+	unit  string
+	query string
+}
+
+// Compare analyzes both deployments and compares their metrics over the window before now
+func (c *CanaryAnalyzer) Compare(ctx context.Context, stable, canary string, window time.Duration) (*CanaryReport, error) {
+	report := &CanaryReport{
+		Namespace: c.namespace,
+		Stable:    stable,
+		Canary:    canary,
+		Window:    window,
+	}
+
+	analyzer := diagnostics.NewDeploymentAnalyzer(c.k8sClient, c.namespace)
+	stableReport, err := analyzer.Analyze(ctx, stable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze stable deployment: %v", err)
+	}
+	canaryReport, err := analyzer.Analyze(ctx, canary)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze canary deployment: %v", err)
+	}
+	report.StableStatus = stableReport.Analysis.Status
+	report.CanaryStatus = canaryReport.Analysis.Status
+	report.CanaryIssues = canaryReport.Analysis.Issues
+
+	if err := c.promClient.TestConnection(); err != nil {
+		return nil, err
+	}
+
+	queries := []canaryQuery{
+		{name: "Error Rate", unit: "%", query: c.errorRateQuery},
+		{name: "CPU", unit: "cores/pod", query: `avg(sum by (pod) (rate(container_cpu_usage_seconds_total{namespace="$namespace", pod=~"$pods", container!="", container!="POD"}[5m])))`},
+		{name: "Memory", unit: "MiB/pod", query: `avg(sum by (pod) (container_memory_working_set_bytes{namespace="$namespace", pod=~"$pods", container!="", container!="POD"}))`},
+		{name: "Restarts", unit: "per pod/hour", query: `avg(sum by (pod) (rate(kube_pod_container_status_restarts_total{namespace="$namespace", pod=~"$pods"}[5m]))) * 3600`},
+	}
+
+	end := time.Now()
+	start := end.Add(-window)
+	step := max(window/60, 30*time.Second)
+	for _, query := range queries {
+		stableSamples, err := c.promClient.QueryRange(deploymentQuery(query.query, c.namespace, stable), start, end, step)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s of %s: %v", strings.ToLower(query.name), stable, err)
+		}
+		canarySamples, err := c.promClient.QueryRange(deploymentQuery(query.query, c.namespace, canary), start, end, step)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s of %s: %v", strings.ToLower(query.name), canary, err)
+		}
+		report.Metrics = append(report.Metrics, compareCanaryMetric(query, sampleValues(stableSamples), sampleValues(canarySamples)))
+	}
+
+	report.Verdict, report.Reasons = canaryVerdict(report)
+	return report, nil
+}
+
+// Severity maps the verdict to a severity, for the exit code
+func (r *CanaryReport) Severity() string {
+	switch r.Verdict {
+	case CanaryFail:
+		return diagnostics.SeverityCritical
+	case CanaryInconclusive:
+		return diagnostics.SeverityWarning
+	}
+	return diagnostics.SeverityHealthy
+}
+
+// deploymentQuery fills in the placeholders of a query for the pods of a deployment.
+// Deployment pods are named <deployment>-<pod-template-hash>-<suffix>, so the pattern
+// also matches pods that were replaced during the window, but not those of another
+// deployment whose name starts with the same prefix. Backslashes are doubled, as the
+// pattern is embedded in a PromQL string.
+func deploymentQuery(query, namespace, deployment string) string {
+	pods := strings.ReplaceAll(regexp.QuoteMeta(deployment), `\`, `\\`) + "-[a-z0-9]+-[a-z0-9]+"
+	return strings.NewReplacer("$namespace", namespace, "$pods", pods).Replace(query)
+}
+
+// sampleValues returns the values of range query samples, skipping NaN values such as
+// those of an error rate without requests
+func sampleValues(samples []Sample) []float64 {
+	values := make([]float64, 0, len(samples))
+	for _, sample := range samples {
+		if !math.IsNaN(sample.Value) && !math.IsInf(sample.Value, 0) {
+			values = append(values, sample.Value)
+		}
+	}
+	return values
+}
+
+func compareCanaryMetric(query canaryQuery, stable, canary []float64) CanaryMetric {
+	scale := 1.0
+	switch query.unit {
+	case "%":
+		scale = 100
+	case "MiB/pod":
+		scale = 1.0 / (1024 * 1024)
+	}
+
+	metric := CanaryMetric{
+		Name:          query.name,
+		Unit:          query.unit,
+		StableSamples: len(stable),
+		CanarySamples: len(canary),
+		StableMean:    mean(stable) * scale,
+		CanaryMean:    mean(canary) * scale,
+	}
+	if len(stable) < canaryMinSamples || len(canary) < canaryMinSamples {
+		return metric
+	}
+	metric.Available = true
+	if metric.StableMean > 0 {
+		metric.ChangePercent = (metric.CanaryMean - metric.StableMean) / metric.StableMean * 100
+	} else {
+		metric.FromZero = metric.CanaryMean > 0
+	}
+	metric.PValue = mannWhitneyGreater(stable, canary)
+	metric.Worse = metric.PValue < canarySignificance && (metric.FromZero || metric.ChangePercent > canaryTolerancePercent)
+	return metric
+}
+
+// canaryVerdict fails the canary when its deployment is unhealthy or any metric is
+// significantly worse, and is inconclusive when no metric had enough samples
+func canaryVerdict(report *CanaryReport) (string, []string) {
+	var reasons []string
+	if diagnostics.SeverityForStatus(report.CanaryStatus, 0) == diagnostics.SeverityCritical {
+		reasons = append(reasons, fmt.Sprintf("Canary deployment %s is %s", report.Canary, report.CanaryStatus))
+	}
+
+	available := 0
+	for _, metric := range report.Metrics {
+		if !metric.Available {
+			continue
+		}
+		available++
+		if !metric.Worse {
+			continue
+		}
+		if metric.FromZero {
+			reasons = append(reasons, fmt.Sprintf("%s is %.2f %s on the canary and 0 on the stable deployment (p=%.3f)",
+				metric.Name, metric.CanaryMean, metric.Unit, metric.PValue))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("%s is %.0f%% higher on the canary (p=%.3f)", metric.Name, metric.ChangePercent, metric.PValue))
+		}
+	}
+	if len(reasons) > 0 {
+		return CanaryFail, reasons
+	}
+	if available == 0 {
+		return CanaryInconclusive, []string{fmt.Sprintf("No metric had %d samples for both deployments over the last %s", canaryMinSamples, report.Window)}
+	}
+	return CanaryPass, []string{fmt.Sprintf("No metric is significantly worse on the canary (%d of %d compared)", available, len(report.Metrics))}
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// mannWhitneyGreater returns the one-sided p-value of the Mann-Whitney U test that the
+// values of b tend to be greater than those of a, using the normal approximation with
+// tie and continuity corrections. It makes no assumption about how values are
+// distributed, which suits error rates and restarts that are mostly zero.
+func mannWhitneyGreater(a, b []float64) float64 {
+	type value struct {
+		v   float64
+		inB bool
+	}
+	values := make([]value, 0, len(a)+len(b))
+	for _, v := range a {
+		values = append(values, value{v: v})
+	}
+	for _, v := range b {
+		values = append(values, value{v: v, inB: true})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].v < values[j].v })
+
+	// Rank the values, giving tied values the average of their ranks
+	n := float64(len(values))
+	rankSumB, tieCorrection := 0.0, 0.0
+	for i := 0; i < len(values); {
+		j := i
+		for j < len(values) && values[j].v == values[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if values[k].inB {
+				rankSumB += rank
+			}
+		}
+		ties := float64(j - i)
+		tieCorrection += ties*ties*ties - ties
+		i = j
+	}
+
+	na, nb := float64(len(a)), float64(len(b))
+	u := rankSumB - nb*(nb+1)/2
+	variance := na * nb / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (u - na*nb/2 - 0.5) / math.Sqrt(variance)
+	return 0.5 * math.Erfc(z/math.Sqrt2)
+}
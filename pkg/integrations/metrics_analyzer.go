@@ -1,22 +1,32 @@
 package integrations
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
 	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	// highThrottlingPercent is the share of throttled CFS periods from which a
+	// container's CPU limit is reported as too tight
+	highThrottlingPercent = 25
+	// lowCPUUsageOfLimit is the share of its CPU limit below which a throttled
+	// container's average usage is considered low
+	lowCPUUsageOfLimit = 0.5
+)
+
 // MetricsAnalyzer combines Kubernetes and Prometheus data for enhanced analysis
 type MetricsAnalyzer struct {
 	k8sClient  kubernetes.Interface
-	promClient *PrometheusClient
+	promClient MetricsBackend
 }
 
 // NewMetricsAnalyzer creates a new metrics analyzer
-func NewMetricsAnalyzer(k8sClient kubernetes.Interface, prometheusURL string) *MetricsAnalyzer {
-	promClient := NewPrometheusClient(prometheusURL)
+func NewMetricsAnalyzer(k8sClient kubernetes.Interface, promClient MetricsBackend) *MetricsAnalyzer {
 	return &MetricsAnalyzer{
 		k8sClient:  k8sClient,
 		promClient: promClient,
@@ -32,10 +42,10 @@ type EnhancedPodReport struct {
 }
 
 // AnalyzePodWithMetrics enhances pod analysis with metrics
-func (m *MetricsAnalyzer) AnalyzePodWithMetrics(podName, namespace string) (*EnhancedPodReport, error) {
+func (m *MetricsAnalyzer) AnalyzePodWithMetrics(ctx context.Context, podName, namespace string) (*EnhancedPodReport, error) {
 	// Get standard pod analysis
 	podAnalyzer := diagnostics.NewPodAnalyzer(m.k8sClient, namespace)
-	podReport, err := podAnalyzer.Analyze(podName)
+	podReport, err := podAnalyzer.Analyze(ctx, podName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze pod: %v", err)
 	}
@@ -112,14 +122,50 @@ func (m *MetricsAnalyzer) generateRecommendations(report *EnhancedPodReport) {
 		}
 	}
 
+	// Flag restarts by their recent rate rather than the ever-growing total
+	activelyRestarting := false
+	if report.PodMetrics.RestartRateAvailable {
+		if report.PodMetrics.RestartRate > 0 {
+			activelyRestarting = true
+			recommendations = append(recommendations,
+				fmt.Sprintf("Containers are actively restarting (%.1f restarts/hour) - check logs of the previous container instance",
+					report.PodMetrics.RestartRate))
+		} else if report.PodReport.RestartCount > 0 {
+			recommendations = append(recommendations,
+				fmt.Sprintf("No restarts in the last hour - the %d total restarts are historical", report.PodReport.RestartCount))
+		}
+	}
+
+	for _, throttling := range report.PodMetrics.CPUThrottling {
+		if throttling.ThrottledPercent < highThrottlingPercent {
+			continue
+		}
+		if throttling.CPULimit > 0 && throttling.CPUUsage < throttling.CPULimit*lowCPUUsageOfLimit {
+			recommendations = append(recommendations,
+				fmt.Sprintf("Container %s is CPU throttled in %.0f%% of periods while averaging only %.0f%% of its %.2f core limit - short bursts hit the limit and add latency; raise or remove the CPU limit",
+					throttling.Container, throttling.ThrottledPercent, throttling.CPUUsage/throttling.CPULimit*100, throttling.CPULimit))
+		} else {
+			recommendations = append(recommendations,
+				fmt.Sprintf("Container %s is CPU throttled in %.0f%% of periods - raise its CPU limit and request to match its demand",
+					throttling.Container, throttling.ThrottledPercent))
+		}
+	}
+
 	// Add Prometheus setup recommendation if metrics are unavailable
 	if report.PodMetrics.Error != "" {
 		recommendations = append(recommendations,
 			"Prometheus metrics unavailable - set up Prometheus for enhanced monitoring")
 	}
 
-	// Combine with existing pod report recommendations
-	recommendations = append(recommendations, report.PodReport.Recommendations...)
+	// Combine with existing pod report recommendations, dropping the total restart
+	// count warning when the rate shows the pod is no longer crashing
+	for _, rec := range report.PodReport.Recommendations {
+		if report.PodMetrics.RestartRateAvailable && !activelyRestarting &&
+			strings.HasPrefix(rec, "Investigate why container has restarted") {
+			continue
+		}
+		recommendations = append(recommendations, rec)
+	}
 	report.Recommendations = recommendations
 }
 
@@ -142,6 +188,12 @@ func (m *MetricsAnalyzer) calculateHealthScore(report *EnhancedPodReport) int {
 		if report.PodMetrics.MemoryUsage > 2*1024*1024*1024 {
 			score -= 15
 		}
+
+		if report.PodMetrics.RestartRate >= 3 {
+			score -= 25
+		} else if report.PodMetrics.RestartRate > 0 {
+			score -= 10
+		}
 	} else {
 		// Deduct points for missing metrics
 		score -= 10
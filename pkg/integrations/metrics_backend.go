@@ -0,0 +1,144 @@
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// Supported metric backends. They all serve the Prometheus HTTP query API but
+// expose it under different paths.
+const (
+	BackendPrometheus      = "prometheus"
+	BackendThanos          = "thanos"
+	BackendVictoriaMetrics = "victoriametrics"
+)
+
+// MetricsBackend is a source of Prometheus-compatible metrics
+type MetricsBackend interface {
+	TestConnection() error
+	GetPodMetrics(podName, namespace string) (*PodMetrics, error)
+	GetNodeMetrics(nodeName string) (*NodeMetrics, error)
+	GetClusterMetrics() (*ClusterMetrics, error)
+	GetPodUsage(podName, namespace string) (float64, float64, error)
+	QueryAt(query string, at time.Time) ([]float64, error)
+	QueryRange(query string, start, end time.Time, step time.Duration) ([]Sample, error)
+}
+
+// MetricsAuth holds the credentials for a metrics backend behind authentication, such
+// as a Thanos Querier or VictoriaMetrics behind an auth proxy. Secrets are read from
+// files, on every request, so rotated tokens are picked up and never show in the
+// process list. The zero value sends no credentials.
+type MetricsAuth struct {
+	// BearerTokenFile holds a token sent as "Authorization: Bearer <token>"
+	BearerTokenFile string
+	// Username and PasswordFile are sent as basic auth
+	Username     string
+	PasswordFile string
+}
+
+// AddMetricsAuthFlags registers the flags setting the credentials for the metrics backend
+func AddMetricsAuthFlags(flags *pflag.FlagSet) {
+	flags.String("metrics-bearer-token-file", "", "File holding a bearer token to authenticate to the metrics backend")
+	flags.String("metrics-username", "", "Username to authenticate to the metrics backend with basic auth")
+	flags.String("metrics-password-file", "", "File holding the basic auth password for --metrics-username")
+}
+
+// MetricsAuthFromFlags returns the metrics backend credentials set by the flags of
+// AddMetricsAuthFlags
+func MetricsAuthFromFlags(flags *pflag.FlagSet) MetricsAuth {
+	var auth MetricsAuth
+	auth.BearerTokenFile, _ = flags.GetString("metrics-bearer-token-file")
+	auth.Username, _ = flags.GetString("metrics-username")
+	auth.PasswordFile, _ = flags.GetString("metrics-password-file")
+	return auth
+}
+
+// validate checks that at most one kind of credentials is set and its files are readable
+func (a MetricsAuth) validate() error {
+	if a.BearerTokenFile != "" && (a.Username != "" || a.PasswordFile != "") {
+		return fmt.Errorf("set either a metrics bearer token or a metrics username and password, not both")
+	}
+	if a.PasswordFile != "" && a.Username == "" {
+		return fmt.Errorf("a metrics password file needs a metrics username")
+	}
+	for _, file := range []string{a.BearerTokenFile, a.PasswordFile} {
+		if file == "" {
+			continue
+		}
+		if _, err := readSecretFile(file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read metrics credentials: %v", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// authTransport adds the metrics backend credentials to every request
+type authTransport struct {
+	next http.RoundTripper
+	auth MetricsAuth
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	switch {
+	case t.auth.BearerTokenFile != "":
+		token, err := readSecretFile(t.auth.BearerTokenFile)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case t.auth.Username != "":
+		var password string
+		if t.auth.PasswordFile != "" {
+			var err error
+			if password, err = readSecretFile(t.auth.PasswordFile); err != nil {
+				return nil, err
+			}
+		}
+		req.SetBasicAuth(t.auth.Username, password)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// NewMetricsBackend creates a client for the given backend type at baseURL, sending
+// the credentials of auth with every request.
+//
+// Thanos Querier serves the API under /api/v1 like Prometheus, and queries ask it to
+// deduplicate replicated series. VictoriaMetrics serves it under /prometheus/api/v1;
+// for a vmselect cluster include the tenant in baseURL, e.g. http://vmselect:8481/select/0.
+func NewMetricsBackend(backend, baseURL string, auth MetricsAuth) (MetricsBackend, error) {
+	if err := auth.validate(); err != nil {
+		return nil, err
+	}
+	client := NewPrometheusClient(baseURL)
+	if auth != (MetricsAuth{}) {
+		client.client.Transport = &authTransport{next: http.DefaultTransport, auth: auth}
+	}
+
+	switch backend {
+	case BackendPrometheus, "":
+	case BackendThanos:
+		client.queryParams = url.Values{"dedup": []string{"true"}}
+	case BackendVictoriaMetrics:
+		client.apiPath = "/prometheus/api/v1"
+	default:
+		return nil, fmt.Errorf("unsupported metrics backend %q, supported backends: %s, %s, %s",
+			backend, BackendPrometheus, BackendThanos, BackendVictoriaMetrics)
+	}
+
+	return client, nil
+}
@@ -0,0 +1,69 @@
+package integrations
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMetricsBackendAuth(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	passwordFile := filepath.Join(dir, "password")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("s3cr3t\n"), 0o600))
+	assert.NoError(t, os.WriteFile(passwordFile, []byte("hunter2"), 0o600))
+
+	tests := []struct {
+		name    string
+		auth    MetricsAuth
+		want    string
+		wantErr string
+	}{
+		{name: "no credentials", auth: MetricsAuth{}, want: ""},
+		{name: "bearer token", auth: MetricsAuth{BearerTokenFile: tokenFile}, want: "Bearer s3cr3t"},
+		{
+			name: "basic auth",
+			auth: MetricsAuth{Username: "lens", PasswordFile: passwordFile},
+			want: "Basic bGVuczpodW50ZXIy",
+		},
+		{
+			name:    "token and basic auth",
+			auth:    MetricsAuth{BearerTokenFile: tokenFile, Username: "lens"},
+			wantErr: "not both",
+		},
+		{
+			name:    "password without username",
+			auth:    MetricsAuth{PasswordFile: passwordFile},
+			wantErr: "needs a metrics username",
+		},
+		{
+			name:    "missing token file",
+			auth:    MetricsAuth{BearerTokenFile: filepath.Join(dir, "missing")},
+			wantErr: "failed to read metrics credentials",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Header.Get("Authorization")
+				w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+			}))
+			defer server.Close()
+
+			backend, err := NewMetricsBackend(BackendThanos, server.URL, tt.auth)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, backend.TestConnection())
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// FormatSlack sends a Slack Block Kit payload
+	FormatSlack = "slack"
+	// FormatJSON sends the raw summary as JSON for generic webhooks
+	FormatJSON = "json"
+
+	// maxTopIssues limits how many issues are included in a notification
+	maxTopIssues = 5
+)
+
+// Summary is the condensed view of an analysis result that gets posted to a webhook
+type Summary struct {
+	Title          string         `json:"title"`
+	ResourceType   string         `json:"resourceType"`
+	ResourceName   string         `json:"resourceName"`
+	Namespace      string         `json:"namespace"`
+	Status         string         `json:"status"`
+	SeverityCounts map[string]int `json:"severityCounts"`
+	TopIssues      []string       `json:"topIssues"`
+	TotalIssues    int            `json:"totalIssues"`
+	Timestamp      time.Time      `json:"timestamp"`
+
+	// topRanks holds the severity rank of each of the top issues
+	topRanks []int
+}
+
+// NewSummary creates an empty summary for a resource
+func NewSummary(resourceType, resourceName, namespace, status string) *Summary {
+	return &Summary{
+		Title:          fmt.Sprintf("K8s Lens Analysis: %s/%s", resourceType, resourceName),
+		ResourceType:   resourceType,
+		ResourceName:   resourceName,
+		Namespace:      namespace,
+		Status:         status,
+		SeverityCounts: make(map[string]int),
+		Timestamp:      time.Now(),
+	}
+}
+
+// AddIssue records an issue with its severity, keeping only the most severe issues in
+// the payload. Issues of the same severity keep the order they were added in.
+func (s *Summary) AddIssue(severity, issue string) {
+	s.SeverityCounts[severity]++
+	s.TotalIssues++
+
+	rank := severityRank(severity)
+	position := sort.Search(len(s.topRanks), func(i int) bool { return s.topRanks[i] > rank })
+	if position >= maxTopIssues {
+		return
+	}
+	s.topRanks = append(s.topRanks[:position], append([]int{rank}, s.topRanks[position:]...)...)
+	s.TopIssues = append(s.TopIssues[:position], append([]string{issue}, s.TopIssues[position:]...)...)
+	if len(s.TopIssues) > maxTopIssues {
+		s.topRanks = s.topRanks[:maxTopIssues]
+		s.TopIssues = s.TopIssues[:maxTopIssues]
+	}
+}
+
+// severityRank orders severities from most to least severe; unknown ones come last
+func severityRank(severity string) int {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return 0
+	case "high":
+		return 1
+	case "medium", "warning":
+		return 2
+	case "low":
+		return 3
+	case "info":
+		return 4
+	}
+	return 5
+}
+
+// WebhookNotifier posts analysis summaries to a Slack-compatible or generic webhook
+type WebhookNotifier struct {
+	url    string
+	format string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier
+func NewWebhookNotifier(url, format string) *WebhookNotifier {
+	if format == "" {
+		format = FormatSlack
+	}
+	return &WebhookNotifier{
+		url:    url,
+		format: format,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify sends the summary to the configured webhook
+func (w *WebhookNotifier) Notify(summary *Summary) error {
+	var payload interface{}
+	switch w.format {
+	case FormatSlack:
+		payload = buildSlackPayload(summary)
+	case FormatJSON:
+		payload = summary
+	default:
+		return fmt.Errorf("unsupported webhook format: %s (supported: %s, %s)", w.format, FormatSlack, FormatJSON)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %v", err)
+	}
+
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post to webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func buildSlackPayload(summary *Summary) map[string]interface{} {
+	fields := []map[string]string{
+		{"type": "mrkdwn", "text": fmt.Sprintf("*Status:*\n%s", summary.Status)},
+		{"type": "mrkdwn", "text": fmt.Sprintf("*Namespace:*\n%s", summary.Namespace)},
+		{"type": "mrkdwn", "text": fmt.Sprintf("*Issues:*\n%d", summary.TotalIssues)},
+	}
+	if len(summary.SeverityCounts) > 0 {
+		fields = append(fields, map[string]string{
+			"type": "mrkdwn",
+			"text": fmt.Sprintf("*Severity:*\n%s", formatSeverityCounts(summary.SeverityCounts)),
+		})
+	}
+
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]string{"type": "plain_text", "text": summary.Title},
+		},
+		{
+			"type":   "section",
+			"fields": fields,
+		},
+	}
+
+	if len(summary.TopIssues) > 0 {
+		var issues strings.Builder
+		issues.WriteString("*Top Issues:*\n")
+		for _, issue := range summary.TopIssues {
+			issues.WriteString(fmt.Sprintf("• %s\n", issue))
+		}
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": issues.String()},
+		})
+	}
+
+	blocks = append(blocks, map[string]interface{}{
+		"type": "context",
+		"elements": []map[string]string{
+			{"type": "mrkdwn", "text": fmt.Sprintf("Generated %s", summary.Timestamp.Format("2006-01-02 15:04:05"))},
+		},
+	})
+
+	return map[string]interface{}{
+		// Fallback text for notifications and clients without Block Kit support
+		"text":   fmt.Sprintf("%s - %s (%d issues)", summary.Title, summary.Status, summary.TotalIssues),
+		"blocks": blocks,
+	}
+}
+
+func formatSeverityCounts(counts map[string]int) string {
+	severities := make([]string, 0, len(counts))
+	for severity := range counts {
+		severities = append(severities, severity)
+	}
+	sort.Strings(severities)
+
+	parts := make([]string, 0, len(severities))
+	for _, severity := range severities {
+		parts = append(parts, fmt.Sprintf("%s: %d", severity, counts[severity]))
+	}
+	return strings.Join(parts, ", ")
+}
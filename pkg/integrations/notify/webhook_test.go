@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummaryTopIssues(t *testing.T) {
+	type issue struct{ severity, text string }
+	tests := []struct {
+		name   string
+		issues []issue
+		want   []string
+	}{
+		{
+			name:   "kept in order within a severity",
+			issues: []issue{{"Medium", "m1"}, {"Medium", "m2"}},
+			want:   []string{"m1", "m2"},
+		},
+		{
+			name:   "sorted by severity",
+			issues: []issue{{"Low", "l1"}, {"Warning", "w1"}, {"Critical", "c1"}, {"High", "h1"}},
+			want:   []string{"c1", "h1", "w1", "l1"},
+		},
+		{
+			name: "a late critical issue is not cut off",
+			issues: []issue{{"Warning", "w1"}, {"Warning", "w2"}, {"Warning", "w3"}, {"Warning", "w4"},
+				{"Warning", "w5"}, {"Warning", "w6"}, {"critical", "c1"}},
+			want: []string{"c1", "w1", "w2", "w3", "w4"},
+		},
+		{
+			name: "less severe issues past the limit are dropped",
+			issues: []issue{{"High", "h1"}, {"High", "h2"}, {"High", "h3"}, {"High", "h4"}, {"High", "h5"},
+				{"Low", "l1"}},
+			want: []string{"h1", "h2", "h3", "h4", "h5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := NewSummary("pod", "web", "default", "Unhealthy")
+			for _, issue := range tt.issues {
+				summary.AddIssue(issue.severity, issue.text)
+			}
+			assert.Equal(t, tt.want, summary.TopIssues)
+			assert.Equal(t, len(tt.issues), summary.TotalIssues)
+		})
+	}
+}
@@ -4,31 +4,80 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/abrarahmad1510/k8s-lens/internal/utils"
 )
 
+// connectionCacheTTL is how long a successful connection test is trusted before
+// metric fetches probe Prometheus again
+const connectionCacheTTL = 5 * time.Minute
+
+// defaultAPIPath is where Prometheus serves its HTTP query API
+const defaultAPIPath = "/api/v1"
+
 // PrometheusClient represents a client to interact with Prometheus
 type PrometheusClient struct {
 	baseURL string
 	client  *http.Client
+	// apiPath is the path of the query API below baseURL
+	apiPath string
+	// queryParams are added to every query, e.g. for backend-specific options
+	queryParams url.Values
+
+	mu            sync.Mutex
+	verifiedAt    time.Time
+	connectionTTL time.Duration
 }
 
 // NewPrometheusClient creates a new Prometheus client
 func NewPrometheusClient(baseURL string) *PrometheusClient {
 	return &PrometheusClient{
-		baseURL: baseURL,
-		client:  &http.Client{Timeout: 30 * time.Second},
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		client:        &http.Client{Timeout: 30 * time.Second},
+		apiPath:       defaultAPIPath,
+		connectionTTL: connectionCacheTTL,
+	}
+}
+
+// ensureConnection verifies connectivity unless a previous test succeeded within the
+// cache TTL, so repeated metric fetches don't each pay for an extra round-trip
+func (p *PrometheusClient) ensureConnection() error {
+	p.mu.Lock()
+	verified := !p.verifiedAt.IsZero() && time.Since(p.verifiedAt) < p.connectionTTL
+	p.mu.Unlock()
+
+	if verified {
+		return nil
 	}
+	return p.TestConnection()
 }
 
-// TestConnection tests if Prometheus is accessible
+// TestConnection tests if Prometheus is accessible. A successful test is cached for
+// subsequent metric fetches; a failed test clears the cache.
 func (p *PrometheusClient) TestConnection() error {
-	u, err := url.Parse(p.baseURL + "/api/v1/query")
+	err := p.testConnection()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		p.verifiedAt = time.Time{}
+	} else {
+		p.verifiedAt = time.Now()
+	}
+
+	return err
+}
+
+func (p *PrometheusClient) testConnection() error {
+	u, err := p.endpoint("query")
 	if err != nil {
 		return fmt.Errorf("invalid Prometheus URL: %v", err)
 	}
@@ -75,8 +124,25 @@ type PodMetrics struct {
 	MemoryUsage float64
 	NetworkRx   float64
 	NetworkTx   float64
-	Timestamp   time.Time
-	Error       string
+	// RestartRate is the number of container restarts per hour over the last hour
+	RestartRate          float64
+	RestartRateAvailable bool
+	// CPUThrottling holds the CFS throttling of each container that has a CPU limit
+	CPUThrottling []ContainerCPUThrottling
+	Timestamp     time.Time
+	Error         string
+}
+
+// ContainerCPUThrottling is how often a container hit its CPU limit over the last 5
+// minutes. A container can be throttled heavily while its average CPU usage looks low,
+// because short bursts use up the quota of a scheduling period.
+type ContainerCPUThrottling struct {
+	Container string
+	// ThrottledPercent is the share of CFS periods in which the container was throttled
+	ThrottledPercent float64
+	// CPUUsage and CPULimit are in cores; CPULimit is 0 when it could not be queried
+	CPUUsage float64
+	CPULimit float64
 }
 
 // NodeMetrics contains metrics for a node
@@ -112,8 +178,8 @@ func (p *PrometheusClient) GetPodMetrics(podName, namespace string) (*PodMetrics
 		Timestamp: time.Now(),
 	}
 
-	// Test connection first
-	if err := p.TestConnection(); err != nil {
+	// Test connection first, reusing a recent successful test
+	if err := p.ensureConnection(); err != nil {
 		metrics.Error = fmt.Sprintf("Prometheus connection failed: %v", err)
 		return metrics, fmt.Errorf("Prometheus connection failed: %v", err)
 	}
@@ -163,9 +229,72 @@ func (p *PrometheusClient) GetPodMetrics(podName, namespace string) (*PodMetrics
 		}
 	}
 
+	// Query the restart rate so only actively crashing pods are flagged;
+	// this needs kube-state-metrics, so a failure is not treated as fatal
+	restartQuery := fmt.Sprintf(`sum(rate(kube_pod_container_status_restarts_total{pod="%s", namespace="%s"}[1h])) * 3600`, podName, namespace)
+	restartValue, err := p.queryPrometheus(restartQuery)
+	if err != nil {
+		utils.PrintWarning("Failed to query restart rate: %v", err)
+	} else if len(restartValue) > 0 {
+		metrics.RestartRate = restartValue[0]
+		metrics.RestartRateAvailable = true
+	}
+
+	// Throttling is reported by the same cAdvisor metrics as usage, but only for
+	// containers with a CPU limit, so a failure is not treated as fatal
+	throttling, err := p.getCPUThrottling(podName, namespace)
+	if err != nil {
+		utils.PrintWarning("Failed to query CPU throttling: %v", err)
+	} else {
+		metrics.CPUThrottling = throttling
+	}
+
 	return metrics, nil
 }
 
+// getCPUThrottling returns the CPU throttling of each container of a pod, sorted by
+// container name
+func (p *PrometheusClient) getCPUThrottling(podName, namespace string) ([]ContainerCPUThrottling, error) {
+	selector := fmt.Sprintf(`pod="%s", namespace="%s", container!=""`, podName, namespace)
+
+	throttledQuery := fmt.Sprintf(`sum by (container) (rate(container_cpu_cfs_throttled_periods_total{%s}[5m])) / sum by (container) (rate(container_cpu_cfs_periods_total{%s}[5m]))`, selector, selector)
+	throttled, err := p.queryPrometheusByLabel(throttledQuery, "container")
+	if err != nil {
+		return nil, err
+	}
+
+	usageQuery := fmt.Sprintf(`sum by (container) (rate(container_cpu_usage_seconds_total{%s}[5m]))`, selector)
+	usage, err := p.queryPrometheusByLabel(usageQuery, "container")
+	if err != nil {
+		return nil, err
+	}
+
+	limitQuery := fmt.Sprintf(`max by (container) (container_spec_cpu_quota{%s} / container_spec_cpu_period{%s})`, selector, selector)
+	limits, err := p.queryPrometheusByLabel(limitQuery, "container")
+	if err != nil {
+		return nil, err
+	}
+
+	var throttling []ContainerCPUThrottling
+	for container, ratio := range throttled {
+		// Containers without CFS periods have no limit and give NaN
+		if math.IsNaN(ratio) {
+			continue
+		}
+		throttling = append(throttling, ContainerCPUThrottling{
+			Container:        container,
+			ThrottledPercent: ratio * 100,
+			CPUUsage:         usage[container],
+			CPULimit:         limits[container],
+		})
+	}
+	sort.Slice(throttling, func(i, j int) bool {
+		return throttling[i].Container < throttling[j].Container
+	})
+
+	return throttling, nil
+}
+
 // GetNodeMetrics retrieves metrics for a specific node
 func (p *PrometheusClient) GetNodeMetrics(nodeName string) (*NodeMetrics, error) {
 	utils.PrintInfo("Fetching metrics for node %s", nodeName)
@@ -175,8 +304,8 @@ func (p *PrometheusClient) GetNodeMetrics(nodeName string) (*NodeMetrics, error)
 		Timestamp: time.Now(),
 	}
 
-	// Test connection first
-	if err := p.TestConnection(); err != nil {
+	// Test connection first, reusing a recent successful test
+	if err := p.ensureConnection(); err != nil {
 		metrics.Error = fmt.Sprintf("Prometheus connection failed: %v", err)
 		return metrics, fmt.Errorf("Prometheus connection failed: %v", err)
 	}
@@ -238,8 +367,8 @@ func (p *PrometheusClient) GetClusterMetrics() (*ClusterMetrics, error) {
 		Timestamp: time.Now(),
 	}
 
-	// Test connection first
-	if err := p.TestConnection(); err != nil {
+	// Test connection first, reusing a recent successful test
+	if err := p.ensureConnection(); err != nil {
 		metrics.Error = fmt.Sprintf("Prometheus connection failed: %v", err)
 		return metrics, fmt.Errorf("Prometheus connection failed: %v", err)
 	}
@@ -323,33 +452,168 @@ func (p *PrometheusClient) GetClusterMetrics() (*ClusterMetrics, error) {
 	return metrics, nil
 }
 
-// queryPrometheus executes a Prometheus query and returns the values
-func (p *PrometheusClient) queryPrometheus(query string) ([]float64, error) {
-	u, err := url.Parse(p.baseURL + "/api/v1/query")
+// GetPodUsage returns the current CPU (cores) and memory (bytes) usage of a pod
+func (p *PrometheusClient) GetPodUsage(podName, namespace string) (float64, float64, error) {
+	cpuQuery := fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{pod="%s", namespace="%s", container!=""}[5m]))`, podName, namespace)
+	cpuValue, err := p.queryPrometheus(cpuQuery)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query CPU usage: %v", err)
+	}
+
+	memoryQuery := fmt.Sprintf(`sum(container_memory_working_set_bytes{pod="%s", namespace="%s", container!=""})`, podName, namespace)
+	memoryValue, err := p.queryPrometheus(memoryQuery)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query memory usage: %v", err)
+	}
+
+	if len(cpuValue) == 0 || len(memoryValue) == 0 {
+		return 0, 0, fmt.Errorf("no usage data for pod %s/%s", namespace, podName)
+	}
+
+	return cpuValue[0], memoryValue[0], nil
+}
+
+// QueryAt executes an instant query evaluated at the given time and returns the values
+func (p *PrometheusClient) QueryAt(query string, at time.Time) ([]float64, error) {
+	if err := p.ensureConnection(); err != nil {
+		return nil, err
+	}
+	return p.queryPrometheusAt(query, at)
+}
+
+// Sample is a single value of a range query
+type Sample struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// RangeQueryResult represents the result of a Prometheus range query
+type RangeQueryResult struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][]interface{}   `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// QueryRange executes a range query and returns the samples of the first series.
+// Queries are expected to aggregate down to a single series, e.g. with sum().
+func (p *PrometheusClient) QueryRange(query string, start, end time.Time, step time.Duration) ([]Sample, error) {
+	if err := p.ensureConnection(); err != nil {
+		return nil, err
+	}
+
+	u, err := p.endpoint("query_range")
 	if err != nil {
 		return nil, err
 	}
 
 	q := u.Query()
 	q.Set("query", query)
+	q.Set("start", strconv.FormatInt(start.Unix(), 10))
+	q.Set("end", strconv.FormatInt(end.Unix(), 10))
+	q.Set("step", strconv.FormatInt(int64(step.Seconds()), 10))
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	body, err := p.get(u.String())
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := p.client.Do(req)
+	var result RangeQueryResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	if result.Status != "success" {
+		return nil, fmt.Errorf("Prometheus query failed: %s", string(body))
+	}
+
+	var samples []Sample
+	if len(result.Data.Result) == 0 {
+		return samples, nil
+	}
+	for _, value := range result.Data.Result[0].Values {
+		if len(value) < 2 {
+			continue
+		}
+		ts, ok := value[0].(float64)
+		if !ok {
+			continue
+		}
+		if str, ok := value[1].(string); ok {
+			if f, err := strconv.ParseFloat(str, 64); err == nil {
+				samples = append(samples, Sample{
+					Timestamp: time.Unix(int64(ts), 0),
+					Value:     f,
+				})
+			}
+		}
+	}
+
+	return samples, nil
+}
+
+// queryPrometheus executes a Prometheus query and returns the values
+func (p *PrometheusClient) queryPrometheus(query string) ([]float64, error) {
+	return p.queryPrometheusAt(query, time.Time{})
+}
+
+// queryPrometheusAt executes an instant query at the given time, or at the
+// current server time when at is zero
+func (p *PrometheusClient) queryPrometheusAt(query string, at time.Time) ([]float64, error) {
+	result, err := p.instantQuery(query, at)
 	if err != nil {
-		return nil, fmt.Errorf("connection failed: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Prometheus returned status %d", resp.StatusCode)
+	var values []float64
+	for _, res := range result.Data.Result {
+		if value, ok := sampleValue(res.Value); ok {
+			values = append(values, value)
+		}
+	}
+
+	return values, nil
+}
+
+// queryPrometheusByLabel executes an instant query and returns the value of each
+// series keyed by the given label, e.g. container for a "sum by (container)" query
+func (p *PrometheusClient) queryPrometheusByLabel(query, label string) (map[string]float64, error) {
+	result, err := p.instantQuery(query, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]float64)
+	for _, res := range result.Data.Result {
+		if value, ok := sampleValue(res.Value); ok {
+			values[res.Metric[label]] = value
+		}
+	}
+
+	return values, nil
+}
+
+// instantQuery executes an instant query at the given time, or at the current server
+// time when at is zero
+func (p *PrometheusClient) instantQuery(query string, at time.Time) (*QueryResult, error) {
+	u, err := p.endpoint("query")
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	q.Set("query", query)
+	if !at.IsZero() {
+		q.Set("time", strconv.FormatInt(at.Unix(), 10))
 	}
+	u.RawQuery = q.Encode()
 
-	body, err := ioutil.ReadAll(resp.Body)
+	body, err := p.get(u.String())
 	if err != nil {
 		return nil, err
 	}
@@ -364,16 +628,58 @@ func (p *PrometheusClient) queryPrometheus(query string) ([]float64, error) {
 		return nil, fmt.Errorf("Prometheus query failed: %s", string(body))
 	}
 
-	var values []float64
-	for _, res := range result.Data.Result {
-		if len(res.Value) >= 2 {
-			if str, ok := res.Value[1].(string); ok {
-				if f, err := strconv.ParseFloat(str, 64); err == nil {
-					values = append(values, f)
-				}
-			}
+	return &result, nil
+}
+
+// sampleValue parses the value of an instant query sample, a [timestamp, "value"] pair
+func sampleValue(sample []interface{}) (float64, bool) {
+	if len(sample) < 2 {
+		return 0, false
+	}
+	str, ok := sample[1].(string)
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// endpoint returns the URL of a query API endpoint, carrying the client's default query parameters
+func (p *PrometheusClient) endpoint(name string) (*url.URL, error) {
+	u, err := url.Parse(p.baseURL + p.apiPath + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+
+	q := u.Query()
+	for key, values := range p.queryParams {
+		for _, value := range values {
+			q.Add(key, value)
 		}
 	}
+	u.RawQuery = q.Encode()
+	return u, nil
+}
 
-	return values, nil
+// get performs a GET request against the Prometheus API and returns the body
+func (p *PrometheusClient) get(rawURL string) ([]byte, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Prometheus returned status %d", resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
 }
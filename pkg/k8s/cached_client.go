@@ -0,0 +1,468 @@
+package k8s
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appsv1client "k8s.io/client-go/kubernetes/typed/apps/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CachedClient is a kubernetes.Interface whose reads of pods, services, endpoints,
+// events, nodes, deployments, replicasets and statefulsets come from shared informers
+// kept warm by watches, instead of a request to the API server each. Analyzers accept
+// it in place of a client, so repeated analyses like a dashboard refresh cost no API
+// calls. Writes, other resources and lists continued from an API server page go to
+// the wrapped client.
+type CachedClient struct {
+	kubernetes.Interface
+	core *cachedCoreV1
+	apps *cachedAppsV1
+}
+
+// cacheSyncTimeout bounds the wait for the informers' first list, which never ends
+// when the client is not allowed to list and watch the cached resources
+const cacheSyncTimeout = 2 * time.Minute
+
+// NewCachedClient starts informers for the cached resources on client and waits for
+// their first list. They keep watching until ctx is done, so ctx should outlive every
+// use of the cached client.
+func NewCachedClient(ctx context.Context, client kubernetes.Interface, resync time.Duration) (*CachedClient, error) {
+	factory := informers.NewSharedInformerFactory(client, resync)
+	core := factory.Core().V1()
+	apps := factory.Apps().V1()
+
+	cached := &CachedClient{
+		Interface: client,
+		core: &cachedCoreV1{
+			CoreV1Interface: client.CoreV1(),
+			pods:            newCachedResource(core.Pods().Informer(), corev1.Resource("pods"), podFields),
+			services:        newCachedResource(core.Services().Informer(), corev1.Resource("services"), nil),
+			endpoints:       newCachedResource(core.Endpoints().Informer(), corev1.Resource("endpoints"), nil),
+			events:          newCachedResource(core.Events().Informer(), corev1.Resource("events"), eventFields),
+			nodes:           newCachedResource(core.Nodes().Informer(), corev1.Resource("nodes"), nil),
+		},
+		apps: &cachedAppsV1{
+			AppsV1Interface: client.AppsV1(),
+			deployments:     newCachedResource(apps.Deployments().Informer(), appsv1.Resource("deployments"), nil),
+			replicaSets:     newCachedResource(apps.ReplicaSets().Informer(), appsv1.Resource("replicasets"), nil),
+			statefulSets:    newCachedResource(apps.StatefulSets().Informer(), appsv1.Resource("statefulsets"), nil),
+		},
+	}
+
+	factory.Start(ctx.Done())
+	syncCtx, cancel := context.WithTimeout(ctx, cacheSyncTimeout)
+	defer cancel()
+	for informer, synced := range factory.WaitForCacheSync(syncCtx.Done()) {
+		if !synced {
+			return nil, fmt.Errorf("failed to sync informer cache for %v", informer)
+		}
+	}
+	return cached, nil
+}
+
+// CoreV1 returns the core client, with cached pods, services, endpoints, events and nodes
+func (c *CachedClient) CoreV1() corev1client.CoreV1Interface {
+	return c.core
+}
+
+// AppsV1 returns the apps client, with cached deployments, replicasets and statefulsets
+func (c *CachedClient) AppsV1() appsv1client.AppsV1Interface {
+	return c.apps
+}
+
+// cachedResource serves gets and lists of one resource from an informer's indexer
+type cachedResource struct {
+	indexer  cache.Indexer
+	resource schema.GroupResource
+	// fields returns the fields of an object field selectors may match, beyond its
+	// name and namespace
+	fields func(obj interface{}) fields.Set
+}
+
+func newCachedResource(informer cache.SharedIndexInformer, resource schema.GroupResource, fields func(obj interface{}) fields.Set) *cachedResource {
+	return &cachedResource{indexer: informer.GetIndexer(), resource: resource, fields: fields}
+}
+
+func (r *cachedResource) get(namespace, name string) (interface{}, error) {
+	key := name
+	if namespace != "" {
+		key = namespace + "/" + name
+	}
+	obj, exists, err := r.indexer.GetByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, apierrors.NewNotFound(r.resource, name)
+	}
+	return obj, nil
+}
+
+// cacheContinuePrefix marks continue tokens issued by the cache, telling them apart
+// from the API server's, which are plain base64
+const cacheContinuePrefix = "cache:"
+
+// list returns the objects in namespace matching the selectors of options, sorted
+// like the API server sorts them. With a limit it returns one page and a continue
+// token for the next, which only the cache understands. It reports false when the
+// options need the API server: a continue token it issued, or a field selector on
+// fields the cache does not index.
+func (r *cachedResource) list(namespace string, options metav1.ListOptions) ([]interface{}, metav1.ListMeta, bool, error) {
+	var after string
+	if options.Continue != "" {
+		token, ok := strings.CutPrefix(options.Continue, cacheContinuePrefix)
+		if !ok {
+			return nil, metav1.ListMeta{}, false, nil
+		}
+		key, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil {
+			return nil, metav1.ListMeta{}, true, apierrors.NewBadRequest(fmt.Sprintf("invalid continue token %q", options.Continue))
+		}
+		after = string(key)
+	}
+
+	labelSelector, err := labels.Parse(options.LabelSelector)
+	if err != nil {
+		return nil, metav1.ListMeta{}, true, err
+	}
+	fieldSelector, err := fields.ParseSelector(options.FieldSelector)
+	if err != nil {
+		return nil, metav1.ListMeta{}, true, err
+	}
+
+	var objs []interface{}
+	var unsupported bool
+	collect := func(obj interface{}) {
+		object := obj.(metav1.Object)
+		set := fields.Set{"metadata.name": object.GetName(), "metadata.namespace": object.GetNamespace()}
+		if r.fields != nil {
+			for field, value := range r.fields(obj) {
+				set[field] = value
+			}
+		}
+		for _, requirement := range fieldSelector.Requirements() {
+			if _, ok := set[requirement.Field]; !ok {
+				unsupported = true
+			}
+		}
+		if fieldSelector.Matches(set) && (after == "" || listKey(object) > after) {
+			objs = append(objs, obj)
+		}
+	}
+	if namespace == metav1.NamespaceAll {
+		err = cache.ListAll(r.indexer, labelSelector, collect)
+	} else {
+		err = cache.ListAllByNamespace(r.indexer, namespace, labelSelector, collect)
+	}
+	if err != nil {
+		return nil, metav1.ListMeta{}, true, err
+	}
+	if unsupported {
+		return nil, metav1.ListMeta{}, false, nil
+	}
+
+	sort.Slice(objs, func(i, j int) bool {
+		return listKey(objs[i].(metav1.Object)) < listKey(objs[j].(metav1.Object))
+	})
+
+	var meta metav1.ListMeta
+	if options.Limit > 0 && int64(len(objs)) > options.Limit {
+		remaining := int64(len(objs)) - options.Limit
+		objs = objs[:options.Limit]
+		meta.Continue = cacheContinuePrefix + base64.RawURLEncoding.EncodeToString([]byte(listKey(objs[len(objs)-1].(metav1.Object))))
+		meta.RemainingItemCount = &remaining
+	}
+	return objs, meta, true, nil
+}
+
+// listKey orders objects by namespace, then name. Neither contains a NUL byte, so
+// comparing keys compares namespaces first.
+func listKey(object metav1.Object) string {
+	return object.GetNamespace() + "\x00" + object.GetName()
+}
+
+func podFields(obj interface{}) fields.Set {
+	pod := obj.(*corev1.Pod)
+	return fields.Set{"spec.nodeName": pod.Spec.NodeName, "status.phase": string(pod.Status.Phase)}
+}
+
+func eventFields(obj interface{}) fields.Set {
+	event := obj.(*corev1.Event)
+	return fields.Set{
+		"involvedObject.kind":      event.InvolvedObject.Kind,
+		"involvedObject.name":      event.InvolvedObject.Name,
+		"involvedObject.namespace": event.InvolvedObject.Namespace,
+		"involvedObject.uid":       string(event.InvolvedObject.UID),
+		"reason":                   event.Reason,
+		"type":                     event.Type,
+	}
+}
+
+// cachedItems deep copies cached objects into list items, since objects in the
+// informer cache are shared and must not be modified by callers
+func cachedItems[T any, PT interface {
+	*T
+	DeepCopy() *T
+}](objs []interface{}) []T {
+	items := make([]T, 0, len(objs))
+	for _, obj := range objs {
+		items = append(items, *obj.(PT).DeepCopy())
+	}
+	return items
+}
+
+type cachedCoreV1 struct {
+	corev1client.CoreV1Interface
+	pods, services, endpoints, events, nodes *cachedResource
+}
+
+func (c *cachedCoreV1) Pods(namespace string) corev1client.PodInterface {
+	return &cachedPods{PodInterface: c.CoreV1Interface.Pods(namespace), namespace: namespace, cache: c.pods}
+}
+
+func (c *cachedCoreV1) Services(namespace string) corev1client.ServiceInterface {
+	return &cachedServices{ServiceInterface: c.CoreV1Interface.Services(namespace), namespace: namespace, cache: c.services}
+}
+
+func (c *cachedCoreV1) Endpoints(namespace string) corev1client.EndpointsInterface {
+	return &cachedEndpoints{EndpointsInterface: c.CoreV1Interface.Endpoints(namespace), namespace: namespace, cache: c.endpoints}
+}
+
+func (c *cachedCoreV1) Events(namespace string) corev1client.EventInterface {
+	return &cachedEvents{EventInterface: c.CoreV1Interface.Events(namespace), namespace: namespace, cache: c.events}
+}
+
+func (c *cachedCoreV1) Nodes() corev1client.NodeInterface {
+	return &cachedNodes{NodeInterface: c.CoreV1Interface.Nodes(), cache: c.nodes}
+}
+
+type cachedAppsV1 struct {
+	appsv1client.AppsV1Interface
+	deployments, replicaSets, statefulSets *cachedResource
+}
+
+func (c *cachedAppsV1) Deployments(namespace string) appsv1client.DeploymentInterface {
+	return &cachedDeployments{DeploymentInterface: c.AppsV1Interface.Deployments(namespace), namespace: namespace, cache: c.deployments}
+}
+
+func (c *cachedAppsV1) ReplicaSets(namespace string) appsv1client.ReplicaSetInterface {
+	return &cachedReplicaSets{ReplicaSetInterface: c.AppsV1Interface.ReplicaSets(namespace), namespace: namespace, cache: c.replicaSets}
+}
+
+func (c *cachedAppsV1) StatefulSets(namespace string) appsv1client.StatefulSetInterface {
+	return &cachedStatefulSets{StatefulSetInterface: c.AppsV1Interface.StatefulSets(namespace), namespace: namespace, cache: c.statefulSets}
+}
+
+type cachedPods struct {
+	corev1client.PodInterface
+	namespace string
+	cache     *cachedResource
+}
+
+func (p *cachedPods) Get(ctx context.Context, name string, options metav1.GetOptions) (*corev1.Pod, error) {
+	obj, err := p.cache.get(p.namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*corev1.Pod).DeepCopy(), nil
+}
+
+func (p *cachedPods) List(ctx context.Context, options metav1.ListOptions) (*corev1.PodList, error) {
+	objs, meta, cached, err := p.cache.list(p.namespace, options)
+	if !cached {
+		return p.PodInterface.List(ctx, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.PodList{ListMeta: meta, Items: cachedItems[corev1.Pod](objs)}, nil
+}
+
+type cachedServices struct {
+	corev1client.ServiceInterface
+	namespace string
+	cache     *cachedResource
+}
+
+func (s *cachedServices) Get(ctx context.Context, name string, options metav1.GetOptions) (*corev1.Service, error) {
+	obj, err := s.cache.get(s.namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*corev1.Service).DeepCopy(), nil
+}
+
+func (s *cachedServices) List(ctx context.Context, options metav1.ListOptions) (*corev1.ServiceList, error) {
+	objs, meta, cached, err := s.cache.list(s.namespace, options)
+	if !cached {
+		return s.ServiceInterface.List(ctx, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.ServiceList{ListMeta: meta, Items: cachedItems[corev1.Service](objs)}, nil
+}
+
+type cachedEndpoints struct {
+	corev1client.EndpointsInterface
+	namespace string
+	cache     *cachedResource
+}
+
+func (e *cachedEndpoints) Get(ctx context.Context, name string, options metav1.GetOptions) (*corev1.Endpoints, error) {
+	obj, err := e.cache.get(e.namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*corev1.Endpoints).DeepCopy(), nil
+}
+
+func (e *cachedEndpoints) List(ctx context.Context, options metav1.ListOptions) (*corev1.EndpointsList, error) {
+	objs, meta, cached, err := e.cache.list(e.namespace, options)
+	if !cached {
+		return e.EndpointsInterface.List(ctx, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.EndpointsList{ListMeta: meta, Items: cachedItems[corev1.Endpoints](objs)}, nil
+}
+
+type cachedEvents struct {
+	corev1client.EventInterface
+	namespace string
+	cache     *cachedResource
+}
+
+func (e *cachedEvents) Get(ctx context.Context, name string, options metav1.GetOptions) (*corev1.Event, error) {
+	obj, err := e.cache.get(e.namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*corev1.Event).DeepCopy(), nil
+}
+
+func (e *cachedEvents) List(ctx context.Context, options metav1.ListOptions) (*corev1.EventList, error) {
+	objs, meta, cached, err := e.cache.list(e.namespace, options)
+	if !cached {
+		return e.EventInterface.List(ctx, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.EventList{ListMeta: meta, Items: cachedItems[corev1.Event](objs)}, nil
+}
+
+type cachedNodes struct {
+	corev1client.NodeInterface
+	cache *cachedResource
+}
+
+func (n *cachedNodes) Get(ctx context.Context, name string, options metav1.GetOptions) (*corev1.Node, error) {
+	obj, err := n.cache.get("", name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*corev1.Node).DeepCopy(), nil
+}
+
+func (n *cachedNodes) List(ctx context.Context, options metav1.ListOptions) (*corev1.NodeList, error) {
+	objs, meta, cached, err := n.cache.list(metav1.NamespaceAll, options)
+	if !cached {
+		return n.NodeInterface.List(ctx, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.NodeList{ListMeta: meta, Items: cachedItems[corev1.Node](objs)}, nil
+}
+
+type cachedDeployments struct {
+	appsv1client.DeploymentInterface
+	namespace string
+	cache     *cachedResource
+}
+
+func (d *cachedDeployments) Get(ctx context.Context, name string, options metav1.GetOptions) (*appsv1.Deployment, error) {
+	obj, err := d.cache.get(d.namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*appsv1.Deployment).DeepCopy(), nil
+}
+
+func (d *cachedDeployments) List(ctx context.Context, options metav1.ListOptions) (*appsv1.DeploymentList, error) {
+	objs, meta, cached, err := d.cache.list(d.namespace, options)
+	if !cached {
+		return d.DeploymentInterface.List(ctx, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appsv1.DeploymentList{ListMeta: meta, Items: cachedItems[appsv1.Deployment](objs)}, nil
+}
+
+type cachedReplicaSets struct {
+	appsv1client.ReplicaSetInterface
+	namespace string
+	cache     *cachedResource
+}
+
+func (r *cachedReplicaSets) Get(ctx context.Context, name string, options metav1.GetOptions) (*appsv1.ReplicaSet, error) {
+	obj, err := r.cache.get(r.namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*appsv1.ReplicaSet).DeepCopy(), nil
+}
+
+func (r *cachedReplicaSets) List(ctx context.Context, options metav1.ListOptions) (*appsv1.ReplicaSetList, error) {
+	objs, meta, cached, err := r.cache.list(r.namespace, options)
+	if !cached {
+		return r.ReplicaSetInterface.List(ctx, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appsv1.ReplicaSetList{ListMeta: meta, Items: cachedItems[appsv1.ReplicaSet](objs)}, nil
+}
+
+type cachedStatefulSets struct {
+	appsv1client.StatefulSetInterface
+	namespace string
+	cache     *cachedResource
+}
+
+func (s *cachedStatefulSets) Get(ctx context.Context, name string, options metav1.GetOptions) (*appsv1.StatefulSet, error) {
+	obj, err := s.cache.get(s.namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*appsv1.StatefulSet).DeepCopy(), nil
+}
+
+func (s *cachedStatefulSets) List(ctx context.Context, options metav1.ListOptions) (*appsv1.StatefulSetList, error) {
+	objs, meta, cached, err := s.cache.list(s.namespace, options)
+	if !cached {
+		return s.StatefulSetInterface.List(ctx, options)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &appsv1.StatefulSetList{ListMeta: meta, Items: cachedItems[appsv1.StatefulSet](objs)}, nil
+}
@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestCachedResourceList(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	pod := func(namespace, name, app, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{"app": app}},
+			Spec:       corev1.PodSpec{NodeName: node},
+		}
+	}
+	for _, p := range []*corev1.Pod{
+		pod("shop", "web-2", "web", "node-a"),
+		pod("shop", "web-1", "web", "node-b"),
+		pod("shop", "api-1", "api", "node-a"),
+		pod("default", "web-1", "web", "node-a"),
+	} {
+		assert.NoError(t, indexer.Add(p))
+	}
+	pods := &cachedResource{indexer: indexer, resource: corev1.Resource("pods"), fields: podFields}
+
+	tests := []struct {
+		name          string
+		namespace     string
+		options       metav1.ListOptions
+		want          []string
+		wantSupported bool
+		wantErr       bool
+	}{
+		{name: "all namespaces, sorted", want: []string{"default/web-1", "shop/api-1", "shop/web-1", "shop/web-2"}, wantSupported: true},
+		{name: "one namespace", namespace: "shop", want: []string{"shop/api-1", "shop/web-1", "shop/web-2"}, wantSupported: true},
+		{name: "label selector", namespace: "shop", options: metav1.ListOptions{LabelSelector: "app=web"},
+			want: []string{"shop/web-1", "shop/web-2"}, wantSupported: true},
+		{name: "set label selector", options: metav1.ListOptions{LabelSelector: "app in (api)"},
+			want: []string{"shop/api-1"}, wantSupported: true},
+		{name: "nothing matches", options: metav1.ListOptions{LabelSelector: "app=db"}, wantSupported: true},
+		{name: "indexed field selector", options: metav1.ListOptions{FieldSelector: "spec.nodeName=node-a"},
+			want: []string{"default/web-1", "shop/api-1", "shop/web-2"}, wantSupported: true},
+		{name: "name field selector", namespace: "shop", options: metav1.ListOptions{FieldSelector: "metadata.name=web-1"},
+			want: []string{"shop/web-1"}, wantSupported: true},
+		{name: "label and field selectors", options: metav1.ListOptions{LabelSelector: "app=web", FieldSelector: "spec.nodeName=node-a"},
+			want: []string{"default/web-1", "shop/web-2"}, wantSupported: true},
+		{name: "limit above the item count", namespace: "default", options: metav1.ListOptions{Limit: 5},
+			want: []string{"default/web-1"}, wantSupported: true},
+		{name: "unindexed field goes to the API server", options: metav1.ListOptions{FieldSelector: "spec.schedulerName=default"}},
+		{name: "invalid cache continue token", options: metav1.ListOptions{Continue: cacheContinuePrefix + "!"}, wantSupported: true, wantErr: true},
+		{name: "continue token goes to the API server", options: metav1.ListOptions{Continue: "token"}},
+		{name: "invalid label selector", options: metav1.ListOptions{LabelSelector: "app in (web"}, wantSupported: true, wantErr: true},
+		{name: "invalid field selector", options: metav1.ListOptions{FieldSelector: "spec.nodeName"}, wantSupported: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs, _, supported, err := pods.list(tt.namespace, tt.options)
+			assert.Equal(t, tt.wantSupported, supported)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var names []string
+			for _, obj := range objs {
+				object := obj.(metav1.Object)
+				names = append(names, object.GetNamespace()+"/"+object.GetName())
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestCachedResourceListPages(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, key := range []string{"shop/web-3", "default/web-1", "shop/web-1", "shop/api-1", "shop/web-2"} {
+		namespace, name, _ := strings.Cut(key, "/")
+		assert.NoError(t, indexer.Add(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name,
+			Labels: map[string]string{"app": name[:3]}}}))
+	}
+	pods := &cachedResource{indexer: indexer, resource: corev1.Resource("pods"), fields: podFields}
+
+	tests := []struct {
+		name      string
+		namespace string
+		selector  string
+		limit     int64
+		want      [][]string
+	}{
+		{name: "all namespaces", limit: 2,
+			want: [][]string{{"default/web-1", "shop/api-1"}, {"shop/web-1", "shop/web-2"}, {"shop/web-3"}}},
+		{name: "one namespace", namespace: "shop", limit: 3,
+			want: [][]string{{"shop/api-1", "shop/web-1", "shop/web-2"}, {"shop/web-3"}}},
+		{name: "with a selector", selector: "app=web", limit: 2,
+			want: [][]string{{"default/web-1", "shop/web-1"}, {"shop/web-2", "shop/web-3"}}},
+		{name: "exact page", namespace: "shop", limit: 4,
+			want: [][]string{{"shop/api-1", "shop/web-1", "shop/web-2", "shop/web-3"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := metav1.ListOptions{Limit: tt.limit, LabelSelector: tt.selector}
+			var pages [][]string
+			for {
+				objs, meta, supported, err := pods.list(tt.namespace, options)
+				assert.True(t, supported)
+				assert.NoError(t, err)
+
+				var names []string
+				for _, obj := range objs {
+					object := obj.(metav1.Object)
+					names = append(names, object.GetNamespace()+"/"+object.GetName())
+				}
+				pages = append(pages, names)
+				if meta.Continue == "" {
+					assert.Nil(t, meta.RemainingItemCount)
+					break
+				}
+				if assert.NotNil(t, meta.RemainingItemCount) {
+					remaining := 0
+					for _, page := range tt.want[len(pages):] {
+						remaining += len(page)
+					}
+					assert.Equal(t, int64(remaining), *meta.RemainingItemCount)
+				}
+				if len(pages) > len(tt.want) {
+					break
+				}
+				options.Continue = meta.Continue
+			}
+			assert.Equal(t, tt.want, pages)
+		})
+	}
+}
@@ -3,13 +3,10 @@ package k8s
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
 )
 
 // Client Manages Kubernetes API Connections and embeds kubernetes.Interface
@@ -20,23 +17,9 @@ type Client struct {
 
 // NewClient Creates A New Kubernetes Client
 func NewClient() (*Client, error) {
-	var kubeconfig string
-
-	// Try To Find Kubeconfig In Home Directory
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = filepath.Join(home, ".kube", "config")
-	} else {
-		return nil, fmt.Errorf("Unable To Find Home Directory For Kubeconfig")
-	}
-
-	// Build Config From Kubeconfig File
-	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	config, err := LoadConfig()
 	if err != nil {
-		// Fall Back To In-Cluster Config
-		config, err = rest.InClusterConfig()
-		if err != nil {
-			return nil, fmt.Errorf("Failed To Get Kubernetes Config: %v", err)
-		}
+		return nil, fmt.Errorf("Failed To Get Kubernetes Config: %v", err)
 	}
 
 	// Create Clientset
@@ -52,8 +35,8 @@ func NewClient() (*Client, error) {
 }
 
 // TestConnection Verifies Kubernetes API Connectivity
-func (c *Client) TestConnection() error {
-	_, err := c.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+func (c *Client) TestConnection(ctx context.Context) error {
+	_, err := c.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return fmt.Errorf("Failed To Connect To Kubernetes API: %v", err)
 	}
@@ -0,0 +1,79 @@
+package k8s
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// kubeconfigPath and kubeContext override the kubeconfig file and context clients are
+// created from, as set with the --kubeconfig and --context flags
+var kubeconfigPath, kubeContext string
+
+// SetKubeconfig makes clients load their configuration from the given kubeconfig file
+// and context, like kubectl's --kubeconfig and --context flags. Empty values keep the
+// defaults: the files listed in $KUBECONFIG, or ~/.kube/config, and their current context.
+func SetKubeconfig(path, context string) {
+	kubeconfigPath, kubeContext = path, context
+}
+
+// Kubeconfig returns the kubeconfig file and context set with SetKubeconfig
+func Kubeconfig() (string, string) {
+	return kubeconfigPath, kubeContext
+}
+
+// impersonateUser and impersonateGroups are the identity API requests are made as, set
+// with the --as and --as-group flags
+var (
+	impersonateUser   string
+	impersonateGroups []string
+)
+
+// SetImpersonation makes clients act as the given user and groups, like kubectl's --as
+// and --as-group flags, so analysis only sees what that identity is allowed to see.
+// Groups require a user; an empty user turns impersonation off.
+func SetImpersonation(user string, groups []string) error {
+	if user == "" && len(groups) > 0 {
+		return fmt.Errorf("--as-group requires --as to name the user to impersonate")
+	}
+	impersonateUser, impersonateGroups = user, groups
+	return nil
+}
+
+// ConfigureImpersonation applies the identity set with SetImpersonation to a rest config
+func ConfigureImpersonation(config *rest.Config) {
+	if impersonateUser == "" {
+		return
+	}
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: impersonateUser,
+		Groups:   impersonateGroups,
+	}
+}
+
+// LoadConfig resolves the client configuration the way kubectl does, so the tool sees
+// the same cluster when run as a kubectl plugin: the --kubeconfig file, else the files
+// in $KUBECONFIG, else ~/.kube/config, using the --context context or the current one.
+// Without any kubeconfig it falls back to the in-cluster configuration.
+func LoadConfig() (*rest.Config, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfigPath
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: kubeContext}
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		if kubeconfigPath != "" || kubeContext != "" {
+			return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+		}
+		// Fall back to the in-cluster config when running in a pod
+		inCluster, inClusterErr := rest.InClusterConfig()
+		if inClusterErr != nil {
+			return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+		}
+		config = inCluster
+	}
+	ConfigureRetries(config)
+	ConfigureImpersonation(config)
+	return config, nil
+}
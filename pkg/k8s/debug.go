@@ -0,0 +1,145 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// debugContainerTimeout is how long to wait for an ephemeral debug container to start
+const debugContainerTimeout = 2 * time.Minute
+
+// AddDebugContainer adds an interactive ephemeral container running image to a pod and
+// waits for it to start. When target is set the debug container shares that container's
+// process namespace. It returns the name of the new container.
+func (c *Client) AddDebugContainer(ctx context.Context, namespace, podName, image, target string) (string, error) {
+	pod, err := c.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("Failed To Get Pod: %v", err)
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return "", fmt.Errorf("Pod %s Is %s, Debug Containers Require A Running Pod", podName, pod.Status.Phase)
+	}
+
+	if target != "" && !hasContainer(pod, target) {
+		return "", fmt.Errorf("Container %s Not Found In Pod %s", target, podName)
+	}
+
+	name := "debugger-" + utilrand.String(5)
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:                     name,
+			Image:                    image,
+			ImagePullPolicy:          corev1.PullIfNotPresent,
+			Stdin:                    true,
+			TTY:                      true,
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+		},
+		TargetContainerName: target,
+	})
+
+	_, err = c.CoreV1().Pods(namespace).UpdateEphemeralContainers(ctx, podName, pod, metav1.UpdateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("Failed To Add Ephemeral Container: %v", err)
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, time.Second, debugContainerTimeout, true, func(ctx context.Context) (bool, error) {
+		pod, err := c.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, status := range pod.Status.EphemeralContainerStatuses {
+			if status.Name != name {
+				continue
+			}
+			if status.State.Terminated != nil {
+				return false, fmt.Errorf("Debug Container Exited: %s", status.State.Terminated.Reason)
+			}
+			return status.State.Running != nil, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return name, fmt.Errorf("Debug Container %s Did Not Start: %v", name, err)
+	}
+
+	return name, nil
+}
+
+// Attach streams stdin and stdout to a running container through the attach subresource
+func (c *Client) Attach(ctx context.Context, namespace, podName, container string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+	req := c.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("attach").
+		VersionedParams(&corev1.PodAttachOptions{
+			Container: container,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    !tty,
+			TTY:       tty,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(c.Config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("Failed To Create Attach Stream: %v", err)
+	}
+
+	streamOptions := remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Tty:    tty,
+	}
+	if tty {
+		streamOptions.TerminalSizeQueue = newTerminalSize(stdout)
+	} else {
+		streamOptions.Stderr = stderr
+	}
+
+	return executor.StreamWithContext(ctx, streamOptions)
+}
+
+// terminalSize reports the size of the local terminal once so the remote shell
+// starts with matching dimensions
+type terminalSize struct {
+	size *remotecommand.TerminalSize
+}
+
+func newTerminalSize(out io.Writer) *terminalSize {
+	file, ok := out.(*os.File)
+	if !ok {
+		return &terminalSize{}
+	}
+	width, height, err := term.GetSize(int(file.Fd()))
+	if err != nil {
+		return &terminalSize{}
+	}
+	return &terminalSize{size: &remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}}
+}
+
+// Next returns the terminal size on the first call and nil afterwards, which ends resizing
+func (t *terminalSize) Next() *remotecommand.TerminalSize {
+	size := t.size
+	t.size = nil
+	return size
+}
+
+func hasContainer(pod *corev1.Pod, name string) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package k8s
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// filterTerm is one --include or --exclude value: a label selector when it contains
+// =, ! or a set expression in parentheses, otherwise a glob matched against names
+type filterTerm struct {
+	glob     string
+	selector labels.Selector
+}
+
+// ResourceFilter scopes bulk runs with --include and --exclude terms. A term is a label
+// selector such as app=payments or "tier in (web,api)", or a name glob such as
+// kube-* matched against the resource name and its namespace.
+type ResourceFilter struct {
+	include []filterTerm
+	exclude []filterTerm
+}
+
+// NewResourceFilter parses the include and exclude terms
+func NewResourceFilter(include, exclude []string) (*ResourceFilter, error) {
+	filter := &ResourceFilter{}
+	var err error
+	if filter.include, err = parseFilterTerms(include); err != nil {
+		return nil, fmt.Errorf("invalid --include: %v", err)
+	}
+	if filter.exclude, err = parseFilterTerms(exclude); err != nil {
+		return nil, fmt.Errorf("invalid --exclude: %v", err)
+	}
+	return filter, nil
+}
+
+func parseFilterTerms(values []string) ([]filterTerm, error) {
+	var terms []filterTerm
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if strings.ContainsAny(value, "=!(") {
+			selector, err := labels.Parse(value)
+			if err != nil {
+				return nil, err
+			}
+			terms = append(terms, filterTerm{selector: selector})
+			continue
+		}
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("bad pattern %q: %v", value, err)
+		}
+		terms = append(terms, filterTerm{glob: value})
+	}
+	return terms, nil
+}
+
+// Empty reports whether the filter has no terms and so matches everything
+func (f *ResourceFilter) Empty() bool {
+	return f == nil || len(f.include) == 0 && len(f.exclude) == 0
+}
+
+// HasSelectors reports whether any term is a label selector, which needs the labels
+// of the resources to be matched
+func (f *ResourceFilter) HasSelectors() bool {
+	if f == nil {
+		return false
+	}
+	for _, term := range append(append([]filterTerm{}, f.include...), f.exclude...) {
+		if term.selector != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether a resource is kept: it matches no exclude term and, when
+// there are include terms, at least one of them
+func (f *ResourceFilter) Matches(name, namespace string, resourceLabels map[string]string) bool {
+	if f == nil {
+		return true
+	}
+	for _, term := range f.exclude {
+		if term.matches(name, namespace, resourceLabels) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, term := range f.include {
+		if term.matches(name, namespace, resourceLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t filterTerm) matches(name, namespace string, resourceLabels map[string]string) bool {
+	if t.selector != nil {
+		return t.selector.Matches(labels.Set(resourceLabels))
+	}
+	if matched, _ := path.Match(t.glob, name); matched {
+		return true
+	}
+	matched, _ := path.Match(t.glob, namespace)
+	return namespace != "" && matched
+}
+
+// FilterNamespaces keeps the namespaces the filter matches by name. Commands that only
+// filter namespaces cannot apply label selectors consistently with the commands filtering
+// resources, so a filter with selectors is rejected.
+func FilterNamespaces(namespaces []string, filter *ResourceFilter) ([]string, error) {
+	if filter.Empty() {
+		return namespaces, nil
+	}
+	if filter.HasSelectors() {
+		return nil, fmt.Errorf("--include and --exclude only take namespace name globs here, not label selectors")
+	}
+
+	var kept []string
+	for _, namespace := range namespaces {
+		if filter.Matches(namespace, "", nil) {
+			kept = append(kept, namespace)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,96 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilterTerms(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []string
+		globs     []string
+		selectors []string
+		wantErr   bool
+	}{
+		{name: "glob", values: []string{"kube-*"}, globs: []string{"kube-*"}},
+		{name: "equality selector", values: []string{"app=payments"}, selectors: []string{"app=payments"}},
+		{name: "negated selector", values: []string{"!canary"}, selectors: []string{"!canary"}},
+		{name: "set selector", values: []string{"tier in (web,api)"}, selectors: []string{"tier in (api,web)"}},
+		{name: "blank values are skipped", values: []string{"", "  "}},
+		{name: "mixed", values: []string{" web-* ", "tier=web"}, globs: []string{"web-*"}, selectors: []string{"tier=web"}},
+		{name: "bad glob", values: []string{"web-[a"}, wantErr: true},
+		{name: "bad selector", values: []string{"tier in (web"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms, err := parseFilterTerms(tt.values)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var globs, selectors []string
+			for _, term := range terms {
+				if term.selector != nil {
+					selectors = append(selectors, term.selector.String())
+				} else {
+					globs = append(globs, term.glob)
+				}
+			}
+			assert.Equal(t, tt.globs, globs)
+			assert.Equal(t, tt.selectors, selectors)
+		})
+	}
+}
+
+func TestResourceFilterMatches(t *testing.T) {
+	web := map[string]string{"tier": "web"}
+	api := map[string]string{"tier": "api"}
+
+	tests := []struct {
+		name      string
+		include   []string
+		exclude   []string
+		resource  string
+		namespace string
+		labels    map[string]string
+		want      bool
+	}{
+		{name: "empty filter keeps everything", resource: "web-1", namespace: "shop", want: true},
+		{name: "include glob on name", include: []string{"web-*"}, resource: "web-1", namespace: "shop", want: true},
+		{name: "include glob on namespace", include: []string{"sh*"}, resource: "web-1", namespace: "shop", want: true},
+		{name: "include glob misses", include: []string{"api-*"}, resource: "web-1", namespace: "shop", want: false},
+		{name: "include selector", include: []string{"tier=web"}, resource: "web-1", labels: web, want: true},
+		{name: "include selector misses", include: []string{"tier=web"}, resource: "api-1", labels: api, want: false},
+		{name: "any include term matches", include: []string{"tier=api", "web-*"}, resource: "web-1", labels: web, want: true},
+		{name: "exclude glob", exclude: []string{"kube-*"}, resource: "coredns", namespace: "kube-system", want: false},
+		{name: "exclude selector", exclude: []string{"tier=web"}, resource: "web-1", labels: web, want: false},
+		{name: "exclude wins over include", include: []string{"tier=web"}, exclude: []string{"*-canary"}, resource: "web-canary", labels: web, want: false},
+		{name: "include with unrelated exclude", include: []string{"tier=web"}, exclude: []string{"kube-*"}, resource: "web-1", namespace: "shop", labels: web, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewResourceFilter(tt.include, tt.exclude)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, filter.Matches(tt.resource, tt.namespace, tt.labels))
+		})
+	}
+}
+
+func TestFilterNamespaces(t *testing.T) {
+	namespaces := []string{"default", "kube-system", "shop"}
+
+	filter, _ := NewResourceFilter(nil, []string{"kube-*"})
+	kept, err := FilterNamespaces(namespaces, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"default", "shop"}, kept)
+
+	filter, _ = NewResourceFilter([]string{"tier=web"}, nil)
+	_, err = FilterNamespaces(namespaces, filter)
+	assert.Error(t, err, "label selectors cannot filter namespaces")
+}
@@ -0,0 +1,44 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResolveNamespaces expands a namespace value into the namespaces it names. The value is
+// a single namespace or a comma-separated list; all selects every namespace instead.
+// "all" is not special, since it is a valid namespace name.
+func ResolveNamespaces(ctx context.Context, client kubernetes.Interface, value string, all bool) ([]string, error) {
+	if all {
+		list, err := client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %v", err)
+		}
+		namespaces := make([]string, 0, len(list.Items))
+		for _, namespace := range list.Items {
+			namespaces = append(namespaces, namespace.Name)
+		}
+		sort.Strings(namespaces)
+		return namespaces, nil
+	}
+
+	var namespaces []string
+	seen := make(map[string]bool)
+	for _, namespace := range strings.Split(value, ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" || seen[namespace] {
+			continue
+		}
+		seen[namespace] = true
+		namespaces = append(namespaces, namespace)
+	}
+	if len(namespaces) == 0 {
+		return nil, fmt.Errorf("no namespace given")
+	}
+	return namespaces, nil
+}
@@ -0,0 +1,102 @@
+package k8s
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// clientQPS and clientBurst replace client-go's defaults of 5 and 10, which throttle
+	// analyses listing many namespaces or clusters far more than the API server would
+	clientQPS   = 50
+	clientBurst = 100
+
+	// maxRetries is how many times a throttled or failed read is retried
+	maxRetries = 4
+	// baseRetryDelay is the first backoff delay, doubled on each retry
+	baseRetryDelay = 500 * time.Millisecond
+	// maxRetryDelay caps the backoff
+	maxRetryDelay = 30 * time.Second
+)
+
+// ConfigureRetries raises the client-side rate limit of a rest config and makes its
+// read requests retry with backoff when the API server throttles them (429) or fails
+// with a transient server error. Responses with a Retry-After header are left to
+// client-go, which already waits and retries those.
+func ConfigureRetries(config *rest.Config) {
+	if config.QPS == 0 {
+		config.QPS = clientQPS
+	}
+	if config.Burst == 0 {
+		config.Burst = clientBurst
+	}
+	config.Wrap(func(next http.RoundTripper) http.RoundTripper {
+		return &retryTransport{next: next, baseDelay: baseRetryDelay}
+	})
+}
+
+// retryTransport retries GET requests on 429 and 5xx responses without a Retry-After
+// header. Other methods are passed through, since they may not be safe to repeat.
+type retryTransport struct {
+	next http.RoundTripper
+	// baseDelay is the first backoff delay, doubled on each retry
+	baseDelay time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || attempt >= maxRetries || !retryable(resp) {
+			return resp, err
+		}
+
+		delay := retryDelay(t.baseDelay, attempt)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether a response is a throttled or transient failure this
+// transport retries. Those with Retry-After are retried by client-go's REST client,
+// and retrying them here as well would multiply the attempts and the wait.
+func retryable(resp *http.Response) bool {
+	if resp.Header.Get("Retry-After") != "" {
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryDelay returns an exponential backoff from base with up to 50% jitter, so
+// concurrent callers do not retry in lockstep, capped at maxRetryDelay
+func retryDelay(base time.Duration, attempt int) time.Duration {
+	delay := base << attempt
+	if delay <= 0 || delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	delay += time.Duration(rand.Int63n(int64(delay)/2 + 1))
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
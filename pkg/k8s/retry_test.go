@@ -0,0 +1,83 @@
+package k8s
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     time.Duration
+		attempt  int
+		min, max time.Duration
+	}{
+		{name: "first retry", base: baseRetryDelay, attempt: 0, min: 500 * time.Millisecond, max: 750 * time.Millisecond},
+		{name: "doubles per attempt", base: baseRetryDelay, attempt: 2, min: 2 * time.Second, max: 3 * time.Second},
+		{name: "capped", base: baseRetryDelay, attempt: 10, min: maxRetryDelay, max: maxRetryDelay},
+		{name: "jitter cannot pass the cap", base: 20 * time.Second, attempt: 0, min: 20 * time.Second, max: maxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				delay := retryDelay(tt.base, tt.attempt)
+				assert.GreaterOrEqual(t, delay, tt.min)
+				assert.LessOrEqual(t, delay, tt.max)
+			}
+		})
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name string
+		// responses are the statuses the server answers with in turn, repeating the last
+		responses  []int
+		retryAfter string
+		method     string
+		wantStatus int
+		wantCalls  int32
+	}{
+		{name: "success is not retried", responses: []int{200}, method: http.MethodGet, wantStatus: 200, wantCalls: 1},
+		{name: "transient failures are retried", responses: []int{503, 500, 200}, method: http.MethodGet, wantStatus: 200, wantCalls: 3},
+		{name: "throttling is retried", responses: []int{429, 200}, method: http.MethodGet, wantStatus: 200, wantCalls: 2},
+		{name: "retries are bounded", responses: []int{503}, method: http.MethodGet, wantStatus: 503, wantCalls: maxRetries + 1},
+		{name: "client errors are not retried", responses: []int{404}, method: http.MethodGet, wantStatus: 404, wantCalls: 1},
+		{name: "Retry-After is left to client-go", responses: []int{429, 200}, retryAfter: "1", method: http.MethodGet, wantStatus: 429, wantCalls: 1},
+		{name: "writes are not retried", responses: []int{503, 200}, method: http.MethodPost, wantStatus: 503, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := int(atomic.AddInt32(&calls, 1)) - 1
+				if call >= len(tt.responses) {
+					call = len(tt.responses) - 1
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.responses[call])
+			}))
+			defer server.Close()
+
+			transport := &retryTransport{next: http.DefaultTransport, baseDelay: time.Millisecond}
+			req, err := http.NewRequest(tt.method, server.URL, strings.NewReader(""))
+			assert.NoError(t, err)
+
+			resp, err := transport.RoundTrip(req)
+			assert.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// StatsSummary is the subset of the kubelet /stats/summary response k8s-lens uses
+type StatsSummary struct {
+	Pods []PodStats `json:"pods"`
+}
+
+// PodStats is the usage the kubelet reports for a pod
+type PodStats struct {
+	PodRef struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"podRef"`
+	EphemeralStorage *struct {
+		UsedBytes *uint64 `json:"usedBytes"`
+	} `json:"ephemeral-storage"`
+	Volumes []struct {
+		Name      string  `json:"name"`
+		UsedBytes *uint64 `json:"usedBytes"`
+	} `json:"volume"`
+}
+
+// NodeStatsSummary reads the kubelet summary stats of a node through the API server proxy
+func NodeStatsSummary(ctx context.Context, client kubernetes.Interface, nodeName string) (*StatsSummary, error) {
+	restClient, ok := client.CoreV1().RESTClient().(*rest.RESTClient)
+	if !ok || restClient == nil {
+		return nil, fmt.Errorf("client cannot proxy to the kubelet")
+	}
+	data, err := restClient.Get().AbsPath("/api/v1/nodes", nodeName, "proxy", "stats", "summary").DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubelet stats of node %s: %v", nodeName, err)
+	}
+
+	var summary StatsSummary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, fmt.Errorf("failed to decode kubelet stats of node %s: %v", nodeName, err)
+	}
+	return &summary, nil
+}
@@ -44,35 +44,39 @@ type Anomaly struct {
 }
 
 // DetectNamespaceAnomalies analyzes a namespace for unusual patterns
-func (a *AnomalyDetector) DetectNamespaceAnomalies(namespace string) (*AnomalyReport, error) {
-	report := &AnomalyReport{
-		Namespace: namespace,
-		Timestamp: time.Now(),
-	}
-
+func (a *AnomalyDetector) DetectNamespaceAnomalies(ctx context.Context, namespace string) (*AnomalyReport, error) {
 	// Get all pods in the namespace
-	pods, err := a.client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	pods, err := a.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pods: %v", err)
 	}
 
-	report.TotalPods = len(pods.Items)
+	return a.detectAnomalies(namespace, pods.Items), nil
+}
+
+// detectAnomalies evaluates the pods of a namespace for anomalies
+func (a *AnomalyDetector) detectAnomalies(namespace string, pods []corev1.Pod) *AnomalyReport {
+	report := &AnomalyReport{
+		Namespace: namespace,
+		TotalPods: len(pods),
+		Timestamp: time.Now(),
+	}
 
 	// Analyze each pod for anomalies
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		podAnomalies := a.analyzePodAnomalies(&pod)
 		report.Anomalies = append(report.Anomalies, podAnomalies...)
 	}
 
 	// Analyze namespace-level anomalies
-	nsAnomalies := a.analyzeNamespaceLevelAnomalies(pods.Items)
+	nsAnomalies := a.analyzeNamespaceLevelAnomalies(pods)
 	report.Anomalies = append(report.Anomalies, nsAnomalies...)
 
 	// Calculate overall anomaly score
 	report.Score = a.calculateAnomalyScore(report.Anomalies)
 	report.Recommendations = a.generateRecommendations(report.Anomalies)
 
-	return report, nil
+	return report
 }
 
 func (a *AnomalyDetector) analyzePodAnomalies(pod *corev1.Pod) []Anomaly {
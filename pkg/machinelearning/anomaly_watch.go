@@ -0,0 +1,101 @@
+package machinelearning
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// AnomalyHandler receives the anomalies that newly appeared in an evaluation, along
+// with the report of that evaluation
+type AnomalyHandler func(report *AnomalyReport, anomalies []Anomaly)
+
+// key identifies an anomaly across evaluations. The message is left out, since it can
+// change while the anomaly lasts, e.g. how long a pod has been pending.
+func (a Anomaly) key() string {
+	return a.Type + "|" + a.Resource
+}
+
+// WatchNamespaceAnomalies keeps the pods of a namespace in an informer and evaluates
+// them for anomalies every time a pod changes, and at least every resync so anomalies
+// that depend on time, like long pending pods, are noticed. handler receives only the
+// anomalies that were not present in the previous evaluation; an anomaly that goes
+// away and comes back is reported again. It returns when the detector's context is done.
+func (a *AnomalyDetector) WatchNamespaceAnomalies(ctx context.Context, namespace string, resync time.Duration, handler AnomalyHandler) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(a.client, resync, informers.WithNamespace(namespace))
+	podInformer := factory.Core().V1().Pods()
+
+	// Changes are coalesced, so a burst of pod updates leads to a single evaluation
+	changed := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	if _, err := podInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	}); err != nil {
+		return fmt.Errorf("failed to watch pods: %v", err)
+	}
+
+	factory.Start(ctx.Done())
+	defer factory.Shutdown()
+	for informer, synced := range factory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to sync informer cache for %v", informer)
+		}
+	}
+
+	reported := make(map[string]bool)
+	evaluate := func() error {
+		cached, err := podInformer.Lister().Pods(namespace).List(labels.Everything())
+		if err != nil {
+			return fmt.Errorf("failed to list pods: %v", err)
+		}
+		pods := make([]corev1.Pod, 0, len(cached))
+		for _, pod := range cached {
+			pods = append(pods, *pod)
+		}
+
+		report := a.detectAnomalies(namespace, pods)
+		current := make(map[string]bool)
+		var appeared []Anomaly
+		for _, anomaly := range report.Anomalies {
+			current[anomaly.key()] = true
+			if !reported[anomaly.key()] {
+				appeared = append(appeared, anomaly)
+			}
+		}
+		reported = current
+
+		if len(appeared) > 0 {
+			handler(report, appeared)
+		}
+		return nil
+	}
+
+	if err := evaluate(); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+			if err := evaluate(); err != nil {
+				return err
+			}
+		}
+	}
+}
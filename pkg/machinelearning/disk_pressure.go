@@ -0,0 +1,304 @@
+package machinelearning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
+)
+
+const (
+	// defaultEvictionThreshold is the kubelet's default nodefs.available hard eviction threshold
+	defaultEvictionThreshold = 0.10
+	// defaultDiskPressureHorizon is how far ahead disk pressure is predicted
+	defaultDiskPressureHorizon = 24 * time.Hour
+	// maxAtRiskPods is the number of eviction candidates listed per node
+	maxAtRiskPods = 5
+)
+
+// FilesystemMetrics provides node filesystem metrics through Prometheus queries
+type FilesystemMetrics interface {
+	QueryAt(query string, at time.Time) ([]float64, error)
+}
+
+// DiskPressurePredictor predicts which pods will be evicted when nodes run out of disk
+type DiskPressurePredictor struct {
+	client            kubernetes.Interface
+	metrics           FilesystemMetrics
+	evictionThreshold float64
+	horizon           time.Duration
+}
+
+// NewDiskPressurePredictor creates a new disk pressure predictor. Node filesystem usage
+// is read from node-exporter metrics whose instance label is the node name, as set up
+// by kube-prometheus.
+func NewDiskPressurePredictor(client kubernetes.Interface, metrics FilesystemMetrics) *DiskPressurePredictor {
+	return &DiskPressurePredictor{
+		client:            client,
+		metrics:           metrics,
+		evictionThreshold: defaultEvictionThreshold,
+		horizon:           defaultDiskPressureHorizon,
+	}
+}
+
+// SetEvictionThreshold sets the fraction of available node filesystem below which the
+// kubelet evicts pods, matching the kubelet's evictionHard nodefs.available setting
+func (d *DiskPressurePredictor) SetEvictionThreshold(fraction float64) {
+	d.evictionThreshold = fraction
+}
+
+// SetHorizon sets how far ahead disk pressure is predicted
+func (d *DiskPressurePredictor) SetHorizon(horizon time.Duration) {
+	d.horizon = horizon
+}
+
+// podDiskUsage is the ephemeral storage a pod requests and uses
+type podDiskUsage struct {
+	pod           *corev1.Pod
+	request       int64
+	used          int64
+	emptyDirUsed  int64
+	usage         bool
+	exceedRequest bool
+}
+
+// PredictEvictions predicts, for every node whose root filesystem will reach the
+// eviction threshold within the horizon, the time it does and the pods the kubelet
+// will evict first
+func (d *DiskPressurePredictor) PredictEvictions(ctx context.Context) (*PredictionReport, error) {
+	report := &PredictionReport{
+		GeneratedAt: time.Now(),
+		TimeHorizon: d.horizon,
+	}
+
+	nodes, err := d.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %v", err)
+	}
+
+	for _, node := range nodes.Items {
+		prediction, err := d.predictNode(ctx, &node, report.GeneratedAt)
+		if err != nil {
+			return nil, err
+		}
+		if prediction != nil {
+			report.Predictions = append(report.Predictions, *prediction)
+		}
+	}
+
+	sort.Slice(report.Predictions, func(i, j int) bool {
+		return report.Predictions[i].ExpectedTime.Before(report.Predictions[j].ExpectedTime)
+	})
+	report.Confidence = overallConfidence(report.Predictions)
+
+	return report, nil
+}
+
+// predictNode returns the disk pressure prediction for a node, or nil if the node
+// will stay below the eviction threshold within the horizon
+func (d *DiskPressurePredictor) predictNode(ctx context.Context, node *corev1.Node, now time.Time) (*Prediction, error) {
+	filesystem := fmt.Sprintf(`instance="%s", mountpoint="/"`, node.Name)
+	available, err := d.metrics.QueryAt(fmt.Sprintf(`node_filesystem_avail_bytes{%s} / node_filesystem_size_bytes{%s}`, filesystem, filesystem), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query filesystem usage of node %s: %v", node.Name, err)
+	}
+	if len(available) == 0 {
+		return nil, nil
+	}
+
+	// Rate of change of the available fraction per second, negative while the disk fills up
+	var rate float64
+	trend, err := d.metrics.QueryAt(fmt.Sprintf(`deriv(node_filesystem_avail_bytes{%s}[1h]) / node_filesystem_size_bytes{%s}`, filesystem, filesystem), now)
+	if err == nil && len(trend) > 0 {
+		rate = trend[0]
+	}
+
+	var eta time.Duration
+	switch {
+	case available[0] <= d.evictionThreshold || nodeUnderDiskPressure(node):
+		eta = 0
+	case rate < 0:
+		eta = time.Duration((available[0] - d.evictionThreshold) / -rate * float64(time.Second))
+		if eta > d.horizon {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	candidates, err := d.evictionCandidates(ctx, node.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	prediction := &Prediction{
+		Type:         "DiskPressureEviction",
+		Resource:     node.Name,
+		ExpectedTime: now.Add(eta),
+	}
+	for _, candidate := range candidates {
+		prediction.Pods = append(prediction.Pods, describeCandidate(candidate))
+	}
+
+	switch {
+	case eta == 0:
+		prediction.Message = fmt.Sprintf("Node %s is at the disk eviction threshold with %.1f%% of its filesystem available; pods are being evicted",
+			node.Name, available[0]*100)
+		prediction.Probability = 0.95
+		prediction.Impact = "Critical"
+	case eta <= 6*time.Hour:
+		prediction.Message = fmt.Sprintf("Node %s will reach the %.0f%% disk eviction threshold in about %s (%.1f%% available now)",
+			node.Name, d.evictionThreshold*100, eta.Round(time.Minute), available[0]*100)
+		prediction.Probability = 0.8
+		prediction.Impact = "High"
+	default:
+		prediction.Message = fmt.Sprintf("Node %s will reach the %.0f%% disk eviction threshold in about %s (%.1f%% available now)",
+			node.Name, d.evictionThreshold*100, eta.Round(time.Minute), available[0]*100)
+		prediction.Probability = 0.6
+		prediction.Impact = "Medium"
+	}
+
+	prediction.Recommendation = "Free disk space on the node (unused images, logs), set ephemeral-storage requests and limits, and cap emptyDir volumes with sizeLimit"
+	return prediction, nil
+}
+
+// evictionCandidates returns the pods on a node in the order the kubelet evicts them
+// under disk pressure: pods using more ephemeral storage than they request first, then
+// by priority, then by how far usage exceeds the request
+func (d *DiskPressurePredictor) evictionCandidates(ctx context.Context, nodeName string) ([]podDiskUsage, error) {
+	pods, err := d.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s: %v", nodeName, err)
+	}
+
+	// Usage is best effort: without access to the kubelet, pods are ranked by requests only
+	stats := d.podStorageStats(ctx, nodeName)
+
+	var candidates []podDiskUsage
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		candidate := podDiskUsage{pod: pod}
+		for _, container := range pod.Spec.Containers {
+			candidate.request += container.Resources.Requests.StorageEphemeral().Value()
+		}
+		if usage, ok := stats[pod.Namespace+"/"+pod.Name]; ok {
+			candidate.usage = true
+			candidate.used = usage.used
+			for _, volume := range pod.Spec.Volumes {
+				if volume.EmptyDir != nil && volume.EmptyDir.Medium != corev1.StorageMediumMemory {
+					candidate.emptyDirUsed += usage.volumes[volume.Name]
+				}
+			}
+			candidate.exceedRequest = usage.used > candidate.request
+		} else {
+			candidate.exceedRequest = candidate.request == 0
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.exceedRequest != b.exceedRequest {
+			return a.exceedRequest
+		}
+		if podPriority(a.pod) != podPriority(b.pod) {
+			return podPriority(a.pod) < podPriority(b.pod)
+		}
+		return a.used-a.request > b.used-b.request
+	})
+
+	if len(candidates) > maxAtRiskPods {
+		candidates = candidates[:maxAtRiskPods]
+	}
+	return candidates, nil
+}
+
+// storageStats is the ephemeral storage usage of a pod from the kubelet summary API
+type storageStats struct {
+	used    int64
+	volumes map[string]int64
+}
+
+// podStorageStats reads per-pod ephemeral storage usage from the node's kubelet through
+// the API server proxy. It returns no stats if the kubelet cannot be reached.
+func (d *DiskPressurePredictor) podStorageStats(ctx context.Context, nodeName string) map[string]storageStats {
+	stats := make(map[string]storageStats)
+
+	summary, err := k8s.NodeStatsSummary(ctx, d.client, nodeName)
+	if err != nil {
+		return stats
+	}
+
+	for _, pod := range summary.Pods {
+		usage := storageStats{volumes: make(map[string]int64)}
+		if pod.EphemeralStorage != nil && pod.EphemeralStorage.UsedBytes != nil {
+			usage.used = int64(*pod.EphemeralStorage.UsedBytes)
+		}
+		for _, volume := range pod.Volumes {
+			if volume.UsedBytes != nil {
+				usage.volumes[volume.Name] = int64(*volume.UsedBytes)
+			}
+		}
+		stats[pod.PodRef.Namespace+"/"+pod.PodRef.Name] = usage
+	}
+	return stats
+}
+
+// describeCandidate summarizes why a pod is an eviction candidate
+func describeCandidate(candidate podDiskUsage) string {
+	var details []string
+	if candidate.request == 0 {
+		details = append(details, "no ephemeral-storage request")
+	} else {
+		details = append(details, fmt.Sprintf("requests %s", formatBytes(candidate.request)))
+	}
+	if candidate.usage {
+		details = append(details, fmt.Sprintf("uses %s", formatBytes(candidate.used)))
+		if candidate.emptyDirUsed > 0 {
+			details = append(details, fmt.Sprintf("%s in emptyDir", formatBytes(candidate.emptyDirUsed)))
+		}
+	}
+	return fmt.Sprintf("%s/%s (%s)", candidate.pod.Namespace, candidate.pod.Name, strings.Join(details, ", "))
+}
+
+func nodeUnderDiskPressure(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeDiskPressure && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func podPriority(pod *corev1.Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return 0
+}
+
+func formatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%dB", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -41,10 +41,12 @@ type Prediction struct {
 	ExpectedTime   time.Time
 	Impact         string // Low, Medium, High, Critical
 	Recommendation string
+	// Pods are the pods affected by the prediction, most affected first
+	Pods []string
 }
 
 // PredictDeploymentFailures analyzes deployment for potential future issues
-func (p *PredictiveAnalyzer) PredictDeploymentFailures(deploymentName, namespace string) (*PredictionReport, error) {
+func (p *PredictiveAnalyzer) PredictDeploymentFailures(ctx context.Context, deploymentName, namespace string) (*PredictionReport, error) {
 	report := &PredictionReport{
 		Namespace:   namespace,
 		GeneratedAt: time.Now(),
@@ -52,13 +54,13 @@ func (p *PredictiveAnalyzer) PredictDeploymentFailures(deploymentName, namespace
 	}
 
 	// Get deployment
-	deployment, err := p.client.AppsV1().Deployments(namespace).Get(context.TODO(), deploymentName, metav1.GetOptions{})
+	deployment, err := p.client.AppsV1().Deployments(namespace).Get(ctx, deploymentName, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get deployment: %v", err)
 	}
 
 	// Get related pods
-	pods, err := p.client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{
+	pods, err := p.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: metav1.FormatLabelSelector(deployment.Spec.Selector),
 	})
 	if err != nil {
@@ -67,7 +69,7 @@ func (p *PredictiveAnalyzer) PredictDeploymentFailures(deploymentName, namespace
 
 	predictions := p.analyzeDeploymentPatterns(deployment, pods.Items)
 	report.Predictions = predictions
-	report.Confidence = p.calculateOverallConfidence(predictions)
+	report.Confidence = overallConfidence(predictions)
 
 	return report, nil
 }
@@ -201,7 +203,7 @@ func (p *PredictiveAnalyzer) predictAvailabilityRisks(deployment *appsv1.Deploym
 	return predictions
 }
 
-func (p *PredictiveAnalyzer) calculateOverallConfidence(predictions []Prediction) float64 {
+func overallConfidence(predictions []Prediction) float64 {
 	if len(predictions) == 0 {
 		return 1.0 // High confidence when no issues predicted
 	}
@@ -48,6 +48,28 @@ type ClusterReport struct {
 	HealthyNodes int
 	TotalPods    int
 	HealthStatus string
+	Metrics      *ClusterMetrics
+}
+
+// ClusterMetrics contains the resource utilization of a cluster from Prometheus
+type ClusterMetrics struct {
+	CPUCapacity    float64 // cores
+	CPUUsage       float64 // cores
+	MemoryCapacity float64 // GB
+	MemoryUsage    float64 // GB
+	Error          string
+}
+
+// Saturation returns the highest of the CPU and memory utilization ratios
+func (m *ClusterMetrics) Saturation() float64 {
+	saturation := 0.0
+	if m.CPUCapacity > 0 {
+		saturation = m.CPUUsage / m.CPUCapacity
+	}
+	if m.MemoryCapacity > 0 && m.MemoryUsage/m.MemoryCapacity > saturation {
+		saturation = m.MemoryUsage / m.MemoryCapacity
+	}
+	return saturation
 }
 
 // FederatedReport contains analysis across all clusters
@@ -63,6 +85,15 @@ type FederatedSummary struct {
 	TotalNodes      int
 	TotalPods       int
 	OverallHealth   string
+
+	// Aggregated utilization across the clusters that reported metrics
+	ClustersWithMetrics  int
+	TotalCPUCapacity     float64
+	TotalCPUUsage        float64
+	TotalMemoryCapacity  float64
+	TotalMemoryUsage     float64
+	MostSaturatedCluster string
+	MaxSaturation        float64
 }
 
 func (c *ClusterComparison) analyzeDifferences() {
@@ -139,6 +170,19 @@ func (f *FederatedReport) generateSummary() {
 		if report.HealthStatus == "Healthy" {
 			f.Summary.HealthyClusters++
 		}
+
+		if report.Metrics != nil && report.Metrics.Error == "" {
+			f.Summary.ClustersWithMetrics++
+			f.Summary.TotalCPUCapacity += report.Metrics.CPUCapacity
+			f.Summary.TotalCPUUsage += report.Metrics.CPUUsage
+			f.Summary.TotalMemoryCapacity += report.Metrics.MemoryCapacity
+			f.Summary.TotalMemoryUsage += report.Metrics.MemoryUsage
+
+			if saturation := report.Metrics.Saturation(); saturation > f.Summary.MaxSaturation {
+				f.Summary.MaxSaturation = saturation
+				f.Summary.MostSaturatedCluster = report.Name
+			}
+		}
 	}
 
 	if f.Summary.HealthyClusters == f.Summary.TotalClusters {
@@ -188,6 +232,16 @@ func (f *FederatedReport) GenerateFederatedReport() string {
 		report += fmt.Sprintf("  Nodes: %d/%d healthy\n", clusterReport.HealthyNodes, clusterReport.TotalNodes)
 		report += fmt.Sprintf("  Pods: %d\n", clusterReport.TotalPods)
 		report += fmt.Sprintf("  Status: %s\n", clusterReport.HealthStatus)
+		if metrics := clusterReport.Metrics; metrics != nil {
+			if metrics.Error != "" {
+				report += fmt.Sprintf("  Metrics: unavailable (%s)\n", metrics.Error)
+			} else {
+				report += fmt.Sprintf("  CPU: %.1f/%.1f cores (%.0f%%)\n",
+					metrics.CPUUsage, metrics.CPUCapacity, percent(metrics.CPUUsage, metrics.CPUCapacity))
+				report += fmt.Sprintf("  Memory: %.1f/%.1f GB (%.0f%%)\n",
+					metrics.MemoryUsage, metrics.MemoryCapacity, percent(metrics.MemoryUsage, metrics.MemoryCapacity))
+			}
+		}
 		report += "  ---\n"
 	}
 
@@ -198,9 +252,27 @@ func (f *FederatedReport) GenerateFederatedReport() string {
 	report += fmt.Sprintf("  Total Pods: %d\n", f.Summary.TotalPods)
 	report += fmt.Sprintf("  Overall Health: %s\n", f.Summary.OverallHealth)
 
+	if f.Summary.ClustersWithMetrics > 0 {
+		report += fmt.Sprintf("\nFleet Utilization (%d cluster(s) with metrics):\n", f.Summary.ClustersWithMetrics)
+		report += fmt.Sprintf("  CPU: %.1f/%.1f cores (%.0f%%)\n", f.Summary.TotalCPUUsage, f.Summary.TotalCPUCapacity,
+			percent(f.Summary.TotalCPUUsage, f.Summary.TotalCPUCapacity))
+		report += fmt.Sprintf("  Memory: %.1f/%.1f GB (%.0f%%)\n", f.Summary.TotalMemoryUsage, f.Summary.TotalMemoryCapacity,
+			percent(f.Summary.TotalMemoryUsage, f.Summary.TotalMemoryCapacity))
+		if f.Summary.MostSaturatedCluster != "" {
+			report += fmt.Sprintf("  Most Saturated Cluster: %s (%.0f%%)\n", f.Summary.MostSaturatedCluster, f.Summary.MaxSaturation*100)
+		}
+	}
+
 	return report
 }
 
+func percent(value, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return value / total * 100
+}
+
 func abs(x int) int {
 	if x < 0 {
 		return -x
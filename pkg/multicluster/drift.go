@@ -0,0 +1,211 @@
+package multicluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DriftReport lists how a resource in each context differs from the baseline context
+type DriftReport struct {
+	ResourceType string
+	Name         string
+	Namespace    string
+	Baseline     string
+	Compared     []string
+	Drifts       []FieldDrift
+	Missing      []string
+	Errors       map[string]string
+}
+
+// FieldDrift is a single field whose value differs from the baseline
+type FieldDrift struct {
+	Context       string
+	Field         string
+	BaselineValue string
+	Value         string
+}
+
+// DetectDeploymentDrift compares a deployment in every loaded context against the
+// baseline context, reporting image, replica, env var and resource differences
+func (c *ClusterManager) DetectDeploymentDrift(ctx context.Context, name, namespace, baseline string) (*DriftReport, error) {
+	baselineContext, err := c.GetContext(baseline)
+	if err != nil {
+		return nil, fmt.Errorf("baseline %v", err)
+	}
+
+	baselineDeployment, err := baselineContext.Client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment %s from baseline context %s: %v", name, baseline, err)
+	}
+	baselineFields := deploymentFields(baselineDeployment)
+
+	report := &DriftReport{
+		ResourceType: "deployment",
+		Name:         name,
+		Namespace:    namespace,
+		Baseline:     baseline,
+		Errors:       make(map[string]string),
+	}
+
+	for i, contextName := range c.sortedContextNames() {
+		c.reportProgress(i+1, contextName)
+		if contextName == baseline {
+			continue
+		}
+
+		deployment, err := c.contexts[contextName].Client.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			report.Missing = append(report.Missing, contextName)
+			continue
+		} else if err != nil {
+			report.Errors[contextName] = err.Error()
+			continue
+		}
+
+		report.Compared = append(report.Compared, contextName)
+		report.Drifts = append(report.Drifts, diffFields(contextName, baselineFields, deploymentFields(deployment))...)
+	}
+
+	return report, nil
+}
+
+// deploymentFields flattens the drift-relevant parts of a deployment into field/value pairs
+func deploymentFields(deployment *appsv1.Deployment) map[string]string {
+	fields := make(map[string]string)
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	fields["replicas"] = fmt.Sprint(replicas)
+
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		prefix := fmt.Sprintf("container[%s]", container.Name)
+		fields[prefix+".image"] = container.Image
+
+		for _, env := range container.Env {
+			fields[prefix+".env."+env.Name] = envValue(env)
+		}
+
+		for resource, quantity := range container.Resources.Requests {
+			fields[fmt.Sprintf("%s.requests.%s", prefix, resource)] = quantity.String()
+		}
+		for resource, quantity := range container.Resources.Limits {
+			fields[fmt.Sprintf("%s.limits.%s", prefix, resource)] = quantity.String()
+		}
+	}
+
+	return fields
+}
+
+func envValue(env corev1.EnvVar) string {
+	if env.ValueFrom == nil {
+		return env.Value
+	}
+
+	switch {
+	case env.ValueFrom.SecretKeyRef != nil:
+		return fmt.Sprintf("secret:%s/%s", env.ValueFrom.SecretKeyRef.Name, env.ValueFrom.SecretKeyRef.Key)
+	case env.ValueFrom.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("configmap:%s/%s", env.ValueFrom.ConfigMapKeyRef.Name, env.ValueFrom.ConfigMapKeyRef.Key)
+	case env.ValueFrom.FieldRef != nil:
+		return "field:" + env.ValueFrom.FieldRef.FieldPath
+	case env.ValueFrom.ResourceFieldRef != nil:
+		return "resource:" + env.ValueFrom.ResourceFieldRef.Resource
+	}
+	return "valueFrom"
+}
+
+// diffFields returns the fields whose values differ from the baseline, including
+// fields present on only one side
+func diffFields(contextName string, baseline, fields map[string]string) []FieldDrift {
+	keys := make(map[string]bool)
+	for key := range baseline {
+		keys[key] = true
+	}
+	for key := range fields {
+		keys[key] = true
+	}
+
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	var drifts []FieldDrift
+	for _, key := range sortedKeys {
+		baselineValue, inBaseline := baseline[key]
+		value, inContext := fields[key]
+		if inBaseline && inContext && baselineValue == value {
+			continue
+		}
+		if !inBaseline {
+			baselineValue = "<unset>"
+		}
+		if !inContext {
+			value = "<unset>"
+		}
+		drifts = append(drifts, FieldDrift{
+			Context:       contextName,
+			Field:         key,
+			BaselineValue: baselineValue,
+			Value:         value,
+		})
+	}
+	return drifts
+}
+
+// GenerateDriftReport generates a human-readable drift table
+func (d *DriftReport) GenerateDriftReport() string {
+	report := fmt.Sprintf("Multi-Cluster Drift Report: %s %s/%s\n", d.ResourceType, d.Namespace, d.Name)
+	report += "============================================\n\n"
+	report += fmt.Sprintf("Baseline Context: %s\n", d.Baseline)
+	report += fmt.Sprintf("Contexts Compared: %d\n\n", len(d.Compared))
+
+	if len(d.Drifts) == 0 {
+		report += "No drift found - all compared contexts match the baseline.\n"
+	} else {
+		var table bytes.Buffer
+		w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "FIELD\tCONTEXT\tBASELINE (%s)\tVALUE\n", d.Baseline)
+		for _, drift := range d.Drifts {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", drift.Field, drift.Context, drift.BaselineValue, drift.Value)
+		}
+		w.Flush()
+		report += table.String()
+	}
+
+	if len(d.Missing) > 0 {
+		report += "\nDeployment Missing In:\n"
+		for _, contextName := range d.Missing {
+			report += fmt.Sprintf("  - %s\n", contextName)
+		}
+	}
+
+	if len(d.Errors) > 0 {
+		report += "\nContexts That Could Not Be Checked:\n"
+		contextNames := make([]string, 0, len(d.Errors))
+		for contextName := range d.Errors {
+			contextNames = append(contextNames, contextName)
+		}
+		sort.Strings(contextNames)
+		for _, contextName := range contextNames {
+			report += fmt.Sprintf("  - %s: %s\n", contextName, d.Errors[contextName])
+		}
+	}
+
+	report += fmt.Sprintf("\nSummary:\n")
+	report += fmt.Sprintf("  Drifted Fields: %d\n", len(d.Drifts))
+	report += fmt.Sprintf("  Missing: %d\n", len(d.Missing))
+
+	return report
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
+	"github.com/abrarahmad1510/k8s-lens/pkg/integrations"
+	"github.com/abrarahmad1510/k8s-lens/pkg/k8s"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -17,8 +20,15 @@ import (
 type ClusterManager struct {
 	contexts       map[string]*ClusterContext
 	currentContext string
+	progress       ProgressFunc
+	prometheusURLs map[string]string
+	metricsBackend string
+	metricsAuth    integrations.MetricsAuth
 }
 
+// ProgressFunc is called before each cluster is processed by a multi-cluster operation
+type ProgressFunc func(current, total int, contextName string)
+
 // ClusterContext represents a Kubernetes cluster context
 type ClusterContext struct {
 	Name   string
@@ -84,6 +94,25 @@ func (c *ClusterManager) GetContext(contextName string) (*ClusterContext, error)
 	return context, nil
 }
 
+// SetProgress registers a callback that reports progress during CompareClusters
+// and FederatedAnalysis
+func (c *ClusterManager) SetProgress(progress ProgressFunc) {
+	c.progress = progress
+}
+
+// SetPrometheusURLs configures the Prometheus URL to query for each context name.
+// Contexts without a URL are analyzed without utilization metrics.
+func (c *ClusterManager) SetPrometheusURLs(urls map[string]string) {
+	c.prometheusURLs = urls
+}
+
+// SetMetricsBackend configures the type of metrics backend the Prometheus URLs point at
+// and the credentials to query it with
+func (c *ClusterManager) SetMetricsBackend(backend string, auth integrations.MetricsAuth) {
+	c.metricsBackend = backend
+	c.metricsAuth = auth
+}
+
 // ListContexts returns all available contexts
 func (c *ClusterManager) ListContexts() []string {
 	var contextNames []string
@@ -94,14 +123,16 @@ func (c *ClusterManager) ListContexts() []string {
 }
 
 // CompareClusters compares resources across clusters
-func (c *ClusterManager) CompareClusters(resourceType string) (*ClusterComparison, error) {
+func (c *ClusterManager) CompareClusters(ctx context.Context, resourceType string) (*ClusterComparison, error) {
 	comparison := &ClusterComparison{
 		ResourceType: resourceType,
 		ClusterData:  make(map[string]ClusterResources),
 	}
 
-	for contextName, context := range c.contexts {
-		resources, err := c.getResourcesForType(context.Client, resourceType)
+	for i, contextName := range c.sortedContextNames() {
+		c.reportProgress(i+1, contextName)
+		context := c.contexts[contextName]
+		resources, err := c.getResourcesForType(ctx, context.Client, resourceType)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get resources for %s in context %s: %v", resourceType, contextName, err)
 		}
@@ -114,13 +145,14 @@ func (c *ClusterManager) CompareClusters(resourceType string) (*ClusterCompariso
 }
 
 // FederatedAnalysis performs analysis across all clusters
-func (c *ClusterManager) FederatedAnalysis() (*FederatedReport, error) {
+func (c *ClusterManager) FederatedAnalysis(ctx context.Context) (*FederatedReport, error) {
 	report := &FederatedReport{
 		ClusterReports: make(map[string]ClusterReport),
 	}
 
-	for contextName, context := range c.contexts {
-		clusterReport, err := c.analyzeCluster(context)
+	for i, contextName := range c.sortedContextNames() {
+		c.reportProgress(i+1, contextName)
+		clusterReport, err := c.analyzeCluster(ctx, c.contexts[contextName])
 		if err != nil {
 			return nil, fmt.Errorf("failed to analyze cluster %s: %v", contextName, err)
 		}
@@ -131,6 +163,20 @@ func (c *ClusterManager) FederatedAnalysis() (*FederatedReport, error) {
 	return report, nil
 }
 
+// sortedContextNames returns the context names in a stable order so progress
+// output is predictable between runs
+func (c *ClusterManager) sortedContextNames() []string {
+	names := c.ListContexts()
+	sort.Strings(names)
+	return names
+}
+
+func (c *ClusterManager) reportProgress(current int, contextName string) {
+	if c.progress != nil {
+		c.progress(current, len(c.contexts), contextName)
+	}
+}
+
 func (c *ClusterManager) createClientForContext(contextName string) (kubernetes.Interface, clientcmd.ClientConfig, error) {
 	kubeconfig := getKubeconfigPath()
 	config, err := clientcmd.LoadFromFile(kubeconfig)
@@ -147,6 +193,8 @@ func (c *ClusterManager) createClientForContext(contextName string) (kubernetes.
 	if err != nil {
 		return nil, nil, err
 	}
+	k8s.ConfigureRetries(restConfig)
+	k8s.ConfigureImpersonation(restConfig)
 
 	client, err := kubernetes.NewForConfig(restConfig)
 	if err != nil {
@@ -156,12 +204,12 @@ func (c *ClusterManager) createClientForContext(contextName string) (kubernetes.
 	return client, clientConfig, nil
 }
 
-func (c *ClusterManager) getResourcesForType(client kubernetes.Interface, resourceType string) (ClusterResources, error) {
+func (c *ClusterManager) getResourcesForType(ctx context.Context, client kubernetes.Interface, resourceType string) (ClusterResources, error) {
 	resources := ClusterResources{}
 
 	switch resourceType {
 	case "pods":
-		pods, err := client.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+		pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
 		if err != nil {
 			return resources, err
 		}
@@ -169,7 +217,7 @@ func (c *ClusterManager) getResourcesForType(client kubernetes.Interface, resour
 		resources.Count = len(pods.Items)
 
 	case "nodes":
-		nodes, err := client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+		nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 		if err != nil {
 			return resources, err
 		}
@@ -177,7 +225,7 @@ func (c *ClusterManager) getResourcesForType(client kubernetes.Interface, resour
 		resources.Count = len(nodes.Items)
 
 	case "deployments":
-		deployments, err := client.AppsV1().Deployments("").List(context.TODO(), metav1.ListOptions{})
+		deployments, err := client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
 		if err != nil {
 			return resources, err
 		}
@@ -190,14 +238,14 @@ func (c *ClusterManager) getResourcesForType(client kubernetes.Interface, resour
 	return resources, nil
 }
 
-func (c *ClusterManager) analyzeCluster(clusterContext *ClusterContext) (*ClusterReport, error) {
+func (c *ClusterManager) analyzeCluster(ctx context.Context, clusterContext *ClusterContext) (*ClusterReport, error) {
 	// Get basic cluster info
-	nodes, err := clusterContext.Client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	nodes, err := clusterContext.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
 
-	pods, err := clusterContext.Client.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+	pods, err := clusterContext.Client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -225,10 +273,37 @@ func (c *ClusterManager) analyzeCluster(clusterContext *ClusterContext) (*Cluste
 		report.HealthStatus = "Degraded"
 	}
 
+	if prometheusURL, ok := c.prometheusURLs[clusterContext.Name]; ok {
+		report.Metrics = getClusterMetrics(c.metricsBackend, prometheusURL, c.metricsAuth)
+	}
+
 	return report, nil
 }
 
+func getClusterMetrics(backend, prometheusURL string, auth integrations.MetricsAuth) *ClusterMetrics {
+	promClient, err := integrations.NewMetricsBackend(backend, prometheusURL, auth)
+	if err != nil {
+		return &ClusterMetrics{Error: err.Error()}
+	}
+
+	metrics, err := promClient.GetClusterMetrics()
+	if err != nil {
+		return &ClusterMetrics{Error: err.Error()}
+	}
+
+	return &ClusterMetrics{
+		CPUCapacity:    metrics.CPUCapacity,
+		CPUUsage:       metrics.CPUUsage,
+		MemoryCapacity: metrics.MemoryCapacity,
+		MemoryUsage:    metrics.MemoryUsage,
+		Error:          metrics.Error,
+	}
+}
+
 func getKubeconfigPath() string {
+	if kubeconfig, _ := k8s.Kubeconfig(); kubeconfig != "" {
+		return kubeconfig
+	}
 	if kubeconfig := os.Getenv("KUBECONFIG"); kubeconfig != "" {
 		return kubeconfig
 	}
@@ -0,0 +1,224 @@
+package multicluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodAnalysisReport holds the analysis of the same pod in every loaded context
+type PodAnalysisReport struct {
+	Name      string
+	Namespace string
+	Results   []ContextPodReport
+	Missing   []string
+	Errors    map[string]string
+	// Differences describes how the pod differs between the contexts it exists in
+	Differences []string
+}
+
+// ContextPodReport is the pod analysis from a single context
+type ContextPodReport struct {
+	Context string
+	Report  *diagnostics.PodReport
+}
+
+// AnalyzePodAcrossContexts runs the pod analyzer against the named pod in every loaded
+// context and reports where the pod exists and how the results differ
+func (c *ClusterManager) AnalyzePodAcrossContexts(ctx context.Context, name, namespace string) (*PodAnalysisReport, error) {
+	if len(c.contexts) == 0 {
+		return nil, fmt.Errorf("no cluster contexts loaded")
+	}
+
+	report := &PodAnalysisReport{
+		Name:      name,
+		Namespace: namespace,
+		Errors:    make(map[string]string),
+	}
+
+	for i, contextName := range c.sortedContextNames() {
+		c.reportProgress(i+1, contextName)
+		client := c.contexts[contextName].Client
+
+		_, err := client.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			report.Missing = append(report.Missing, contextName)
+			continue
+		} else if err != nil {
+			report.Errors[contextName] = err.Error()
+			continue
+		}
+
+		analyzer := diagnostics.NewPodAnalyzer(client, namespace)
+		podReport, err := analyzer.Analyze(ctx, name)
+		if err != nil {
+			report.Errors[contextName] = err.Error()
+			continue
+		}
+		report.Results = append(report.Results, ContextPodReport{Context: contextName, Report: podReport})
+	}
+
+	report.findDifferences()
+	return report, nil
+}
+
+// findDifferences compares the pod's phase, images, restarts and issues between contexts
+// and points out the contexts where the pod is healthier or worse than elsewhere
+func (r *PodAnalysisReport) findDifferences() {
+	if len(r.Results) < 2 {
+		return
+	}
+
+	phases := make(map[string][]string)
+	images := make(map[string][]string)
+	for _, result := range r.Results {
+		phases[result.Report.Phase] = append(phases[result.Report.Phase], result.Context)
+		images[podImages(result.Report)] = append(images[podImages(result.Report)], result.Context)
+	}
+	if len(phases) > 1 {
+		r.Differences = append(r.Differences, "Phase differs: "+describeGroups(phases))
+	}
+	if len(images) > 1 {
+		r.Differences = append(r.Differences, "Images differ: "+describeGroups(images))
+	}
+
+	best, worst := r.Results[0], r.Results[0]
+	for _, result := range r.Results[1:] {
+		if result.Report.HealthScore > best.Report.HealthScore {
+			best = result
+		}
+		if result.Report.HealthScore < worst.Report.HealthScore {
+			worst = result
+		}
+	}
+	if best.Report.HealthScore != worst.Report.HealthScore {
+		r.Differences = append(r.Differences,
+			fmt.Sprintf("Lowest health score in %s (%d/100), highest in %s (%d/100)",
+				worst.Context, worst.Report.HealthScore, best.Context, best.Report.HealthScore))
+	}
+
+	var maxRestarts, minRestarts int32 = r.Results[0].Report.RestartCount, r.Results[0].Report.RestartCount
+	for _, result := range r.Results {
+		if result.Report.RestartCount > maxRestarts {
+			maxRestarts = result.Report.RestartCount
+		}
+		if result.Report.RestartCount < minRestarts {
+			minRestarts = result.Report.RestartCount
+		}
+	}
+	if maxRestarts != minRestarts {
+		var restarting []string
+		for _, result := range r.Results {
+			if result.Report.RestartCount == maxRestarts {
+				restarting = append(restarting, result.Context)
+			}
+		}
+		r.Differences = append(r.Differences,
+			fmt.Sprintf("Most restarts in %s (%d, fewest elsewhere: %d)", strings.Join(restarting, ", "), maxRestarts, minRestarts))
+	}
+
+	// Issues reported in some contexts but not all point at the cluster behaving badly
+	issueContexts := make(map[string][]string)
+	for _, result := range r.Results {
+		for _, issue := range result.Report.Issues {
+			issueContexts[issue] = append(issueContexts[issue], result.Context)
+		}
+	}
+	issues := make([]string, 0, len(issueContexts))
+	for issue := range issueContexts {
+		issues = append(issues, issue)
+	}
+	sort.Strings(issues)
+	for _, issue := range issues {
+		if contexts := issueContexts[issue]; len(contexts) < len(r.Results) {
+			r.Differences = append(r.Differences,
+				fmt.Sprintf("Only in %s: %s", strings.Join(contexts, ", "), issue))
+		}
+	}
+}
+
+// podImages returns the container images of a pod report as a single comparable string
+func podImages(report *diagnostics.PodReport) string {
+	var images []string
+	for _, container := range report.Containers {
+		image := container.SpecImage
+		if image == "" {
+			image = container.Image
+		}
+		images = append(images, fmt.Sprintf("%s=%s", container.Name, image))
+	}
+	sort.Strings(images)
+	return strings.Join(images, ",")
+}
+
+// describeGroups formats value to context groups as "value (ctx-a, ctx-b); value (ctx-c)"
+func describeGroups(groups map[string][]string) string {
+	values := make([]string, 0, len(groups))
+	for value := range groups {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	var parts []string
+	for _, value := range values {
+		parts = append(parts, fmt.Sprintf("%s (%s)", value, strings.Join(groups[value], ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// GeneratePodReport generates a human-readable table of the pod in every context
+func (r *PodAnalysisReport) GeneratePodReport() string {
+	report := fmt.Sprintf("Multi-Cluster Pod Analysis: %s/%s\n", r.Namespace, r.Name)
+	report += "============================================\n\n"
+	report += fmt.Sprintf("Found In: %d context(s)\n\n", len(r.Results))
+
+	if len(r.Results) > 0 {
+		var table bytes.Buffer
+		w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CONTEXT\tPHASE\tSTATUS\tHEALTH\tRESTARTS\tNODE\tISSUES")
+		for _, result := range r.Results {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d/100\t%d\t%s\t%d\n",
+				result.Context, result.Report.Phase, result.Report.Status, result.Report.HealthScore,
+				result.Report.RestartCount, result.Report.Node, len(result.Report.Issues))
+		}
+		w.Flush()
+		report += table.String()
+	}
+
+	if len(r.Differences) > 0 {
+		report += "\nDifferences:\n"
+		for _, difference := range r.Differences {
+			report += fmt.Sprintf("  - %s\n", difference)
+		}
+	} else if len(r.Results) > 1 {
+		report += "\nNo differences found - the pod looks the same in every context.\n"
+	}
+
+	if len(r.Missing) > 0 {
+		report += "\nPod Missing In:\n"
+		for _, contextName := range r.Missing {
+			report += fmt.Sprintf("  - %s\n", contextName)
+		}
+	}
+
+	if len(r.Errors) > 0 {
+		report += "\nContexts That Could Not Be Checked:\n"
+		contextNames := make([]string, 0, len(r.Errors))
+		for contextName := range r.Errors {
+			contextNames = append(contextNames, contextName)
+		}
+		sort.Strings(contextNames)
+		for _, contextName := range contextNames {
+			report += fmt.Sprintf("  - %s: %s\n", contextName, r.Errors[contextName])
+		}
+	}
+
+	return report
+}
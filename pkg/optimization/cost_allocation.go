@@ -0,0 +1,79 @@
+package optimization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CostAllocationReport contains the estimated monthly cost of every Deployment and
+// StatefulSet of a namespace, most expensive first
+type CostAllocationReport struct {
+	Namespace        string         `json:"namespace"`
+	Workloads        []WorkloadCost `json:"workloads"`
+	TotalMonthlyCost float64        `json:"totalMonthlyCost"`
+}
+
+// WorkloadCost is the estimated cost of a workload: the requests of its pod template
+// times its desired replicas, priced by a CostCalculator
+type WorkloadCost struct {
+	Namespace          string  `json:"namespace"`
+	Kind               string  `json:"kind"`
+	Name               string  `json:"name"`
+	Replicas           int32   `json:"replicas"`
+	CPURequestCores    float64 `json:"cpuRequestCores"`
+	MemoryRequestBytes float64 `json:"memoryRequestBytes"`
+	MonthlyCost        float64 `json:"monthlyCost"`
+}
+
+// CostAllocation estimates what each Deployment and StatefulSet of a namespace costs
+// per month from the resources it requests, regardless of how much it uses
+func (r *ResourceOptimizer) CostAllocation(ctx context.Context, namespace string, calculator *CostCalculator) (*CostAllocationReport, error) {
+	deployments, err := r.client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments in namespace %s: %v", namespace, err)
+	}
+	statefulSets, err := r.client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets in namespace %s: %v", namespace, err)
+	}
+
+	report := &CostAllocationReport{Namespace: namespace}
+	add := func(kind, name string, replicas *int32, spec *corev1.PodSpec) {
+		workload := WorkloadCost{Namespace: namespace, Kind: kind, Name: name, Replicas: 1}
+		if replicas != nil {
+			workload.Replicas = *replicas
+		}
+		cpu, memory := podSpecRequests(spec)
+		workload.CPURequestCores = cpu * float64(workload.Replicas)
+		workload.MemoryRequestBytes = memory * float64(workload.Replicas)
+		workload.MonthlyCost = calculator.MonthlyCost(workload.CPURequestCores, workload.MemoryRequestBytes)
+		report.TotalMonthlyCost += workload.MonthlyCost
+		report.Workloads = append(report.Workloads, workload)
+	}
+	for _, deployment := range deployments.Items {
+		add("Deployment", deployment.Name, deployment.Spec.Replicas, &deployment.Spec.Template.Spec)
+	}
+	for _, statefulSet := range statefulSets.Items {
+		add("StatefulSet", statefulSet.Name, statefulSet.Spec.Replicas, &statefulSet.Spec.Template.Spec)
+	}
+
+	sort.SliceStable(report.Workloads, func(i, j int) bool {
+		return report.Workloads[i].MonthlyCost > report.Workloads[j].MonthlyCost
+	})
+	return report, nil
+}
+
+// podSpecRequests returns the CPU cores and memory bytes requested by the containers
+// of a pod spec
+func podSpecRequests(spec *corev1.PodSpec) (float64, float64) {
+	var cpu, memory float64
+	for _, container := range spec.Containers {
+		cpu += container.Resources.Requests.Cpu().AsApproximateFloat64()
+		memory += container.Resources.Requests.Memory().AsApproximateFloat64()
+	}
+	return cpu, memory
+}
@@ -1,5 +1,20 @@
 package optimization
 
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Default on-demand cloud prices of requested resources, in dollars per hour
+const (
+	DefaultCPUCostPerHour      = 0.04
+	DefaultMemoryGBCostPerHour = 0.005
+)
+
+// hoursPerMonth is the average number of hours in a month
+const hoursPerMonth = 730
+
 // CostCalculator provides cost estimation functionality
 type CostCalculator struct {
 	clusterCostPerCPUHour      float64
@@ -26,31 +41,38 @@ func (c *CostCalculator) CalculatePodCost(cpuRequest, memoryRequest string) (flo
 		return 0, err
 	}
 
-	// Monthly cost (730 hours in a month)
-	monthlyCost := (cpuCost + memoryCost) * 730
+	monthlyCost := (cpuCost + memoryCost) * hoursPerMonth
 	return monthlyCost, nil
 }
 
+// MonthlyCost estimates the monthly cost of requesting CPU cores and memory bytes
+func (c *CostCalculator) MonthlyCost(cpuCores, memoryBytes float64) float64 {
+	hourly := cpuCores*c.clusterCostPerCPUHour + memoryBytes/(1024*1024*1024)*c.clusterCostPerMemoryGBHour
+	return hourly * hoursPerMonth
+}
+
 func (c *CostCalculator) calculateCPUCost(cpuRequest string) (float64, error) {
-	// Parse CPU request and calculate hourly cost
-	// This is a simplified implementation
 	if cpuRequest == "" {
 		return 0, nil
 	}
 
-	// Default assumption: 1 CPU core
-	return c.clusterCostPerCPUHour, nil
+	quantity, err := resource.ParseQuantity(cpuRequest)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CPU request %q: %v", cpuRequest, err)
+	}
+	return quantity.AsApproximateFloat64() * c.clusterCostPerCPUHour, nil
 }
 
 func (c *CostCalculator) calculateMemoryCost(memoryRequest string) (float64, error) {
-	// Parse memory request and calculate hourly cost
-	// This is a simplified implementation
 	if memoryRequest == "" {
 		return 0, nil
 	}
 
-	// Default assumption: 1 GB
-	return c.clusterCostPerMemoryGBHour, nil
+	quantity, err := resource.ParseQuantity(memoryRequest)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory request %q: %v", memoryRequest, err)
+	}
+	return quantity.AsApproximateFloat64() / (1024 * 1024 * 1024) * c.clusterCostPerMemoryGBHour, nil
 }
 
 // CalculateNamespaceCost estimates total cost for a namespace
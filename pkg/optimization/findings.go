@@ -0,0 +1,41 @@
+package optimization
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
+)
+
+// Findings returns the optimizations of the report as cost findings
+func (r *OptimizationReport) Findings() []diagnostics.Finding {
+	findings := make([]diagnostics.Finding, 0, len(r.Optimizations))
+	for _, opt := range r.Optimizations {
+		message := opt.Type + ": " + opt.Description
+		if opt.Savings.MonthlySavings > 0 {
+			message += fmt.Sprintf(" (saves about $%.2f/month)", opt.Savings.MonthlySavings)
+		}
+		findings = append(findings, diagnostics.NewFinding("optimize", diagnostics.CategoryCost, diagnostics.SeverityWarning,
+			fmt.Sprintf("Pod %s/%s container %s", r.Namespace, opt.PodName, opt.ContainerName), message, optimizationRemediation(opt)))
+	}
+	return findings
+}
+
+// optimizationRemediation describes the resource values an optimization recommends
+func optimizationRemediation(opt Optimization) string {
+	var values []string
+	if opt.Recommended.CPU != "" {
+		values = append(values, "cpu "+opt.Recommended.CPU)
+	}
+	if opt.Recommended.Memory != "" {
+		values = append(values, "memory "+opt.Recommended.Memory)
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	target := "requests"
+	if opt.Type == "Missing Resource Limits" {
+		target = "limits"
+	}
+	return fmt.Sprintf("Set the %s of container %s to %s", target, opt.ContainerName, strings.Join(values, ", "))
+}
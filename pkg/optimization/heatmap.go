@@ -0,0 +1,184 @@
+package optimization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UsageSource provides the live CPU (cores) and memory (bytes) usage of a pod
+type UsageSource interface {
+	GetPodUsage(podName, namespace string) (float64, float64, error)
+}
+
+// HeatmapReport contains requested and used resources per pod and workload of a
+// namespace, in a form suitable for heatmap visualizations
+type HeatmapReport struct {
+	Namespace   string               `json:"namespace"`
+	GeneratedAt time.Time            `json:"generatedAt"`
+	Pods        []PodUsage           `json:"pods"`
+	Workloads   []WorkloadEfficiency `json:"workloads"`
+}
+
+// PodUsage compares the requested resources of a pod with its live usage.
+// Efficiencies are usage divided by requests and are 0 when nothing is requested.
+type PodUsage struct {
+	Pod                string  `json:"pod"`
+	Workload           string  `json:"workload"`
+	CPURequestCores    float64 `json:"cpuRequestCores"`
+	CPUUsageCores      float64 `json:"cpuUsageCores"`
+	CPUEfficiency      float64 `json:"cpuEfficiency"`
+	MemoryRequestBytes float64 `json:"memoryRequestBytes"`
+	MemoryUsageBytes   float64 `json:"memoryUsageBytes"`
+	MemoryEfficiency   float64 `json:"memoryEfficiency"`
+	Error              string  `json:"error,omitempty"`
+}
+
+// WorkloadEfficiency aggregates the pods of a workload. Efficiency is the mean of the
+// CPU and memory efficiencies of the resources that have requests. The usage of pods
+// that request none of a resource is reported as unrequested and left out of its
+// usage and efficiency, since it has no request to be measured against.
+type WorkloadEfficiency struct {
+	Workload               string  `json:"workload"`
+	Pods                   int     `json:"pods"`
+	CPURequestCores        float64 `json:"cpuRequestCores"`
+	CPUUsageCores          float64 `json:"cpuUsageCores"`
+	CPUUnrequestedCores    float64 `json:"cpuUnrequestedCores,omitempty"`
+	CPUEfficiency          float64 `json:"cpuEfficiency"`
+	MemoryRequestBytes     float64 `json:"memoryRequestBytes"`
+	MemoryUsageBytes       float64 `json:"memoryUsageBytes"`
+	MemoryUnrequestedBytes float64 `json:"memoryUnrequestedBytes,omitempty"`
+	MemoryEfficiency       float64 `json:"memoryEfficiency"`
+	Efficiency             float64 `json:"efficiency"`
+}
+
+// BuildHeatmap collects requests from the specs of the running pods in a namespace
+// and their usage from usage. Pods whose usage is unavailable are reported with an
+// error and left out of the workload totals.
+func (r *ResourceOptimizer) BuildHeatmap(ctx context.Context, namespace string, usage UsageSource) (*HeatmapReport, error) {
+	pods, err := r.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pods in namespace %s: %v", namespace, err)
+	}
+
+	replicaSets, err := r.client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replica sets in namespace %s: %v", namespace, err)
+	}
+	deploymentOf := make(map[string]string)
+	for i := range replicaSets.Items {
+		if owner := metav1.GetControllerOf(&replicaSets.Items[i]); owner != nil && owner.Kind == "Deployment" {
+			deploymentOf[replicaSets.Items[i].Name] = owner.Name
+		}
+	}
+
+	report := &HeatmapReport{
+		Namespace:   namespace,
+		GeneratedAt: time.Now(),
+	}
+
+	workloads := make(map[string]*WorkloadEfficiency)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+
+		entry := PodUsage{
+			Pod:      pod.Name,
+			Workload: podWorkload(pod, deploymentOf),
+		}
+		for _, container := range pod.Spec.Containers {
+			entry.CPURequestCores += container.Resources.Requests.Cpu().AsApproximateFloat64()
+			entry.MemoryRequestBytes += container.Resources.Requests.Memory().AsApproximateFloat64()
+		}
+
+		cpu, memory, err := usage.GetPodUsage(pod.Name, namespace)
+		if err != nil {
+			entry.Error = err.Error()
+			report.Pods = append(report.Pods, entry)
+			continue
+		}
+		entry.CPUUsageCores = cpu
+		entry.MemoryUsageBytes = memory
+		entry.CPUEfficiency = efficiency(cpu, entry.CPURequestCores)
+		entry.MemoryEfficiency = efficiency(memory, entry.MemoryRequestBytes)
+		report.Pods = append(report.Pods, entry)
+
+		workload := workloads[entry.Workload]
+		if workload == nil {
+			workload = &WorkloadEfficiency{Workload: entry.Workload}
+			workloads[entry.Workload] = workload
+		}
+		workload.Pods++
+		workload.CPURequestCores += entry.CPURequestCores
+		if entry.CPURequestCores > 0 {
+			workload.CPUUsageCores += entry.CPUUsageCores
+		} else {
+			workload.CPUUnrequestedCores += entry.CPUUsageCores
+		}
+		workload.MemoryRequestBytes += entry.MemoryRequestBytes
+		if entry.MemoryRequestBytes > 0 {
+			workload.MemoryUsageBytes += entry.MemoryUsageBytes
+		} else {
+			workload.MemoryUnrequestedBytes += entry.MemoryUsageBytes
+		}
+	}
+
+	for _, workload := range workloads {
+		workload.CPUEfficiency = efficiency(workload.CPUUsageCores, workload.CPURequestCores)
+		workload.MemoryEfficiency = efficiency(workload.MemoryUsageBytes, workload.MemoryRequestBytes)
+
+		var ratios []float64
+		if workload.CPURequestCores > 0 {
+			ratios = append(ratios, workload.CPUEfficiency)
+		}
+		if workload.MemoryRequestBytes > 0 {
+			ratios = append(ratios, workload.MemoryEfficiency)
+		}
+		for _, ratio := range ratios {
+			workload.Efficiency += ratio / float64(len(ratios))
+		}
+
+		report.Workloads = append(report.Workloads, *workload)
+	}
+
+	sort.Slice(report.Pods, func(i, j int) bool {
+		return report.Pods[i].Pod < report.Pods[j].Pod
+	})
+	// Least efficient workloads first, as they are the best candidates for right-sizing
+	sort.Slice(report.Workloads, func(i, j int) bool {
+		if report.Workloads[i].Efficiency != report.Workloads[j].Efficiency {
+			return report.Workloads[i].Efficiency < report.Workloads[j].Efficiency
+		}
+		return report.Workloads[i].Workload < report.Workloads[j].Workload
+	})
+
+	return report, nil
+}
+
+// podWorkload names the workload owning a pod as kind/name, resolving ReplicaSets to
+// their Deployment. Pods without a controller are their own workload.
+func podWorkload(pod *corev1.Pod, deploymentOf map[string]string) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "Pod/" + pod.Name
+	}
+	if owner.Kind == "ReplicaSet" {
+		if deployment, ok := deploymentOf[owner.Name]; ok {
+			return "Deployment/" + deployment
+		}
+	}
+	return owner.Kind + "/" + owner.Name
+}
+
+func efficiency(used, requested float64) float64 {
+	if requested <= 0 {
+		return 0
+	}
+	return used / requested
+}
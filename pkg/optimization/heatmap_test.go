@@ -0,0 +1,61 @@
+package optimization
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type staticUsage map[string][2]float64
+
+func (u staticUsage) GetPodUsage(podName, namespace string) (float64, float64, error) {
+	return u[podName][0], u[podName][1], nil
+}
+
+func TestBuildHeatmapLeavesUnrequestedUsageOutOfEfficiency(t *testing.T) {
+	owner := metav1.OwnerReference{APIVersion: "apps/v1", Kind: "StatefulSet", Name: "db", UID: "db-uid", Controller: boolPtr(true)}
+	pod := func(name string, requests corev1.ResourceList) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "shop", OwnerReferences: []metav1.OwnerReference{owner}},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:      "db",
+				Resources: corev1.ResourceRequirements{Requests: requests},
+			}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+	client := fake.NewSimpleClientset(
+		pod("db-0", corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		}),
+		pod("db-1", nil),
+	)
+	usage := staticUsage{
+		"db-0": {0.5, 512 * 1024 * 1024},
+		"db-1": {2, 2 * 1024 * 1024 * 1024},
+	}
+
+	report, err := NewResourceOptimizer(client).BuildHeatmap(context.Background(), "shop", usage)
+	assert.NoError(t, err)
+	assert.Len(t, report.Workloads, 1)
+
+	workload := report.Workloads[0]
+	assert.Equal(t, "StatefulSet/db", workload.Workload)
+	assert.Equal(t, 2, workload.Pods)
+	assert.Equal(t, 0.5, workload.CPUUsageCores)
+	assert.Equal(t, 2.0, workload.CPUUnrequestedCores)
+	assert.Equal(t, float64(2*1024*1024*1024), workload.MemoryUnrequestedBytes)
+	assert.Equal(t, 0.5, workload.CPUEfficiency)
+	assert.Equal(t, 0.5, workload.MemoryEfficiency)
+	assert.Equal(t, 0.5, workload.Efficiency)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,215 @@
+package optimization
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourceCommand is a kubectl set resources command applying the recommendations for
+// one container of a workload
+type ResourceCommand struct {
+	// Workload is the kubectl resource the command targets, e.g. deployment/web
+	Workload  string
+	Container string
+	// Requests and Limits hold only the values the command sets
+	Requests corev1.ResourceList
+	Limits   corev1.ResourceList
+	// Changes describes each value the command changes, e.g. "cpu request 1 -> 250m"
+	Changes []string
+	Command string
+
+	// currentRequests and currentLimits are the container's resources once the command
+	// is applied, which new limits are derived from
+	currentRequests corev1.ResourceList
+	currentLimits   corev1.ResourceList
+}
+
+// ResourceCommandPlan holds the commands for a namespace and the pods whose
+// recommendations could not be turned into a command
+type ResourceCommandPlan struct {
+	Namespace string
+	Commands  []ResourceCommand
+	// Skipped maps pod names to why no command was generated for them
+	Skipped map[string]string
+}
+
+// workloadContainer identifies a container of a workload, so the recommendations of
+// every replica collapse into one command
+type workloadContainer struct {
+	workload  string
+	container string
+}
+
+// RecommendResourceCommands turns the recommendations of an optimization report into
+// kubectl set resources commands against the workloads owning the pods. Recommendations
+// for pods without a Deployment, StatefulSet or DaemonSet are skipped, since changing a
+// pod's resources means recreating it.
+func (r *ResourceOptimizer) RecommendResourceCommands(ctx context.Context, report *OptimizationReport) (*ResourceCommandPlan, error) {
+	pods, err := r.client.CoreV1().Pods(report.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pods in namespace %s: %v", report.Namespace, err)
+	}
+	podsByName := make(map[string]*corev1.Pod, len(pods.Items))
+	for i := range pods.Items {
+		podsByName[pods.Items[i].Name] = &pods.Items[i]
+	}
+
+	replicaSets, err := r.client.AppsV1().ReplicaSets(report.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replica sets in namespace %s: %v", report.Namespace, err)
+	}
+	deploymentOf := make(map[string]string)
+	for i := range replicaSets.Items {
+		if owner := metav1.GetControllerOf(&replicaSets.Items[i]); owner != nil && owner.Kind == "Deployment" {
+			deploymentOf[replicaSets.Items[i].Name] = owner.Name
+		}
+	}
+
+	plan := &ResourceCommandPlan{
+		Namespace: report.Namespace,
+		Skipped:   make(map[string]string),
+	}
+	commands := make(map[workloadContainer]*ResourceCommand)
+	var order []workloadContainer
+
+	for _, opt := range report.Optimizations {
+		pod, ok := podsByName[opt.PodName]
+		if !ok {
+			plan.Skipped[opt.PodName] = "pod no longer exists"
+			continue
+		}
+		workload := kubectlWorkload(pod, deploymentOf)
+		if workload == "" {
+			plan.Skipped[opt.PodName] = "pod is not managed by a Deployment, StatefulSet or DaemonSet"
+			continue
+		}
+
+		key := workloadContainer{workload: workload, container: opt.ContainerName}
+		command, ok := commands[key]
+		if !ok {
+			command = newResourceCommand(pod, workload, opt.ContainerName)
+			commands[key] = command
+			order = append(order, key)
+		}
+		command.apply(opt)
+	}
+
+	for _, key := range order {
+		command := commands[key]
+		if len(command.Changes) == 0 {
+			continue
+		}
+		command.Command = kubectlSetResources(report.Namespace, command)
+		plan.Commands = append(plan.Commands, *command)
+	}
+	sort.SliceStable(plan.Commands, func(i, j int) bool {
+		return plan.Commands[i].Workload < plan.Commands[j].Workload
+	})
+
+	return plan, nil
+}
+
+// settableWorkloads are the workload kinds kubectl set resources can update
+var settableWorkloads = map[string]bool{"Deployment": true, "StatefulSet": true, "DaemonSet": true}
+
+// kubectlWorkload returns the kubectl resource of the workload controlling a pod, e.g.
+// deployment/web, or "" when its resources cannot be set through a workload
+func kubectlWorkload(pod *corev1.Pod, deploymentOf map[string]string) string {
+	kind, name, _ := strings.Cut(podWorkload(pod, deploymentOf), "/")
+	if !settableWorkloads[kind] {
+		return ""
+	}
+	return strings.ToLower(kind) + "/" + name
+}
+
+// newResourceCommand starts a command from the container's current resources
+func newResourceCommand(pod *corev1.Pod, workload, containerName string) *ResourceCommand {
+	command := &ResourceCommand{
+		Workload:        workload,
+		Container:       containerName,
+		Requests:        corev1.ResourceList{},
+		Limits:          corev1.ResourceList{},
+		currentRequests: corev1.ResourceList{},
+		currentLimits:   corev1.ResourceList{},
+	}
+	for _, container := range pod.Spec.Containers {
+		if container.Name == containerName {
+			command.currentRequests = container.Resources.Requests.DeepCopy()
+			command.currentLimits = container.Resources.Limits.DeepCopy()
+		}
+	}
+	return command
+}
+
+// apply adds a recommendation to the command. Every replica carries the same
+// recommendation, so one already applied is not applied again.
+func (c *ResourceCommand) apply(opt Optimization) {
+	switch opt.Type {
+	case "CPU Right-Sizing":
+		c.setRequest(corev1.ResourceCPU, opt.Current.CPU, opt.Recommended.CPU)
+	case "Memory Right-Sizing":
+		c.setRequest(corev1.ResourceMemory, opt.Current.Memory, opt.Recommended.Memory)
+	case "Missing Resource Limits":
+		if len(c.currentLimits) > 0 {
+			return
+		}
+		// Limits follow the requests the command sets, so lowered requests are not left
+		// with limits computed from the old ones
+		limits := RecommendLimits(c.currentRequests)
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			limit := limits[name]
+			c.Limits[name] = limit
+			c.Changes = append(c.Changes, fmt.Sprintf("%s limit not set -> %s", name, limit.String()))
+		}
+		c.currentLimits = limits
+	}
+}
+
+func (c *ResourceCommand) setRequest(name corev1.ResourceName, current, recommended string) {
+	quantity, err := resource.ParseQuantity(recommended)
+	if err != nil {
+		return
+	}
+	if existing, ok := c.currentRequests[name]; ok && existing.Cmp(quantity) == 0 {
+		return
+	}
+	// A request above the container's limit would be rejected by the API server
+	if limit, ok := c.currentLimits[name]; ok && quantity.Cmp(limit) > 0 {
+		return
+	}
+	if c.currentRequests == nil {
+		c.currentRequests = corev1.ResourceList{}
+	}
+	c.currentRequests[name] = quantity
+	c.Requests[name] = quantity
+	c.Changes = append(c.Changes, fmt.Sprintf("%s request %s -> %s", name, current, recommended))
+}
+
+// kubectlSetResources renders the command. Only the requests and limits the
+// recommendations change are passed; kubectl keeps the other values as they are.
+func kubectlSetResources(namespace string, command *ResourceCommand) string {
+	args := []string{"kubectl", "set", "resources", command.Workload, "-n", namespace, "-c", command.Container}
+	if len(command.Requests) > 0 {
+		args = append(args, "--requests="+formatResources(command.Requests))
+	}
+	if len(command.Limits) > 0 {
+		args = append(args, "--limits="+formatResources(command.Limits))
+	}
+	return strings.Join(args, " ")
+}
+
+// formatResources formats resources as kubectl's name=quantity list, in a stable order
+func formatResources(resources corev1.ResourceList) string {
+	var parts []string
+	for name, quantity := range resources {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
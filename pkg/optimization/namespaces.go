@@ -0,0 +1,70 @@
+package optimization
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// ProgressFunc is called each time the analysis of a namespace finishes
+type ProgressFunc func(done, total int, namespace string)
+
+// AnalyzeNamespaces analyzes several namespaces, up to concurrency at a time, calling
+// progress as each one finishes. It returns the reports in the order of namespaces and
+// the error of every namespace that could not be analyzed.
+func (r *ResourceOptimizer) AnalyzeNamespaces(ctx context.Context, namespaces []string, concurrency int, progress ProgressFunc) ([]*OptimizationReport, map[string]error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	reports := make([]*OptimizationReport, len(namespaces))
+	errs := make(map[string]error)
+	var mu sync.Mutex
+	done := 0
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(namespaces); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				report, err := r.AnalyzeNamespace(ctx, namespaces[index])
+
+				mu.Lock()
+				if err != nil {
+					errs[namespaces[index]] = err
+				} else {
+					reports[index] = report
+				}
+				done++
+				if progress != nil {
+					progress(done, len(namespaces), namespaces[index])
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range namespaces {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	var analyzed []*OptimizationReport
+	for _, report := range reports {
+		if report != nil {
+			analyzed = append(analyzed, report)
+		}
+	}
+	return analyzed, errs
+}
+
+// RankBySavings returns the reports ordered by estimated monthly savings, highest first
+func RankBySavings(reports []*OptimizationReport) []*OptimizationReport {
+	ranked := append([]*OptimizationReport(nil), reports...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Summary.TotalMonthlySavings > ranked[j].Summary.TotalMonthlySavings
+	})
+	return ranked
+}
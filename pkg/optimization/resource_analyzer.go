@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -12,7 +13,8 @@ import (
 
 // ResourceOptimizer provides resource optimization recommendations
 type ResourceOptimizer struct {
-	client kubernetes.Interface
+	client   kubernetes.Interface
+	findings *diagnostics.FindingCollector
 }
 
 // NewResourceOptimizer creates a new ResourceOptimizer
@@ -22,6 +24,11 @@ func NewResourceOptimizer(client kubernetes.Interface) *ResourceOptimizer {
 	}
 }
 
+// SetFindingCollector makes the optimizer emit each optimization as a cost finding
+func (r *ResourceOptimizer) SetFindingCollector(collector *diagnostics.FindingCollector) {
+	r.findings = collector
+}
+
 // OptimizationReport contains resource optimization recommendations
 type OptimizationReport struct {
 	Namespace     string
@@ -30,6 +37,9 @@ type OptimizationReport struct {
 	Optimizations []Optimization
 	CostSavings   CostSavings
 	Summary       OptimizationSummary
+	// ExtendedResources totals the GPUs and other extended resources requested by the
+	// pods, which the CPU and memory right-sizing does not cover
+	ExtendedResources map[string]int64
 }
 
 // Optimization represents a single optimization recommendation
@@ -66,9 +76,9 @@ type OptimizationSummary struct {
 }
 
 // AnalyzeNamespace analyzes resource usage in a namespace
-func (r *ResourceOptimizer) AnalyzeNamespace(namespace string) (*OptimizationReport, error) {
+func (r *ResourceOptimizer) AnalyzeNamespace(ctx context.Context, namespace string) (*OptimizationReport, error) {
 	// Get all pods in the namespace
-	pods, err := r.client.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	pods, err := r.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pods in namespace %s: %v", namespace, err)
 	}
@@ -84,6 +94,17 @@ func (r *ResourceOptimizer) AnalyzeNamespace(namespace string) (*OptimizationRep
 	totalConfidence := 0
 
 	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			for name, quantity := range container.Resources.Requests {
+				if diagnostics.IsExtendedResource(name) {
+					if report.ExtendedResources == nil {
+						report.ExtendedResources = make(map[string]int64)
+					}
+					report.ExtendedResources[string(name)] += quantity.Value()
+				}
+			}
+		}
+
 		podOptimizations := r.analyzePodResources(&pod)
 		report.Optimizations = append(report.Optimizations, podOptimizations...)
 
@@ -111,6 +132,7 @@ func (r *ResourceOptimizer) AnalyzeNamespace(namespace string) (*OptimizationRep
 		}
 	}
 
+	r.findings.Add(report.Findings()...)
 	return report, nil
 }
 
@@ -170,14 +192,20 @@ func (r *ResourceOptimizer) analyzePodResources(pod *corev1.Pod) []Optimization
 			}
 		}
 
-		// Check for missing limits
-		if container.Resources.Limits == nil || len(container.Resources.Limits) == 0 {
+		// Check for missing limits. GPUs and other extended resources always have a limit,
+		// so only CPU and memory limits count.
+		_, hasCPULimit := container.Resources.Limits[corev1.ResourceCPU]
+		_, hasMemoryLimit := container.Resources.Limits[corev1.ResourceMemory]
+		if !hasCPULimit && !hasMemoryLimit {
+			recommendedLimits := RecommendLimits(container.Resources.Requests)
+			cpuLimit := recommendedLimits[corev1.ResourceCPU]
+			memoryLimit := recommendedLimits[corev1.ResourceMemory]
 			optimizations = append(optimizations, Optimization{
 				PodName:       pod.Name,
 				ContainerName: container.Name,
 				Type:          "Missing Resource Limits",
 				Current:       ResourceValues{CPU: "Not set", Memory: "Not set"},
-				Recommended:   ResourceValues{CPU: "500m", Memory: "512Mi"},
+				Recommended:   ResourceValues{CPU: cpuLimit.String(), Memory: memoryLimit.String()},
 				Savings: CostSavings{
 					MonthlySavings: 0, // No direct savings, but prevents cost spikes
 					PercentSavings: 0,
@@ -192,6 +220,33 @@ func (r *ResourceOptimizer) analyzePodResources(pod *corev1.Pod) []Optimization
 	return optimizations
 }
 
+// Default limits used for containers that have no requests to derive limits from
+const (
+	DefaultCPULimit    = "500m"
+	DefaultMemoryLimit = "512Mi"
+
+	// limitToRequestRatio is the headroom given above requests when computing limits
+	limitToRequestRatio = 2
+)
+
+// RecommendLimits computes CPU and memory limits for a container from its requests,
+// allowing burst headroom above each request, or falls back to sensible defaults
+func RecommendLimits(requests corev1.ResourceList) corev1.ResourceList {
+	limits := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(DefaultCPULimit),
+		corev1.ResourceMemory: resource.MustParse(DefaultMemoryLimit),
+	}
+
+	if cpu, ok := requests[corev1.ResourceCPU]; ok && !cpu.IsZero() {
+		limits[corev1.ResourceCPU] = *resource.NewMilliQuantity(cpu.MilliValue()*limitToRequestRatio, resource.DecimalSI)
+	}
+	if memory, ok := requests[corev1.ResourceMemory]; ok && !memory.IsZero() {
+		limits[corev1.ResourceMemory] = *resource.NewQuantity(memory.Value()*limitToRequestRatio, resource.BinarySI)
+	}
+
+	return limits
+}
+
 func (r *ResourceOptimizer) calculateRecommendedCPU(currentCPU resource.Quantity) string {
 	// Simplified calculation - in real implementation, this would use metrics
 	// For demonstration, we're recommending a fixed value
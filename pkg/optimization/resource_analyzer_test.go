@@ -0,0 +1,55 @@
+package optimization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestRecommendLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		requests   corev1.ResourceList
+		wantCPU    string
+		wantMemory string
+	}{
+		{name: "no requests", wantCPU: DefaultCPULimit, wantMemory: DefaultMemoryLimit},
+		{
+			name: "both requested",
+			requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("250m"),
+				corev1.ResourceMemory: resource.MustParse("128Mi"),
+			},
+			wantCPU: "500m", wantMemory: "256Mi",
+		},
+		{
+			name:     "whole cores",
+			requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+			wantCPU:  "4", wantMemory: DefaultMemoryLimit,
+		},
+		{
+			name:     "memory only",
+			requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			wantCPU:  DefaultCPULimit, wantMemory: "2Gi",
+		},
+		{
+			name: "zero requests fall back to defaults",
+			requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("0"),
+				corev1.ResourceMemory: resource.MustParse("0"),
+			},
+			wantCPU: DefaultCPULimit, wantMemory: DefaultMemoryLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := RecommendLimits(tt.requests)
+			cpu, memory := limits[corev1.ResourceCPU], limits[corev1.ResourceMemory]
+			assert.Zero(t, cpu.Cmp(resource.MustParse(tt.wantCPU)), "cpu limit %s", cpu.String())
+			assert.Zero(t, memory.Cmp(resource.MustParse(tt.wantMemory)), "memory limit %s", memory.String())
+		})
+	}
+}
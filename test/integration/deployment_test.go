@@ -1,6 +1,7 @@
 package integration
 
 import (
+	"context"
 	"testing"
 
 	"github.com/abrarahmad1510/k8s-lens/pkg/diagnostics"
@@ -14,7 +15,7 @@ func TestDeploymentAnalysis(t *testing.T) {
 	analyzer := diagnostics.NewDeploymentAnalyzer(client, "default")
 
 	// Test with non-existent deployment
-	_, err := analyzer.Analyze("test-deployment")
+	_, err := analyzer.Analyze(context.Background(), "test-deployment")
 	if err == nil {
 		t.Error("Expected error for non-existent deployment, got nil")
 	}